@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/amonks/incrementum/internal/config"
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/internal/validation"
+	"github.com/amonks/incrementum/job"
+	"github.com/amonks/incrementum/todo"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect configuration",
+}
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Print the merged configuration as TOML",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEffective,
+}
+
+var (
+	configEffectiveType    string
+	configEffectiveProfile string
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configEffectiveCmd)
+
+	configEffectiveCmd.Flags().StringVarP(&configEffectiveType, "type", "t", "", "Resolve per-type settings for a todo type (task, bug, feature, design)")
+	configEffectiveCmd.Flags().StringVar(&configEffectiveProfile, "profile", "", "Resolve models for a config profile")
+}
+
+func runConfigEffective(cmd *cobra.Command, args []string) error {
+	repoPath, err := getRepoPath()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		return err
+	}
+
+	if internalstrings.IsBlank(configEffectiveType) && internalstrings.IsBlank(configEffectiveProfile) {
+		cfg = job.RedactConfig(cfg)
+	} else {
+		var item todo.Todo
+		if !internalstrings.IsBlank(configEffectiveType) {
+			item.Type = todo.TodoType(internalstrings.NormalizeLowerTrimSpace(configEffectiveType))
+			if !item.Type.IsValid() {
+				return validation.FormatInvalidValueError(todo.ErrInvalidType, item.Type, todo.ValidTodoTypes())
+			}
+		}
+		cfg, err = job.EffectiveConfig(cfg, configEffectiveProfile, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	enc := toml.NewEncoder(os.Stdout)
+	enc.Indent = ""
+	return enc.Encode(cfg)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/amonks/incrementum/habit"
 	"github.com/amonks/incrementum/internal/editor"
@@ -53,9 +54,17 @@ var habitCreateCmd = &cobra.Command{
 	RunE:  runHabitCreate,
 }
 
+// habit due
+var habitDueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List scheduled habits that are due to run",
+	Args:  cobra.NoArgs,
+	RunE:  runHabitDue,
+}
+
 func init() {
 	rootCmd.AddCommand(habitCmd)
-	habitCmd.AddCommand(habitListCmd, habitShowCmd, habitEditCmd, habitCreateCmd)
+	habitCmd.AddCommand(habitListCmd, habitShowCmd, habitEditCmd, habitCreateCmd, habitDueCmd)
 }
 
 func runHabitList(cmd *cobra.Command, args []string) error {
@@ -68,6 +77,7 @@ func runHabitList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	warnHabitScheduleErrors(habits)
 
 	if len(habits) == 0 {
 		fmt.Println("No habits found.")
@@ -119,6 +129,42 @@ func printHabitTable(habits []*habit.Habit, prefixLengths map[string]int, jobCou
 	fmt.Print(builder.String())
 }
 
+// warnHabitScheduleErrors reports habits whose schedule does not parse;
+// they only run on demand.
+func warnHabitScheduleErrors(habits []*habit.Habit) {
+	for _, h := range habits {
+		if h.ScheduleErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", h.ScheduleErr)
+		}
+	}
+}
+
+func runHabitDue(cmd *cobra.Command, args []string) error {
+	repoPath, err := getRepoPath()
+	if err != nil {
+		return err
+	}
+
+	habits, err := habit.LoadAll(repoPath)
+	if err != nil {
+		return err
+	}
+	warnHabitScheduleErrors(habits)
+
+	due, err := habit.Due(repoPath, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		fmt.Println("No habits due.")
+		return nil
+	}
+	for _, name := range due {
+		fmt.Println(name)
+	}
+	return nil
+}
+
 func runHabitShow(cmd *cobra.Command, args []string) error {
 	repoPath, err := getRepoPath()
 	if err != nil {
@@ -3,8 +3,10 @@ package main
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/amonks/incrementum/internal/config"
 	"github.com/amonks/incrementum/internal/listflags"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 	"github.com/amonks/incrementum/internal/ui"
@@ -38,21 +40,66 @@ var jobLogsCmd = &cobra.Command{
 	RunE:  runJobLogs,
 }
 
+var jobPromptsCmd = &cobra.Command{
+	Use:   "prompts <job-id>",
+	Short: "Show the prompts a job sent to opencode",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runJobPrompts,
+}
+
+var jobReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Find finished jobs whose todos are still in progress",
+	Args:  cobra.NoArgs,
+	RunE:  runJobReconcile,
+}
+
+var jobPruneLogsCmd = &cobra.Command{
+	Use:   "prune-logs",
+	Short: "Delete event logs of finished jobs older than a cutoff",
+	Args:  cobra.NoArgs,
+	RunE:  runJobPruneLogs,
+}
+
 var jobOpen = jobpkg.Open
 
 var (
 	jobListJSON   bool
 	jobListStatus string
 	jobListAll    bool
+	jobListTodo   string
+	jobListStage  string
+)
+
+var (
+	jobPromptsJSON  bool
+	jobPromptsIndex int
 )
 
+var (
+	jobReconcileFix  bool
+	jobReconcileJSON bool
+)
+
+var jobPruneLogsOlderThan time.Duration
+
 func init() {
 	rootCmd.AddCommand(jobCmd)
-	jobCmd.AddCommand(jobShowCmd, jobListCmd, jobLogsCmd)
+	jobCmd.AddCommand(jobShowCmd, jobListCmd, jobLogsCmd, jobPromptsCmd, jobReconcileCmd, jobPruneLogsCmd)
 
 	jobListCmd.Flags().BoolVar(&jobListJSON, "json", false, "Output as JSON")
 	jobListCmd.Flags().StringVar(&jobListStatus, "status", "", "Filter by status")
+	jobListCmd.Flags().StringVar(&jobListTodo, "todo", "", "Filter by todo ID or prefix")
+	jobListCmd.Flags().StringVar(&jobListStage, "stage", "", "Filter by stage")
 	listflags.AddAllFlag(jobListCmd, &jobListAll)
+
+	jobPromptsCmd.Flags().BoolVar(&jobPromptsJSON, "json", false, "Output as JSON")
+	jobPromptsCmd.Flags().IntVar(&jobPromptsIndex, "index", 0, "Print only the rendered text of the nth prompt (1-based)")
+
+	jobReconcileCmd.Flags().BoolVar(&jobReconcileFix, "fix", false, "Update mismatched todos to match their jobs")
+	jobReconcileCmd.Flags().BoolVar(&jobReconcileJSON, "json", false, "Output as JSON")
+
+	jobPruneLogsCmd.Flags().DurationVar(&jobPruneLogsOlderThan, "older-than", 30*24*time.Hour, "Only prune logs last written at least this long ago")
 }
 
 func runJobShow(cmd *cobra.Command, args []string) error {
@@ -84,6 +131,24 @@ func runJobShow(cmd *cobra.Command, args []string) error {
 	jobHighlight := logHighlighter(jobPrefixLengths, ui.HighlightID)
 	todoHighlight := logHighlighter(todoPrefixLengths, ui.HighlightID)
 	printJobDetail(item, todoTitle, jobHighlight, todoHighlight)
+
+	changeIDs, err := manager.ChangeIDs(item.ID)
+	if err != nil {
+		return err
+	}
+	printJobChanges(changeIDs)
+
+	timings, err := jobpkg.StageTimings(item.ID, jobpkg.EventLogOptions{})
+	if err != nil {
+		return err
+	}
+	printJobStageTimings(timings)
+
+	usage, err := jobpkg.UsageSummary(item.ID, jobpkg.EventLogOptions{})
+	if err != nil {
+		return err
+	}
+	printJobUsage(usage)
 	return nil
 }
 
@@ -93,7 +158,7 @@ func runJobList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	manager, err := jobOpen(repoPath, jobpkg.OpenOptions{})
+	manager, err := openJobManagerWithStaleAfter(repoPath)
 	if err != nil {
 		return err
 	}
@@ -103,11 +168,15 @@ func runJobList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	filter := jobpkg.ListFilter{IncludeAll: jobListAll}
+	filter := jobpkg.ListFilter{IncludeAll: jobListAll, TodoID: jobListTodo}
 	if jobListStatus != "" {
 		status := jobpkg.Status(jobListStatus)
 		filter.Status = &status
 	}
+	if jobListStage != "" {
+		stage := jobpkg.Stage(jobListStage)
+		filter.Stage = &stage
+	}
 
 	jobs, err := manager.List(filter)
 	if err != nil {
@@ -115,11 +184,11 @@ func runJobList(cmd *cobra.Command, args []string) error {
 	}
 
 	if jobListJSON {
-		return encodeJSONToStdout(jobs)
+		return encodeJSONToStdout(jobListItems(jobs))
 	}
 
 	allJobs := jobs
-	if jobListStatus != "" || !jobListAll {
+	if jobListStatus != "" || jobListTodo != "" || jobListStage != "" || !jobListAll {
 		allJobs, err = manager.List(jobpkg.ListFilter{IncludeAll: true})
 		if err != nil {
 			return err
@@ -127,6 +196,10 @@ func runJobList(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(jobs) == 0 {
+		if len(allJobs) > 0 && (jobListTodo != "" || jobListStage != "") {
+			fmt.Println("No jobs found matching --todo/--stage.")
+			return nil
+		}
 		fmt.Println(jobEmptyListMessage(len(allJobs), jobListStatus, jobListAll))
 		return nil
 	}
@@ -177,6 +250,156 @@ func runJobLogs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runJobPrompts(cmd *cobra.Command, args []string) error {
+	repoPath, err := getRepoPath()
+	if err != nil {
+		return err
+	}
+
+	manager, err := jobOpen(repoPath, jobpkg.OpenOptions{})
+	if err != nil {
+		return err
+	}
+
+	item, err := manager.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	records, err := jobpkg.Prompts(item.ID, jobpkg.EventLogOptions{RepoPath: repoPath})
+	if err != nil {
+		return err
+	}
+
+	if jobPromptsIndex != 0 {
+		if jobPromptsIndex < 1 || jobPromptsIndex > len(records) {
+			return fmt.Errorf("prompt index %d out of range: job %s has %d prompts", jobPromptsIndex, item.ID, len(records))
+		}
+		fmt.Print(records[jobPromptsIndex-1].Prompt)
+		return nil
+	}
+	if jobPromptsJSON {
+		return encodeJSONToStdout(records)
+	}
+	if len(records) == 0 {
+		fmt.Println("No prompts recorded.")
+		return nil
+	}
+	printJobPrompts(records)
+	return nil
+}
+
+// printJobPrompts prints each prompt with a numbered header naming its stage,
+// purpose, template, and time.
+func printJobPrompts(records []jobpkg.PromptRecord) {
+	for i, record := range records {
+		if i > 0 {
+			fmt.Println()
+		}
+		header := fmt.Sprintf("Prompt %d: %s (%s, %s)", i+1, record.Purpose, record.Stage, record.Template)
+		if !record.At.IsZero() {
+			header += " at " + record.At.Format("2006-01-02 15:04:05")
+		}
+		fmt.Println(header)
+		fmt.Println()
+		fmt.Println(strings.TrimRight(record.Prompt, "\n"))
+	}
+}
+
+// openJobManagerWithStaleAfter opens the job manager with the configured
+// job.stale-after threshold, for commands that mark stale jobs failed.
+func openJobManagerWithStaleAfter(repoPath string) (*jobpkg.Manager, error) {
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return jobOpen(repoPath, jobpkg.OpenOptions{StaleAfter: time.Duration(cfg.Job.StaleAfter)})
+}
+
+func runJobPruneLogs(cmd *cobra.Command, args []string) error {
+	if jobPruneLogsOlderThan < 0 {
+		return fmt.Errorf("--older-than must not be negative")
+	}
+
+	pruned, err := jobpkg.PruneEventLogs(jobpkg.EventLogOptions{}, jobPruneLogsOlderThan)
+	if err != nil {
+		return err
+	}
+	if pruned == 0 {
+		fmt.Println("No job logs to prune.")
+		return nil
+	}
+	fmt.Printf("Pruned %d job log(s)\n", pruned)
+	return nil
+}
+
+func runJobReconcile(cmd *cobra.Command, args []string) error {
+	repoPath, err := getRepoPath()
+	if err != nil {
+		return err
+	}
+
+	manager, err := openJobManagerWithStaleAfter(repoPath)
+	if err != nil {
+		return err
+	}
+
+	// Jobs orphaned by a crash are only detectable once marked stale.
+	if _, err := manager.MarkStaleJobsFailed(time.Now()); err != nil {
+		return err
+	}
+
+	jobs, err := manager.List(jobpkg.ListFilter{IncludeAll: true})
+	if err != nil {
+		return err
+	}
+
+	store, err := openTodoStoreForJob(repoPath, todoStorePurpose(cmd, args))
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		if jobReconcileJSON {
+			return encodeJSONToStdout([]jobpkg.TodoMismatch{})
+		}
+		fmt.Println("No mismatches found.")
+		return nil
+	}
+	todos, err := store.List(todo.ListFilter{})
+	releaseErr := store.Release()
+	if err != nil {
+		return errors.Join(err, releaseErr)
+	}
+	if releaseErr != nil {
+		return releaseErr
+	}
+
+	mismatches := jobpkg.FindTodoMismatches(jobs, todos)
+	if jobReconcileFix {
+		if err := jobpkg.FixTodoMismatches(repoPath, mismatches); err != nil {
+			return err
+		}
+	}
+
+	if jobReconcileJSON {
+		return encodeJSONToStdout(mismatches)
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("No mismatches found.")
+		return nil
+	}
+
+	jobHighlight := logHighlighter(jobIDPrefixLengths(jobs), ui.HighlightID)
+	for _, mismatch := range mismatches {
+		fmt.Printf("%s %s: todo %s is %s, expected %s\n",
+			jobHighlight(mismatch.Job.ID), mismatch.Job.Status, mismatch.Job.TodoID, mismatch.TodoStatus, mismatch.ExpectedStatus)
+	}
+	if jobReconcileFix {
+		fmt.Printf("Fixed %d mismatched todo(s).\n", len(mismatches))
+	}
+	return nil
+}
+
 func jobIDPrefixLengths(jobs []jobpkg.Job) map[string]int {
 	ids := make([]string, 0, len(jobs))
 	for _, item := range jobs {
@@ -195,7 +418,7 @@ func jobTodoTableInfo(repoPath string, purpose string) (map[string]int, map[stri
 	}
 	defer store.Release()
 
-	todos, err := store.List(todo.ListFilter{IncludeTombstones: true})
+	todos, err := store.List(todo.ListFilter{IncludeTombstones: true, IncludeArchived: true})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -227,7 +450,7 @@ func formatJobTable(opts TableFormatOptions) string {
 	now := opts.Now
 	todoPrefixLengths := opts.TodoPrefixLengths
 	jobPrefixLengths := opts.JobPrefixLengths
-	builder := ui.NewTableBuilder([]string{"JOB", "TODO", "STAGE", "STATUS", "IMPL", "REVIEW", "PROJECT", "AGE", "DURATION", "TITLE"}, len(jobs))
+	builder := ui.NewTableBuilder([]string{"JOB", "TODO", "STAGE", "STATUS", "TESTS", "IMPL", "REVIEW", "PROJECT", "AGE", "DURATION", "TITLE"}, len(jobs))
 
 	jobIDs := make([]string, 0, len(jobs))
 	todoIDs := make([]string, 0, len(jobs))
@@ -274,6 +497,7 @@ func formatJobTable(opts TableFormatOptions) string {
 			todoID,
 			string(item.Stage),
 			string(item.Status),
+			formatJobTestsCell(item.AllTestsPassed()),
 			implementationModel,
 			codeReviewModel,
 			projectReviewModel,
@@ -295,6 +519,32 @@ func formatJobDuration(item jobpkg.Job, now time.Time) string {
 	return formatOptionalDuration(jobpkg.DurationData(item, now))
 }
 
+// jobListItem is a job as job list --json prints it, with the test summary
+// derived from its commits.
+type jobListItem struct {
+	jobpkg.Job
+	AllTestsPassed *bool `json:"all_tests_passed,omitempty"`
+}
+
+func jobListItems(jobs []jobpkg.Job) []jobListItem {
+	items := make([]jobListItem, len(jobs))
+	for i := range jobs {
+		items[i] = jobListItem{Job: jobs[i], AllTestsPassed: jobs[i].AllTestsPassed()}
+	}
+	return items
+}
+
+func formatJobTestsCell(passed *bool) string {
+	switch {
+	case passed == nil:
+		return "-"
+	case *passed:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
 func formatJobModelCell(value string) string {
 	value = internalstrings.TrimSpace(value)
 	if value == "" {
@@ -313,6 +563,7 @@ func printJobDetail(item jobpkg.Job, todoTitle string, highlightJob func(string)
 	fmt.Printf("Todo:    %s\n", todoLine)
 	fmt.Printf("Stage:   %s\n", item.Stage)
 	fmt.Printf("Status:  %s\n", item.Status)
+	fmt.Printf("Tests:   %s\n", formatJobTestsCell(item.AllTestsPassed()))
 
 	if len(item.OpencodeSessions) > 0 {
 		fmt.Printf("\nOpencode Sessions:\n")
@@ -324,6 +575,58 @@ func printJobDetail(item jobpkg.Job, todoTitle string, highlightJob func(string)
 	if item.Feedback != "" {
 		fmt.Printf("\nFeedback:\n%s\n", item.Feedback)
 	}
+
+	if item.Reason != "" {
+		fmt.Printf("\nAbandon Reason:\n%s\n", item.Reason)
+	}
+}
+
+// printJobChanges prints the jj change IDs a job produced, oldest first.
+func printJobChanges(changeIDs []string) {
+	if len(changeIDs) == 0 {
+		return
+	}
+	fmt.Printf("\nChanges:\n")
+	for _, changeID := range changeIDs {
+		fmt.Printf("- %s\n", changeID)
+	}
+}
+
+// printJobStageTimings prints the time spent in each stage, in stage order.
+func printJobStageTimings(timings map[jobpkg.Stage]time.Duration) {
+	if len(timings) == 0 {
+		return
+	}
+	fmt.Printf("\nStage Timings:\n")
+	for _, stage := range jobpkg.ValidStages() {
+		duration, ok := timings[stage]
+		if !ok {
+			continue
+		}
+		fmt.Printf("- %s: %s\n", stage, ui.FormatDurationShort(duration))
+	}
+}
+
+// printJobUsage prints opencode token counts and cost per purpose, then the
+// total. Jobs without recorded usage print nothing.
+func printJobUsage(usage jobpkg.JobUsage) {
+	if usage.Total.IsZero() {
+		return
+	}
+	fmt.Printf("\nUsage:\n")
+	for _, purpose := range []string{"implement", "review", "project-review"} {
+		entry, ok := usage.ByPurpose[purpose]
+		if !ok || entry.IsZero() {
+			continue
+		}
+		fmt.Printf("- %s: %s\n", purpose, formatOpencodeUsage(entry))
+	}
+	fmt.Printf("- total: %s\n", formatOpencodeUsage(usage.Total))
+}
+
+func formatOpencodeUsage(usage jobpkg.OpencodeUsage) string {
+	return fmt.Sprintf("%d in, %d out, %d cached, $%.4f",
+		usage.InputTokens, usage.OutputTokens+usage.ReasoningTokens, usage.CacheReadTokens, usage.Cost)
 }
 
 func jobShowPrefixLengths(manager *jobpkg.Manager) (map[string]int, error) {
@@ -46,7 +46,11 @@ var (
 	jobDoEdit                bool
 	jobDoNoEdit              bool
 	jobDoAgent               string
+	jobDoProfile             string
 	jobDoHabit               string
+	jobDoDryRun              bool
+	jobDoCaptureOutput       bool
+	jobDoRequireClean        bool
 )
 
 func init() {
@@ -64,6 +68,10 @@ func init() {
 	jobDoCmd.Flags().BoolVarP(&jobDoEdit, "edit", "e", false, "Open $EDITOR (default if interactive and no create flags)")
 	jobDoCmd.Flags().BoolVar(&jobDoNoEdit, "no-edit", false, "Do not open $EDITOR")
 	jobDoCmd.Flags().StringVar(&jobDoAgent, "agent", "", "Opencode agent")
+	jobDoCmd.Flags().StringVar(&jobDoProfile, "profile", "", "Config profile supplying job models (overrides the todo's profile)")
+	jobDoCmd.Flags().BoolVar(&jobDoDryRun, "dry-run", false, "Run the job stages without invoking opencode, committing, or updating the todo")
+	jobDoCmd.Flags().BoolVar(&jobDoCaptureOutput, "capture-output", false, "Record opencode's stdout and stderr in the job event log")
+	jobDoCmd.Flags().BoolVar(&jobDoRequireClean, "require-clean", false, "Fail instead of starting a new change when the workspace has uncommitted changes")
 	jobDoCmd.Flags().StringVar(&jobDoHabit, "habit", "", "Run a habit instead of a todo (use habit name or empty for first)")
 	// Allow --habit without a value to run the first habit alphabetically
 	jobDoCmd.Flags().Lookup("habit").NoOptDefVal = " "
@@ -83,6 +91,12 @@ func runJobDo(cmd *cobra.Command, args []string) error {
 		if len(args) > 0 {
 			return fmt.Errorf("--habit cannot be combined with todo ids")
 		}
+		if jobDoDryRun {
+			return fmt.Errorf("--dry-run cannot be combined with --habit")
+		}
+		if cmd.Flags().Changed("profile") {
+			return fmt.Errorf("--profile cannot be combined with --habit")
+		}
 		return runHabitJob(cmd)
 	}
 
@@ -178,12 +192,17 @@ func runHabitJob(cmd *cobra.Command) error {
 	}()
 
 	result, err := jobpkg.RunHabit(repoPath, h.Name, jobpkg.HabitRunOptions{
-		OnStart:       onStart,
-		OnStageChange: onStageChange,
-		Logger:        logger,
-		EventStream:   eventStream,
-		OpencodeAgent: opencodeAgent,
+		OnStart:               onStart,
+		OnStageChange:         onStageChange,
+		Logger:                logger,
+		EventStream:           eventStream,
+		OpencodeAgent:         opencodeAgent,
+		CaptureOutput:         jobDoCaptureOutput,
+		RequireCleanWorkspace: jobDoRequireClean,
 	})
+	if result != nil {
+		defer waitForCompletionWebhook(result.WebhookDone)
+	}
 	close(eventDone)
 	streamErr := <-eventErrs
 	if err != nil {
@@ -253,6 +272,9 @@ func runJobDoTodo(cmd *cobra.Command, todoID string) error {
 
 	// Design todos require interactive sessions
 	if item.Type.IsInteractive() {
+		if jobDoDryRun {
+			return fmt.Errorf("--dry-run is not supported for %s todos", item.Type)
+		}
 		return runDesignTodo(cmd, repoPath, item)
 	}
 
@@ -392,7 +414,7 @@ func runHeadlessJob(cmd *cobra.Command, repoPath, todoID string) error {
 
 	logger := jobpkg.NewConsoleLogger(os.Stdout)
 	reporter := newJobStageReporter(logger)
-	onStageChange := reporter.OnStageChange
+	onStageChange := reporter.OnJobStageChange
 	onStart := func(info jobpkg.StartInfo) {
 		printJobStart(info)
 	}
@@ -425,12 +447,19 @@ func runHeadlessJob(cmd *cobra.Command, repoPath, todoID string) error {
 	}()
 
 	result, err := jobRun(repoPath, todoID, jobpkg.RunOptions{
-		OnStart:       onStart,
-		OnStageChange: onStageChange,
-		Logger:        logger,
-		EventStream:   eventStream,
-		OpencodeAgent: opencodeAgent,
+		OnStart:               onStart,
+		OnStageChange:         onStageChange,
+		Logger:                logger,
+		EventStream:           eventStream,
+		OpencodeAgent:         opencodeAgent,
+		Profile:               jobDoProfile,
+		DryRun:                jobDoDryRun,
+		CaptureOutput:         jobDoCaptureOutput,
+		RequireCleanWorkspace: jobDoRequireClean,
 	})
+	if result != nil {
+		defer waitForCompletionWebhook(result.WebhookDone)
+	}
 	close(eventDone)
 	streamErr := <-eventErrs
 	if err != nil {
@@ -453,6 +482,17 @@ func runHeadlessJob(cmd *cobra.Command, repoPath, todoID string) error {
 	return nil
 }
 
+// waitForCompletionWebhook lets a background webhook delivery finish before
+// the process exits. Delivery failures are reported but do not fail the job.
+func waitForCompletionWebhook(done <-chan error) {
+	if done == nil {
+		return
+	}
+	if err := <-done; err != nil {
+		fmt.Fprintf(os.Stderr, "warning: completion webhook: %v\n", err)
+	}
+}
+
 func formatCommitMessagesOutput(entries []jobpkg.CommitLogEntry) string {
 	var out strings.Builder
 	out.WriteString("Commit messages:\n")
@@ -605,6 +645,10 @@ func (reporter *jobStageReporter) OnStageChange(stage jobpkg.Stage) {
 	}
 }
 
+func (reporter *jobStageReporter) OnJobStageChange(change jobpkg.StageChange) {
+	reporter.OnStageChange(change.Stage)
+}
+
 const (
 	jobLineWidth         = 80
 	jobDocumentIndent    = 4
@@ -131,6 +131,9 @@ func runDoAllHabit(cmd *cobra.Command, repoPath, habitName string) error {
 		Logger:        logger,
 		OpencodeAgent: opencodeAgent,
 	})
+	if result != nil {
+		defer waitForCompletionWebhook(result.WebhookDone)
+	}
 	if err != nil {
 		var abandonedErr *jobpkg.AbandonedError
 		if errors.As(err, &abandonedErr) {
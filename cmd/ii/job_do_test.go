@@ -125,6 +125,7 @@ func resetJobDoGlobals() {
 	jobDoEdit = false
 	jobDoNoEdit = false
 	jobDoAgent = ""
+	jobDoDryRun = false
 }
 
 func newTestJobDoCommand() *cobra.Command {
@@ -138,6 +139,7 @@ func newTestJobDoCommand() *cobra.Command {
 	cmd.Flags().BoolVarP(&jobDoEdit, "edit", "e", false, "Open $EDITOR (default if interactive and no create flags)")
 	cmd.Flags().BoolVar(&jobDoNoEdit, "no-edit", false, "Do not open $EDITOR")
 	cmd.Flags().StringVar(&jobDoAgent, "agent", "", "Opencode agent")
+	cmd.Flags().BoolVar(&jobDoDryRun, "dry-run", false, "Run the job stages without invoking opencode, committing, or updating the todo")
 	return cmd
 }
 
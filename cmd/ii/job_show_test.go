@@ -50,3 +50,91 @@ func TestPrintJobDetailIncludesFeedbackAndSessions(t *testing.T) {
 		t.Fatalf("expected review session in output, got: %q", output)
 	}
 }
+
+func TestPrintJobStageTimingsInStageOrder(t *testing.T) {
+	output := captureStdout(t, func() {
+		printJobStageTimings(map[jobpkg.Stage]time.Duration{
+			jobpkg.StageReviewing:    30 * time.Second,
+			jobpkg.StageImplementing: 3 * time.Minute,
+		})
+	})
+
+	want := "\nStage Timings:\n- implementing: 3m\n- reviewing: 30s\n"
+	if output != want {
+		t.Fatalf("expected %q, got %q", want, output)
+	}
+}
+
+func TestPrintJobUsagePerPurposeAndTotal(t *testing.T) {
+	output := captureStdout(t, func() {
+		printJobUsage(jobpkg.JobUsage{
+			Total: jobpkg.OpencodeUsage{InputTokens: 1500, OutputTokens: 300, CacheReadTokens: 100, Cost: 0.05},
+			ByPurpose: map[string]jobpkg.OpencodeUsage{
+				"review":    {InputTokens: 500, OutputTokens: 100, Cost: 0.02},
+				"implement": {InputTokens: 1000, OutputTokens: 200, CacheReadTokens: 100, Cost: 0.03},
+			},
+		})
+	})
+
+	want := "\nUsage:\n- implement: 1000 in, 200 out, 100 cached, $0.0300\n- review: 500 in, 100 out, 0 cached, $0.0200\n- total: 1500 in, 300 out, 100 cached, $0.0500\n"
+	if output != want {
+		t.Fatalf("expected %q, got %q", want, output)
+	}
+
+	empty := captureStdout(t, func() {
+		printJobUsage(jobpkg.JobUsage{})
+	})
+	if empty != "" {
+		t.Fatalf("expected no output without usage, got %q", empty)
+	}
+}
+
+func TestPrintJobDetailIncludesAbandonReason(t *testing.T) {
+	job := jobpkg.Job{
+		ID:     "job-123",
+		TodoID: "todo-abc",
+		Stage:  jobpkg.StageReviewing,
+		Status: jobpkg.StatusAbandoned,
+		Reason: "The todo duplicates existing behavior.",
+	}
+
+	output := captureStdout(t, func() {
+		printJobDetail(job, "", func(id string) string { return id }, func(id string) string { return id })
+	})
+
+	if !strings.Contains(output, "Abandon Reason:\nThe todo duplicates existing behavior.") {
+		t.Fatalf("expected abandon reason in output, got: %q", output)
+	}
+}
+
+func TestPrintJobPrompts(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	output := captureStdout(t, func() {
+		printJobPrompts([]jobpkg.PromptRecord{
+			{Stage: jobpkg.StageImplementing, Purpose: "implement", Template: "prompt-implementation.tmpl", Prompt: "Do the thing.\n", At: at},
+			{Stage: jobpkg.StageReviewing, Purpose: "review", Template: "prompt-commit-review.tmpl", Prompt: "Review it."},
+		})
+	})
+
+	want := "Prompt 1: implement (implementing, prompt-implementation.tmpl) at 2026-01-02 03:04:05\n\nDo the thing.\n\n" +
+		"Prompt 2: review (reviewing, prompt-commit-review.tmpl)\n\nReview it.\n"
+	if output != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", output, want)
+	}
+}
+
+func TestPrintJobChangesInOrder(t *testing.T) {
+	output := captureStdout(t, func() {
+		printJobChanges([]string{"kxqpmrvz", "wlnoystu"})
+	})
+	if output != "\nChanges:\n- kxqpmrvz\n- wlnoystu\n" {
+		t.Fatalf("unexpected changes output: %q", output)
+	}
+
+	output = captureStdout(t, func() {
+		printJobChanges(nil)
+	})
+	if output != "" {
+		t.Fatalf("expected no output without changes, got %q", output)
+	}
+}
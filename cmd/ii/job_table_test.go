@@ -151,15 +151,15 @@ func TestFormatJobTableUsesCompactAge(t *testing.T) {
 	}
 
 	fields := strings.Fields(lines[1])
-	if len(fields) < 10 {
-		t.Fatalf("expected at least 10 columns, got: %q", lines[1])
+	if len(fields) < 11 {
+		t.Fatalf("expected at least 11 columns, got: %q", lines[1])
 	}
 
-	if fields[7] != "2m" {
-		t.Fatalf("expected compact age 2m, got: %s", fields[7])
-	}
 	if fields[8] != "2m" {
-		t.Fatalf("expected compact duration 2m, got: %s", fields[8])
+		t.Fatalf("expected compact age 2m, got: %s", fields[8])
+	}
+	if fields[9] != "2m" {
+		t.Fatalf("expected compact duration 2m, got: %s", fields[9])
 	}
 }
 
@@ -194,12 +194,12 @@ func TestFormatJobTableUsesUpdatedDurationForCompleted(t *testing.T) {
 	}
 
 	fields := strings.Fields(lines[1])
-	if len(fields) < 10 {
-		t.Fatalf("expected at least 10 columns, got: %q", lines[1])
+	if len(fields) < 11 {
+		t.Fatalf("expected at least 11 columns, got: %q", lines[1])
 	}
 
-	if fields[8] != "10m" {
-		t.Fatalf("expected duration 10m, got: %s", fields[8])
+	if fields[9] != "10m" {
+		t.Fatalf("expected duration 10m, got: %s", fields[9])
 	}
 }
 
@@ -262,16 +262,46 @@ func TestFormatJobTableIncludesModelColumns(t *testing.T) {
 	}
 
 	fields := strings.Fields(lines[1])
-	if len(fields) < 10 {
-		t.Fatalf("expected at least 10 columns, got: %q", lines[1])
+	if len(fields) < 11 {
+		t.Fatalf("expected at least 11 columns, got: %q", lines[1])
+	}
+	if fields[5] != "impl-model" {
+		t.Fatalf("expected implementation model, got: %s", fields[5])
+	}
+	if fields[6] != "review-model" {
+		t.Fatalf("expected code review model, got: %s", fields[6])
 	}
-	if fields[4] != "impl-model" {
-		t.Fatalf("expected implementation model, got: %s", fields[4])
+	if fields[7] != "project-model" {
+		t.Fatalf("expected project review model, got: %s", fields[7])
+	}
+}
+
+func TestFormatJobTableShowsTestResults(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	passed, failed := true, false
+
+	jobs := []jobpkg.Job{
+		{ID: "job-pass", TodoID: "abc12345", Stage: jobpkg.StageReviewing, Status: jobpkg.StatusActive, CreatedAt: now, StartedAt: now, UpdatedAt: now, Changes: []jobpkg.JobChange{{Commits: []jobpkg.JobCommit{{TestsPassed: &passed}}}}},
+		{ID: "job-fail", TodoID: "abc12345", Stage: jobpkg.StageReviewing, Status: jobpkg.StatusActive, CreatedAt: now, StartedAt: now, UpdatedAt: now, Changes: []jobpkg.JobChange{{Commits: []jobpkg.JobCommit{{TestsPassed: &failed}}}}},
+		{ID: "job-none", TodoID: "abc12345", Stage: jobpkg.StageImplementing, Status: jobpkg.StatusActive, CreatedAt: now, StartedAt: now, UpdatedAt: now},
+	}
+
+	output := trimmedJobTable(TableFormatOptions{
+		Jobs:      jobs,
+		Highlight: func(id string, prefix int) string { return id },
+		Now:       now,
+	})
+	lines := strings.Split(output, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got: %q", output)
 	}
-	if fields[5] != "review-model" {
-		t.Fatalf("expected code review model, got: %s", fields[5])
+	if fields := strings.Fields(lines[0]); fields[4] != "TESTS" {
+		t.Fatalf("expected TESTS column after STATUS, got: %q", lines[0])
 	}
-	if fields[6] != "project-model" {
-		t.Fatalf("expected project review model, got: %s", fields[6])
+	for i, want := range []string{"pass", "fail", "-"} {
+		fields := strings.Fields(lines[i+1])
+		if fields[4] != want {
+			t.Fatalf("expected %s tests cell for %s, got: %s", want, fields[0], fields[4])
+		}
 	}
 }
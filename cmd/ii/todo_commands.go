@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,7 +29,10 @@ var todoCreateCmd = &cobra.Command{
 
 By default, opens $EDITOR to edit a TOML representation of the todo
 when running interactively and no create flags are provided. Use --no-edit
-to skip the editor, or --edit to force opening the editor even when not interactive.`,
+to skip the editor, or --edit to force opening the editor even when not interactive.
+
+Use --template <name> to pre-fill the todo from
+.incrementum/todo-templates/<name>.toml. Flags override template fields.`,
 	Args: cobra.NoArgs,
 	RunE: runTodoCreate,
 }
@@ -39,7 +45,13 @@ var (
 	todoCreateImplementationModel string
 	todoCreateCodeReviewModel     string
 	todoCreateProjectReviewModel  string
+	todoCreateProfile             string
+	todoCreateDesignDoc           string
 	todoCreateDeps                []string
+	todoCreateTags                []string
+	todoCreateDue                 string
+	todoCreateEstimate            int
+	todoCreateTemplate            string
 	todoCreateEdit                bool
 	todoCreateNoEdit              bool
 )
@@ -69,6 +81,10 @@ var (
 	todoUpdateImplementationModel string
 	todoUpdateCodeReviewModel     string
 	todoUpdateProjectReviewModel  string
+	todoUpdateProfile             string
+	todoUpdateDesignDoc           string
+	todoUpdateDue                 string
+	todoUpdateEstimate            int
 	todoUpdateEdit                bool
 	todoUpdateNoEdit              bool
 )
@@ -108,6 +124,22 @@ var todoReopenCmd = &cobra.Command{
 	RunE:  runTodoReopen,
 }
 
+// todo archive
+var todoArchiveCmd = &cobra.Command{
+	Use:   "archive <id>...",
+	Short: "Hide one or more todos from default listings without deleting them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runTodoArchive,
+}
+
+// todo unarchive
+var todoUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <id>...",
+	Short: "Reopen one or more archived todos",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runTodoUnarchive,
+}
+
 // todo delete
 var todoDeleteCmd = &cobra.Command{
 	Use:   "delete <id>...",
@@ -145,6 +177,32 @@ var (
 	todoListJSON       bool
 	todoListAll        bool
 	todoListTombstones bool
+	todoListArchived   bool
+	todoListTags       []string
+	todoListAllTags    bool
+	todoListDueBefore  string
+	todoListOverdue    bool
+	todoListSince      string
+	todoListUntil      string
+)
+
+// todo search
+var todoSearchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Search todo titles, descriptions, and notes",
+	Long: `Search todo titles, descriptions, and notes. The arguments are joined with
+spaces and split into words; a todo matches when every word appears in one of
+those fields, ignoring case. Todos matching in their titles are listed first.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTodoSearch,
+}
+
+var (
+	todoSearchStatus     string
+	todoSearchTags       []string
+	todoSearchJSON       bool
+	todoSearchTombstones bool
+	todoSearchArchived   bool
 )
 
 // todo ready
@@ -157,6 +215,58 @@ var todoReadyCmd = &cobra.Command{
 var (
 	todoReadyLimit int
 	todoReadyJSON  bool
+	todoReadyByDue bool
+)
+
+// todo reprioritize
+var todoReprioritizeCmd = &cobra.Command{
+	Use:   "reprioritize",
+	Short: "Reorder todos in $EDITOR and update priorities to match",
+	Args:  cobra.NoArgs,
+	RunE:  runTodoReprioritize,
+}
+
+var todoReprioritizeAll bool
+
+// todo note
+var todoNoteCmd = &cobra.Command{
+	Use:   "note <id> <text>...",
+	Short: "Append a note to a todo",
+	Long: `Append a note to a todo's notes log. The arguments after the ID are joined
+with spaces to form the note. Notes are shown by "ii todo show".`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTodoNote,
+}
+
+var todoNoteAuthor string
+
+// todo import
+var todoImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Create todos from a markdown checklist",
+	Long: `Create todos from a markdown checklist read from a file, or stdin when the
+file is omitted or "-".
+
+Each "- [ ] Title" line becomes a todo. Lines indented beneath an item become its
+description, and nested items become its dependencies. "@priority:high" sets the
+priority and "#tag" adds a tag. If any item is invalid, nothing is created.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTodoImport,
+}
+
+// todo export
+var todoExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export todos and their dependencies as JSON or markdown",
+	Args:  cobra.NoArgs,
+	RunE:  runTodoExport,
+}
+
+var (
+	todoExportFormat     string
+	todoExportStatus     string
+	todoExportTags       []string
+	todoExportTombstones bool
 )
 
 // todo dep
@@ -173,6 +283,26 @@ var todoDepAddCmd = &cobra.Command{
 	RunE:  runTodoDepAdd,
 }
 
+var todoDepAddType string
+
+// todo dep remove
+var todoDepRemoveCmd = &cobra.Command{
+	Use:   "remove <todo-id> <depends-on-id>",
+	Short: "Remove a dependency between todos",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTodoDepRemove,
+}
+
+// todo dep rdeps
+var todoDepRdepsCmd = &cobra.Command{
+	Use:   "rdeps <id>",
+	Short: "Show todos that depend on a todo",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTodoDepRdeps,
+}
+
+var todoDepRdepsTombstones bool
+
 // todo dep tree
 var todoDepTreeCmd = &cobra.Command{
 	Use:   "tree <id>",
@@ -181,11 +311,16 @@ var todoDepTreeCmd = &cobra.Command{
 	RunE:  runTodoDepTree,
 }
 
+var todoDepTreeDepth int
+
 func init() {
 	rootCmd.AddCommand(todoCmd)
 	todoCmd.AddCommand(todoCreateCmd, todoUpdateCmd, todoStartCmd, todoCloseCmd, todoFinishCmd, todoReopenCmd,
-		todoDeleteCmd, todoShowCmd, todoListCmd, todoReadyCmd, todoDepCmd)
-	todoDepCmd.AddCommand(todoDepAddCmd, todoDepTreeCmd)
+		todoArchiveCmd, todoUnarchiveCmd, todoDeleteCmd, todoShowCmd, todoListCmd, todoSearchCmd, todoReadyCmd, todoReprioritizeCmd, todoNoteCmd, todoImportCmd, todoExportCmd, todoDepCmd)
+	todoDepCmd.AddCommand(todoDepAddCmd, todoDepRemoveCmd, todoDepRdepsCmd, todoDepTreeCmd)
+	todoDepAddCmd.Flags().StringVar(&todoDepAddType, "type", string(todo.DepTypeBlocks), "Dependency type (blocks, discovered-from)")
+	todoDepRdepsCmd.Flags().BoolVar(&todoDepRdepsTombstones, "tombstones", false, "Include tombstoned dependents")
+	todoDepTreeCmd.Flags().IntVar(&todoDepTreeDepth, "depth", 0, "Maximum depth to show (0 for unlimited)")
 	addDescriptionFlagAliases(todoCreateCmd, todoUpdateCmd, todoListCmd)
 
 	// todo create flags
@@ -196,19 +331,29 @@ func init() {
 	todoCreateCmd.Flags().StringVar(&todoCreateImplementationModel, "implementation-model", "", "Opencode model for implementation")
 	todoCreateCmd.Flags().StringVar(&todoCreateCodeReviewModel, "code-review-model", "", "Opencode model for commit review")
 	todoCreateCmd.Flags().StringVar(&todoCreateProjectReviewModel, "project-review-model", "", "Opencode model for project review")
+	todoCreateCmd.Flags().StringVar(&todoCreateProfile, "profile", "", "Config profile supplying job models")
+	todoCreateCmd.Flags().StringVar(&todoCreateDesignDoc, "design-doc", "", "Repo-relative path to a design doc for job prompts")
 	todoCreateCmd.Flags().StringArrayVar(&todoCreateDeps, "deps", nil, "Dependencies in format <id> (e.g., abc123)")
+	todoCreateCmd.Flags().StringArrayVar(&todoCreateTags, "tag", nil, "Tag (repeatable)")
+	todoCreateCmd.Flags().StringVar(&todoCreateDue, "due", "", "Due date (YYYY-MM-DD or RFC 3339)")
+	todoCreateCmd.Flags().IntVar(&todoCreateEstimate, "estimate", 0, "Estimated effort in minutes")
+	todoCreateCmd.Flags().StringVar(&todoCreateTemplate, "template", "", "Todo template name from .incrementum/todo-templates")
 	todoCreateCmd.Flags().BoolVarP(&todoCreateEdit, "edit", "e", false, "Open $EDITOR (default if interactive and no create flags)")
 	todoCreateCmd.Flags().BoolVar(&todoCreateNoEdit, "no-edit", false, "Do not open $EDITOR")
 
 	// todo update flags
 	todoUpdateCmd.Flags().StringVar(&todoUpdateTitle, "title", "", "New title")
 	todoUpdateCmd.Flags().StringVarP(&todoUpdateDescription, "description", "d", "", "New description (use '-' to read from stdin)")
-	todoUpdateCmd.Flags().StringVar(&todoUpdateStatus, "status", "", "New status (open, proposed, in_progress, closed, done, tombstone)")
+	todoUpdateCmd.Flags().StringVar(&todoUpdateStatus, "status", "", "New status (open, proposed, in_progress, closed, done, waiting, tombstone, archived)")
 	todoUpdateCmd.Flags().IntVar(&todoUpdatePriority, "priority", 0, "New priority (0-4)")
 	todoUpdateCmd.Flags().StringVar(&todoUpdateType, "type", "", "New type (task, bug, feature, design)")
 	todoUpdateCmd.Flags().StringVar(&todoUpdateImplementationModel, "implementation-model", "", "Opencode model for implementation")
 	todoUpdateCmd.Flags().StringVar(&todoUpdateCodeReviewModel, "code-review-model", "", "Opencode model for commit review")
 	todoUpdateCmd.Flags().StringVar(&todoUpdateProjectReviewModel, "project-review-model", "", "Opencode model for project review")
+	todoUpdateCmd.Flags().StringVar(&todoUpdateProfile, "profile", "", "Config profile supplying job models (empty to clear)")
+	todoUpdateCmd.Flags().StringVar(&todoUpdateDesignDoc, "design-doc", "", "Repo-relative path to a design doc for job prompts (empty to clear)")
+	todoUpdateCmd.Flags().StringVar(&todoUpdateDue, "due", "", "Due date (YYYY-MM-DD or RFC 3339; empty to clear)")
+	todoUpdateCmd.Flags().IntVar(&todoUpdateEstimate, "estimate", 0, "Estimated effort in minutes (0 to clear)")
 	todoUpdateCmd.Flags().BoolVarP(&todoUpdateEdit, "edit", "e", false, "Open $EDITOR (default if interactive)")
 	todoUpdateCmd.Flags().BoolVar(&todoUpdateNoEdit, "no-edit", false, "Do not open $EDITOR")
 
@@ -224,6 +369,14 @@ func init() {
 	// todo show flags
 	todoShowCmd.Flags().BoolVar(&todoShowJSON, "json", false, "Output as JSON")
 
+	// todo export flags
+	todoNoteCmd.Flags().StringVar(&todoNoteAuthor, "author", os.Getenv("USER"), "Note author")
+
+	todoExportCmd.Flags().StringVar(&todoExportFormat, "format", "json", "Output format (json, markdown/md)")
+	todoExportCmd.Flags().StringVar(&todoExportStatus, "status", "", "Filter by status")
+	todoExportCmd.Flags().StringArrayVar(&todoExportTags, "tag", nil, "Filter by tag (repeatable; matches any)")
+	todoExportCmd.Flags().BoolVar(&todoExportTombstones, "tombstones", false, "Include tombstoned todos")
+
 	// todo list flags
 	todoListCmd.Flags().StringVar(&todoListStatus, "status", "", "Filter by status")
 	todoListCmd.Flags().IntVar(&todoListPriority, "priority", -1, "Filter by priority (0-4)")
@@ -233,11 +386,29 @@ func init() {
 	todoListCmd.Flags().StringVarP(&todoListDesc, "description", "d", "", "Filter by description substring")
 	todoListCmd.Flags().BoolVar(&todoListJSON, "json", false, "Output as JSON")
 	todoListCmd.Flags().BoolVar(&todoListTombstones, "tombstones", false, "Include tombstoned todos")
+	todoListCmd.Flags().BoolVar(&todoListArchived, "archived", false, "Include archived todos")
+	todoListCmd.Flags().StringArrayVar(&todoListTags, "tag", nil, "Filter by tag (repeatable; matches any)")
+	todoListCmd.Flags().BoolVar(&todoListAllTags, "all-tags", false, "Require every --tag to match")
+	todoListCmd.Flags().StringVar(&todoListDueBefore, "due-before", "", "Filter to todos due before a date (YYYY-MM-DD or RFC 3339)")
+	todoListCmd.Flags().BoolVar(&todoListOverdue, "overdue", false, "Filter to overdue todos")
+	todoListCmd.Flags().StringVar(&todoListSince, "since", "", "Filter to todos updated since a date, RFC 3339 time, or duration ago (e.g. 7d)")
+	todoListCmd.Flags().StringVar(&todoListUntil, "until", "", "Filter to todos updated before a date, RFC 3339 time, or duration ago (e.g. 1d)")
 	listflags.AddAllFlag(todoListCmd, &todoListAll)
 
+	// todo search flags
+	todoSearchCmd.Flags().StringVar(&todoSearchStatus, "status", "", "Filter by status")
+	todoSearchCmd.Flags().StringArrayVar(&todoSearchTags, "tag", nil, "Filter by tag (repeatable; matches any)")
+	todoSearchCmd.Flags().BoolVar(&todoSearchJSON, "json", false, "Output as JSON")
+	todoSearchCmd.Flags().BoolVar(&todoSearchTombstones, "tombstones", false, "Include tombstoned todos")
+	todoSearchCmd.Flags().BoolVar(&todoSearchArchived, "archived", false, "Include archived todos")
+
 	// todo ready flags
 	todoReadyCmd.Flags().IntVar(&todoReadyLimit, "limit", 20, "Maximum number of todos to show")
 	todoReadyCmd.Flags().BoolVar(&todoReadyJSON, "json", false, "Output as JSON")
+	todoReadyCmd.Flags().BoolVar(&todoReadyByDue, "by-due", false, "Break priority ties by due date")
+
+	// todo reprioritize flags
+	todoReprioritizeCmd.Flags().BoolVar(&todoReprioritizeAll, "all", false, "Include blocked open todos, not just ready ones")
 
 }
 
@@ -257,13 +428,28 @@ func runTodoCreate(cmd *cobra.Command, args []string) error {
 	// - --edit forces editor
 	// - --no-edit skips editor
 	// - otherwise, open editor only when no create fields and interactive
-	hasCreateFlags := hasTodoCreateFlags(cmd)
+	hasCreateFlags := hasTodoCreateFlags(cmd) || hasChangedFlags(cmd, "profile")
 	useEditor := shouldUseEditor(hasCreateFlags, todoCreateEdit, todoCreateNoEdit, editor.IsInteractive())
 
+	var tmpl *todo.Template
+	if todoCreateTemplate != "" {
+		repoPath, err := getRepoPath()
+		if err != nil {
+			return err
+		}
+		tmpl, err = todo.LoadTemplate(repoPath, todoCreateTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
 	if useEditor {
-		// Pre-populate from flags if provided
+		// Pre-populate from the template, then from flags if provided
 		data := editor.DefaultCreateData()
 		data.Status = string(defaultTodoStatus())
+		if tmpl != nil {
+			applyTodoTemplateToEditorData(&data, tmpl)
+		}
 		if cmd.Flags().Changed("title") {
 			data.Title = todoCreateTitle
 		}
@@ -285,6 +471,9 @@ func runTodoCreate(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().Changed("project-review-model") {
 			data.ProjectReviewModel = todoCreateProjectReviewModel
 		}
+		if cmd.Flags().Changed("estimate") {
+			data.EstimateMinutes = todoCreateEstimate
+		}
 
 		parsed, err := editor.EditTodoWithData(data)
 		if err != nil {
@@ -299,17 +488,24 @@ func runTodoCreate(cmd *cobra.Command, args []string) error {
 
 		opts := parsed.ToCreateOptions()
 		opts.Dependencies = todoCreateDeps
-
-		created, err := store.Create(parsed.Title, opts)
+		opts.Profile = todoCreateProfile
+		opts.DesignDoc = todoCreateDesignDoc
+		opts.Tags = todoCreateTags
+		if tmpl != nil {
+			// The buffer already held the template's other fields.
+			opts.Tags = append(append([]string(nil), tmpl.Tags...), todoCreateTags...)
+		}
+		opts.DueAt, err = parseTodoDueFlag(todoCreateDue)
 		if err != nil {
 			return err
 		}
 
-		highlight, err := todoLogHighlighterForStore(store)
+		created, err := store.Create(parsed.Title, opts)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Created todo %s: %s\n", highlight(created.ID), created.Title)
+
+		fmt.Printf("Created todo %s: %s\n", ui.HighlightID(created.ID, store.MinUniquePrefix(created.ID)), created.Title)
 		return nil
 	}
 
@@ -318,13 +514,18 @@ func runTodoCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("title is required (use --edit to open editor)")
 	}
 
+	dueAt, err := parseTodoDueFlag(todoCreateDue)
+	if err != nil {
+		return err
+	}
+
 	store, err := openTodoStore(cmd, args)
 	if err != nil {
 		return err
 	}
 	defer store.Release()
 
-	created, err := store.Create(todoCreateTitle, todo.CreateOptions{
+	opts := todo.CreateOptions{
 		Status:              defaultTodoStatus(),
 		Type:                todo.TodoType(todoCreateType),
 		Priority:            todoCreatePriorityValue(cmd),
@@ -332,17 +533,27 @@ func runTodoCreate(cmd *cobra.Command, args []string) error {
 		ImplementationModel: todoCreateImplementationModel,
 		CodeReviewModel:     todoCreateCodeReviewModel,
 		ProjectReviewModel:  todoCreateProjectReviewModel,
+		Profile:             todoCreateProfile,
+		DesignDoc:           todoCreateDesignDoc,
 		Dependencies:        todoCreateDeps,
-	})
-	if err != nil {
-		return err
+		Tags:                todoCreateTags,
+		DueAt:               dueAt,
+		EstimateMinutes:     todoCreateEstimate,
+	}
+	if tmpl != nil {
+		// Only an explicit --type overrides the template's type.
+		if !cmd.Flags().Changed("type") {
+			opts.Type = ""
+		}
+		opts = tmpl.CreateOptions(opts)
 	}
 
-	highlight, err := todoLogHighlighterForStore(store)
+	created, err := store.Create(todoCreateTitle, opts)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Created todo %s: %s\n", highlight(created.ID), created.Title)
+
+	fmt.Printf("Created todo %s: %s\n", ui.HighlightID(created.ID, store.MinUniquePrefix(created.ID)), created.Title)
 	return nil
 }
 
@@ -357,7 +568,7 @@ func runTodoUpdate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	hasFlags := hasChangedFlags(cmd, "title", "description", "status", "priority", "type", "implementation-model", "code-review-model", "project-review-model")
+	hasFlags := hasChangedFlags(cmd, "title", "description", "status", "priority", "type", "implementation-model", "code-review-model", "project-review-model", "profile", "design-doc", "due", "estimate")
 
 	// Determine whether to open editor:
 	// - --edit forces editor
@@ -400,6 +611,9 @@ func runTodoUpdate(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().Changed("project-review-model") {
 				data.ProjectReviewModel = todoUpdateProjectReviewModel
 			}
+			if cmd.Flags().Changed("estimate") {
+				data.EstimateMinutes = todoUpdateEstimate
+			}
 
 			parsed, err := editor.EditTodoWithData(data)
 			if err != nil {
@@ -407,6 +621,18 @@ func runTodoUpdate(cmd *cobra.Command, args []string) error {
 			}
 
 			opts := parsed.ToUpdateOptions()
+			if cmd.Flags().Changed("profile") {
+				opts.Profile = &todoUpdateProfile
+			}
+			if cmd.Flags().Changed("design-doc") {
+				opts.DesignDoc = &todoUpdateDesignDoc
+			}
+			if cmd.Flags().Changed("due") {
+				opts.DueAt, err = parseTodoDueUpdateFlag(todoUpdateDue)
+				if err != nil {
+					return err
+				}
+			}
 			updated, err := store.Update([]string{id}, opts)
 			if err != nil {
 				return err
@@ -450,6 +676,21 @@ func runTodoUpdate(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("project-review-model") {
 		opts.ProjectReviewModel = &todoUpdateProjectReviewModel
 	}
+	if cmd.Flags().Changed("profile") {
+		opts.Profile = &todoUpdateProfile
+	}
+	if cmd.Flags().Changed("design-doc") {
+		opts.DesignDoc = &todoUpdateDesignDoc
+	}
+	if cmd.Flags().Changed("due") {
+		opts.DueAt, err = parseTodoDueUpdateFlag(todoUpdateDue)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("estimate") {
+		opts.EstimateMinutes = &todoUpdateEstimate
+	}
 
 	updated, err := store.Update(args, opts)
 	if err != nil {
@@ -483,12 +724,60 @@ func runTodoReopen(cmd *cobra.Command, args []string) error {
 	})
 }
 
-func runTodoDelete(cmd *cobra.Command, args []string) error {
-	return runTodoAction(cmd, args, "Deleted", func(store *todo.Store) ([]todo.Todo, error) {
-		return store.Delete(args, todoDeleteReason)
+func runTodoArchive(cmd *cobra.Command, args []string) error {
+	return runTodoAction(cmd, args, "Archived", func(store *todo.Store) ([]todo.Todo, error) {
+		return store.Archive(args)
 	})
 }
 
+func runTodoUnarchive(cmd *cobra.Command, args []string) error {
+	return runTodoAction(cmd, args, "Unarchived", func(store *todo.Store) ([]todo.Todo, error) {
+		return store.Unarchive(args)
+	})
+}
+
+func runTodoDelete(cmd *cobra.Command, args []string) error {
+	store, err := openTodoStore(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	items, err := store.Delete(args, todoDeleteReason)
+	if err != nil {
+		return err
+	}
+	if err := printTodoActionResults(store, "Deleted", items); err != nil {
+		return err
+	}
+	return warnOpenDependents(store, items)
+}
+
+// warnOpenDependents tells the user which unresolved todos still list a
+// deleted todo as a blocker.
+func warnOpenDependents(store *todo.Store, deleted []todo.Todo) error {
+	highlight, err := todoLogHighlighterForStore(store)
+	if err != nil {
+		return err
+	}
+	for _, item := range deleted {
+		dependents, err := store.Dependents(item.ID)
+		if err != nil {
+			return err
+		}
+		open := make([]string, 0, len(dependents))
+		for _, dependent := range dependents {
+			if !dependent.Status.IsResolved() {
+				open = append(open, highlight(dependent.ID))
+			}
+		}
+		if len(open) > 0 {
+			fmt.Fprintf(os.Stderr, "warning: %s still has open dependents: %s\n", highlight(item.ID), strings.Join(open, ", "))
+		}
+	}
+	return nil
+}
+
 func runTodoShow(cmd *cobra.Command, args []string) error {
 	store, err := openTodoStoreReadOnly(cmd, args)
 	if err != nil {
@@ -554,7 +843,24 @@ func runTodoList(cmd *cobra.Command, args []string) error {
 	}
 	filter.TitleSubstring = todoListTitle
 	filter.DescriptionSubstring = todoListDesc
+	filter.Tags = todoListTags
+	filter.MatchAllTags = todoListAllTags
+	filter.DueBefore, err = parseTodoDueFlag(todoListDueBefore)
+	if err != nil {
+		return err
+	}
+	filter.Overdue = todoListOverdue
+	now := time.Now()
+	filter.UpdatedAfter, err = parseTodoTimeFlag("since", todoListSince, now, false)
+	if err != nil {
+		return err
+	}
+	filter.UpdatedBefore, err = parseTodoTimeFlag("until", todoListUntil, now, true)
+	if err != nil {
+		return err
+	}
 	filter.IncludeTombstones = filter.IncludeTombstones || todoListTombstones
+	filter.IncludeArchived = todoListArchived
 
 	var (
 		todos []todo.Todo
@@ -593,6 +899,7 @@ func runTodoList(cmd *cobra.Command, args []string) error {
 			allFilter := filter
 			allFilter.Status = nil
 			allFilter.IncludeTombstones = true
+			allFilter.IncludeArchived = true
 			allTodos, err := store.List(allFilter)
 			if err != nil {
 				return err
@@ -627,6 +934,55 @@ func runTodoList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTodoSearch(cmd *cobra.Command, args []string) error {
+	store, handled, err := openTodoStoreReadOnlyOrEmpty(cmd, args, todoSearchJSON, func() error {
+		fmt.Println("No matching todos found.")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+	defer store.Release()
+
+	filter := todo.ListFilter{
+		Tags:              todoSearchTags,
+		IncludeTombstones: todoSearchTombstones,
+		IncludeArchived:   todoSearchArchived,
+	}
+	if todoSearchStatus != "" {
+		status := todo.Status(todoSearchStatus)
+		filter.Status = &status
+	}
+
+	results, err := store.Search(strings.Join(args, " "), filter)
+	if err != nil {
+		return err
+	}
+
+	if todoSearchJSON {
+		return encodeJSONToStdout(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No matching todos found.")
+		return nil
+	}
+
+	index, err := store.IDIndex()
+	if err != nil {
+		return err
+	}
+	todos := make([]todo.Todo, len(results))
+	for i, result := range results {
+		todos[i] = result.Todo
+	}
+	printTodoTable(todos, index.PrefixLengths(), time.Now())
+	return nil
+}
+
 func runTodoReady(cmd *cobra.Command, args []string) error {
 	store, handled, err := openTodoStoreReadOnlyOrEmpty(cmd, args, todoReadyJSON, func() error {
 		fmt.Println("No ready todos found.")
@@ -640,15 +996,10 @@ func runTodoReady(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Release()
 
-	var (
-		todos []todo.Todo
-		index todo.IDIndex
-	)
-	if todoReadyJSON {
-		todos, err = store.Ready(todoReadyLimit)
-	} else {
-		todos, index, err = store.ReadyWithIndex(todoReadyLimit)
-	}
+	todos, index, err := store.ReadyWithOptions(todo.ReadyOptions{
+		Limit:           todoReadyLimit,
+		DueDateTiebreak: todoReadyByDue,
+	})
 	if err != nil {
 		return err
 	}
@@ -666,6 +1017,130 @@ func runTodoReady(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTodoReprioritize(cmd *cobra.Command, args []string) error {
+	store, err := openTodoStore(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	var todos []todo.Todo
+	if todoReprioritizeAll {
+		status := todo.StatusOpen
+		todos, err = store.List(todo.ListFilter{Status: &status})
+		if err == nil {
+			sort.SliceStable(todos, func(i, j int) bool {
+				return todos[i].Priority < todos[j].Priority
+			})
+		}
+	} else {
+		todos, err = store.Ready(0)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(todos) == 0 {
+		fmt.Println("No todos to reprioritize.")
+		return nil
+	}
+
+	order, err := editor.EditTodoOrder(todos)
+	if err != nil {
+		return err
+	}
+	order, err = resolveTodoOrder(todos, order)
+	if err != nil {
+		return err
+	}
+
+	updated, err := store.Reprioritize(order)
+	if err != nil {
+		return err
+	}
+	if discarded := todo.DiscardedReorders(todos, order); len(discarded) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: todos that share a priority cannot be reordered; the order of %s was not kept\n", strings.Join(discarded, ", "))
+	}
+	if len(updated) == 0 {
+		fmt.Println("Priorities unchanged.")
+		return nil
+	}
+	return printTodoActionResults(store, "Reprioritized", updated)
+}
+
+// resolveTodoOrder resolves an edited order's IDs, which may be prefixes,
+// against the offered todos, and ensures it lists exactly those todos.
+func resolveTodoOrder(todos []todo.Todo, order []string) ([]string, error) {
+	index := todo.NewIDIndex(todos)
+	resolved := make([]string, 0, len(order))
+	for _, id := range order {
+		full, err := index.Resolve(id)
+		if errors.Is(err, todo.ErrTodoNotFound) {
+			return nil, fmt.Errorf("reprioritize order lists unknown todo: %s", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, full)
+	}
+	if len(resolved) != len(todos) {
+		return nil, fmt.Errorf("reprioritize order must list each of the %d todos exactly once", len(todos))
+	}
+	return resolved, nil
+}
+
+func runTodoImport(cmd *cobra.Command, args []string) error {
+	var input io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	store, err := openTodoStore(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	imported, err := store.ImportMarkdown(input)
+	if err != nil {
+		return err
+	}
+	if len(imported) == 0 {
+		fmt.Println("No todos found to import.")
+		return nil
+	}
+	return printTodoActionResults(store, "Imported", imported)
+}
+
+func runTodoExport(cmd *cobra.Command, args []string) error {
+	format, err := todo.ParseExportFormat(todoExportFormat)
+	if err != nil {
+		return err
+	}
+
+	filter := todo.ListFilter{
+		Tags:              todoExportTags,
+		IncludeTombstones: todoExportTombstones,
+	}
+	if todoExportStatus != "" {
+		status := todo.Status(todoExportStatus)
+		filter.Status = &status
+	}
+
+	store, err := openTodoStoreReadOnly(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	return store.Export(os.Stdout, format, filter)
+}
+
 func runTodoDepAdd(cmd *cobra.Command, args []string) error {
 	store, err := openTodoStore(cmd, args)
 	if err != nil {
@@ -673,7 +1148,7 @@ func runTodoDepAdd(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Release()
 
-	dep, err := store.DepAdd(args[0], args[1])
+	dep, err := store.DepAddWithOptions(args[0], args[1], todo.DepAddOptions{Type: todo.DependencyType(todoDepAddType)})
 	if err != nil {
 		return err
 	}
@@ -682,7 +1157,73 @@ func runTodoDepAdd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Added dependency: %s depends on %s\n", highlight(dep.TodoID), highlight(dep.DependsOnID))
+	if dep.IsBlocking() {
+		fmt.Printf("Added dependency: %s depends on %s\n", highlight(dep.TodoID), highlight(dep.DependsOnID))
+		return nil
+	}
+	fmt.Printf("Added dependency: %s %s %s\n", highlight(dep.TodoID), dep.Type, highlight(dep.DependsOnID))
+	return nil
+}
+
+func runTodoNote(cmd *cobra.Command, args []string) error {
+	store, err := openTodoStore(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	if _, err := store.AddNote(args[0], todoNoteAuthor, strings.Join(args[1:], " ")); err != nil {
+		return err
+	}
+
+	highlight, err := todoLogHighlighterForStore(store)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added note to %s\n", highlight(args[0]))
+	return nil
+}
+
+func runTodoDepRemove(cmd *cobra.Command, args []string) error {
+	store, err := openTodoStore(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	if err := store.DepRemove(args[0], args[1]); err != nil {
+		return err
+	}
+
+	highlight, err := todoLogHighlighterForStore(store)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed dependency: %s no longer depends on %s\n", highlight(args[0]), highlight(args[1]))
+	return nil
+}
+
+func runTodoDepRdeps(cmd *cobra.Command, args []string) error {
+	store, err := openTodoStoreReadOnly(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	dependents, err := store.DependentsWithOptions(args[0], todo.DependentsOptions{IncludeTombstones: todoDepRdepsTombstones})
+	if err != nil {
+		return err
+	}
+	if len(dependents) == 0 {
+		fmt.Println("No dependents found.")
+		return nil
+	}
+
+	prefixLengths, err := todoIDPrefixLengthsForStore(store)
+	if err != nil {
+		return err
+	}
+	printTodoTable(dependents, prefixLengths, time.Now())
 	return nil
 }
 
@@ -693,7 +1234,7 @@ func runTodoDepTree(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Release()
 
-	tree, err := store.DepTree(args[0])
+	tree, err := store.DepTree(args[0], todo.DepTreeOptions{MaxDepth: todoDepTreeDepth})
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/amonks/incrementum/todo"
 )
@@ -22,6 +24,24 @@ func printTodoDetail(t todo.Todo, highlight func(string) string) {
 	if t.ProjectReviewModel != "" {
 		fmt.Printf("Project Review Model: %s\n", t.ProjectReviewModel)
 	}
+	if t.Profile != "" {
+		fmt.Printf("Profile:  %s\n", t.Profile)
+	}
+	if t.DesignDoc != "" {
+		fmt.Printf("Design Doc: %s\n", t.DesignDoc)
+	}
+	if t.DueAt != nil {
+		fmt.Printf("Due:      %s\n", t.DueAt.Format("2006-01-02 15:04:05"))
+	}
+	if t.EstimateMinutes > 0 {
+		fmt.Printf("Estimate: %s\n", formatTodoMinutes(t.EstimateMinutes))
+	}
+	if actual, ok := t.ActualMinutes(); ok {
+		fmt.Printf("Actual:   %s\n", formatTodoMinutes(actual))
+	}
+	if len(t.Tags) > 0 {
+		fmt.Printf("Tags:     %s\n", strings.Join(t.Tags, ", "))
+	}
 	fmt.Printf("Created:  %s\n", t.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated:  %s\n", t.UpdatedAt.Format("2006-01-02 15:04:05"))
 
@@ -40,6 +60,31 @@ func printTodoDetail(t todo.Todo, highlight func(string) string) {
 	if t.Description != "" {
 		fmt.Printf("\nDescription:\n%s\n", formatTodoDescription(t.Description))
 	}
+
+	if len(t.Notes) > 0 {
+		fmt.Printf("\nNotes:\n")
+		for _, note := range t.Notes {
+			fmt.Printf("%s\n", formatTodoNoteHeader(note))
+			fmt.Printf("%s\n", formatTodoDescription(note.Text))
+		}
+	}
+}
+
+// formatTodoMinutes renders an effort in minutes as a duration like "1h30m".
+func formatTodoMinutes(minutes int) string {
+	if minutes == 0 {
+		return "0m"
+	}
+	formatted := (time.Duration(minutes) * time.Minute).String()
+	return strings.TrimSuffix(formatted, "0s")
+}
+
+func formatTodoNoteHeader(note todo.TodoNote) string {
+	header := note.CreatedAt.Format("2006-01-02 15:04:05")
+	if note.Author != "" {
+		header += " " + note.Author
+	}
+	return header
 }
 
 const todoDetailLineWidth = 80
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/amonks/incrementum/internal/age"
+	"github.com/amonks/incrementum/internal/editor"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 	"github.com/amonks/incrementum/todo"
 	"github.com/spf13/cobra"
@@ -90,6 +93,76 @@ func resolveDescriptionFlag(cmd *cobra.Command, description *string, reader io.R
 	return nil
 }
 
+// applyTodoTemplateToEditorData pre-fills the create buffer with the fields a
+// template sets.
+func applyTodoTemplateToEditorData(data *editor.TodoData, tmpl *todo.Template) {
+	if tmpl.Type != "" {
+		data.Type = string(tmpl.Type)
+	}
+	if tmpl.Priority != nil {
+		data.Priority = *tmpl.Priority
+	}
+	data.Description = tmpl.Description
+}
+
 func hasTodoCreateFlags(cmd *cobra.Command) bool {
-	return hasChangedFlags(cmd, "title", "type", "priority", "description", "implementation-model", "code-review-model", "project-review-model", "deps")
+	return hasChangedFlags(cmd, "title", "type", "priority", "description", "implementation-model", "code-review-model", "project-review-model", "design-doc", "deps", "tag", "due", "estimate")
+}
+
+// parseTodoDueFlag parses a --due style flag value. Blank values yield nil.
+// A bare date (YYYY-MM-DD) means the end of that day in local time.
+func parseTodoDueFlag(value string) (*time.Time, error) {
+	value = internalstrings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		endOfDay := date.AddDate(0, 0, 1).Add(-time.Second)
+		return &endOfDay, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid due date %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return &parsed, nil
+}
+
+// parseTodoDueUpdateFlag parses a --due update value, where a blank value
+// clears the due date.
+func parseTodoDueUpdateFlag(value string) (*time.Time, error) {
+	dueAt, err := parseTodoDueFlag(value)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt == nil {
+		return &time.Time{}, nil
+	}
+	return dueAt, nil
+}
+
+// parseTodoTimeFlag parses a --since/--until style flag value relative to
+// now. Blank values yield nil. It accepts a bare date (YYYY-MM-DD, local
+// time), an RFC 3339 timestamp, or a duration ago such as 7d, 2w, or 36h.
+// A bare date means the start of that day, or the start of the next day when
+// endOfDay is set, so an exclusive upper bound covers the whole day.
+func parseTodoTimeFlag(name, value string, now time.Time, endOfDay bool) (*time.Time, error) {
+	value = internalstrings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		if endOfDay {
+			date = date.AddDate(0, 0, 1)
+		}
+		return &date, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	ago, err := age.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q: expected YYYY-MM-DD, RFC 3339, or a duration like 7d", name, value)
+	}
+	at := now.Add(-ago)
+	return &at, nil
 }
@@ -20,7 +20,7 @@ func printTodoTable(todos []todo.Todo, prefixLengths map[string]int, now time.Ti
 }
 
 func formatTodoTable(todos []todo.Todo, prefixLengths map[string]int, highlight func(string, int) string, now time.Time) string {
-	builder := ui.NewTableBuilder([]string{"ID", "PRI", "TYPE", "STATUS", "AGE", "UPDATED", "DURATION", "TITLE"}, len(todos))
+	builder := ui.NewTableBuilder([]string{"ID", "PRI", "TYPE", "STATUS", "DUE", "AGE", "UPDATED", "DURATION", "TITLE"}, len(todos))
 
 	if prefixLengths == nil {
 		prefixLengths = todoIDPrefixLengths(todos)
@@ -38,6 +38,7 @@ func formatTodoTable(todos []todo.Todo, prefixLengths map[string]int, highlight
 			priorityShort(t.Priority),
 			string(t.Type),
 			string(t.Status),
+			formatTodoDue(t, now),
 			age,
 			updated,
 			duration,
@@ -58,6 +59,18 @@ func formatTodoAge(item todo.Todo, now time.Time) string {
 	return formatOptionalDuration(todo.AgeData(item, now))
 }
 
+// formatTodoDue renders a todo's due date, highlighting it when overdue.
+func formatTodoDue(item todo.Todo, now time.Time) string {
+	if item.DueAt == nil {
+		return "-"
+	}
+	due := item.DueAt.Local().Format(time.DateOnly)
+	if item.IsOverdue(now) {
+		return ui.HighlightWarning(due)
+	}
+	return due
+}
+
 func formatTodoDuration(item todo.Todo, now time.Time) string {
 	return formatOptionalDuration(todo.DurationData(item, now))
 }
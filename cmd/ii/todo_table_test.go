@@ -160,3 +160,29 @@ func TestFormatTodoTableShowsDuration(t *testing.T) {
 		t.Fatalf("expected done duration in output, got:\n%s", output)
 	}
 }
+
+func TestFormatTodoTableShowsDueDate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	due := time.Date(2025, 1, 3, 23, 59, 59, 0, time.Local)
+	todos := []todo.Todo{
+		{
+			ID:        "abc123",
+			Priority:  2,
+			Type:      todo.TodoType("task"),
+			Status:    todo.StatusOpen,
+			Title:     "Deadline",
+			CreatedAt: now,
+			UpdatedAt: now,
+			DueAt:     &due,
+		},
+	}
+
+	output := formatTodoTable(todos, nil, func(id string, prefix int) string { return id }, now)
+
+	if !strings.Contains(output, "DUE") {
+		t.Fatalf("expected due column present, got:\n%s", output)
+	}
+	if !strings.Contains(output, "2025-01-03") {
+		t.Fatalf("expected due date in output, got:\n%s", output)
+	}
+}
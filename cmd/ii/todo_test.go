@@ -233,6 +233,72 @@ func TestTodoListPriorityFilter(t *testing.T) {
 	}
 }
 
+func TestParseTodoTimeFlag(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	at, err := parseTodoTimeFlag("since", "", now, false)
+	if err != nil || at != nil {
+		t.Fatalf("expected blank value to be nil, got %v, %v", at, err)
+	}
+
+	for value, want := range map[string]time.Time{
+		"7d":                   now.Add(-7 * 24 * time.Hour),
+		"2w":                   now.Add(-14 * 24 * time.Hour),
+		"36h":                  now.Add(-36 * time.Hour),
+		"2026-03-01T09:30:00Z": time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local),
+	} {
+		at, err := parseTodoTimeFlag("since", value, now, false)
+		if err != nil || at == nil || !at.Equal(want) {
+			t.Fatalf("parseTodoTimeFlag(%q) = %v, %v; want %v", value, at, err, want)
+		}
+	}
+
+	at, err = parseTodoTimeFlag("until", "2026-03-01", now, true)
+	if err != nil || !at.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("expected --until date to cover the whole day, got %v, %v", at, err)
+	}
+
+	for _, value := range []string{"last week", "-3d", "d"} {
+		if _, err := parseTodoTimeFlag("since", value, now, false); err == nil || !strings.Contains(err.Error(), "--since") {
+			t.Fatalf("expected invalid --since error for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestParseTodoDueFlag(t *testing.T) {
+	dueAt, err := parseTodoDueFlag("")
+	if err != nil || dueAt != nil {
+		t.Fatalf("expected blank due date to be nil, got %v, %v", dueAt, err)
+	}
+
+	dueAt, err = parseTodoDueFlag("2026-03-01")
+	if err != nil {
+		t.Fatalf("parse date: %v", err)
+	}
+	want := time.Date(2026, 3, 1, 23, 59, 59, 0, time.Local)
+	if !dueAt.Equal(want) {
+		t.Fatalf("expected end of day %v, got %v", want, dueAt)
+	}
+
+	dueAt, err = parseTodoDueFlag("2026-03-01T09:30:00Z")
+	if err != nil {
+		t.Fatalf("parse timestamp: %v", err)
+	}
+	if !dueAt.Equal(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected timestamp %v", dueAt)
+	}
+
+	if _, err := parseTodoDueFlag("next week"); err == nil {
+		t.Fatalf("expected invalid due date error")
+	}
+
+	cleared, err := parseTodoDueUpdateFlag("")
+	if err != nil || cleared == nil || !cleared.IsZero() {
+		t.Fatalf("expected blank update to clear the due date, got %v, %v", cleared, err)
+	}
+}
+
 func TestPrintTodoDetailIncludesDeleteMetadata(t *testing.T) {
 	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
 	item := todo.Todo{
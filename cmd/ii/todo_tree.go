@@ -19,8 +19,19 @@ func printDepTree(node *todo.DepTreeNode, prefix string, isLast bool, highlight
 
 	statusIcon := statusIcon(node.Todo.Status)
 
-	fmt.Printf("%s%s%s %s (%s)\n",
-		prefix, connector, statusIcon, node.Todo.Title, highlight(node.Todo.ID))
+	marker := ""
+	if node.Type != "" && !node.Type.IsBlocking() {
+		marker = " [" + string(node.Type) + "]"
+	}
+	switch {
+	case node.SeeAbove:
+		marker += " (see above)"
+	case node.Truncated:
+		marker += " (...)"
+	}
+
+	fmt.Printf("%s%s%s %s (%s)%s\n",
+		prefix, connector, statusIcon, node.Todo.Title, highlight(node.Todo.ID), marker)
 
 	// Print children
 	childPrefix := prefix
@@ -51,6 +62,8 @@ func statusIcon(s todo.Status) string {
 		return "[d]"
 	case todo.StatusTombstone:
 		return "[-]"
+	case todo.StatusArchived:
+		return "[a]"
 	default:
 		return "[?]"
 	}
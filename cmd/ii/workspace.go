@@ -28,33 +28,78 @@ var workspaceReleaseCmd = &cobra.Command{
 	RunE:  runWorkspaceRelease,
 }
 
+var workspaceRenewCmd = &cobra.Command{
+	Use:   "renew [name]",
+	Short: "Refresh the lease on an acquired workspace",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runWorkspaceRenew,
+}
+
 var workspaceListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all workspaces for the current repo",
 	RunE:  runWorkspaceList,
 }
 
+var workspaceReapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Release workspaces whose holder process on this host has exited",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkspaceReap,
+}
+
 var workspaceDestroyAllCmd = &cobra.Command{
 	Use:   "destroy-all",
 	Short: "Destroy all workspaces for the current repository",
 	RunE:  runWorkspaceDestroyAll,
 }
 
+var workspacePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete available workspaces released before a cutoff",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkspacePrune,
+}
+
 var (
-	workspaceAcquireRev     string
-	workspaceAcquirePurpose string
-	workspaceListJSON       bool
-	workspaceListAll        bool
+	workspaceAcquireRev         string
+	workspaceAcquirePurpose     string
+	workspaceAcquireName        string
+	workspaceAcquirePool        string
+	workspaceAcquireMaxPoolSize int
+	workspaceAcquireWaitTimeout time.Duration
+	workspaceReleaseAll         bool
+	workspaceRenewAll           bool
+	workspaceListJSON           bool
+	workspaceListAll            bool
+	workspaceListSize           bool
+	workspaceListIncludeVCS     bool
+	workspaceListHealth         bool
+	workspacePruneOlderThan     time.Duration
+	workspacePruneDryRun        bool
+	workspaceReapDryRun         bool
 )
 
 func init() {
 	rootCmd.AddCommand(workspaceCmd)
-	workspaceCmd.AddCommand(workspaceAcquireCmd, workspaceReleaseCmd, workspaceListCmd, workspaceDestroyAllCmd)
+	workspaceCmd.AddCommand(workspaceAcquireCmd, workspaceReleaseCmd, workspaceRenewCmd, workspaceListCmd, workspacePruneCmd, workspaceReapCmd, workspaceDestroyAllCmd)
 
 	workspaceAcquireCmd.Flags().StringVar(&workspaceAcquireRev, "rev", "@", "Revision to base the new change on")
 	workspaceAcquireCmd.Flags().StringVar(&workspaceAcquirePurpose, "purpose", "", "Purpose for acquiring the workspace")
+	workspaceAcquireCmd.Flags().StringVar(&workspaceAcquirePool, "pool", "", "Isolation pool; only workspaces from this pool are reused")
+	workspaceAcquireCmd.Flags().StringVar(&workspaceAcquireName, "name", "", "Acquire (or create) the workspace with this name")
+	workspaceAcquireCmd.Flags().IntVar(&workspaceAcquireMaxPoolSize, "max-pool-size", 0, "Wait for a free workspace once the repo has this many (0 for unlimited)")
+	workspaceAcquireCmd.Flags().DurationVar(&workspaceAcquireWaitTimeout, "wait-timeout", 0, "Give up waiting for a free workspace after this long (0 waits indefinitely)")
+	workspaceReleaseCmd.Flags().BoolVar(&workspaceReleaseAll, "all", false, "Release every acquired workspace for the repo")
+	workspaceRenewCmd.Flags().BoolVar(&workspaceRenewAll, "all", false, "Renew every acquired workspace for the repo")
 	workspaceListCmd.Flags().BoolVar(&workspaceListJSON, "json", false, "Output as JSON")
 	listflags.AddAllFlag(workspaceListCmd, &workspaceListAll)
+	workspaceListCmd.Flags().BoolVar(&workspaceListSize, "size", false, "Measure disk usage of each workspace")
+	workspaceListCmd.Flags().BoolVar(&workspaceListIncludeVCS, "include-vcs", false, "Count the .jj directory toward --size")
+	workspaceListCmd.Flags().BoolVar(&workspaceListHealth, "health", false, "Check that jj can operate in each workspace")
+	workspacePruneCmd.Flags().DurationVar(&workspacePruneOlderThan, "older-than", 7*24*time.Hour, "Only prune workspaces released at least this long ago")
+	workspacePruneCmd.Flags().BoolVar(&workspacePruneDryRun, "dry-run", false, "Show what would be pruned without deleting anything")
+	workspaceReapCmd.Flags().BoolVar(&workspaceReapDryRun, "dry-run", false, "Show orphaned workspaces without releasing them")
 }
 
 func openWorkspacePoolAndRepoPath() (*workspace.Pool, string, error) {
@@ -82,8 +127,12 @@ func runWorkspaceAcquire(cmd *cobra.Command, args []string) error {
 	}
 
 	wsPath, err := pool.Acquire(repoPath, workspace.AcquireOptions{
-		Rev:     workspaceAcquireRev,
-		Purpose: workspaceAcquirePurpose,
+		Rev:         workspaceAcquireRev,
+		Purpose:     workspaceAcquirePurpose,
+		Name:        workspaceAcquireName,
+		Pool:        workspaceAcquirePool,
+		MaxPoolSize: workspaceAcquireMaxPoolSize,
+		WaitTimeout: workspaceAcquireWaitTimeout,
 	})
 	if err != nil {
 		return fmt.Errorf("acquire workspace: %w", err)
@@ -94,11 +143,21 @@ func runWorkspaceAcquire(cmd *cobra.Command, args []string) error {
 }
 
 func runWorkspaceRelease(cmd *cobra.Command, args []string) error {
+	if workspaceReleaseAll && len(args) > 0 {
+		return fmt.Errorf("cannot combine a workspace name with --all")
+	}
+
 	pool, repoPath, err := openWorkspacePoolAndRepoPath()
 	if err != nil {
 		return err
 	}
 
+	if workspaceReleaseAll {
+		released, err := pool.ReleaseAll(repoPath)
+		printWorkspaceNames("Released", released, err)
+		return err
+	}
+
 	wsName, err := resolveWorkspaceName(args, pool)
 	if err != nil {
 		return err
@@ -107,13 +166,51 @@ func runWorkspaceRelease(cmd *cobra.Command, args []string) error {
 	return pool.ReleaseByName(repoPath, wsName)
 }
 
+func runWorkspaceRenew(cmd *cobra.Command, args []string) error {
+	if workspaceRenewAll && len(args) > 0 {
+		return fmt.Errorf("cannot combine a workspace name with --all")
+	}
+
+	pool, repoPath, err := openWorkspacePoolAndRepoPath()
+	if err != nil {
+		return err
+	}
+
+	if workspaceRenewAll {
+		renewed, err := pool.RenewAll(repoPath)
+		printWorkspaceNames("Renewed", renewed, err)
+		return err
+	}
+
+	wsName, err := resolveWorkspaceName(args, pool)
+	if err != nil {
+		return err
+	}
+
+	return pool.RenewByName(repoPath, wsName)
+}
+
+func printWorkspaceNames(verb string, names []string, err error) {
+	if len(names) == 0 && err == nil {
+		fmt.Println("No acquired workspaces.")
+		return
+	}
+	for _, name := range names {
+		fmt.Printf("%s %s\n", verb, name)
+	}
+}
+
 func runWorkspaceList(cmd *cobra.Command, args []string) error {
 	pool, repoPath, err := openWorkspacePoolAndRepoPath()
 	if err != nil {
 		return err
 	}
 
-	items, err := pool.List(repoPath)
+	items, err := pool.ListWithOptions(repoPath, workspace.ListOptions{
+		IncludeSizes: workspaceListSize,
+		IncludeVCS:   workspaceListIncludeVCS,
+		CheckHealth:  workspaceListHealth,
+	})
 	if err != nil {
 		return fmt.Errorf("list workspaces: %w", err)
 	}
@@ -129,7 +226,7 @@ func runWorkspaceList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Print(formatWorkspaceTable(items, nil, time.Now()))
+	fmt.Print(formatWorkspaceTable(items, nil, time.Now(), workspaceListSize))
 	return nil
 }
 
@@ -148,6 +245,72 @@ func filterWorkspaceList(items []workspace.Info, includeAll bool) []workspace.In
 	return filtered
 }
 
+func runWorkspacePrune(cmd *cobra.Command, args []string) error {
+	if workspacePruneOlderThan < 0 {
+		return fmt.Errorf("--older-than must not be negative")
+	}
+
+	pool, repoPath, err := openWorkspacePoolAndRepoPath()
+	if err != nil {
+		return err
+	}
+
+	pruned, err := pool.Prune(repoPath, workspace.PruneOptions{
+		OlderThan: workspacePruneOlderThan,
+		DryRun:    workspacePruneDryRun,
+	})
+	if len(pruned) == 0 && err == nil {
+		fmt.Println("No workspaces to prune.")
+		return nil
+	}
+
+	verb := "Pruned"
+	if workspacePruneDryRun {
+		verb = "Would prune"
+	}
+	var total int64
+	for _, item := range pruned {
+		total += item.SizeBytes
+		fmt.Printf("%s %s (%s)\n", verb, item.Name, ui.FormatBytes(item.SizeBytes))
+	}
+	if len(pruned) > 0 {
+		fmt.Printf("%s %d workspace(s), %s total\n", verb, len(pruned), ui.FormatBytes(total))
+	}
+	return err
+}
+
+func runWorkspaceReap(cmd *cobra.Command, args []string) error {
+	pool, repoPath, err := openWorkspacePoolAndRepoPath()
+	if err != nil {
+		return err
+	}
+
+	if workspaceReapDryRun {
+		orphans, err := pool.Orphans(repoPath)
+		if err != nil {
+			return err
+		}
+		if len(orphans) == 0 {
+			fmt.Println("No orphaned workspaces.")
+			return nil
+		}
+		for _, item := range orphans {
+			fmt.Printf("Would reap %s (pid %d)\n", item.Name, item.AcquiredByPID)
+		}
+		return nil
+	}
+
+	reaped, err := pool.ReapOrphans(repoPath)
+	if len(reaped) == 0 && err == nil {
+		fmt.Println("No orphaned workspaces.")
+		return nil
+	}
+	for _, name := range reaped {
+		fmt.Printf("Reaped %s\n", name)
+	}
+	return err
+}
+
 func runWorkspaceDestroyAll(cmd *cobra.Command, args []string) error {
 	pool, repoPath, err := openWorkspacePoolAndRepoPath()
 	if err != nil {
@@ -157,11 +320,19 @@ func runWorkspaceDestroyAll(cmd *cobra.Command, args []string) error {
 	return pool.DestroyAll(repoPath)
 }
 
-func formatWorkspaceTable(items []workspace.Info, highlight func(string) string, now time.Time) string {
+func formatWorkspaceTable(items []workspace.Info, highlight func(string) string, now time.Time, showSizes bool) string {
 	if highlight == nil {
 		highlight = func(value string) string { return value }
 	}
 
+	showHealth := false
+	for _, item := range items {
+		if item.Health != "" {
+			showHealth = true
+			break
+		}
+	}
+
 	rows := make([][]string, 0, len(items))
 	for _, item := range items {
 		purpose := item.Purpose
@@ -174,20 +345,44 @@ func formatWorkspaceTable(items []workspace.Info, highlight func(string) string,
 			rev = "-"
 		}
 
+		pool := item.Pool
+		if pool == "" {
+			pool = "-"
+		}
+
 		age := formatWorkspaceAge(item, now)
 		duration := formatWorkspaceDuration(item, now)
-		rows = append(rows, []string{
+		row := []string{
 			highlight(item.Name),
 			string(item.Status),
+			ui.TruncateTableCell(pool),
 			age,
 			duration,
 			rev,
-			ui.TruncateTableCell(purpose),
-			ui.TruncateTableCell(item.Path),
-		})
+		}
+		if showSizes {
+			row = append(row, ui.FormatBytes(item.SizeBytes))
+		}
+		if showHealth {
+			health := string(item.Health)
+			if health == "" {
+				health = "-"
+			}
+			row = append(row, health)
+		}
+		row = append(row, ui.TruncateTableCell(purpose), ui.TruncateTableCell(item.Path))
+		rows = append(rows, row)
 	}
 
-	return ui.FormatTable([]string{"NAME", "STATUS", "AGE", "DURATION", "REV", "PURPOSE", "PATH"}, rows)
+	headers := []string{"NAME", "STATUS", "POOL", "AGE", "DURATION", "REV"}
+	if showSizes {
+		headers = append(headers, "SIZE")
+	}
+	if showHealth {
+		headers = append(headers, "HEALTH")
+	}
+	headers = append(headers, "PURPOSE", "PATH")
+	return ui.FormatTable(headers, rows)
 }
 
 func formatWorkspaceAge(item workspace.Info, now time.Time) string {
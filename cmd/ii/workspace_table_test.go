@@ -27,10 +27,10 @@ func TestFormatWorkspaceTablePreservesAlignmentWithANSI(t *testing.T) {
 
 	now := time.Date(2026, 1, 23, 1, 0, 0, 0, time.UTC)
 
-	plain := formatWorkspaceTable(items, func(value string) string { return value }, now)
+	plain := formatWorkspaceTable(items, func(value string) string { return value }, now, false)
 	ansi := formatWorkspaceTable(items, func(value string) string {
 		return "\x1b[1m\x1b[36m" + value + "\x1b[0m"
-	}, now)
+	}, now, false)
 
 	if stripANSICodes(ansi) != plain {
 		t.Fatalf("expected ANSI output to align with plain output\nplain:\n%s\nansi:\n%s", plain, ansi)
@@ -50,7 +50,7 @@ func TestFormatWorkspaceTableTruncatesLongPaths(t *testing.T) {
 
 	now := time.Date(2026, 1, 23, 1, 0, 0, 0, time.UTC)
 
-	output := formatWorkspaceTable(items, nil, now)
+	output := formatWorkspaceTable(items, nil, now, false)
 	expected := longPath[:47] + "..."
 	if !strings.Contains(output, expected) {
 		t.Fatalf("expected truncated path %q in output: %s", expected, output)
@@ -73,7 +73,7 @@ func TestFormatWorkspaceTableShowsAcquiredAge(t *testing.T) {
 		},
 	}
 
-	output := formatWorkspaceTable(items, nil, now)
+	output := formatWorkspaceTable(items, nil, now, false)
 	expected := ui.FormatDurationShort(now.Sub(createdAt))
 	if !strings.Contains(output, expected) {
 		t.Fatalf("expected acquired age %q in output: %s", expected, output)
@@ -102,7 +102,7 @@ func TestFormatWorkspaceTableShowsDurationForActiveAndAvailable(t *testing.T) {
 		},
 	}
 
-	output := formatWorkspaceTable(items, nil, now)
+	output := formatWorkspaceTable(items, nil, now, false)
 	activeDuration := ui.FormatDurationShort(now.Sub(createdAt))
 	availableDuration := ui.FormatDurationShort(updatedAt.Sub(createdAt))
 	if !strings.Contains(output, activeDuration) {
@@ -125,7 +125,7 @@ func TestFormatWorkspaceTableShowsRevision(t *testing.T) {
 		},
 	}
 
-	output := formatWorkspaceTable(items, nil, now)
+	output := formatWorkspaceTable(items, nil, now, false)
 	if !strings.Contains(output, "main~2") {
 		t.Fatalf("expected revision %q in output: %s", "main~2", output)
 	}
@@ -160,3 +160,65 @@ func TestFilterWorkspaceListWithAll(t *testing.T) {
 		t.Fatalf("expected 2 workspaces, got %d", len(filtered))
 	}
 }
+
+func TestFormatWorkspaceTableShowsSizes(t *testing.T) {
+	items := []workspace.Info{
+		{
+			Name:      "ws-001",
+			Path:      "/tmp/ws-001",
+			Purpose:   "feature work",
+			Status:    workspace.StatusAvailable,
+			SizeBytes: 3 * 1024 * 1024,
+		},
+	}
+
+	now := time.Date(2026, 1, 23, 1, 0, 0, 0, time.UTC)
+
+	output := formatWorkspaceTable(items, nil, now, true)
+	if !strings.Contains(output, "SIZE") || !strings.Contains(output, "3.0MB") {
+		t.Fatalf("expected size column in output: %s", output)
+	}
+
+	output = formatWorkspaceTable(items, nil, now, false)
+	if strings.Contains(output, "SIZE") {
+		t.Fatalf("expected no size column without sizes: %s", output)
+	}
+}
+
+func TestFormatWorkspaceTableShowsPool(t *testing.T) {
+	now := time.Date(2026, 1, 23, 3, 0, 0, 0, time.UTC)
+	items := []workspace.Info{
+		{
+			Name:    "ws-007",
+			Path:    "/tmp/ws-007",
+			Purpose: "job do abc",
+			Pool:    "swarm",
+			Status:  workspace.StatusAcquired,
+		},
+	}
+
+	output := formatWorkspaceTable(items, nil, now, false)
+	if !strings.Contains(output, "POOL") || !strings.Contains(output, "swarm") {
+		t.Fatalf("expected pool column in output: %s", output)
+	}
+}
+
+func TestFormatWorkspaceTableShowsHealth(t *testing.T) {
+	now := time.Date(2026, 1, 23, 3, 0, 0, 0, time.UTC)
+	items := []workspace.Info{
+		{Name: "ws-001", Path: "/tmp/ws-001", Status: workspace.StatusAvailable, Health: workspace.HealthOK},
+		{Name: "ws-002", Path: "/tmp/ws-002", Status: workspace.StatusAvailable, Health: workspace.HealthUnhealthy},
+	}
+
+	output := formatWorkspaceTable(items, nil, now, false)
+	if !strings.Contains(output, "HEALTH") || !strings.Contains(output, "unhealthy") {
+		t.Fatalf("expected health column in output: %s", output)
+	}
+
+	items[0].Health = ""
+	items[1].Health = ""
+	output = formatWorkspaceTable(items, nil, now, false)
+	if strings.Contains(output, "HEALTH") {
+		t.Fatalf("expected no health column without health checks: %s", output)
+	}
+}
@@ -0,0 +1,94 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amonks/incrementum/internal/age"
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/todo"
+)
+
+// ArtifactSourcePrefix prefixes the source of every habit artifact todo; the
+// habit name follows it.
+const ArtifactSourcePrefix = "habit:"
+
+// ParseSchedule parses a habit schedule into the interval between runs. It
+// accepts hourly, daily, and weekly, or a positive duration such as 12h, 3d,
+// or 2w.
+func ParseSchedule(value string) (time.Duration, error) {
+	value = internalstrings.NormalizeLowerTrimSpace(value)
+	switch value {
+	case "hourly":
+		return time.Hour, nil
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	}
+
+	interval, err := age.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid schedule %q (valid: hourly, daily, weekly, or a duration like 3d)", value)
+	}
+	return interval, nil
+}
+
+// Due returns the names of scheduled habits that should run at now, sorted
+// alphabetically. A habit is due when it has never produced an artifact or
+// when its schedule's interval has elapsed since its latest artifact was
+// completed. Habits without a schedule are never due.
+func Due(repoPath string, now time.Time) ([]string, error) {
+	habits, err := LoadAll(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []todo.Todo
+	store, err := todo.Open(repoPath, todo.OpenOptions{ReadOnly: true, Purpose: "habit due check"})
+	switch {
+	case errors.Is(err, todo.ErrNoTodoStore):
+		// Without a todo store no habit has run yet.
+	case err != nil:
+		return nil, err
+	default:
+		defer store.Release()
+		artifacts, err = store.List(todo.ListFilter{IncludeArchived: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dueHabits(habits, artifacts, now), nil
+}
+
+// dueHabits reports which habits are due given the todos that may include
+// their artifacts.
+func dueHabits(habits []*Habit, artifacts []todo.Todo, now time.Time) []string {
+	lastRun := make(map[string]time.Time)
+	for _, item := range artifacts {
+		name, ok := strings.CutPrefix(item.Source, ArtifactSourcePrefix)
+		if !ok || item.CompletedAt == nil {
+			continue
+		}
+		if item.CompletedAt.After(lastRun[name]) {
+			lastRun[name] = *item.CompletedAt
+		}
+	}
+
+	due := make([]string, 0)
+	for _, h := range habits {
+		if h.Interval <= 0 {
+			continue
+		}
+		last, ok := lastRun[h.Name]
+		if !ok || !now.Before(last.Add(h.Interval)) {
+			due = append(due, h.Name)
+		}
+	}
+	sort.Strings(due)
+	return due
+}
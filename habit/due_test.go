@@ -0,0 +1,109 @@
+package habit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestLoadParsesSchedule(t *testing.T) {
+	dir := t.TempDir()
+	habitsDir := filepath.Join(dir, HabitsDir)
+	if err := os.MkdirAll(habitsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `---
+schedule: 3d
+models:
+  implementation: opus
+---
+
+# Docs`
+	if err := os.WriteFile(filepath.Join(habitsDir, "docs.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	habit, err := Load(dir, "docs")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if habit.Schedule != "3d" || habit.Interval != 72*time.Hour {
+		t.Errorf("Schedule = %q (%v), want %q (%v)", habit.Schedule, habit.Interval, "3d", 72*time.Hour)
+	}
+	if habit.ImplementationModel != "opus" {
+		t.Errorf("ImplementationModel = %q, want %q", habit.ImplementationModel, "opus")
+	}
+
+	invalid := "---\nschedule: 0 9 * * *\n---\n\n# Cron"
+	if err := os.WriteFile(filepath.Join(habitsDir, "cron.md"), []byte(invalid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cron, err := Load(dir, "cron")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cron.ScheduleErr == nil || cron.Interval != 0 {
+		t.Errorf("expected a schedule error and no interval, got %v (%v)", cron.ScheduleErr, cron.Interval)
+	}
+
+	habits, err := LoadAll(dir)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(habits) != 2 {
+		t.Errorf("expected both habits to load, got %d", len(habits))
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"hourly": time.Hour,
+		"Daily":  24 * time.Hour,
+		"weekly": 7 * 24 * time.Hour,
+		"12h":    12 * time.Hour,
+		"3d":     72 * time.Hour,
+		"2w":     14 * 24 * time.Hour,
+	} {
+		got, err := ParseSchedule(value)
+		if err != nil || got != want {
+			t.Errorf("ParseSchedule(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "0d", "-1h", "often", "w"} {
+		if _, err := ParseSchedule(value); err == nil {
+			t.Errorf("ParseSchedule(%q): expected error", value)
+		}
+	}
+}
+
+func TestDueHabits(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	daily := 24 * time.Hour
+	habits := []*Habit{
+		{Name: "cleanup", Interval: daily},
+		{Name: "docs", Interval: daily},
+		{Name: "never-run", Interval: daily},
+		{Name: "on-demand"},
+	}
+
+	recent := now.Add(-time.Hour)
+	stale := now.Add(-25 * time.Hour)
+	older := now.Add(-48 * time.Hour)
+	artifacts := []todo.Todo{
+		{ID: "a", Source: "habit:cleanup", CompletedAt: &older},
+		{ID: "b", Source: "habit:cleanup", CompletedAt: &recent},
+		{ID: "c", Source: "habit:docs", CompletedAt: &stale},
+		{ID: "d", Source: "habit:on-demand", CompletedAt: &older},
+		{ID: "e", Source: "habit:never-run"},
+	}
+
+	got := dueHabits(habits, artifacts, now)
+	want := []string{"docs", "never-run"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dueHabits = %v, want %v", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/amonks/incrementum/internal/ids"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
@@ -32,6 +33,17 @@ type Habit struct {
 
 	// ReviewModel is the model to use for review, if specified in frontmatter.
 	ReviewModel string
+
+	// Schedule is the cadence from frontmatter, if specified. Habits without
+	// a schedule only run on demand.
+	Schedule string
+
+	// Interval is the parsed Schedule, or zero when there is none.
+	Interval time.Duration
+
+	// ScheduleErr reports a Schedule that does not parse. The habit still
+	// loads, with a zero Interval, so it only runs on demand.
+	ScheduleErr error
 }
 
 // Load loads a habit by name from the given repo path.
@@ -254,6 +266,14 @@ func parseHabit(name string, data []byte) (*Habit, error) {
 	implModel, reviewModel := parseFrontmatter(fmData)
 	habit.ImplementationModel = implModel
 	habit.ReviewModel = reviewModel
+	habit.Schedule = parseFrontmatterSchedule(fmData)
+	if habit.Schedule != "" {
+		interval, err := ParseSchedule(habit.Schedule)
+		if err != nil {
+			habit.ScheduleErr = fmt.Errorf("habit %s: %w", name, err)
+		}
+		habit.Interval = interval
+	}
 
 	// Extract body after frontmatter
 	bodyStart := endIdx + 4 // Skip "\n---"
@@ -307,3 +327,18 @@ func parseFrontmatter(data string) (implementationModel, reviewModel string) {
 
 	return implementationModel, reviewModel
 }
+
+// parseFrontmatterSchedule extracts the top-level schedule key from simple
+// YAML frontmatter.
+func parseFrontmatterSchedule(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		trimmed := internalstrings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "schedule:") {
+			return internalstrings.TrimSpace(strings.TrimPrefix(trimmed, "schedule:"))
+		}
+	}
+	return ""
+}
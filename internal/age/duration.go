@@ -0,0 +1,33 @@
+package age
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a non-negative duration, adding d (days) and w
+// (weeks) to the units time.ParseDuration accepts. Day and week values must
+// be whole numbers, such as 3d or 2w.
+func ParseDuration(value string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit == 0 {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return duration, nil
+	}
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return time.Duration(count) * unit, nil
+}
@@ -0,0 +1,25 @@
+package age
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"0d":  0,
+		"36h": 36 * time.Hour,
+		"3d":  72 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	} {
+		got, err := ParseDuration(value)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "-1h", "-2d", "1.5d", "often", "w"} {
+		if _, err := ParseDuration(value); err == nil {
+			t.Errorf("ParseDuration(%q): expected error", value)
+		}
+	}
+}
@@ -2,14 +2,21 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
+	statestore "github.com/amonks/incrementum/internal/state"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
 
@@ -17,6 +24,20 @@ import (
 type Config struct {
 	Workspace Workspace `toml:"workspace"`
 	Job       Job       `toml:"job"`
+	// Profiles maps profile names to bundles of job models, selected per
+	// todo or per run.
+	Profiles map[string]Profile `toml:"profiles"`
+}
+
+// Profile bundles the opencode models a job uses for each stage. Empty
+// fields fall through to the job defaults.
+type Profile struct {
+	// ImplementationModel selects the opencode model for implementing.
+	ImplementationModel string `toml:"implementation-model"`
+	// CodeReviewModel selects the opencode model for step review.
+	CodeReviewModel string `toml:"code-review-model"`
+	// ProjectReviewModel selects the opencode model for final project review.
+	ProjectReviewModel string `toml:"project-review-model"`
 }
 
 // Workspace contains workspace-related configuration.
@@ -25,6 +46,10 @@ type Workspace struct {
 	// Can include a shebang line; defaults to bash if not specified.
 	OnCreate string `toml:"on-create"`
 
+	// OnCreateTimeout bounds how long the on-create script may run before it
+	// and any processes it started are killed. Zero means no limit.
+	OnCreateTimeout Duration `toml:"on-create-timeout"`
+
 	// OnAcquire is a script to run every time a workspace is acquired.
 	// Can include a shebang line; defaults to bash if not specified.
 	OnAcquire string `toml:"on-acquire"`
@@ -34,6 +59,10 @@ type Workspace struct {
 type Job struct {
 	// TestCommands defines commands to run during job testing.
 	TestCommands []string `toml:"test-commands"`
+	// RequireTests makes jobs fail when no test commands are configured.
+	// When false and TestCommands is empty, jobs skip the testing stage.
+	// Nil means true; use TestsRequired to read it.
+	RequireTests *bool `toml:"require-tests"`
 	// Agent selects the default opencode agent for job runs.
 	Agent string `toml:"agent"`
 	// ImplementationModel selects the opencode model for implementing.
@@ -42,8 +71,103 @@ type Job struct {
 	CodeReviewModel string `toml:"code-review-model"`
 	// ProjectReviewModel selects the opencode model for final project review.
 	ProjectReviewModel string `toml:"project-review-model"`
+	// AgentByType maps todo types to the default opencode agent for job runs.
+	AgentByType map[string]string `toml:"agent-by-type"`
+	// ImplementationModelByType maps todo types to the implementing model.
+	ImplementationModelByType map[string]string `toml:"implementation-model-by-type"`
+	// CodeReviewModelByType maps todo types to the step review model.
+	CodeReviewModelByType map[string]string `toml:"code-review-model-by-type"`
+	// ProjectReviewModelByType maps todo types to the project review model.
+	ProjectReviewModelByType map[string]string `toml:"project-review-model-by-type"`
+	// SnapshotInterval sets how often the workspace is snapshotted while
+	// opencode implements a change. Zero snapshots only before opencode runs.
+	SnapshotInterval Duration `toml:"snapshot-interval"`
+	// StaleAfter sets how long an active job may go without updates before
+	// it is considered orphaned and marked failed. Zero uses the job
+	// package's default.
+	StaleAfter Duration `toml:"stale-after"`
+	// TestParallelism caps how many test commands run at once. Zero or one
+	// runs them sequentially.
+	TestParallelism int `toml:"test-parallelism"`
+	// ImplementRetries caps how many times a crashed opencode implementation
+	// run is restored and retried. Nil means 1; use ImplementRetryLimit to
+	// read it.
+	ImplementRetries *int `toml:"implement-retries"`
+	// ReviewOutcomes maps custom review feedback tokens to the canonical
+	// outcomes (ACCEPT, REQUEST_CHANGES, ABANDON).
+	ReviewOutcomes map[string]string `toml:"review-outcomes"`
+	// CompletionWebhook is a URL that receives a JSON POST when a job
+	// finishes.
+	CompletionWebhook string `toml:"completion-webhook"`
+	// CompletionWebhookSecret signs completion webhook bodies with
+	// HMAC-SHA256 when set.
+	CompletionWebhookSecret string `toml:"completion-webhook-secret"`
+	// CompletionNote appends a note summarizing the job's commits to its
+	// todo when a job completes.
+	CompletionNote bool `toml:"completion-note"`
+	// Opencode configures the opencode runs jobs start.
+	Opencode JobOpencode `toml:"opencode"`
+	// ProjectReviewEvery sends a job to project review after this many
+	// commits, even if implementing would continue. Zero reviews the project
+	// only once implementing makes no changes.
+	ProjectReviewEvery int `toml:"project-review-every"`
+	// MaxDiffLines fails a job instead of committing a change whose inserted
+	// plus deleted lines exceed it. Zero means unlimited.
+	MaxDiffLines int `toml:"max-diff-lines"`
+	// EventLogMaxBytes rotates a job's event log once it grows past this
+	// many bytes. Zero means unlimited.
+	EventLogMaxBytes int `toml:"event-log-max-bytes"`
+	// OnConflict chooses what a job does when implementing leaves the
+	// working copy with unresolved conflicts: "implement" sends it back to
+	// implementing with conflict-resolution feedback and "fail" fails it.
+	// Empty means "implement"; use ConflictPolicy to read it.
+	OnConflict string `toml:"on-conflict"`
+	// ConflictRetries caps how many times in a row the implement conflict
+	// policy sends a job back to implementing before failing it instead.
+	// Nil means 3; use ConflictRetryLimit to read it.
+	ConflictRetries *int `toml:"conflict-retries"`
+	// CommitMessagePattern is a regular expression the summary line of each
+	// draft commit message must match. Empty accepts any summary.
+	CommitMessagePattern string `toml:"commit-message-pattern"`
+	// OnInvalidCommitMessage chooses what a job does when a draft summary
+	// does not match CommitMessagePattern: "fail" fails it and "implement"
+	// sends it back to implementing with feedback. Empty means "fail"; use
+	// InvalidCommitMessagePolicy to read it.
+	OnInvalidCommitMessage string `toml:"on-invalid-commit-message"`
+	// CommitMessageRetries caps how many times in a row the implement
+	// invalid-commit-message policy sends a job back to implementing before
+	// failing it instead. Nil means 3; use CommitMessageRetryLimit to read it.
+	CommitMessageRetries *int `toml:"commit-message-retries"`
+	// CommitMessageWidth sets the column width final commit messages are
+	// wrapped to. Zero uses the job package's default.
+	CommitMessageWidth int `toml:"commit-message-width"`
+	// FeedbackFile names the workspace file reviewers write their verdict
+	// to. Empty uses the job package's default.
+	FeedbackFile string `toml:"feedback-file"`
+	// CommitMessageFile names the workspace file opencode writes its draft
+	// commit message to. Empty uses the job package's default.
+	CommitMessageFile string `toml:"commit-message-file"`
 }
 
+// Policies for job.on-conflict.
+const (
+	ConflictPolicyImplement = "implement"
+	ConflictPolicyFail      = "fail"
+)
+
+// Policies for job.on-invalid-commit-message.
+const (
+	CommitMessagePolicyFail      = "fail"
+	CommitMessagePolicyImplement = "implement"
+)
+
+// Commit message widths outside this range are rejected at load. The lower
+// bound leaves room for the indented todo block.
+const (
+	minCommitMessageWidth = 40
+	maxCommitMessageWidth = 200
+)
+
 // Load loads configuration from the repo root and the global config file.
 // Returns an empty config if no config files exist.
 func Load(repoPath string) (*Config, error) {
@@ -127,10 +251,113 @@ func loadConfigFile(path string) (*Config, toml.MetaData, error) {
 	if err != nil {
 		return nil, toml.MetaData{}, fmt.Errorf("parse config file %s: %w", path, err)
 	}
+	if err := validateConfig(&cfg, meta); err != nil {
+		return nil, toml.MetaData{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
 
 	return &cfg, meta, nil
 }
 
+// validateReviewOutcomes checks that every job.review-outcomes alias has a
+// token and targets a canonical review outcome, compared case-insensitively.
+func validateReviewOutcomes(aliases map[string]string) error {
+	outcomes := statestore.ValidReviewOutcomes()
+	for _, token := range slices.Sorted(maps.Keys(aliases)) {
+		if internalstrings.IsBlank(token) {
+			return fmt.Errorf("job.review-outcomes: alias token is required")
+		}
+		target := internalstrings.TrimSpace(aliases[token])
+		if !slices.ContainsFunc(outcomes, func(outcome statestore.ReviewOutcome) bool {
+			return strings.EqualFold(target, string(outcome))
+		}) {
+			valid := make([]string, len(outcomes))
+			for i, outcome := range outcomes {
+				valid[i] = string(outcome)
+			}
+			return fmt.Errorf("job.review-outcomes: alias %q maps to unknown outcome %q (valid: %s)", token, aliases[token], strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// validateConfig rejects values that decode but cannot be used.
+func validateConfig(cfg *Config, meta toml.MetaData) error {
+	if cfg.Workspace.OnCreateTimeout < 0 {
+		return fmt.Errorf("workspace.on-create-timeout must not be negative, got %s", time.Duration(cfg.Workspace.OnCreateTimeout))
+	}
+	if cfg.Job.ProjectReviewEvery < 0 {
+		return fmt.Errorf("job.project-review-every must not be negative, got %d", cfg.Job.ProjectReviewEvery)
+	}
+	if cfg.Job.MaxDiffLines < 0 {
+		return fmt.Errorf("job.max-diff-lines must not be negative, got %d", cfg.Job.MaxDiffLines)
+	}
+	if cfg.Job.EventLogMaxBytes < 0 {
+		return fmt.Errorf("job.event-log-max-bytes must not be negative, got %d", cfg.Job.EventLogMaxBytes)
+	}
+	if err := validateReviewOutcomes(cfg.Job.ReviewOutcomes); err != nil {
+		return err
+	}
+	switch cfg.Job.OnConflict {
+	case "", ConflictPolicyImplement, ConflictPolicyFail:
+	default:
+		return fmt.Errorf("job.on-conflict must be %q or %q, got %q", ConflictPolicyImplement, ConflictPolicyFail, cfg.Job.OnConflict)
+	}
+	if _, err := regexp.Compile(cfg.Job.CommitMessagePattern); err != nil {
+		return fmt.Errorf("job.commit-message-pattern: %w", err)
+	}
+	switch cfg.Job.OnInvalidCommitMessage {
+	case "", CommitMessagePolicyFail, CommitMessagePolicyImplement:
+	default:
+		return fmt.Errorf("job.on-invalid-commit-message must be %q or %q, got %q", CommitMessagePolicyFail, CommitMessagePolicyImplement, cfg.Job.OnInvalidCommitMessage)
+	}
+	if meta.IsDefined("job", "commit-message-width") {
+		width := cfg.Job.CommitMessageWidth
+		if width < minCommitMessageWidth || width > maxCommitMessageWidth {
+			return fmt.Errorf("job.commit-message-width must be between %d and %d, got %d", minCommitMessageWidth, maxCommitMessageWidth, width)
+		}
+	}
+	for name := range cfg.Profiles {
+		if internalstrings.IsBlank(name) || name != internalstrings.TrimSpace(name) {
+			return fmt.Errorf("invalid profile name %q", name)
+		}
+	}
+	for _, file := range []struct {
+		key   string
+		value string
+	}{
+		{"feedback-file", cfg.Job.FeedbackFile},
+		{"commit-message-file", cfg.Job.CommitMessageFile},
+	} {
+		if !meta.IsDefined("job", file.key) {
+			continue
+		}
+		if err := validateWorkspaceFileName(file.value); err != nil {
+			return fmt.Errorf("job.%s: %w", file.key, err)
+		}
+	}
+	return nil
+}
+
+// validateWorkspaceFileName ensures name is a plain file name that stays in
+// the workspace root.
+func validateWorkspaceFileName(name string) error {
+	if internalstrings.IsBlank(name) {
+		return fmt.Errorf("file name is required")
+	}
+	if name != internalstrings.TrimSpace(name) {
+		return fmt.Errorf("file name %q has surrounding whitespace", name)
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("file name %q must not be a path", name)
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("file name %q contains a control character", name)
+		}
+	}
+	return nil
+}
+
 func mergeConfigs(globalCfg, projectCfg *Config, globalMeta, projectMeta toml.MetaData) *Config {
 	if globalCfg == nil {
 		globalCfg = &Config{}
@@ -141,20 +368,103 @@ func mergeConfigs(globalCfg, projectCfg *Config, globalMeta, projectMeta toml.Me
 
 	merged := Config{}
 	merged.Workspace.OnCreate = mergeString(projectMeta.IsDefined("workspace", "on-create"), projectCfg.Workspace.OnCreate, globalCfg.Workspace.OnCreate)
+	merged.Workspace.OnCreateTimeout = mergeDuration(projectMeta.IsDefined("workspace", "on-create-timeout"), projectCfg.Workspace.OnCreateTimeout, globalCfg.Workspace.OnCreateTimeout)
 	merged.Workspace.OnAcquire = mergeString(projectMeta.IsDefined("workspace", "on-acquire"), projectCfg.Workspace.OnAcquire, globalCfg.Workspace.OnAcquire)
 	merged.Job.Agent = mergeString(projectMeta.IsDefined("job", "agent"), projectCfg.Job.Agent, globalCfg.Job.Agent)
 	merged.Job.ImplementationModel = mergeString(projectMeta.IsDefined("job", "implementation-model"), projectCfg.Job.ImplementationModel, globalCfg.Job.ImplementationModel)
 	merged.Job.CodeReviewModel = mergeString(projectMeta.IsDefined("job", "code-review-model"), projectCfg.Job.CodeReviewModel, globalCfg.Job.CodeReviewModel)
 	merged.Job.ProjectReviewModel = mergeString(projectMeta.IsDefined("job", "project-review-model"), projectCfg.Job.ProjectReviewModel, globalCfg.Job.ProjectReviewModel)
+	merged.Job.AgentByType = mergeStringMap(projectMeta.IsDefined("job", "agent-by-type"), projectCfg.Job.AgentByType, globalCfg.Job.AgentByType)
+	merged.Job.ImplementationModelByType = mergeStringMap(projectMeta.IsDefined("job", "implementation-model-by-type"), projectCfg.Job.ImplementationModelByType, globalCfg.Job.ImplementationModelByType)
+	merged.Job.CodeReviewModelByType = mergeStringMap(projectMeta.IsDefined("job", "code-review-model-by-type"), projectCfg.Job.CodeReviewModelByType, globalCfg.Job.CodeReviewModelByType)
+	merged.Job.ProjectReviewModelByType = mergeStringMap(projectMeta.IsDefined("job", "project-review-model-by-type"), projectCfg.Job.ProjectReviewModelByType, globalCfg.Job.ProjectReviewModelByType)
+	merged.Job.SnapshotInterval = mergeDuration(projectMeta.IsDefined("job", "snapshot-interval"), projectCfg.Job.SnapshotInterval, globalCfg.Job.SnapshotInterval)
+	merged.Job.StaleAfter = mergeDuration(projectMeta.IsDefined("job", "stale-after"), projectCfg.Job.StaleAfter, globalCfg.Job.StaleAfter)
+	merged.Job.CompletionWebhook = mergeString(projectMeta.IsDefined("job", "completion-webhook"), projectCfg.Job.CompletionWebhook, globalCfg.Job.CompletionWebhook)
+	merged.Job.CompletionWebhookSecret = mergeString(projectMeta.IsDefined("job", "completion-webhook-secret"), projectCfg.Job.CompletionWebhookSecret, globalCfg.Job.CompletionWebhookSecret)
+	merged.Job.ReviewOutcomes = mergeStringMap(projectMeta.IsDefined("job", "review-outcomes"), projectCfg.Job.ReviewOutcomes, globalCfg.Job.ReviewOutcomes)
+	merged.Job.CompletionNote = mergeBool(projectMeta.IsDefined("job", "completion-note"), projectCfg.Job.CompletionNote, globalCfg.Job.CompletionNote)
+	if projectMeta.IsDefined("job", "require-tests") {
+		merged.Job.RequireTests = projectCfg.Job.RequireTests
+	} else if globalMeta.IsDefined("job", "require-tests") {
+		merged.Job.RequireTests = globalCfg.Job.RequireTests
+	}
+	merged.Job.Opencode.Config = mergeOpencodeConfig(projectMeta.IsDefined("job", "opencode", "config"), projectCfg.Job.Opencode.Config, globalCfg.Job.Opencode.Config)
+	merged.Job.FeedbackFile = mergeString(projectMeta.IsDefined("job", "feedback-file"), projectCfg.Job.FeedbackFile, globalCfg.Job.FeedbackFile)
+	merged.Job.CommitMessageFile = mergeString(projectMeta.IsDefined("job", "commit-message-file"), projectCfg.Job.CommitMessageFile, globalCfg.Job.CommitMessageFile)
+	merged.Job.CommitMessageWidth = mergeInt(projectMeta.IsDefined("job", "commit-message-width"), projectCfg.Job.CommitMessageWidth, globalCfg.Job.CommitMessageWidth)
+	merged.Job.ProjectReviewEvery = mergeInt(projectMeta.IsDefined("job", "project-review-every"), projectCfg.Job.ProjectReviewEvery, globalCfg.Job.ProjectReviewEvery)
+	merged.Job.MaxDiffLines = mergeInt(projectMeta.IsDefined("job", "max-diff-lines"), projectCfg.Job.MaxDiffLines, globalCfg.Job.MaxDiffLines)
+	merged.Job.EventLogMaxBytes = mergeInt(projectMeta.IsDefined("job", "event-log-max-bytes"), projectCfg.Job.EventLogMaxBytes, globalCfg.Job.EventLogMaxBytes)
+	merged.Job.CommitMessagePattern = mergeString(projectMeta.IsDefined("job", "commit-message-pattern"), projectCfg.Job.CommitMessagePattern, globalCfg.Job.CommitMessagePattern)
+	merged.Job.OnInvalidCommitMessage = mergeString(projectMeta.IsDefined("job", "on-invalid-commit-message"), projectCfg.Job.OnInvalidCommitMessage, globalCfg.Job.OnInvalidCommitMessage)
+	merged.Job.OnConflict = mergeString(projectMeta.IsDefined("job", "on-conflict"), projectCfg.Job.OnConflict, globalCfg.Job.OnConflict)
+	merged.Job.TestParallelism = mergeInt(projectMeta.IsDefined("job", "test-parallelism"), projectCfg.Job.TestParallelism, globalCfg.Job.TestParallelism)
+	if projectMeta.IsDefined("job", "implement-retries") {
+		merged.Job.ImplementRetries = projectCfg.Job.ImplementRetries
+	} else if globalMeta.IsDefined("job", "implement-retries") {
+		merged.Job.ImplementRetries = globalCfg.Job.ImplementRetries
+	}
+	if projectMeta.IsDefined("job", "conflict-retries") {
+		merged.Job.ConflictRetries = projectCfg.Job.ConflictRetries
+	} else if globalMeta.IsDefined("job", "conflict-retries") {
+		merged.Job.ConflictRetries = globalCfg.Job.ConflictRetries
+	}
+	if projectMeta.IsDefined("job", "commit-message-retries") {
+		merged.Job.CommitMessageRetries = projectCfg.Job.CommitMessageRetries
+	} else if globalMeta.IsDefined("job", "commit-message-retries") {
+		merged.Job.CommitMessageRetries = globalCfg.Job.CommitMessageRetries
+	}
 	if projectMeta.IsDefined("job", "test-commands") {
 		merged.Job.TestCommands = append([]string(nil), projectCfg.Job.TestCommands...)
 	} else if globalMeta.IsDefined("job", "test-commands") {
 		merged.Job.TestCommands = append([]string(nil), globalCfg.Job.TestCommands...)
 	}
+	merged.Profiles = mergeProfiles(projectMeta, projectCfg.Profiles, globalCfg.Profiles)
 
 	return &merged
 }
 
+// mergeProfiles combines global and project profiles. A profile defined in
+// both takes each model from the project when the project sets it.
+func mergeProfiles(projectMeta toml.MetaData, projectProfiles, globalProfiles map[string]Profile) map[string]Profile {
+	if len(projectProfiles) == 0 && len(globalProfiles) == 0 {
+		return nil
+	}
+	merged := make(map[string]Profile, len(globalProfiles)+len(projectProfiles))
+	for name, profile := range globalProfiles {
+		merged[name] = profile.trimmed()
+	}
+	for name, project := range projectProfiles {
+		global := globalProfiles[name]
+		defined := func(key string) bool { return projectMeta.IsDefined("profiles", name, key) }
+		merged[name] = Profile{
+			ImplementationModel: mergeString(defined("implementation-model"), project.ImplementationModel, global.ImplementationModel),
+			CodeReviewModel:     mergeString(defined("code-review-model"), project.CodeReviewModel, global.CodeReviewModel),
+			ProjectReviewModel:  mergeString(defined("project-review-model"), project.ProjectReviewModel, global.ProjectReviewModel),
+		}.trimmed()
+	}
+	return merged
+}
+
+func (p Profile) trimmed() Profile {
+	return Profile{
+		ImplementationModel: internalstrings.TrimSpace(p.ImplementationModel),
+		CodeReviewModel:     internalstrings.TrimSpace(p.CodeReviewModel),
+		ProjectReviewModel:  internalstrings.TrimSpace(p.ProjectReviewModel),
+	}
+}
+
+// LookupProfile returns the named profile. Names are matched exactly after
+// trimming surrounding whitespace.
+func (c *Config) LookupProfile(name string) (Profile, bool) {
+	if c == nil {
+		return Profile{}, false
+	}
+	profile, ok := c.Profiles[internalstrings.TrimSpace(name)]
+	return profile, ok
+}
+
 func mergeString(projectDefined bool, projectValue, globalValue string) string {
 	value := globalValue
 	if projectDefined {
@@ -163,10 +473,103 @@ func mergeString(projectDefined bool, projectValue, globalValue string) string {
 	return internalstrings.TrimSpace(value)
 }
 
+func mergeInt(projectDefined bool, projectValue, globalValue int) int {
+	if projectDefined {
+		return projectValue
+	}
+	return globalValue
+}
+
+func mergeBool(projectDefined bool, projectValue, globalValue bool) bool {
+	if projectDefined {
+		return projectValue
+	}
+	return globalValue
+}
+
+// mergeStringMap picks the project map when defined, otherwise the global one.
+// Keys are normalized to lowercase and values are trimmed.
+func mergeStringMap(projectDefined bool, projectValue, globalValue map[string]string) map[string]string {
+	value := globalValue
+	if projectDefined {
+		value = projectValue
+	}
+	if value == nil {
+		return nil
+	}
+	merged := make(map[string]string, len(value))
+	for key, entry := range value {
+		merged[internalstrings.NormalizeLowerTrimSpace(key)] = internalstrings.TrimSpace(entry)
+	}
+	return merged
+}
+
+// TestsRequired reports whether jobs must have test commands configured.
+// It defaults to true when require-tests is unset.
+func (j Job) TestsRequired() bool {
+	return j.RequireTests == nil || *j.RequireTests
+}
+
+// ImplementRetryLimit returns how many times a crashed implementation run is
+// retried. It defaults to 1 when implement-retries is unset; negative values
+// count as zero.
+func (j Job) ImplementRetryLimit() int {
+	if j.ImplementRetries == nil {
+		return 1
+	}
+	return max(*j.ImplementRetries, 0)
+}
+
+// ConflictPolicy returns the job.on-conflict policy, defaulting to
+// ConflictPolicyImplement when unset.
+func (j Job) ConflictPolicy() string {
+	if j.OnConflict == "" {
+		return ConflictPolicyImplement
+	}
+	return j.OnConflict
+}
+
+// ConflictRetryLimit returns how many consecutive times unresolved conflicts
+// send a job back to implementing before it fails. It defaults to 3 when
+// conflict-retries is unset; negative values count as zero.
+func (j Job) ConflictRetryLimit() int {
+	if j.ConflictRetries == nil {
+		return 3
+	}
+	return max(*j.ConflictRetries, 0)
+}
+
+// InvalidCommitMessagePolicy returns the job.on-invalid-commit-message
+// policy, defaulting to CommitMessagePolicyFail when unset.
+func (j Job) InvalidCommitMessagePolicy() string {
+	if j.OnInvalidCommitMessage == "" {
+		return CommitMessagePolicyFail
+	}
+	return j.OnInvalidCommitMessage
+}
+
+// CommitMessageRetryLimit returns how many consecutive times an invalid
+// commit message sends a job back to implementing before it fails. It
+// defaults to 3 when commit-message-retries is unset; negative values count
+// as zero.
+func (j Job) CommitMessageRetryLimit() int {
+	if j.CommitMessageRetries == nil {
+		return 3
+	}
+	return max(*j.CommitMessageRetries, 0)
+}
+
 // RunScript executes a script in the given directory.
 // If the script starts with a shebang (#!), that interpreter is used.
 // Otherwise, the script is run with /bin/bash.
 func RunScript(dir, script string) error {
+	return RunScriptContext(context.Background(), dir, script)
+}
+
+// RunScriptContext is like RunScript, but when ctx can be canceled the script
+// runs in its own process group, and the whole group is killed once ctx is
+// done so that commands it started do not outlive it.
+func RunScriptContext(ctx context.Context, dir, script string) error {
 	script = internalstrings.TrimSpace(script)
 	if script == "" {
 		return nil
@@ -193,11 +596,17 @@ func RunScript(dir, script string) error {
 		return fmt.Errorf("empty interpreter in shebang")
 	}
 
-	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
 	cmd.Dir = dir
 	cmd.Stdin = strings.NewReader(scriptBody)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if ctx.Done() != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+	}
 
 	return cmd.Run()
 }
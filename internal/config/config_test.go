@@ -1,9 +1,14 @@
 package config_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/amonks/incrementum/internal/config"
 	"github.com/amonks/incrementum/internal/testsupport"
@@ -29,6 +34,22 @@ func TestLoad_NotFound(t *testing.T) {
 	if cfg.Workspace.OnAcquire != "" {
 		t.Error("expected empty OnAcquire")
 	}
+
+	if !cfg.Job.TestsRequired() {
+		t.Error("expected tests to be required by default")
+	}
+
+	if cfg.Job.ImplementRetryLimit() != 1 {
+		t.Errorf("expected one implement retry by default, got %d", cfg.Job.ImplementRetryLimit())
+	}
+
+	if cfg.Job.ConflictRetryLimit() != 3 {
+		t.Errorf("expected three conflict retries by default, got %d", cfg.Job.ConflictRetryLimit())
+	}
+
+	if cfg.Job.CommitMessageRetryLimit() != 3 {
+		t.Errorf("expected three commit message retries by default, got %d", cfg.Job.CommitMessageRetryLimit())
+	}
 }
 
 func TestLoad_Full(t *testing.T) {
@@ -41,6 +62,7 @@ on-create = """
 npm install
 go mod download
 """
+on-create-timeout = "10m"
 on-acquire = "npm install"
 `
 
@@ -60,6 +82,10 @@ on-acquire = "npm install"
 	if cfg.Workspace.OnAcquire != "npm install" {
 		t.Errorf("OnAcquire = %q, expected %q", cfg.Workspace.OnAcquire, "npm install")
 	}
+
+	if got := time.Duration(cfg.Workspace.OnCreateTimeout); got != 10*time.Minute {
+		t.Errorf("OnCreateTimeout = %s, expected %s", got, 10*time.Minute)
+	}
 }
 
 func TestLoad_Full_DotIncrementum(t *testing.T) {
@@ -168,6 +194,33 @@ agent = "gpt-5.2-codex"
 implementation-model = "gpt-5.2-impl"
 code-review-model = "gpt-5.2-review"
 project-review-model = "gpt-5.2-project"
+snapshot-interval = "90s"
+test-parallelism = 4
+max-diff-lines = 500
+project-review-every = 3
+stale-after = "45m"
+completion-webhook = " https://hooks.example.com/jobs "
+completion-webhook-secret = "s3cret"
+completion-note = true
+require-tests = false
+implement-retries = 3
+conflict-retries = 1
+commit-message-retries = 2
+commit-message-width = 72
+feedback-file = ".agent-1-feedback"
+commit-message-file = ".agent-1-commit-message"
+
+[job.agent-by-type]
+Bug = "gpt-5.2-careful"
+
+[job.implementation-model-by-type]
+feature = "gpt-5.2-feature"
+
+[job.review-outcomes]
+AKZEPTIEREN = "ACCEPT"
+
+[job.opencode]
+config = '''{"permission": {"bash": {"jj status": "allow"}}}'''
 `
 
 	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
@@ -187,6 +240,14 @@ project-review-model = "gpt-5.2-project"
 		t.Fatalf("expected first test command %q, got %q", "go test ./...", cfg.Job.TestCommands[0])
 	}
 
+	if cfg.Job.FeedbackFile != ".agent-1-feedback" || cfg.Job.CommitMessageFile != ".agent-1-commit-message" {
+		t.Fatalf("expected configured output files, got %q and %q", cfg.Job.FeedbackFile, cfg.Job.CommitMessageFile)
+	}
+
+	if cfg.Job.CommitMessageWidth != 72 {
+		t.Fatalf("expected commit message width 72, got %d", cfg.Job.CommitMessageWidth)
+	}
+
 	if cfg.Job.Agent != "gpt-5.2-codex" {
 		t.Fatalf("expected agent %q, got %q", "gpt-5.2-codex", cfg.Job.Agent)
 	}
@@ -199,6 +260,304 @@ project-review-model = "gpt-5.2-project"
 	if cfg.Job.ProjectReviewModel != "gpt-5.2-project" {
 		t.Fatalf("expected project review model %q, got %q", "gpt-5.2-project", cfg.Job.ProjectReviewModel)
 	}
+	if cfg.Job.AgentByType["bug"] != "gpt-5.2-careful" {
+		t.Fatalf("expected bug agent %q, got %q", "gpt-5.2-careful", cfg.Job.AgentByType["bug"])
+	}
+	if cfg.Job.ImplementationModelByType["feature"] != "gpt-5.2-feature" {
+		t.Fatalf("expected feature implementation model %q, got %q", "gpt-5.2-feature", cfg.Job.ImplementationModelByType["feature"])
+	}
+	if time.Duration(cfg.Job.SnapshotInterval) != 90*time.Second {
+		t.Fatalf("expected snapshot interval 90s, got %v", time.Duration(cfg.Job.SnapshotInterval))
+	}
+	if time.Duration(cfg.Job.StaleAfter) != 45*time.Minute {
+		t.Fatalf("expected stale after 45m, got %v", time.Duration(cfg.Job.StaleAfter))
+	}
+	if cfg.Job.TestParallelism != 4 {
+		t.Fatalf("expected test parallelism 4, got %d", cfg.Job.TestParallelism)
+	}
+	if cfg.Job.ProjectReviewEvery != 3 {
+		t.Fatalf("expected project review every 3 commits, got %d", cfg.Job.ProjectReviewEvery)
+	}
+	if cfg.Job.MaxDiffLines != 500 {
+		t.Fatalf("expected max diff lines 500, got %d", cfg.Job.MaxDiffLines)
+	}
+	if cfg.Job.CompletionWebhook != "https://hooks.example.com/jobs" {
+		t.Fatalf("expected completion webhook, got %q", cfg.Job.CompletionWebhook)
+	}
+	if cfg.Job.CompletionWebhookSecret != "s3cret" {
+		t.Fatalf("expected completion webhook secret, got %q", cfg.Job.CompletionWebhookSecret)
+	}
+	if !cfg.Job.CompletionNote {
+		t.Fatalf("expected completion note to be enabled")
+	}
+	if cfg.Job.TestsRequired() {
+		t.Fatalf("expected require-tests to be disabled")
+	}
+	if cfg.Job.ImplementRetryLimit() != 3 {
+		t.Fatalf("expected 3 implement retries, got %d", cfg.Job.ImplementRetryLimit())
+	}
+	if cfg.Job.ConflictRetryLimit() != 1 {
+		t.Fatalf("expected 1 conflict retry, got %d", cfg.Job.ConflictRetryLimit())
+	}
+	if cfg.Job.CommitMessageRetryLimit() != 2 {
+		t.Fatalf("expected 2 commit message retries, got %d", cfg.Job.CommitMessageRetryLimit())
+	}
+	if cfg.Job.ReviewOutcomes["akzeptieren"] != "ACCEPT" {
+		t.Fatalf("expected review outcome alias %q, got %q", "ACCEPT", cfg.Job.ReviewOutcomes["akzeptieren"])
+	}
+	if cfg.Job.Opencode.Config != `{"permission": {"bash": {"jj status": "allow"}}}` {
+		t.Fatalf("expected opencode config, got %q", cfg.Job.Opencode.Config)
+	}
+}
+
+func TestLoad_InvalidDuration(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := `
+[job]
+snapshot-interval = "soon"
+`
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if _, err := config.Load(tmpDir); err == nil {
+		t.Fatal("expected error for invalid snapshot interval")
+	}
+}
+
+func TestLoad_NegativeOnCreateTimeout(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[workspace]\non-create-timeout = \"-1m\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for negative on-create timeout")
+	}
+	if !strings.Contains(err.Error(), "on-create-timeout") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_NegativeMaxDiffLines(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\nmax-diff-lines = -1\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for negative max-diff-lines")
+	}
+	if !strings.Contains(err.Error(), "max-diff-lines") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_InvalidReviewOutcome(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job.review-outcomes]\nship-it = \"MERGE\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for unknown review outcome")
+	}
+	if !strings.Contains(err.Error(), "review-outcomes") || !strings.Contains(err.Error(), "MERGE") {
+		t.Fatalf("expected error to name the setting and target, got %v", err)
+	}
+}
+
+func TestLoad_NegativeEventLogMaxBytes(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\nevent-log-max-bytes = -1\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for negative event-log-max-bytes")
+	}
+	if !strings.Contains(err.Error(), "event-log-max-bytes") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_OnConflict(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\non-conflict = \"fail\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := config.Load(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if got := cfg.Job.ConflictPolicy(); got != config.ConflictPolicyFail {
+		t.Fatalf("expected on-conflict fail, got %q", got)
+	}
+	if got := (config.Job{}).ConflictPolicy(); got != config.ConflictPolicyImplement {
+		t.Fatalf("expected default on-conflict implement, got %q", got)
+	}
+}
+
+func TestLoad_InvalidOnConflict(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\non-conflict = \"ignore\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for unknown on-conflict policy")
+	}
+	if !strings.Contains(err.Error(), "on-conflict") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_InvalidCommitMessagePattern(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\ncommit-message-pattern = \"^(feat\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for a pattern that does not compile")
+	}
+	if !strings.Contains(err.Error(), "commit-message-pattern") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_InvalidCommitMessagePolicy(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\non-invalid-commit-message = \"fix\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for unknown on-invalid-commit-message policy")
+	}
+	if !strings.Contains(err.Error(), "on-invalid-commit-message") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+	if got := (config.Job{}).InvalidCommitMessagePolicy(); got != config.CommitMessagePolicyFail {
+		t.Fatalf("expected default policy fail, got %q", got)
+	}
+}
+
+func TestLoad_NegativeProjectReviewEvery(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	tmpDir := t.TempDir()
+
+	configContent := "[job]\nproject-review-every = -1\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(tmpDir)
+	if err == nil {
+		t.Fatal("expected error for negative project-review-every")
+	}
+	if !strings.Contains(err.Error(), "project-review-every") {
+		t.Fatalf("expected error to name the setting, got %v", err)
+	}
+}
+
+func TestLoad_InvalidOpencodeConfig(t *testing.T) {
+	testsupport.SetupTestHome(t)
+
+	for name, value := range map[string]string{
+		"invalid JSON": `{"permission": `,
+		"not object":   `["deny"]`,
+		"null":         `null`,
+	} {
+		tmpDir := t.TempDir()
+		configContent := "[job.opencode]\nconfig = '" + value + "'\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		_, err := config.Load(tmpDir)
+		if err == nil {
+			t.Fatalf("%s: expected error for opencode config %q", name, value)
+		}
+		if !strings.Contains(err.Error(), "opencode config") {
+			t.Fatalf("%s: expected error to mention the opencode config, got %v", name, err)
+		}
+	}
+}
+
+func TestLoad_InvalidCommitMessageWidth(t *testing.T) {
+	testsupport.SetupTestHome(t)
+
+	for _, width := range []string{"0", "-72", "10", "1000"} {
+		tmpDir := t.TempDir()
+		configContent := "[job]\ncommit-message-width = " + width + "\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		_, err := config.Load(tmpDir)
+		if err == nil {
+			t.Fatalf("expected error for commit message width %s", width)
+		}
+		if !strings.Contains(err.Error(), "commit-message-width") {
+			t.Fatalf("expected error to name the setting, got %v", err)
+		}
+	}
+}
+
+func TestLoad_InvalidOutputFileNames(t *testing.T) {
+	testsupport.SetupTestHome(t)
+
+	for _, name := range []string{`""`, `"  "`, `"../feedback"`, `"notes/feedback"`, `".."`, `"a\nb"`} {
+		tmpDir := t.TempDir()
+		configContent := "[job]\nfeedback-file = " + name + "\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "incrementum.toml"), []byte(configContent), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		_, err := config.Load(tmpDir)
+		if err == nil {
+			t.Fatalf("expected error for feedback file %s", name)
+		}
+		if !strings.Contains(err.Error(), "feedback-file") {
+			t.Fatalf("expected error to name the setting, got %v", err)
+		}
+	}
 }
 
 func TestRunScript_Empty(t *testing.T) {
@@ -214,6 +573,39 @@ func TestRunScript_Empty(t *testing.T) {
 	}
 }
 
+func TestRunScriptContext_KillsProcessGroupOnCancel(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The backgrounded sleep would keep running if only bash were killed.
+	script := "sleep 30 &\necho $! > child.pid\nwait\n"
+	start := time.Now()
+	if err := config.RunScriptContext(ctx, tmpDir, script); err == nil {
+		t.Fatal("expected a canceled script to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the script to be killed promptly, took %s", elapsed)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "child.pid"))
+	if err != nil {
+		t.Fatalf("read child pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("parse child pid: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected background child %d to be killed", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func TestRunScript_SimpleBash(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -479,3 +871,74 @@ test-commands = []
 		t.Fatalf("expected empty test commands, got %d", len(cfg.Job.TestCommands))
 	}
 }
+
+func TestLoad_MergesProfiles(t *testing.T) {
+	homeDir := testsupport.SetupTestHome(t)
+	configDir := filepath.Join(homeDir, ".config", "incrementum")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+
+	globalContent := `
+[profiles.cheap]
+implementation-model = "global-cheap-implement"
+code-review-model = "global-cheap-review"
+
+[profiles.thorough]
+project-review-model = "global-thorough-project"
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(globalContent), 0o644); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+
+	projectContent := `
+[profiles.cheap]
+code-review-model = " project-cheap-review "
+
+[profiles.review-only]
+code-review-model = "project-review-only"
+`
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "incrementum.toml"), []byte(projectContent), 0o644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+
+	cfg, err := config.Load(repoDir)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	cheap, ok := cfg.LookupProfile("cheap")
+	if !ok {
+		t.Fatal("expected cheap profile")
+	}
+	if cheap.ImplementationModel != "global-cheap-implement" || cheap.CodeReviewModel != "project-cheap-review" {
+		t.Errorf("expected cheap to merge per model, got %+v", cheap)
+	}
+	if thorough, _ := cfg.LookupProfile("thorough"); thorough.ProjectReviewModel != "global-thorough-project" {
+		t.Errorf("expected global thorough profile, got %+v", thorough)
+	}
+	if reviewOnly, _ := cfg.LookupProfile(" review-only "); reviewOnly != (config.Profile{CodeReviewModel: "project-review-only"}) {
+		t.Errorf("expected project review-only profile, got %+v", reviewOnly)
+	}
+	if _, ok := cfg.LookupProfile("missing"); ok {
+		t.Error("expected missing profile to be absent")
+	}
+}
+
+func TestLoad_InvalidProfileName(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	repoDir := t.TempDir()
+	content := `
+[profiles." "]
+implementation-model = "model"
+`
+	if err := os.WriteFile(filepath.Join(repoDir, "incrementum.toml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.Load(repoDir)
+	if err == nil || !strings.Contains(err.Error(), "invalid profile name") {
+		t.Fatalf("expected invalid profile name error, got %v", err)
+	}
+}
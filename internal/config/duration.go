@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// Duration is a time.Duration that decodes from TOML strings such as "30s"
+// or "5m".
+type Duration time.Duration
+
+// UnmarshalText parses a Go duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	value := internalstrings.TrimSpace(string(text))
+	if value == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	if parsed < 0 {
+		return fmt.Errorf("invalid duration %q: must not be negative", value)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func mergeDuration(projectDefined bool, projectValue, globalValue Duration) Duration {
+	if projectDefined {
+		return projectValue
+	}
+	return globalValue
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// JobOpencode contains opencode settings for job runs.
+type JobOpencode struct {
+	// Config is merged over the job package's default opencode config and
+	// passed to opencode via OPENCODE_CONFIG_CONTENT.
+	Config OpencodeConfig `toml:"config"`
+}
+
+// OpencodeConfig is a JSON object that decodes from a TOML string. Invalid
+// JSON, or JSON that is not an object, fails to load.
+type OpencodeConfig string
+
+// UnmarshalText validates that text is a JSON object.
+func (c *OpencodeConfig) UnmarshalText(text []byte) error {
+	value := internalstrings.TrimSpace(string(text))
+	if value == "" {
+		*c = ""
+		return nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(value), &object); err != nil {
+		return fmt.Errorf("invalid opencode config: must be a JSON object: %w", err)
+	}
+	if object == nil {
+		return fmt.Errorf("invalid opencode config: must be a JSON object, got null")
+	}
+	*c = OpencodeConfig(value)
+	return nil
+}
+
+// MarshalText returns the JSON text.
+func (c OpencodeConfig) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// Object decodes the config. An empty config yields nil.
+func (c OpencodeConfig) Object() (map[string]any, error) {
+	if c == "" {
+		return nil, nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(c), &object); err != nil {
+		return nil, fmt.Errorf("decode opencode config: %w", err)
+	}
+	return object, nil
+}
+
+func mergeOpencodeConfig(projectDefined bool, projectValue, globalValue OpencodeConfig) OpencodeConfig {
+	if projectDefined {
+		return projectValue
+	}
+	return globalValue
+}
@@ -0,0 +1,70 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/todo"
+)
+
+const todoOrderHeader = `# Reorder the todos below from most to least urgent, then save and exit.
+# Keep each todo on its own line; only the leading ID is read.
+# Lines starting with '#' and blank lines are ignored.
+`
+
+// RenderTodoOrder renders an editable ordering of todos, one per line.
+func RenderTodoOrder(todos []todo.Todo) string {
+	var out strings.Builder
+	out.WriteString(todoOrderHeader)
+	out.WriteString("\n")
+	for _, item := range todos {
+		title := internalstrings.NormalizeWhitespace(item.Title)
+		fmt.Fprintf(&out, "%s  p%d  %s\n", item.ID, item.Priority, title)
+	}
+	return out.String()
+}
+
+// ParseTodoOrder returns the todo IDs from an edited ordering, in order.
+func ParseTodoOrder(content string) []string {
+	var ids []string
+	for _, line := range strings.Split(content, "\n") {
+		line = internalstrings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, strings.Fields(line)[0])
+	}
+	return ids
+}
+
+// EditTodoOrder opens the editor with the given todos and returns the
+// reordered todo IDs.
+func EditTodoOrder(todos []todo.Todo) ([]string, error) {
+	tmpfile, err := os.CreateTemp("", "ii-todo-order-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpfile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpfile.WriteString(RenderTodoOrder(todos)); err != nil {
+		tmpfile.Close()
+		return nil, fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		return nil, fmt.Errorf("close temp file: %w", err)
+	}
+
+	if err := Edit(tmpPath); err != nil {
+		return nil, err
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("read edited file: %w", err)
+	}
+
+	return ParseTodoOrder(string(edited)), nil
+}
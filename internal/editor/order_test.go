@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestRenderTodoOrderRoundTrips(t *testing.T) {
+	todos := []todo.Todo{
+		{ID: "aaaaaaaa", Title: "First", Priority: 1},
+		{ID: "bbbbbbbb", Title: "Second\ttitle", Priority: 2},
+	}
+
+	content := RenderTodoOrder(todos)
+	if !strings.Contains(content, "aaaaaaaa  p1  First\n") {
+		t.Fatalf("expected first todo line, got:\n%s", content)
+	}
+	if !strings.Contains(content, "bbbbbbbb  p2  Second title\n") {
+		t.Fatalf("expected normalized second todo line, got:\n%s", content)
+	}
+
+	got := ParseTodoOrder(content)
+	want := []string{"aaaaaaaa", "bbbbbbbb"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseTodoOrderSkipsCommentsAndBlankLines(t *testing.T) {
+	content := "# header\n\n  bbbb  p2  Second\n# aaaa p1 commented out\naaaa\n"
+
+	got := ParseTodoOrder(content)
+	want := []string{"bbbb", "aaaa"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -35,6 +35,8 @@ type TodoData struct {
 	CodeReviewModel string
 	// ProjectReviewModel selects the opencode model for project review.
 	ProjectReviewModel string
+	// EstimateMinutes is the expected effort in minutes (0 for none).
+	EstimateMinutes int
 }
 
 // DefaultCreateData returns TodoData with default values for creating a new todo.
@@ -65,6 +67,7 @@ func DataFromTodo(t *todo.Todo) TodoData {
 		ImplementationModel: t.ImplementationModel,
 		CodeReviewModel:     t.CodeReviewModel,
 		ProjectReviewModel:  t.ProjectReviewModel,
+		EstimateMinutes:     t.EstimateMinutes,
 	}
 }
 
@@ -78,6 +81,7 @@ status = {{ printf "%q" .Status }} # {{ validStatuses }}
 implementation-model = {{ printf "%q" .ImplementationModel }}
 code-review-model = {{ printf "%q" .CodeReviewModel }}
 project-review-model = {{ printf "%q" .ProjectReviewModel }}
+estimate-minutes = {{ .EstimateMinutes }} # expected effort, 0=none
 ---
 {{ .Description }}
 `))
@@ -100,6 +104,7 @@ type ParsedTodo struct {
 	ImplementationModel string  `toml:"implementation-model"`
 	CodeReviewModel     string  `toml:"code-review-model"`
 	ProjectReviewModel  string  `toml:"project-review-model"`
+	EstimateMinutes     int     `toml:"estimate-minutes"`
 	Description         string
 }
 
@@ -134,6 +139,9 @@ func ParseTodoTOML(content string) (*ParsedTodo, error) {
 	if parsed.Status != nil && !todo.Status(*parsed.Status).IsValid() {
 		return nil, fmt.Errorf("invalid status %q: must be %s", *parsed.Status, validTodoStatuses())
 	}
+	if err := todo.ValidateEstimate(parsed.EstimateMinutes); err != nil {
+		return nil, err
+	}
 
 	return &parsed, nil
 }
@@ -236,6 +244,7 @@ func (p *ParsedTodo) ToCreateOptions() todo.CreateOptions {
 		ImplementationModel: p.ImplementationModel,
 		CodeReviewModel:     p.CodeReviewModel,
 		ProjectReviewModel:  p.ProjectReviewModel,
+		EstimateMinutes:     p.EstimateMinutes,
 	}
 	if p.Status != nil {
 		status := todo.Status(*p.Status)
@@ -252,6 +261,7 @@ func (p *ParsedTodo) ToUpdateOptions() todo.UpdateOptions {
 		ImplementationModel: &p.ImplementationModel,
 		CodeReviewModel:     &p.CodeReviewModel,
 		ProjectReviewModel:  &p.ProjectReviewModel,
+		EstimateMinutes:     &p.EstimateMinutes,
 	}
 
 	typ := todo.TodoType(p.Type)
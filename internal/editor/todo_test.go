@@ -113,6 +113,7 @@ func TestParseTodoTOML(t *testing.T) {
  implementation-model = "impl"
  code-review-model = "review"
  project-review-model = "project"
+ estimate-minutes = 45
  ---
  This is a description
  with multiple lines
@@ -144,6 +145,9 @@ func TestParseTodoTOML(t *testing.T) {
 	if parsed.ProjectReviewModel != "project" {
 		t.Errorf("expected project review model 'project', got %q", parsed.ProjectReviewModel)
 	}
+	if parsed.EstimateMinutes != 45 {
+		t.Errorf("expected estimate 45, got %d", parsed.EstimateMinutes)
+	}
 	if strings.Contains(parsed.Description, "description =") {
 		t.Errorf("expected description body without key, got %q", parsed.Description)
 	}
@@ -202,6 +206,14 @@ priority = 2
 status = "bad"`,
 			wantErr: "invalid status",
 		},
+		{
+			name: "negative estimate",
+			content: `title = "test"
+type = "task"
+priority = 2
+estimate-minutes = -5`,
+			wantErr: "estimate",
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,6 +255,7 @@ func TestToCreateOptions(t *testing.T) {
 		ImplementationModel: "impl",
 		CodeReviewModel:     "review",
 		ProjectReviewModel:  "project",
+		EstimateMinutes:     30,
 	}
 
 	opts := parsed.ToCreateOptions()
@@ -265,6 +278,9 @@ func TestToCreateOptions(t *testing.T) {
 	if opts.ProjectReviewModel != "project" {
 		t.Errorf("expected project review model 'project', got %q", opts.ProjectReviewModel)
 	}
+	if opts.EstimateMinutes != 30 {
+		t.Errorf("expected estimate 30, got %d", opts.EstimateMinutes)
+	}
 	if opts.Status != todo.StatusProposed {
 		t.Errorf("expected status proposed, got %v", opts.Status)
 	}
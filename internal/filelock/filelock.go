@@ -0,0 +1,48 @@
+// Package filelock takes advisory flock locks that can give up waiting.
+package filelock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	// InitialDelay is the wait before the first retry of a contended lock.
+	InitialDelay = 10 * time.Millisecond
+	// MaxDelay caps the wait between retries.
+	MaxDelay = 250 * time.Millisecond
+)
+
+// Exclusive takes an exclusive flock on file. While another process holds
+// it, Exclusive retries with exponential backoff from InitialDelay up to
+// MaxDelay until the lock frees up or ctx is done, in which case it returns
+// ctx.Err() unwrapped. A ctx that can never be done blocks in flock instead.
+func Exclusive(ctx context.Context, file *os.File) error {
+	fd := int(file.Fd())
+	if ctx.Done() == nil {
+		return syscall.Flock(fd, syscall.LOCK_EX)
+	}
+	delay := InitialDelay
+	for {
+		err := syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB)
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, MaxDelay)
+	}
+}
+
+// Unlock releases a lock taken by Exclusive.
+func Unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,42 @@
+package filelock
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExclusiveGivesUpWhenContextEnds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	holder, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		t.Fatalf("open holder: %v", err)
+	}
+	defer holder.Close()
+	if err := Exclusive(context.Background(), holder); err != nil {
+		t.Fatalf("lock holder: %v", err)
+	}
+
+	waiter, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		t.Fatalf("open waiter: %v", err)
+	}
+	defer waiter.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := Exclusive(ctx, waiter); err != context.DeadlineExceeded {
+		t.Fatalf("expected bare context.DeadlineExceeded, got %v", err)
+	}
+
+	if err := Unlock(holder); err != nil {
+		t.Fatalf("unlock holder: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Exclusive(ctx, waiter); err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package jj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConflictList(t *testing.T) {
+	output := []byte("main.go    2-sided conflict\ndocs/read me.md    3-sided conflict including 1 deletion\n\n")
+	got := parseConflictList(output)
+	want := []string{"main.go", "docs/read me.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
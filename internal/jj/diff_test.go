@@ -0,0 +1,24 @@
+package jj
+
+import "testing"
+
+func TestTruncateDiff(t *testing.T) {
+	diff := "diff --git a/x b/x\n+one\n+two\n"
+	tests := []struct {
+		name     string
+		maxBytes int
+		expected string
+	}{
+		{name: "no limit", maxBytes: 0, expected: diff},
+		{name: "fits", maxBytes: len(diff), expected: diff},
+		{name: "cut at line", maxBytes: len(diff) - 2, expected: "diff --git a/x b/x\n+one\n" + DiffTruncatedMarker + "\n"},
+		{name: "shorter than first line", maxBytes: 4, expected: DiffTruncatedMarker + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateDiff(diff, tt.maxBytes); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -36,3 +36,17 @@ func TestIsFileNotFoundOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestIsRevisionNotFoundOutput(t *testing.T) {
+	for output, expected := range map[string]bool{
+		`Error: Revision "mian" doesn't exist`:        true,
+		"Error: Revision `abc` does not exist":        true,
+		"Error: No such revision: abc":                true,
+		`Error: There is no jj repo in "."`:           false,
+		"Error: Failed to parse revset: syntax error": false,
+	} {
+		if got := isRevisionNotFoundOutput([]byte(output)); got != expected {
+			t.Fatalf("%q: expected %v, got %v", output, expected, got)
+		}
+	}
+}
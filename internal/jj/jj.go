@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	internalstrings "github.com/amonks/incrementum/internal/strings"
@@ -21,7 +22,12 @@ func New() *Client {
 	return &Client{}
 }
 
+// commandOutput runs cmd and returns its stdout. Like every jj invocation,
+// it first runs the cached capability check.
 func commandOutput(cmd *exec.Cmd, context string) ([]byte, error) {
+	if _, err := CheckInstalled(); err != nil {
+		return nil, fmt.Errorf("%s: %w", context, err)
+	}
 	output, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -54,6 +60,9 @@ func splitTrimmedLines(output []byte) []string {
 }
 
 func commandCombinedOutput(cmd *exec.Cmd, context string) ([]byte, error) {
+	if _, err := CheckInstalled(); err != nil {
+		return nil, fmt.Errorf("%s: %w", context, err)
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return output, fmt.Errorf("%s: %w: %s", context, err, output)
@@ -165,6 +174,14 @@ func (c *Client) BookmarkCreate(workspacePath, name, rev string) error {
 	return runCombinedOutput(cmd, "jj bookmark create")
 }
 
+// BookmarkSet points a bookmark at the specified revision, creating it if it
+// does not exist. Moving the bookmark backwards or sideways is allowed.
+func (c *Client) BookmarkSet(workspacePath, name, rev string) error {
+	cmd := exec.Command("jj", "bookmark", "set", name, "-r", rev, "--allow-backwards")
+	cmd.Dir = workspacePath
+	return runCombinedOutput(cmd, "jj bookmark set")
+}
+
 // NewChange creates a new change with the given parent revision.
 // Returns the change ID of the newly created change.
 // Note: This moves the working copy to the new change.
@@ -206,6 +223,21 @@ func (c *Client) CommitIDAt(workspacePath, rev string) (string, error) {
 	return logFieldAt(workspacePath, rev, "commit_id")
 }
 
+// RevisionExists reports whether rev resolves to at least one commit in the
+// repository at repoPath. A revision jj reports as missing is not an error.
+func (c *Client) RevisionExists(repoPath, rev string) (bool, error) {
+	cmd := exec.Command("jj", "log", "-r", rev, "--no-graph", "--limit", "1", "-T", "commit_id")
+	cmd.Dir = repoPath
+	output, err := commandCombinedOutput(cmd, "jj log")
+	if err != nil {
+		if isRevisionNotFoundOutput(output) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !internalstrings.IsBlank(string(output)), nil
+}
+
 // DiffStat returns the diff stat between two revisions.
 func (c *Client) DiffStat(workspacePath, from, to string) (string, error) {
 	cmd := exec.Command("jj", "diff", "--from", from, "--to", to, "--stat")
@@ -217,6 +249,35 @@ func (c *Client) DiffStat(workspacePath, from, to string) (string, error) {
 	return string(output), nil
 }
 
+// DiffTruncatedMarker ends a DiffPreview that was cut short.
+const DiffTruncatedMarker = "[diff truncated]"
+
+// DiffPreview returns the git-format diff between two revisions. Diffs longer
+// than maxBytes are cut at the last line boundary within the limit and end
+// with a DiffTruncatedMarker line. A maxBytes of zero or less means no limit.
+func (c *Client) DiffPreview(workspacePath, from, to string, maxBytes int) (string, error) {
+	cmd := exec.Command("jj", "diff", "--from", from, "--to", to, "--git")
+	cmd.Dir = workspacePath
+	output, err := commandCombinedOutput(cmd, "jj diff --git")
+	if err != nil {
+		return "", err
+	}
+	return truncateDiff(string(output), maxBytes), nil
+}
+
+func truncateDiff(diff string, maxBytes int) string {
+	if maxBytes <= 0 || len(diff) <= maxBytes {
+		return diff
+	}
+	cut := diff[:maxBytes]
+	if idx := strings.LastIndexByte(cut, '\n'); idx >= 0 {
+		cut = cut[:idx+1]
+	} else {
+		cut = ""
+	}
+	return cut + DiffTruncatedMarker + "\n"
+}
+
 // DescriptionAt returns the description at the given revision.
 func (c *Client) DescriptionAt(workspacePath, rev string) (string, error) {
 	return logFieldAt(workspacePath, rev, "description")
@@ -255,6 +316,99 @@ func (c *Client) WorkspaceUpdateStale(workspacePath string) error {
 	return runCombinedOutput(cmd, "jj workspace update-stale")
 }
 
+// Status runs jj status, which fails when the working copy is stale or
+// otherwise unusable.
+func (c *Client) Status(workspacePath string) error {
+	cmd := exec.Command("jj", "status")
+	cmd.Dir = workspacePath
+	return runCombinedOutput(cmd, "jj status")
+}
+
+// Rebase moves branchRev, along with its ancestors that are not already
+// ancestors of destRev, onto destRev (jj rebase -b).
+func (c *Client) Rebase(workspacePath, branchRev, destRev string) error {
+	cmd := exec.Command("jj", "rebase", "-b", branchRev, "-d", destRev)
+	cmd.Dir = workspacePath
+	return runCombinedOutput(cmd, "jj rebase")
+}
+
+// CurrentOperationID returns the ID of the repository's latest operation,
+// for use with OperationRevert. It does not snapshot the working copy, so
+// reading it adds no operation of its own.
+func (c *Client) CurrentOperationID(workspacePath string) (string, error) {
+	cmd := exec.Command("jj", "op", "log", "--ignore-working-copy", "--limit", "1", "--no-graph", "-T", "id")
+	cmd.Dir = workspacePath
+	return commandOutputString(cmd, "jj op log")
+}
+
+// OperationRevert undoes the changes made by the given operation alone,
+// keeping any operations recorded after it (jj op revert, or jj op undo
+// before 0.33).
+func (c *Client) OperationRevert(workspacePath, operationID string) error {
+	version, err := CheckInstalled()
+	if err != nil {
+		return fmt.Errorf("jj op revert: %w", err)
+	}
+	subcommand := "revert"
+	if version.Less(opRevertVersion) {
+		subcommand = "undo"
+	}
+	cmd := exec.Command("jj", "op", subcommand, operationID)
+	cmd.Dir = workspacePath
+	return runCombinedOutput(cmd, "jj op "+subcommand)
+}
+
+// ConflictedChangeIDs returns the change IDs of the commits in revset that
+// have unresolved conflicts.
+func (c *Client) ConflictedChangeIDs(workspacePath, revset string) ([]string, error) {
+	cmd := exec.Command("jj", "log", "-r", "("+revset+") & conflicts()", "--no-graph", "-T", "change_id ++ \"\\n\"")
+	cmd.Dir = workspacePath
+	output, err := commandOutput(cmd, "jj log")
+	if err != nil {
+		return nil, err
+	}
+	return splitTrimmedLines(output), nil
+}
+
+// HasConflicts reports whether the working-copy commit has unresolved
+// conflicts.
+func (c *Client) HasConflicts(workspacePath string) (bool, error) {
+	ids, err := c.ConflictedChangeIDs(workspacePath, "@")
+	if err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// conflictListSuffix matches the conflict description jj resolve --list
+// prints after each path, such as "2-sided conflict".
+var conflictListSuffix = regexp.MustCompile(`\s+\d+-sided conflict.*$`)
+
+// ConflictedPaths returns the paths with unresolved conflicts at rev, as
+// listed by jj resolve --list. A revision without conflicts returns no paths.
+func (c *Client) ConflictedPaths(workspacePath, rev string) ([]string, error) {
+	cmd := exec.Command("jj", "resolve", "--list", "-r", rev)
+	cmd.Dir = workspacePath
+	output, err := commandCombinedOutput(cmd, "jj resolve --list")
+	if err != nil {
+		if internalstrings.ContainsAnyLower(string(output), "no conflicts found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseConflictList(output), nil
+}
+
+// parseConflictList extracts the paths from jj resolve --list output.
+func parseConflictList(output []byte) []string {
+	lines := splitTrimmedLines(output)
+	paths := make([]string, 0, len(lines))
+	for _, line := range lines {
+		paths = append(paths, conflictListSuffix.ReplaceAllString(line, ""))
+	}
+	return paths
+}
+
 // WorkspaceForget removes a workspace from the repository without deleting it from disk.
 func (c *Client) WorkspaceForget(repoPath, workspaceName string) error {
 	cmd := exec.Command("jj", "workspace", "forget", workspaceName)
@@ -284,3 +438,11 @@ func isFileNotFoundOutput(output []byte) bool {
 		"path doesn't exist",
 	)
 }
+
+func isRevisionNotFoundOutput(output []byte) bool {
+	return internalstrings.ContainsAnyLower(string(output),
+		"doesn't exist",
+		"does not exist",
+		"no such revision",
+	)
+}
@@ -234,6 +234,125 @@ func TestBookmarkCreate_WithSlash(t *testing.T) {
 	}
 }
 
+func TestBookmarkSet_CreatesAndMoves(t *testing.T) {
+	tmpDir := t.TempDir()
+	client := jj.New()
+
+	if err := client.Init(tmpDir); err != nil {
+		t.Fatalf("failed to init jj repo: %v", err)
+	}
+
+	// Setting a missing bookmark creates it
+	if err := client.BookmarkSet(tmpDir, "feature", "@"); err != nil {
+		t.Fatalf("failed to set bookmark: %v", err)
+	}
+	bookmarks, err := client.BookmarkList(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to list bookmarks: %v", err)
+	}
+	if len(bookmarks) != 1 || bookmarks[0] != "feature" {
+		t.Fatalf("expected bookmark 'feature', got %v", bookmarks)
+	}
+
+	if err := client.Commit(tmpDir, "first"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := client.BookmarkSet(tmpDir, "feature", "@-"); err != nil {
+		t.Fatalf("failed to move bookmark: %v", err)
+	}
+	target, err := client.CommitIDAt(tmpDir, "feature")
+	if err != nil {
+		t.Fatalf("failed to resolve bookmark: %v", err)
+	}
+	want, err := client.CommitIDAt(tmpDir, "@-")
+	if err != nil {
+		t.Fatalf("failed to resolve @-: %v", err)
+	}
+	if target != want {
+		t.Errorf("expected bookmark at %s, got %s", want, target)
+	}
+}
+
+func TestOperationRevert_KeepsLaterOperations(t *testing.T) {
+	tmpDir := t.TempDir()
+	client := jj.New()
+
+	if err := client.Init(tmpDir); err != nil {
+		t.Fatalf("failed to init jj repo: %v", err)
+	}
+	file := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(file, []byte("base\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := client.Commit(tmpDir, "base"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	baseID, err := client.ChangeIDAt(tmpDir, "@-")
+	if err != nil {
+		t.Fatalf("failed to get change id: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("mine\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := client.Commit(tmpDir, "mine"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// A second workspace commits a conflicting edit on top of base.
+	otherPath := filepath.Join(t.TempDir(), "other")
+	if err := client.WorkspaceAdd(tmpDir, "other", otherPath); err != nil {
+		t.Fatalf("failed to add workspace: %v", err)
+	}
+	if _, err := client.NewChange(otherPath, baseID); err != nil {
+		t.Fatalf("failed to create change: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(otherPath, "file.txt"), []byte("theirs\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := client.Commit(otherPath, "theirs"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	theirsID, err := client.ChangeIDAt(otherPath, "@-")
+	if err != nil {
+		t.Fatalf("failed to get change id: %v", err)
+	}
+
+	if err := client.Rebase(tmpDir, "@", theirsID); err != nil {
+		t.Fatalf("failed to rebase: %v", err)
+	}
+	rebaseOp, err := client.CurrentOperationID(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to get operation id: %v", err)
+	}
+	if conflicted, err := client.ConflictedChangeIDs(tmpDir, "all()"); err != nil || len(conflicted) == 0 {
+		t.Fatalf("expected the rebase to conflict, got %v, %v", conflicted, err)
+	}
+
+	// Another operation lands before the rebase is reverted.
+	if err := client.Describe(otherPath, "later"); err != nil {
+		t.Fatalf("failed to describe: %v", err)
+	}
+	laterID, err := client.CurrentChangeID(otherPath)
+	if err != nil {
+		t.Fatalf("failed to get change id: %v", err)
+	}
+
+	if err := client.OperationRevert(tmpDir, rebaseOp); err != nil {
+		t.Fatalf("failed to revert operation: %v", err)
+	}
+
+	if conflicted, err := client.ConflictedChangeIDs(tmpDir, "all()"); err != nil || len(conflicted) != 0 {
+		t.Fatalf("expected the rebase to be reverted, got %v, %v", conflicted, err)
+	}
+	description, err := client.DescriptionAt(tmpDir, laterID)
+	if err != nil {
+		t.Fatalf("failed to get description: %v", err)
+	}
+	if internalstrings.TrimSpace(description) != "later" {
+		t.Errorf("expected the later operation to survive, got description %q", description)
+	}
+}
+
 func TestNewChange(t *testing.T) {
 	tmpDir := t.TempDir()
 	client := jj.New()
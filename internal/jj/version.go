@@ -0,0 +1,106 @@
+package jj
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+var (
+	// ErrNotFound indicates the jj executable is not on PATH.
+	ErrNotFound = errors.New("jj (jujutsu) not found on PATH")
+
+	// ErrVersionUnsupported indicates the installed jj is older than
+	// MinVersion.
+	ErrVersionUnsupported = errors.New("unsupported jj (jujutsu) version")
+)
+
+// MinVersion is the oldest jj release this package supports. The bookmark
+// commands first appeared in 0.22.
+var MinVersion = Version{Major: 0, Minor: 22}
+
+// opRevertVersion is the release that renamed jj op undo to jj op revert.
+var opRevertVersion = Version{Major: 0, Minor: 33}
+
+// Version is a jj release version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String formats the version as major.minor.patch.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than other.
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// ParseVersion parses `jj --version` output such as "jj 0.28.2" or
+// "jj 0.28.2-abc123". A missing patch component is zero.
+func ParseVersion(output string) (Version, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "jj" {
+		return Version{}, fmt.Errorf("parse jj version %q: unexpected format", internalstrings.TrimSpace(output))
+	}
+	number, _, _ := strings.Cut(fields[1], "-")
+	number, _, _ = strings.Cut(number, "+")
+	parts := strings.Split(number, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("parse jj version %q: unexpected format", fields[1])
+	}
+	values := make([]int, 3)
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return Version{}, fmt.Errorf("parse jj version %q: unexpected format", fields[1])
+		}
+		values[i] = value
+	}
+	return Version{Major: values[0], Minor: values[1], Patch: values[2]}, nil
+}
+
+// checkInstalled runs the jj capability check once per process.
+var checkInstalled = sync.OnceValues(detectVersion)
+
+// CheckInstalled verifies that jj is on PATH and at least MinVersion,
+// returning the detected version. It returns an error wrapping ErrNotFound or
+// ErrVersionUnsupported otherwise. The check runs once per process; later
+// calls return the cached result.
+func CheckInstalled() (Version, error) {
+	return checkInstalled()
+}
+
+func detectVersion() (Version, error) {
+	path, err := exec.LookPath("jj")
+	if err != nil {
+		return Version{}, ErrNotFound
+	}
+	// Run directly: commandOutput would recurse into this check.
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return Version{}, fmt.Errorf("jj --version: %w", err)
+	}
+	version, err := ParseVersion(string(output))
+	if err != nil {
+		return Version{}, err
+	}
+	if version.Less(MinVersion) {
+		return version, fmt.Errorf("%w: found %s, need %s or newer", ErrVersionUnsupported, version, MinVersion)
+	}
+	return version, nil
+}
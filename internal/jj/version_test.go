@@ -0,0 +1,41 @@
+package jj
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Version
+	}{
+		{"jj 0.28.2\n", Version{Major: 0, Minor: 28, Patch: 2}},
+		{"jj 0.22.0-2a1b3c4d", Version{Major: 0, Minor: 22}},
+		{"jj 1.3", Version{Major: 1, Minor: 3}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.output)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.output, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parse %q: expected %v, got %v", tt.output, tt.want, got)
+		}
+	}
+
+	for _, output := range []string{"", "git version 2.40.0", "jj nightly", "jj 1.2.3.4"} {
+		if _, err := ParseVersion(output); err == nil {
+			t.Fatalf("expected error parsing %q", output)
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	if !(Version{Minor: 21, Patch: 9}).Less(MinVersion) {
+		t.Fatal("expected 0.21.9 to be older than the minimum")
+	}
+	if (Version{Minor: 22}).Less(MinVersion) {
+		t.Fatal("expected the minimum not to be older than itself")
+	}
+	if (Version{Major: 1}).Less(MinVersion) {
+		t.Fatal("expected 1.0.0 to be newer than the minimum")
+	}
+}
@@ -35,13 +35,30 @@ func normalizeWorkingDir(cwd string) string {
 	return cwd
 }
 
-// DefaultStateDir returns the default incrementum state directory.
+const (
+	// StateDirEnvVar overrides the default state directory.
+	StateDirEnvVar = "INCREMENTUM_STATE_DIR"
+
+	// WorkspacesDirEnvVar overrides the default workspaces directory.
+	WorkspacesDirEnvVar = "INCREMENTUM_WORKSPACES_DIR"
+)
+
+// DefaultStateDir returns the default incrementum state directory:
+// $INCREMENTUM_STATE_DIR when set, otherwise ~/.local/state/incrementum.
 func DefaultStateDir() (string, error) {
+	if dir := os.Getenv(StateDirEnvVar); dir != "" {
+		return dir, nil
+	}
 	return defaultHomeDirPath(".local", "state", "incrementum")
 }
 
-// DefaultWorkspacesDir returns the default incrementum workspaces directory.
+// DefaultWorkspacesDir returns the default incrementum workspaces directory:
+// $INCREMENTUM_WORKSPACES_DIR when set, otherwise
+// ~/.local/share/incrementum/workspaces.
 func DefaultWorkspacesDir() (string, error) {
+	if dir := os.Getenv(WorkspacesDirEnvVar); dir != "" {
+		return dir, nil
+	}
 	return defaultHomeDirPath(".local", "share", "incrementum", "workspaces")
 }
 
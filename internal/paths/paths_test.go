@@ -20,6 +20,27 @@ func TestDefaultStateDirUsesHome(t *testing.T) {
 	}
 }
 
+func TestDefaultStateDirPrefersEnv(t *testing.T) {
+	t.Setenv("HOME", filepath.Join("/tmp", "test-home"))
+	t.Setenv(StateDirEnvVar, filepath.Join("/tmp", "custom-state"))
+
+	dir, err := DefaultStateDir()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dir != filepath.Join("/tmp", "custom-state") {
+		t.Fatalf("expected env state dir, got %s", dir)
+	}
+
+	override, err := ResolveWithDefault("/explicit", DefaultStateDir)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if override != "/explicit" {
+		t.Fatalf("expected explicit dir to win, got %s", override)
+	}
+}
+
 func TestHomeDirUsesHome(t *testing.T) {
 	t.Setenv("HOME", filepath.Join("/tmp", "test-home"))
 
@@ -47,6 +68,19 @@ func TestDefaultWorkspacesDirUsesHome(t *testing.T) {
 	}
 }
 
+func TestDefaultWorkspacesDirPrefersEnv(t *testing.T) {
+	t.Setenv("HOME", filepath.Join("/tmp", "test-home"))
+	t.Setenv(WorkspacesDirEnvVar, filepath.Join("/tmp", "custom-workspaces"))
+
+	dir, err := DefaultWorkspacesDir()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dir != filepath.Join("/tmp", "custom-workspaces") {
+		t.Fatalf("expected env workspaces dir, got %s", dir)
+	}
+}
+
 func TestDefaultOpencodeEventsDirUsesHome(t *testing.T) {
 	t.Setenv("HOME", filepath.Join("/tmp", "test-home"))
 
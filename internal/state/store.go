@@ -2,14 +2,15 @@ package state
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
-	"syscall"
 
+	"github.com/amonks/incrementum/internal/filelock"
 	"github.com/amonks/incrementum/internal/paths"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
@@ -164,6 +165,17 @@ func (s *Store) Save(st *State) error {
 
 // Update atomically reads, modifies, and writes the state with file locking.
 func (s *Store) Update(fn func(st *State) error) error {
+	return s.UpdateContext(context.Background(), fn)
+}
+
+// UpdateContext is Update with cancellation. While another process holds the
+// lock it retries until the lock frees up or ctx is done, and it gives up
+// without saving when ctx is done after loading or modifying the state.
+// Whenever ctx ends the update, the returned error is ctx.Err().
+func (s *Store) UpdateContext(ctx context.Context, fn func(st *State) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if err := s.ensureStateDir(); err != nil {
 		return err
 	}
@@ -176,21 +188,30 @@ func (s *Store) Update(fn func(st *State) error) error {
 	defer lockFile.Close()
 
 	// Acquire exclusive lock
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+	if err := filelock.Exclusive(ctx, lockFile); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		return fmt.Errorf("acquire lock: %w", err)
 	}
-	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+	defer filelock.Unlock(lockFile)
 
 	// Load current state
 	st, err := s.Load()
 	if err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Apply modifications
 	if err := fn(st); err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Save updated state
 	return s.Save(st)
@@ -221,9 +242,15 @@ func (s *Store) RepoPathForWorkspace(wsPath string) (string, bool, error) {
 // GetOrCreateRepoName returns the repo name for the given source path,
 // creating a new entry if needed. Handles collisions by appending suffixes.
 func (s *Store) GetOrCreateRepoName(sourcePath string) (string, error) {
+	return s.GetOrCreateRepoNameContext(context.Background(), sourcePath)
+}
+
+// GetOrCreateRepoNameContext is GetOrCreateRepoName with cancellation while
+// waiting for the state lock.
+func (s *Store) GetOrCreateRepoNameContext(ctx context.Context, sourcePath string) (string, error) {
 	var result string
 
-	err := s.Update(func(st *State) error {
+	err := s.UpdateContext(ctx, func(st *State) error {
 		// Check if this path already has a name
 		for name, info := range st.Repos {
 			if info.SourcePath == sourcePath {
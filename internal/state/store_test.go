@@ -1,9 +1,11 @@
 package state
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -215,6 +217,42 @@ func TestStore_ConcurrentUpdates(t *testing.T) {
 	}
 }
 
+func TestStore_UpdateContextCanceledWhileLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	holder, err := os.OpenFile(store.lockPath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("open lock file: %v", err)
+	}
+	defer holder.Close()
+	if err := syscall.Flock(int(holder.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatalf("hold lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = store.UpdateContext(ctx, func(st *State) error {
+		t.Fatal("expected update not to run while the lock is held")
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected bare deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected update to give up promptly, took %s", elapsed)
+	}
+
+	// Once the lock frees up, updates go through.
+	if err := syscall.Flock(int(holder.Fd()), syscall.LOCK_UN); err != nil {
+		t.Fatalf("release lock: %v", err)
+	}
+	if err := store.UpdateContext(context.Background(), func(st *State) error { return nil }); err != nil {
+		t.Fatalf("update after release: %v", err)
+	}
+}
+
 func TestSanitizeRepoName(t *testing.T) {
 	tests := []struct {
 		input    string
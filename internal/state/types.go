@@ -47,17 +47,23 @@ func (s WorkspaceStatus) IsValid() bool {
 
 // WorkspaceInfo stores information about a workspace.
 type WorkspaceInfo struct {
-	Name          string          `json:"name"`
-	Repo          string          `json:"repo"`
-	Path          string          `json:"path"`
-	Purpose       string          `json:"purpose,omitempty"`
-	Rev           string          `json:"rev,omitempty"`
-	Status        WorkspaceStatus `json:"status"`
-	AcquiredByPID int             `json:"acquired_by_pid,omitempty"`
-	CreatedAt     time.Time       `json:"created_at,omitempty"`
-	UpdatedAt     time.Time       `json:"updated_at,omitempty"`
-	AcquiredAt    time.Time       `json:"acquired_at,omitempty"`
-	Provisioned   bool            `json:"provisioned"`
+	Name           string            `json:"name"`
+	Repo           string            `json:"repo"`
+	Path           string            `json:"path"`
+	Purpose        string            `json:"purpose,omitempty"`
+	Pool           string            `json:"pool,omitempty"`
+	Rev            string            `json:"rev,omitempty"`
+	Status         WorkspaceStatus   `json:"status"`
+	AcquiredByPID  int               `json:"acquired_by_pid,omitempty"`
+	AcquiredByHost string            `json:"acquired_by_host,omitempty"`
+	CreatedAt      time.Time         `json:"created_at,omitempty"`
+	UpdatedAt      time.Time         `json:"updated_at,omitempty"`
+	AcquiredAt     time.Time         `json:"acquired_at,omitempty"`
+	RefreshedAt    time.Time         `json:"refreshed_at,omitempty"`
+	LastUsedAt     time.Time         `json:"last_used_at,omitempty"`
+	Provisioned    bool              `json:"provisioned"`
+	Unhealthy      bool              `json:"unhealthy,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
 }
 
 // OpencodeSessionStatus represents the state of an opencode session.
@@ -124,11 +130,14 @@ const (
 	JobStatusFailed JobStatus = "failed"
 	// JobStatusAbandoned indicates the job was abandoned.
 	JobStatusAbandoned JobStatus = "abandoned"
+	// JobStatusPaused indicates the job stopped at a stage boundary and can
+	// be resumed.
+	JobStatusPaused JobStatus = "paused"
 )
 
 // ValidJobStatuses returns all valid job status values.
 func ValidJobStatuses() []JobStatus {
-	return []JobStatus{JobStatusActive, JobStatusCompleted, JobStatusFailed, JobStatusAbandoned}
+	return []JobStatus{JobStatusActive, JobStatusCompleted, JobStatusFailed, JobStatusAbandoned, JobStatusPaused}
 }
 
 // IsValid returns true if the status is a known value.
@@ -155,12 +164,19 @@ func (o ReviewOutcome) IsValid() bool {
 	return validation.IsValidValue(o, ValidReviewOutcomes())
 }
 
+// ReviewFileComment is a reviewer's request to change one file.
+type ReviewFileComment struct {
+	File    string `json:"file"`
+	Comment string `json:"comment"`
+}
+
 // JobReview captures a review decision for a commit or the project.
 type JobReview struct {
-	Outcome           ReviewOutcome `json:"outcome"`
-	Comments          string        `json:"comments,omitempty"`
-	OpencodeSessionID string        `json:"opencode_session_id"`
-	ReviewedAt        time.Time     `json:"reviewed_at"`
+	Outcome           ReviewOutcome       `json:"outcome"`
+	Comments          string              `json:"comments,omitempty"`
+	FileComments      []ReviewFileComment `json:"file_comments,omitempty"`
+	OpencodeSessionID string              `json:"opencode_session_id"`
+	ReviewedAt        time.Time           `json:"reviewed_at"`
 }
 
 // JobCommit represents one commit within a change.
@@ -204,6 +220,7 @@ type Job struct {
 	ImplementationModel string               `json:"implementation_model,omitempty"`
 	CodeReviewModel     string               `json:"code_review_model,omitempty"`
 	ProjectReviewModel  string               `json:"project_review_model,omitempty"`
+	Workspace           string               `json:"workspace,omitempty"`
 	Stage               JobStage             `json:"stage"`
 	Feedback            string               `json:"feedback,omitempty"`
 	OpencodeSessions    []JobOpencodeSession `json:"opencode_sessions,omitempty"`
@@ -212,6 +229,7 @@ type Job struct {
 	// ProjectReview captures the final project review (after all changes complete).
 	ProjectReview *JobReview `json:"project_review,omitempty"`
 	Status        JobStatus  `json:"status"`
+	Reason        string     `json:"reason,omitempty"`
 	CreatedAt     time.Time  `json:"created_at,omitempty"`
 	StartedAt     time.Time  `json:"started_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
@@ -234,6 +252,26 @@ func (j *Job) CurrentChange() *JobChange {
 	return last
 }
 
+// AllTestsPassed summarizes the commits' test results: nil when no commit
+// has been tested, true when every tested commit passed, false when any
+// failed.
+func (j *Job) AllTestsPassed() *bool {
+	var result *bool
+	for _, change := range j.Changes {
+		for _, commit := range change.Commits {
+			if commit.TestsPassed == nil {
+				continue
+			}
+			passed := *commit.TestsPassed
+			if !passed {
+				return &passed
+			}
+			result = &passed
+		}
+	}
+	return result
+}
+
 // CurrentCommit returns the current in-progress commit.
 func (j *Job) CurrentCommit() *JobCommit {
 	change := j.CurrentChange()
@@ -11,6 +11,7 @@ import (
 const (
 	ansiBold  = "\x1b[1m"
 	ansiCyan  = "\x1b[36m"
+	ansiRed   = "\x1b[31m"
 	ansiReset = "\x1b[0m"
 )
 
@@ -33,6 +34,15 @@ func HighlightID(id string, prefixLen int) string {
 	return ansiBold + ansiCyan + prefix + ansiReset + suffix
 }
 
+// HighlightWarning returns value colored to draw attention, such as an
+// overdue date.
+func HighlightWarning(value string) string {
+	if value == "" || !ansiEnabled() {
+		return value
+	}
+	return ansiBold + ansiRed + value + ansiReset
+}
+
 func ansiEnabled() bool {
 	if os.Getenv("NO_COLOR") != "" {
 		return false
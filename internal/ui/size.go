@@ -0,0 +1,26 @@
+package ui
+
+import "fmt"
+
+// FormatBytes formats a byte count using binary units (B/KB/MB/GB/TB).
+func FormatBytes(size int64) string {
+	if size < 0 {
+		size = 0
+	}
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	value := float64(size)
+	suffixes := []string{"KB", "MB", "GB", "TB"}
+	suffix := ""
+	for _, next := range suffixes {
+		value /= unit
+		suffix = next
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: -1, want: "0B"},
+		{size: 0, want: "0B"},
+		{size: 1023, want: "1023B"},
+		{size: 1024, want: "1.0KB"},
+		{size: 1536, want: "1.5KB"},
+		{size: 5 * 1024 * 1024, want: "5.0MB"},
+		{size: 3 * 1024 * 1024 * 1024, want: "3.0GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.size); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
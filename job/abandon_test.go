@@ -0,0 +1,101 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHandleStageOutcomeRecordsAbandonReason(t *testing.T) {
+	stateDir := t.TempDir()
+	eventLogOpts := EventLogOptions{EventsDir: t.TempDir()}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/abandon-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	status := StatusAbandoned
+	abandoned, err := manager.Update(created.ID, UpdateOptions{Status: &status}, now)
+	if err != nil {
+		t.Fatalf("abandon job: %v", err)
+	}
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	ctx := runContext{
+		opts:    RunOptions{Now: func() time.Time { return now }, EventLog: eventLog},
+		manager: manager,
+		result:  &RunResult{},
+	}
+	stageErr := &AbandonedError{Reason: "The todo duplicates existing behavior."}
+	recorded, err := ctx.handleStageOutcome(created, abandoned, stageErr)
+	if !errors.Is(err, ErrJobAbandoned) {
+		t.Fatalf("expected ErrJobAbandoned, got %v", err)
+	}
+	if recorded.Reason != stageErr.Reason {
+		t.Fatalf("expected reason %q, got %q", stageErr.Reason, recorded.Reason)
+	}
+	if ctx.result.Job.Reason != stageErr.Reason {
+		t.Fatalf("expected result job to carry the reason, got %q", ctx.result.Job.Reason)
+	}
+	found, err := manager.Find(created.ID)
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if found.Reason != stageErr.Reason || found.Status != StatusAbandoned {
+		t.Fatalf("expected stored abandoned job with reason, got %+v", found)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventAbandoned {
+		t.Fatalf("expected one abandoned event, got %+v", events)
+	}
+	if events[0].Data != `{"reason":"The todo duplicates existing behavior."}` {
+		t.Fatalf("unexpected abandoned event data %s", events[0].Data)
+	}
+}
+
+func TestHabitHandleStageOutcomeRecordsAbandonReason(t *testing.T) {
+	stateDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/abandon-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("habit:cleanup", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	status := StatusAbandoned
+	abandoned, err := manager.Update(created.ID, UpdateOptions{Status: &status}, now)
+	if err != nil {
+		t.Fatalf("abandon job: %v", err)
+	}
+
+	ctx := habitRunContext{
+		opts:    HabitRunOptions{Now: func() time.Time { return now }},
+		manager: manager,
+		result:  &HabitRunResult{},
+	}
+	recorded, err := ctx.handleStageOutcome(created, abandoned, &AbandonedError{Reason: "Nothing to clean up."})
+	if err != nil {
+		t.Fatalf("expected habit abandon to succeed, got %v", err)
+	}
+	if recorded.Reason != "Nothing to clean up." || !ctx.result.Abandoned {
+		t.Fatalf("expected abandoned habit job with reason, got %+v", recorded)
+	}
+}
@@ -0,0 +1,29 @@
+package job
+
+import (
+	"fmt"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+type bookmarkUpdatedEventData struct {
+	Bookmark string `json:"bookmark"`
+	CommitID string `json:"commit_id"`
+}
+
+// moveJobBookmark points RunOptions.Bookmark at a commit the job just made,
+// recording a job.bookmark_updated event. It does nothing when no bookmark
+// is configured.
+func moveJobBookmark(opts RunOptions, workspacePath, commitID string) error {
+	bookmark := internalstrings.TrimSpace(opts.Bookmark)
+	if bookmark == "" {
+		return nil
+	}
+	if opts.MoveBookmark == nil {
+		return fmt.Errorf("move bookmark is required")
+	}
+	if err := opts.MoveBookmark(workspacePath, bookmark, commitID); err != nil {
+		return fmt.Errorf("move bookmark %s: %w", bookmark, err)
+	}
+	return appendJobEvent(opts.EventLog, jobEventBookmarkUpdated, bookmarkUpdatedEventData{Bookmark: bookmark, CommitID: commitID})
+}
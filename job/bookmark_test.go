@@ -0,0 +1,104 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRunCommittingStageMovesBookmarkToCommit(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/bookmark-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	logOpts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	workspacePath := t.TempDir()
+	committed := false
+	type moveCall struct {
+		workspacePath, bookmark, commit string
+	}
+	var moves []moveCall
+	_, err = runCommittingStage(CommittingStageOptions{
+		Manager:       manager,
+		Current:       created,
+		WorkspacePath: workspacePath,
+		RunOptions: RunOptions{
+			Now:      func() time.Time { return now },
+			EventLog: eventLog,
+			DiffStat: func(string, string, string) (string, error) {
+				return "main.go | 1 +\n1 file changed, 1 insertion(+)", nil
+			},
+			DiffPreview: func(string, string, string, int) (string, error) {
+				return "", nil
+			},
+			Commit: func(string, string) error {
+				committed = true
+				return nil
+			},
+			CommitIDAt: func(_, rev string) (string, error) {
+				if rev != "@-" {
+					t.Fatalf("expected commit lookup at @-, got %q", rev)
+				}
+				return "commit-abc", nil
+			},
+			Bookmark: "feature/login",
+			MoveBookmark: func(path, bookmark, commit string) error {
+				if !committed {
+					t.Fatalf("expected bookmark to move after committing")
+				}
+				moves = append(moves, moveCall{path, bookmark, commit})
+				return nil
+			},
+		},
+		Result:        &RunResult{},
+		CommitMessage: "feat: add login",
+	})
+	if err != nil {
+		t.Fatalf("run committing stage: %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	want := moveCall{workspacePath, "feature/login", "commit-abc"}
+	if len(moves) != 1 || moves[0] != want {
+		t.Fatalf("expected bookmark move %+v, got %+v", want, moves)
+	}
+
+	events, err := EventSnapshot(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Name != jobEventBookmarkUpdated {
+		t.Fatalf("expected %s event last, got %#v", jobEventBookmarkUpdated, events)
+	}
+	var data bookmarkUpdatedEventData
+	if err := json.Unmarshal([]byte(last.Data), &data); err != nil {
+		t.Fatalf("decode bookmark event: %v", err)
+	}
+	if data.Bookmark != "feature/login" || data.CommitID != "commit-abc" {
+		t.Fatalf("unexpected bookmark event: %+v", data)
+	}
+}
+
+func TestMoveJobBookmarkSkipsWithoutBookmark(t *testing.T) {
+	if err := moveJobBookmark(RunOptions{
+		MoveBookmark: func(string, string, string) error {
+			t.Fatalf("expected no bookmark move without RunOptions.Bookmark")
+			return nil
+		},
+	}, "/tmp/ws", "commit-abc"); err != nil {
+		t.Fatalf("move bookmark: %v", err)
+	}
+}
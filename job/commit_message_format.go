@@ -1,13 +1,93 @@
 package job
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"text/template"
 
+	"github.com/amonks/incrementum/internal/config"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 	"github.com/amonks/incrementum/todo"
 )
 
+// commitMessageTemplateName is the optional override for the final commit
+// message layout. Unlike prompt templates it has no bundled default; when the
+// override is absent the fixed layout from formatCommitMessageWithWidth is used.
+const commitMessageTemplateName = "commit-message.tmpl"
+
+// CommitMessageData supplies values to a commit-message.tmpl override.
+type CommitMessageData struct {
+	Todo           todo.Todo
+	Message        string
+	Summary        string
+	Body           string
+	ReviewComments string
+	// TodoBlock is the todo block used by the default layout, at the
+	// rendering width.
+	TodoBlock string
+}
+
+// renderCommitMessage formats a draft commit message through the repo's
+// commit-message.tmpl override, falling back to the default layout.
+func renderCommitMessage(repoPath string, item todo.Todo, message, reviewComments string, width int) (string, error) {
+	contents, ok, err := loadCommitMessageTemplate(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return formatCommitMessageWithWidth(item, message, reviewComments, width), nil
+	}
+
+	tmpl, err := template.New("commit-message").Option("missingkey=error").Parse(contents)
+	if err != nil {
+		return "", fmt.Errorf("parse commit message template: %w", err)
+	}
+	summary, body := splitCommitMessage(message)
+	data := CommitMessageData{
+		Todo:           item,
+		Message:        normalizeCommitMessage(message),
+		Summary:        summary,
+		Body:           internalstrings.TrimSpace(body),
+		ReviewComments: internalstrings.TrimSpace(reviewComments),
+		TodoBlock:      formatCommitTodoWithWidth(item, width),
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("render commit message template: %w", err)
+	}
+	formatted := normalizeFormattedCommitMessage(out.String())
+	if formatted == "" {
+		return "", fmt.Errorf("commit message template rendered an empty message")
+	}
+	return formatted, nil
+}
+
+func loadCommitMessageTemplate(repoPath string) (string, bool, error) {
+	if repoPath == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(filepath.Join(repoPath, promptOverrideDir, commitMessageTemplateName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("read commit message template: %w", err)
+	}
+	return string(data), true, nil
+}
+
+// commitMessageWidth returns the configured job.commit-message-width, or
+// lineWidth when unset.
+func commitMessageWidth(cfg *config.Config) int {
+	if cfg == nil || cfg.Job.CommitMessageWidth <= 0 {
+		return lineWidth
+	}
+	return cfg.Job.CommitMessageWidth
+}
+
 func formatCommitMessage(item todo.Todo, message, reviewComments string) string {
 	return formatCommitMessageWithWidth(item, message, reviewComments, lineWidth)
 }
@@ -58,10 +58,13 @@ func TestRunCommitMessageShowsSummaryInJjLog(t *testing.T) {
 		},
 	}
 
-	_, err = Run(repoPath, created.ID, opts)
+	result, err := Run(repoPath, created.ID, opts)
 	if err != nil {
 		t.Fatalf("run job: %v", err)
 	}
+	if !result.DidWork {
+		t.Fatalf("expected run to report work done")
+	}
 
 	logOutput, err := jjLogDescription(repoPath)
 	if err != nil {
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+type commitMessageInvalidEventData struct {
+	Summary string `json:"summary"`
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+}
+
+// checkCommitMessagePattern matches the draft summary line against
+// job.commit-message-pattern, recording a job.commit_message_invalid event
+// on a mismatch. Under the implement policy it returns feedback asking for a
+// new message; under the fail policy, or once retries (the number of
+// messages already rejected in a row) reaches job.commit-message-retries, it
+// returns ErrCommitMessageInvalid. It does nothing when no pattern is
+// configured.
+func checkCommitMessagePattern(log *EventLog, cfg *config.Config, draft string, retries int) (string, error) {
+	if cfg == nil || cfg.Job.CommitMessagePattern == "" {
+		return "", nil
+	}
+	pattern, err := regexp.Compile(cfg.Job.CommitMessagePattern)
+	if err != nil {
+		return "", fmt.Errorf("job.commit-message-pattern: %w", err)
+	}
+	summary, _ := splitCommitMessage(draft)
+	if pattern.MatchString(summary) {
+		return "", nil
+	}
+
+	policy := cfg.Job.InvalidCommitMessagePolicy()
+	if policy == config.CommitMessagePolicyImplement && retries >= cfg.Job.CommitMessageRetryLimit() {
+		policy = config.CommitMessagePolicyFail
+	}
+	if err := appendJobEvent(log, jobEventCommitInvalid, commitMessageInvalidEventData{Summary: summary, Pattern: pattern.String(), Action: policy}); err != nil {
+		return "", err
+	}
+	if policy == config.CommitMessagePolicyFail {
+		return "", fmt.Errorf("%w: summary %q does not match %q", ErrCommitMessageInvalid, summary, pattern)
+	}
+	return fmt.Sprintf("The commit message summary %q does not match the required pattern %q. Rewrite the commit message so its first line matches the pattern.", summary, pattern), nil
+}
+
+func commitMessageInvalidLogBody(data commitMessageInvalidEventData) string {
+	action := "Failing the job."
+	if data.Action == config.CommitMessagePolicyImplement {
+		action = "Returning to implementing."
+	}
+	return fmt.Sprintf("%q does not match %q. %s", data.Summary, data.Pattern, action)
+}
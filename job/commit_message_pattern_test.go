@@ -0,0 +1,215 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestRunCommittingStageChecksCommitMessagePattern(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		message    string
+		policy     string
+		retries    int
+		wantCommit bool
+		wantRetry  bool
+		wantErr    error
+		wantStage  Stage
+	}{
+		{name: "matching summary", message: "feat(job): add lint\n\nBody text.", wantCommit: true, wantStage: StageImplementing},
+		{name: "mismatch fails", message: "Add lint", wantErr: ErrCommitMessageInvalid},
+		{name: "mismatch reimplements", message: "Add lint", policy: config.CommitMessagePolicyImplement, retries: 2, wantRetry: true, wantStage: StageImplementing},
+		{name: "mismatch fails once retries run out", message: "Add lint", policy: config.CommitMessagePolicyImplement, retries: 3, wantErr: ErrCommitMessageInvalid},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			manager, err := Open("/tmp/commit-pattern-repo", OpenOptions{StateDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("open manager: %v", err)
+			}
+			created, err := manager.Create("todo-123", now, CreateOptions{})
+			if err != nil {
+				t.Fatalf("create job: %v", err)
+			}
+			stage := StageCommitting
+			created, err = manager.Update(created.ID, UpdateOptions{Stage: &stage}, now)
+			if err != nil {
+				t.Fatalf("update job: %v", err)
+			}
+
+			committed := false
+			stageResult, err := runCommittingStage(CommittingStageOptions{
+				Manager:       manager,
+				Current:       created,
+				WorkspacePath: t.TempDir(),
+				RunOptions: RunOptions{
+					Now: func() time.Time { return now },
+					Config: &config.Config{Job: config.Job{
+						CommitMessagePattern:   `^(feat|fix)(\([a-z]+\))?: `,
+						OnInvalidCommitMessage: tc.policy,
+					}},
+					DiffStat: func(string, string, string) (string, error) {
+						return "main.go | 1 +\n1 file changed, 1 insertion(+)", nil
+					},
+					DiffPreview: func(string, string, string, int) (string, error) {
+						return "", nil
+					},
+					Commit: func(string, string) error {
+						committed = true
+						return nil
+					},
+					CommitIDAt: func(string, string) (string, error) {
+						return "commit-1", nil
+					},
+				},
+				Result:         &RunResult{},
+				CommitMessage:  tc.message,
+				MessageRetries: tc.retries,
+			})
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				if committed {
+					t.Fatal("expected no commit for an invalid summary")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run committing stage: %v", err)
+			}
+			if stageResult.MessageRetry != tc.wantRetry {
+				t.Fatalf("expected message retry=%t, got %t", tc.wantRetry, stageResult.MessageRetry)
+			}
+			if committed != tc.wantCommit {
+				t.Fatalf("expected committed=%t, got %t", tc.wantCommit, committed)
+			}
+			if stageResult.Job.Stage != tc.wantStage {
+				t.Fatalf("expected stage %q, got %q", tc.wantStage, stageResult.Job.Stage)
+			}
+			if !tc.wantCommit && !strings.Contains(stageResult.Job.Feedback, `"Add lint"`) {
+				t.Fatalf("expected feedback to quote the summary, got %q", stageResult.Job.Feedback)
+			}
+		})
+	}
+}
+
+func TestRunImplementingStageKeepsChangeForMessageRetry(t *testing.T) {
+	workspacePath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/commit-pattern-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	opts := RunOptions{
+		Now:    func() time.Time { return now },
+		Config: &config.Config{Job: config.Job{TestCommands: []string{"go test ./..."}}},
+		// The agent only rewrites the message file, so the working copy's
+		// commit does not move.
+		CurrentCommitID:    func(string) (string, error) { return "pending", nil },
+		CurrentChangeID:    func(string) (string, error) { return "change-1", nil },
+		CurrentChangeEmpty: func(string) (bool, error) { return false, nil },
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			path := filepath.Join(runOpts.WorkspacePath, commitMessageFilename)
+			return OpencodeRunResult{SessionID: "ses-1"}, os.WriteFile(path, []byte("feat: rewritten\n"), 0o644)
+		},
+	}
+
+	result, err := runImplementingStage(manager, created, todo.Todo{ID: "todo-123", Title: "Lint"}, workspacePath, workspacePath, opts, nil, "Add lint", implementingStageState{messageRetry: true})
+	if err != nil {
+		t.Fatalf("run implementing stage: %v", err)
+	}
+	if !result.Changed || result.CommitMessage != "feat: rewritten" {
+		t.Fatalf("expected the pending change with the rewritten message, got %+v", result)
+	}
+	if result.Job.Stage != StageTesting {
+		t.Fatalf("expected testing stage, got %q", result.Job.Stage)
+	}
+}
+
+func TestRunJobStagesFailsOnceCommitMessageRetriesRunOut(t *testing.T) {
+	workspacePath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/commit-pattern-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	requireTests := false
+	retries := 1
+	cfg := &config.Config{Job: config.Job{
+		RequireTests:           &requireTests,
+		ImplementationModel:    "implement",
+		CodeReviewModel:        "review",
+		CommitMessagePattern:   `^feat: `,
+		OnInvalidCommitMessage: config.CommitMessagePolicyImplement,
+		CommitMessageRetries:   &retries,
+	}}
+	files := resolveOutputFiles(cfg)
+
+	// The agent never writes a summary that matches the pattern.
+	commit := 0
+	var runs []string
+	ctx := runContext{
+		workspacePath: workspacePath,
+		item:          todo.Todo{ID: "todo-123", Title: "Lint"},
+		opts: RunOptions{
+			Config:             cfg,
+			Now:                func() time.Time { return now },
+			CurrentCommitID:    func(string) (string, error) { return fmt.Sprintf("commit-%d", commit), nil },
+			CurrentChangeID:    func(string) (string, error) { return "change-1", nil },
+			CurrentChangeEmpty: func(string) (bool, error) { return false, nil },
+			DiffStat: func(string, string, string) (string, error) {
+				return "main.go | 1 +\n1 file changed, 1 insertion(+)\n", nil
+			},
+			DiffPreview: func(string, string, string, int) (string, error) { return "", nil },
+			Commit: func(string, string) error {
+				t.Fatal("expected no commit for an invalid summary")
+				return nil
+			},
+			RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+				runs = append(runs, runOpts.Agent)
+				name, content := files.Feedback, "ACCEPT\n"
+				if runOpts.Agent == "implement" {
+					commit++
+					name, content = files.CommitMessage, "Add lint\n"
+				}
+				if err := os.WriteFile(filepath.Join(workspacePath, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("write %s: %v", name, err)
+				}
+				return OpencodeRunResult{SessionID: fmt.Sprintf("session-%d", len(runs))}, nil
+			},
+		},
+		manager: manager,
+		result:  &RunResult{},
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if !errors.Is(err, ErrCommitMessageInvalid) {
+		t.Fatalf("expected ErrCommitMessageInvalid once retries ran out, got %v", err)
+	}
+	if finalJob.Status != StatusFailed {
+		t.Fatalf("expected failed job, got %q", finalJob.Status)
+	}
+	want := []string{"implement", "review", "implement", "review"}
+	if fmt.Sprint(runs) != fmt.Sprint(want) {
+		t.Fatalf("expected one run plus one message retry, got %v", runs)
+	}
+}
@@ -209,6 +209,59 @@ func TestFormatCommitMessageEmptyReviewComments(t *testing.T) {
 	}
 }
 
+func TestRenderCommitMessageUsesTemplateOverride(t *testing.T) {
+	repoPath := t.TempDir()
+	templateDir := filepath.Join(repoPath, promptOverrideDir)
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		t.Fatalf("create template dir: %v", err)
+	}
+	contents := "{{.Summary}}\n\n{{.Body}}\n{{if .ReviewComments}}\nReviewed: {{.ReviewComments}}\n{{end}}"
+	if err := os.WriteFile(filepath.Join(templateDir, commitMessageTemplateName), []byte(contents), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	item := todo.Todo{
+		ID:          "todo-777",
+		Title:       "Custom layout",
+		Description: "Should not appear in the commit.",
+		Type:        todo.TypeTask,
+		Priority:    todo.PriorityLow,
+	}
+	message := "feat: custom layout\n\nUse the repo's own commit format."
+
+	formatted, err := renderCommitMessage(repoPath, item, message, "Looks good.", lineWidth)
+	if err != nil {
+		t.Fatalf("render commit message: %v", err)
+	}
+	expected := "feat: custom layout\n\nUse the repo's own commit format.\n\nReviewed: Looks good."
+	if formatted != expected {
+		t.Fatalf("expected commit message %q, got %q", expected, formatted)
+	}
+	for _, omitted := range []string{"todo-777", "Custom layout", "This commit is a step towards"} {
+		if strings.Contains(formatted, omitted) {
+			t.Fatalf("expected commit message to omit %q, got %q", omitted, formatted)
+		}
+	}
+}
+
+func TestRenderCommitMessageFallsBackToDefaultLayout(t *testing.T) {
+	item := todo.Todo{
+		ID:       "todo-778",
+		Title:    "Default layout",
+		Type:     todo.TypeTask,
+		Priority: todo.PriorityLow,
+	}
+	message := "feat: default layout\n\nNo override present."
+
+	formatted, err := renderCommitMessage(t.TempDir(), item, message, "", lineWidth)
+	if err != nil {
+		t.Fatalf("render commit message: %v", err)
+	}
+	if expected := formatCommitMessage(item, message, ""); formatted != expected {
+		t.Fatalf("expected default layout %q, got %q", expected, formatted)
+	}
+}
+
 func maxLineLength(value string) int {
 	max := 0
 	for _, line := range strings.Split(value, "\n") {
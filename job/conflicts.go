@@ -0,0 +1,85 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+type conflictDetectedEventData struct {
+	Paths  []string `json:"paths"`
+	Action string   `json:"action"`
+}
+
+// checkConflicts looks for unresolved conflicts in the working copy after
+// implementing, recording a job.conflict_detected event when it finds some.
+// Under the implement policy it returns feedback asking opencode to resolve
+// them; under the fail policy, or once retries (the number of implementing
+// runs already sent back for conflicts in a row) reaches
+// job.conflict-retries, it returns ErrWorkspaceConflicted. A nil
+// HasConflicts skips the check.
+func checkConflicts(opts RunOptions, workspacePath string, retries int) (string, error) {
+	if opts.HasConflicts == nil {
+		return "", nil
+	}
+	conflicted, err := opts.HasConflicts(workspacePath)
+	if err != nil {
+		return "", fmt.Errorf("check conflicts: %w", err)
+	}
+	if !conflicted {
+		return "", nil
+	}
+	var paths []string
+	if opts.ConflictedPaths != nil {
+		paths, err = opts.ConflictedPaths(workspacePath)
+		if err != nil {
+			return "", fmt.Errorf("list conflicted paths: %w", err)
+		}
+	}
+
+	policy := config.ConflictPolicyImplement
+	limit := config.Job{}.ConflictRetryLimit()
+	if opts.Config != nil {
+		policy = opts.Config.Job.ConflictPolicy()
+		limit = opts.Config.Job.ConflictRetryLimit()
+	}
+	if policy == config.ConflictPolicyImplement && retries >= limit {
+		policy = config.ConflictPolicyFail
+	}
+	if err := appendJobEvent(opts.EventLog, jobEventConflicted, conflictDetectedEventData{Paths: paths, Action: policy}); err != nil {
+		return "", err
+	}
+	if policy == config.ConflictPolicyFail {
+		if len(paths) == 0 {
+			return "", ErrWorkspaceConflicted
+		}
+		return "", fmt.Errorf("%w: %s", ErrWorkspaceConflicted, strings.Join(paths, ", "))
+	}
+	return conflictFeedback(paths), nil
+}
+
+// conflictFeedback asks opencode to resolve the listed conflicts.
+func conflictFeedback(paths []string) string {
+	if len(paths) == 0 {
+		return "The working copy has unresolved merge conflicts. Find them with `jj resolve --list`, resolve them keeping the intended changes from both sides, and remove every conflict marker."
+	}
+	var b strings.Builder
+	b.WriteString("The working copy has unresolved merge conflicts in:\n\n")
+	for _, path := range paths {
+		fmt.Fprintf(&b, "- %s\n", path)
+	}
+	b.WriteString("\nResolve them keeping the intended changes from both sides, and remove every conflict marker.")
+	return b.String()
+}
+
+func conflictDetectedLogBody(data conflictDetectedEventData) string {
+	action := "Returning to implementing."
+	if data.Action == config.ConflictPolicyFail {
+		action = "Failing the job."
+	}
+	if len(data.Paths) == 0 {
+		return action
+	}
+	return fmt.Sprintf("%s %s", strings.Join(data.Paths, ", "), action)
+}
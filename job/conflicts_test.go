@@ -0,0 +1,204 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+// conflictedRunOptions fakes an implementing run that changes the workspace
+// and leaves conflicts in main.go.
+func conflictedRunOptions(t *testing.T, workspacePath string, now time.Time, eventLog *EventLog, prompts *[]string) RunOptions {
+	t.Helper()
+	commits := 0
+	return RunOptions{
+		Now:      func() time.Time { return now },
+		EventLog: eventLog,
+		CurrentCommitID: func(string) (string, error) {
+			commits++
+			return fmt.Sprintf("commit-%d", commits), nil
+		},
+		CurrentChangeID: func(string) (string, error) {
+			return "change-1", nil
+		},
+		CurrentChangeEmpty: func(string) (bool, error) {
+			return false, nil
+		},
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			*prompts = append(*prompts, runOpts.Prompt)
+			if err := os.WriteFile(filepath.Join(workspacePath, commitMessageFilename), []byte("feat: merge\n"), 0o644); err != nil {
+				t.Fatalf("write commit message: %v", err)
+			}
+			return OpencodeRunResult{SessionID: "ses-1"}, nil
+		},
+		HasConflicts: func(string) (bool, error) {
+			return true, nil
+		},
+		ConflictedPaths: func(string) ([]string, error) {
+			return []string{"main.go"}, nil
+		},
+	}
+}
+
+func TestRunJobStagesReturnsToImplementingOnConflict(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/conflict-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	logOpts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	workspacePath := t.TempDir()
+	var prompts []string
+	opts := conflictedRunOptions(t, workspacePath, now, eventLog, &prompts)
+	opts.MaxIterations = 2
+	ctx := runContext{
+		workspacePath: workspacePath,
+		item:          todo.Todo{ID: "todo-123", Title: "Merge upstream"},
+		opts:          opts,
+		manager:       manager,
+		result:        &RunResult{},
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if !errors.Is(err, ErrMaxIterationsExceeded) {
+		t.Fatalf("expected the conflict to keep the job implementing until the cap, got %v", err)
+	}
+	if finalJob.Status != StatusFailed {
+		t.Fatalf("expected failed job, got %q", finalJob.Status)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected two implementing runs, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "unresolved merge conflicts") || !strings.Contains(prompts[1], "- main.go") {
+		t.Fatalf("expected the second prompt to ask for conflict resolution, got:\n%s", prompts[1])
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	conflicts := 0
+	for _, event := range events {
+		if event.Name == jobEventConflicted {
+			conflicts++
+			if event.Data != `{"paths":["main.go"],"action":"implement"}` {
+				t.Fatalf("unexpected conflict event data: %s", event.Data)
+			}
+		}
+	}
+	if conflicts != 2 {
+		t.Fatalf("expected two conflict events, got %d in %+v", conflicts, events)
+	}
+}
+
+func TestRunImplementingStageFailsOnConflictWhenConfigured(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/conflict-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	workspacePath := t.TempDir()
+	var prompts []string
+	opts := conflictedRunOptions(t, workspacePath, now, nil, &prompts)
+	opts.Config = &config.Config{Job: config.Job{OnConflict: config.ConflictPolicyFail}}
+
+	item := todo.Todo{ID: "todo-123", Title: "Merge upstream"}
+	_, err = runImplementingStage(manager, created, item, workspacePath, workspacePath, opts, nil, "", implementingStageState{})
+	if !errors.Is(err, ErrWorkspaceConflicted) {
+		t.Fatalf("expected ErrWorkspaceConflicted, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "main.go") {
+		t.Fatalf("expected the error to name the conflicted path, got %v", err)
+	}
+}
+
+func TestRunJobStagesFailsOnceConflictRetriesAreUsedUp(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/conflict-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	workspacePath := t.TempDir()
+	var prompts []string
+	opts := conflictedRunOptions(t, workspacePath, now, nil, &prompts)
+	retries := 1
+	opts.Config = &config.Config{Job: config.Job{ConflictRetries: &retries}}
+	ctx := runContext{
+		workspacePath: workspacePath,
+		item:          todo.Todo{ID: "todo-123", Title: "Merge upstream"},
+		opts:          opts,
+		manager:       manager,
+		result:        &RunResult{},
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if !errors.Is(err, ErrWorkspaceConflicted) {
+		t.Fatalf("expected ErrWorkspaceConflicted once retries ran out, got %v", err)
+	}
+	if finalJob.Status != StatusFailed {
+		t.Fatalf("expected failed job, got %q", finalJob.Status)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected one run plus one conflict retry, got %d", len(prompts))
+	}
+}
+
+func TestRunImplementingStageChecksConflictsWithoutChanges(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/conflict-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	workspacePath := t.TempDir()
+	var prompts []string
+	opts := conflictedRunOptions(t, workspacePath, now, nil, &prompts)
+	opts.CurrentCommitID = func(string) (string, error) {
+		return "commit-1", nil
+	}
+
+	item := todo.Todo{ID: "todo-123", Title: "Merge upstream"}
+	result, err := runImplementingStage(manager, created, item, workspacePath, workspacePath, opts, nil, "", implementingStageState{})
+	if err != nil {
+		t.Fatalf("run implementing stage: %v", err)
+	}
+	if result.Changed || !result.Conflicted {
+		t.Fatalf("expected an unchanged but conflicted run, got %+v", result)
+	}
+	if result.Job.Stage != StageImplementing || !strings.Contains(result.Job.Feedback, "main.go") {
+		t.Fatalf("expected the job back in implementing with conflict feedback, got %+v", result.Job)
+	}
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/todo"
+)
+
+// designDocBudget caps how many bytes of a design doc are included in prompts.
+const designDocBudget = 16 * 1024
+
+const designDocTruncatedMarker = "[design doc truncated]"
+
+// readDesignDoc loads a todo's design doc relative to the workspace root,
+// truncating the contents to designDocBudget bytes.
+func readDesignDoc(workspacePath, path string) (string, error) {
+	if err := todo.ValidateDesignDoc(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(workspacePath, path))
+	if err != nil {
+		return "", err
+	}
+	return truncateDesignDoc(string(data), designDocBudget), nil
+}
+
+func truncateDesignDoc(contents string, budget int) string {
+	if len(contents) <= budget {
+		return contents
+	}
+	cut := budget
+	for cut > 0 && !utf8.RuneStart(contents[cut]) {
+		cut--
+	}
+	return internalstrings.TrimTrailingNewlines(contents[:cut]) + "\n\n" + designDocTruncatedMarker
+}
+
+// formatDesignDocBlock renders the design doc section for prompts. It returns
+// an empty string when the todo has no design doc or it cannot be read.
+func formatDesignDocBlock(item todo.Todo, workspacePath string) string {
+	if internalstrings.IsBlank(item.DesignDoc) {
+		return ""
+	}
+	contents, err := readDesignDoc(workspacePath, item.DesignDoc)
+	if err != nil || internalstrings.IsBlank(contents) {
+		return ""
+	}
+	label := fmt.Sprintf("Design doc (%s)", item.DesignDoc)
+	contents = internalstrings.TrimTrailingNewlines(contents)
+	return fmt.Sprintf("%s\n\n%s", label, IndentBlock(contents, documentIndent))
+}
+
+// recordDesignDocWarning emits a warning event when a todo references a
+// design doc that cannot be read. Stale paths should not fail the job.
+func recordDesignDocWarning(log *EventLog, item todo.Todo, workspacePath string) error {
+	if internalstrings.IsBlank(item.DesignDoc) {
+		return nil
+	}
+	if _, err := readDesignDoc(workspacePath, item.DesignDoc); err != nil {
+		message := fmt.Sprintf("design doc %s could not be read: %v", item.DesignDoc, err)
+		return appendJobEvent(log, jobEventWarning, warningEventData{Message: message})
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package job
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestRenderPromptIncludesDesignDoc(t *testing.T) {
+	workspacePath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspacePath, "docs"), 0o755); err != nil {
+		t.Fatalf("create docs dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspacePath, "docs", "design.md"), []byte("Use a queue.\n"), 0o644); err != nil {
+		t.Fatalf("write design doc: %v", err)
+	}
+
+	item := todo.Todo{ID: "todo-1", Title: "Build it", Type: todo.TypeTask, DesignDoc: "docs/design.md"}
+	prompt, err := renderPromptTemplate(item, "", "", nil, nil, "prompt-implementation.tmpl", workspacePath, resolveOutputFiles(nil))
+	if err != nil {
+		t.Fatalf("render prompt: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Design doc (docs/design.md)\n\n    Use a queue.") {
+		t.Fatalf("expected design doc block, got:\n%s", prompt)
+	}
+}
+
+func TestFormatDesignDocBlockMissingFileIsEmpty(t *testing.T) {
+	item := todo.Todo{DesignDoc: "docs/missing.md"}
+
+	if block := formatDesignDocBlock(item, t.TempDir()); block != "" {
+		t.Fatalf("expected empty block, got %q", block)
+	}
+}
+
+func TestTruncateDesignDocRespectsBudget(t *testing.T) {
+	contents := strings.Repeat("é", 10)
+
+	truncated := truncateDesignDoc(contents, 5)
+
+	if !strings.HasPrefix(truncated, "éé\n\n") {
+		t.Fatalf("expected truncation at a rune boundary, got %q", truncated)
+	}
+	if !strings.HasSuffix(truncated, designDocTruncatedMarker) {
+		t.Fatalf("expected truncation marker, got %q", truncated)
+	}
+}
+
+func TestRecordDesignDocWarningForMissingFile(t *testing.T) {
+	eventsDir := t.TempDir()
+	log, err := OpenEventLog("design-doc-warning", EventLogOptions{EventsDir: eventsDir})
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	item := todo.Todo{DesignDoc: "docs/missing.md"}
+	if err := recordDesignDocWarning(log, item, t.TempDir()); err != nil {
+		_ = log.Close()
+		t.Fatalf("record warning: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("design-doc-warning", EventLogOptions{EventsDir: eventsDir})
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventWarning {
+		t.Fatalf("expected one warning event, got %#v", events)
+	}
+	if !strings.Contains(events[0].Data, "docs/missing.md") {
+		t.Fatalf("expected warning to mention path, got %q", events[0].Data)
+	}
+}
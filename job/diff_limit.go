@@ -0,0 +1,29 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+type diffTooLargeEventData struct {
+	ChangedLines int `json:"changed_lines"`
+	MaxDiffLines int `json:"max_diff_lines"`
+}
+
+// checkDiffSize fails with ErrDiffTooLarge, recording a job.diff_too_large
+// event, when the diff stat changes more lines than job.max-diff-lines
+// allows.
+func checkDiffSize(log *EventLog, cfg *config.Config, diffStat string) error {
+	if cfg == nil || cfg.Job.MaxDiffLines <= 0 {
+		return nil
+	}
+	changed := parseDiffStat(diffStat).ChangedLines()
+	if changed <= cfg.Job.MaxDiffLines {
+		return nil
+	}
+	limitErr := fmt.Errorf("%w: %d changed lines exceeds job.max-diff-lines (%d)", ErrDiffTooLarge, changed, cfg.Job.MaxDiffLines)
+	eventErr := appendJobEvent(log, jobEventDiffTooLarge, diffTooLargeEventData{ChangedLines: changed, MaxDiffLines: cfg.Job.MaxDiffLines})
+	return errors.Join(limitErr, eventErr)
+}
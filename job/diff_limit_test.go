@@ -0,0 +1,118 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+func TestParseDiffStatTotals(t *testing.T) {
+	cases := []struct {
+		name     string
+		diffStat string
+		want     diffStatTotals
+	}{
+		{
+			name:     "summary",
+			diffStat: "a.go | 3 ++-\nb.go | 2 +-\n2 files changed, 3 insertions(+), 2 deletions(-)\n",
+			want:     diffStatTotals{Changed: true, Files: 2, Insertions: 3, Deletions: 2},
+		},
+		{
+			name:     "summary with only insertions",
+			diffStat: "a.go | 4 ++++\n1 file changed, 4 insertions(+)\n",
+			want:     diffStatTotals{Changed: true, Files: 1, Insertions: 4},
+		},
+		{
+			name:     "file lines without summary are summed",
+			diffStat: "a.go | 10 +++++-----\nb.go | 4 ++++\nc.go | 1 -\n",
+			want:     diffStatTotals{Changed: true, Files: 3, Insertions: 9, Deletions: 6},
+		},
+		{
+			name:     "binary file counts no lines",
+			diffStat: "logo.png | Bin 0 -> 512 bytes\nmain.go | 2 ++\n",
+			want:     diffStatTotals{Changed: true, Files: 2, Insertions: 2},
+		},
+		{
+			name:     "no changes",
+			diffStat: "No changes.\n",
+			want:     diffStatTotals{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseDiffStat(tc.diffStat); got != tc.want {
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRunCommittingStageFailsWhenDiffTooLarge(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open("/tmp/diff-limit-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	logOpts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	_, err = runCommittingStage(CommittingStageOptions{
+		Manager:       manager,
+		Current:       created,
+		WorkspacePath: t.TempDir(),
+		RunOptions: RunOptions{
+			Now:      func() time.Time { return now },
+			EventLog: eventLog,
+			Config:   &config.Config{Job: config.Job{MaxDiffLines: 100}},
+			DiffStat: func(string, string, string) (string, error) {
+				return "a.go | 80 ++++\nb.go | 40 --\n2 files changed, 90 insertions(+), 30 deletions(-)\n", nil
+			},
+			Commit: func(string, string) error {
+				t.Fatal("expected no commit")
+				return nil
+			},
+		},
+		Result:        &RunResult{},
+		CommitMessage: "feat: rewrite everything",
+	})
+	if !errors.Is(err, ErrDiffTooLarge) {
+		t.Fatalf("expected ErrDiffTooLarge, got %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, logOpts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventDiffTooLarge {
+		t.Fatalf("expected a diff_too_large event, got %#v", events)
+	}
+	if events[0].Data != `{"changed_lines":120,"max_diff_lines":100}` {
+		t.Fatalf("unexpected event data %s", events[0].Data)
+	}
+}
+
+func TestCheckDiffSizeAllowsDiffsWithinLimit(t *testing.T) {
+	stat := "a.go | 60 +++\nb.go | 40 ---\n"
+	if err := checkDiffSize(nil, &config.Config{Job: config.Job{MaxDiffLines: 100}}, stat); err != nil {
+		t.Fatalf("expected a diff at the limit to pass, got %v", err)
+	}
+	if err := checkDiffSize(nil, &config.Config{}, stat); err != nil {
+		t.Fatalf("expected zero to mean unlimited, got %v", err)
+	}
+	if err := checkDiffSize(nil, &config.Config{Job: config.Job{MaxDiffLines: 99}}, stat); !errors.Is(err, ErrDiffTooLarge) {
+		t.Fatalf("expected ErrDiffTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package job
+
+import "fmt"
+
+// diffPreviewMaxBytes caps the diff recorded in a job.diff_preview event.
+const diffPreviewMaxBytes = 64 * 1024
+
+type diffPreviewEventData struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Diff string `json:"diff"`
+}
+
+// recordDiffPreview appends a job.diff_preview event with the change about to
+// be committed. The preview only feeds the event log, so a failure to build
+// it is recorded as a warning rather than failing the commit.
+func recordDiffPreview(log *EventLog, preview func(string, string, string, int) (string, error), workspacePath string) error {
+	if preview == nil {
+		return nil
+	}
+	diff, err := preview(workspacePath, "@-", "@", diffPreviewMaxBytes)
+	if err != nil {
+		return appendJobEvent(log, jobEventWarning, warningEventData{Message: fmt.Sprintf("diff preview unavailable: %v", err)})
+	}
+	return appendJobEvent(log, jobEventDiffPreview, diffPreviewEventData{From: "@-", To: "@", Diff: diff})
+}
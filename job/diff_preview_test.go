@@ -0,0 +1,97 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunCommittingStageRecordsDiffPreview(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		previewErr error
+		wantEvent  string
+	}{
+		{name: "preview", wantEvent: jobEventDiffPreview},
+		{name: "preview fails", previewErr: errors.New("jj exploded"), wantEvent: jobEventWarning},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			manager, err := Open("/tmp/diff-preview-repo", OpenOptions{StateDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("open manager: %v", err)
+			}
+			created, err := manager.Create("todo-123", now, CreateOptions{})
+			if err != nil {
+				t.Fatalf("create job: %v", err)
+			}
+			logOpts := EventLogOptions{EventsDir: t.TempDir()}
+			eventLog, err := OpenEventLog(created.ID, logOpts)
+			if err != nil {
+				t.Fatalf("open event log: %v", err)
+			}
+
+			diff := "diff --git a/main.go b/main.go\n+func main() {}\n"
+			var committed string
+			_, err = runCommittingStage(CommittingStageOptions{
+				Manager:       manager,
+				Current:       created,
+				WorkspacePath: t.TempDir(),
+				RunOptions: RunOptions{
+					Now:      func() time.Time { return now },
+					EventLog: eventLog,
+					DiffStat: func(string, string, string) (string, error) {
+						return "main.go | 1 +\n1 file changed, 1 insertion(+)", nil
+					},
+					DiffPreview: func(_, from, to string, maxBytes int) (string, error) {
+						if from != "@-" || to != "@" || maxBytes != diffPreviewMaxBytes {
+							t.Fatalf("unexpected preview request %s..%s (%d bytes)", from, to, maxBytes)
+						}
+						return diff, tc.previewErr
+					},
+					Commit: func(_ string, message string) error {
+						committed = message
+						return nil
+					},
+					CommitIDAt: func(string, string) (string, error) {
+						return "commit-1", nil
+					},
+				},
+				Result:        &RunResult{},
+				CommitMessage: "feat: add main",
+			})
+			if err != nil {
+				t.Fatalf("run committing stage: %v", err)
+			}
+			if err := eventLog.Close(); err != nil {
+				t.Fatalf("close event log: %v", err)
+			}
+			if strings.Contains(committed, "diff --git") {
+				t.Fatalf("expected the diff to stay out of the commit message, got %q", committed)
+			}
+
+			events, err := EventSnapshot(created.ID, logOpts)
+			if err != nil {
+				t.Fatalf("event snapshot: %v", err)
+			}
+			if len(events) != 2 || events[0].Name != tc.wantEvent || events[1].Name != jobEventCommitMessage {
+				t.Fatalf("expected %s before the commit message, got %#v", tc.wantEvent, events)
+			}
+			if tc.previewErr != nil {
+				if !strings.Contains(events[0].Data, "jj exploded") {
+					t.Fatalf("expected warning to carry the error, got %q", events[0].Data)
+				}
+				return
+			}
+			var data diffPreviewEventData
+			if err := json.Unmarshal([]byte(events[0].Data), &data); err != nil {
+				t.Fatalf("decode diff preview: %v", err)
+			}
+			if data.Diff != diff || data.From != "@-" || data.To != "@" {
+				t.Fatalf("unexpected diff preview: %+v", data)
+			}
+		})
+	}
+}
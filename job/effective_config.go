@@ -0,0 +1,50 @@
+package job
+
+import (
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+// EffectiveConfig returns a copy of cfg with the agent and each stage model
+// set to what a job for item would use, resolved exactly as the runner does
+// (todo models, then profile, then per-type settings, then defaults). profile
+// overrides item.Profile as RunOptions.Profile does. The per-type maps are
+// cleared in the copy, and the completion webhook secret is redacted.
+//
+// It returns ErrUnknownProfile when the selected profile is not defined.
+func EffectiveConfig(cfg *config.Config, profile string, item todo.Todo) (*config.Config, error) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	if err := checkProfile(cfg, profile, item); err != nil {
+		return nil, err
+	}
+
+	resolved := RedactConfig(cfg)
+	resolved.Job.Agent = resolveOpencodeAgentForPurpose(cfg, "", profile, "", item)
+	resolved.Job.ImplementationModel = resolveOpencodeAgentForPurpose(cfg, "", profile, "implement", item)
+	resolved.Job.CodeReviewModel = resolveOpencodeAgentForPurpose(cfg, "", profile, "review", item)
+	resolved.Job.ProjectReviewModel = resolveOpencodeAgentForPurpose(cfg, "", profile, "project-review", item)
+	resolved.Job.AgentByType = nil
+	resolved.Job.ImplementationModelByType = nil
+	resolved.Job.CodeReviewModelByType = nil
+	resolved.Job.ProjectReviewModelByType = nil
+	return resolved, nil
+}
+
+// redactedSecret replaces secret config values in printed configuration.
+const redactedSecret = "<redacted>"
+
+// RedactConfig returns a copy of cfg with secret values, such as the
+// completion webhook secret, replaced so the config can be printed.
+func RedactConfig(cfg *config.Config) *config.Config {
+	if cfg == nil {
+		return &config.Config{}
+	}
+	redacted := *cfg
+	redacted.Job.TestCommands = append([]string(nil), cfg.Job.TestCommands...)
+	if redacted.Job.CompletionWebhookSecret != "" {
+		redacted.Job.CompletionWebhookSecret = redactedSecret
+	}
+	return &redacted
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestEffectiveConfigResolvesModelsLikeTheRunner(t *testing.T) {
+	cfg := &config.Config{
+		Job: config.Job{
+			Agent:                     "default",
+			ImplementationModel:       "impl",
+			CodeReviewModel:           "review",
+			AgentByType:               map[string]string{"bug": "careful"},
+			CodeReviewModelByType:     map[string]string{"bug": "strict-review"},
+			ImplementationModelByType: map[string]string{"feature": "feature-impl"},
+			CompletionWebhookSecret:   "hunter2",
+		},
+		Profiles: map[string]config.Profile{"fast": {ImplementationModel: "quick"}},
+	}
+
+	bug, err := EffectiveConfig(cfg, "", todo.Todo{Type: todo.TypeBug})
+	if err != nil {
+		t.Fatalf("effective config: %v", err)
+	}
+	if bug.Job.Agent != "careful" || bug.Job.ImplementationModel != "careful" || bug.Job.CodeReviewModel != "strict-review" {
+		t.Fatalf("unexpected bug models: %+v", bug.Job)
+	}
+	if bug.Job.ProjectReviewModel != "careful" {
+		t.Fatalf("expected project review to fall back to the bug agent, got %q", bug.Job.ProjectReviewModel)
+	}
+	if bug.Job.AgentByType != nil || bug.Job.CodeReviewModelByType != nil {
+		t.Fatal("expected per-type maps to be cleared")
+	}
+	if bug.Job.CompletionWebhookSecret != redactedSecret {
+		t.Fatalf("expected webhook secret to be redacted, got %q", bug.Job.CompletionWebhookSecret)
+	}
+
+	fast, err := EffectiveConfig(cfg, "fast", todo.Todo{Type: todo.TypeBug})
+	if err != nil {
+		t.Fatalf("effective config: %v", err)
+	}
+	if fast.Job.ImplementationModel != "quick" || fast.Job.CodeReviewModel != "strict-review" {
+		t.Fatalf("expected profile to win over per-type models, got %+v", fast.Job)
+	}
+
+	if _, err := EffectiveConfig(cfg, "slow", todo.Todo{}); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile, got %v", err)
+	}
+
+	if cfg.Job.AgentByType["bug"] != "careful" || cfg.Job.CompletionWebhookSecret != "hunter2" {
+		t.Fatal("expected EffectiveConfig not to modify the original config")
+	}
+}
+
+func TestRedactConfigLeavesUnsetSecretEmpty(t *testing.T) {
+	redacted := RedactConfig(&config.Config{Job: config.Job{Agent: "default"}})
+	if redacted.Job.CompletionWebhookSecret != "" || redacted.Job.Agent != "default" {
+		t.Fatalf("unexpected redacted config: %+v", redacted.Job)
+	}
+}
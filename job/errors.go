@@ -15,16 +15,43 @@ var (
 	ErrInvalidFeedbackFormat = errors.New("invalid feedback format")
 	// ErrJobInterrupted indicates the job was interrupted.
 	ErrJobInterrupted = errors.New("job interrupted")
+	// ErrJobPaused indicates the job was paused at a stage boundary.
+	ErrJobPaused = errors.New("job paused")
 	// ErrJobAbandoned indicates the job was abandoned.
 	ErrJobAbandoned = errors.New("job abandoned")
+	// ErrMaxIterationsExceeded indicates a job reached its implement/review
+	// iteration limit without finishing.
+	ErrMaxIterationsExceeded = errors.New("max iterations exceeded")
+	// ErrJobNotResumable indicates a job cannot be resumed because it is
+	// neither failed nor paused.
+	ErrJobNotResumable = errors.New("job is not resumable")
+	// ErrWorkspaceMissing indicates a resumed job's workspace no longer exists.
+	ErrWorkspaceMissing = errors.New("job workspace does not exist")
+	// ErrWorkspaceNotClean indicates a job was asked to start in a workspace
+	// with uncommitted changes while RequireCleanWorkspace was set.
+	ErrWorkspaceNotClean = errors.New("workspace not clean")
 	// ErrJobNotFound indicates the requested job is missing.
 	ErrJobNotFound = errors.New("job not found")
 	// ErrAmbiguousJobIDPrefix indicates a prefix matches multiple jobs.
 	ErrAmbiguousJobIDPrefix = errors.New("ambiguous job id prefix")
+	// ErrAmbiguousTodoIDPrefix indicates a ListFilter.TodoID prefix matches
+	// jobs for multiple todos.
+	ErrAmbiguousTodoIDPrefix = errors.New("ambiguous todo id prefix")
 	// ErrNoCurrentChange indicates a job has no current change.
 	ErrNoCurrentChange = errors.New("no current change")
 	// ErrNoCurrentCommit indicates a job has no current commit.
 	ErrNoCurrentCommit = errors.New("no current commit")
+	// ErrDiffTooLarge indicates a change exceeded job.max-diff-lines.
+	ErrDiffTooLarge = errors.New("diff too large")
+	// ErrWorkspaceConflicted indicates implementing left unresolved
+	// conflicts in the working copy and job.on-conflict is "fail".
+	ErrWorkspaceConflicted = errors.New("workspace has unresolved conflicts")
+	// ErrCommitMessageInvalid indicates a draft commit summary did not match
+	// job.commit-message-pattern and job.on-invalid-commit-message is "fail".
+	ErrCommitMessageInvalid = errors.New("commit message invalid")
+	// ErrUnknownProfile indicates a run or todo names a profile the config
+	// does not define.
+	ErrUnknownProfile = errors.New("unknown profile")
 )
 
 // AbandonedError is returned when a job is abandoned with a reason.
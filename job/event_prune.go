@@ -0,0 +1,84 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amonks/incrementum/internal/paths"
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// logRotatedEventData is the payload of a job.log_rotated event.
+type logRotatedEventData struct {
+	RotatedBytes int64  `json:"rotated_bytes"`
+	MaxSizeBytes int64  `json:"max_size_bytes"`
+	RotatedPath  string `json:"rotated_path"`
+}
+
+// PruneEventLogs deletes the event logs, including rotated segments, of jobs
+// whose logs were last written more than olderThan ago, and returns how many
+// jobs' logs it removed. Logs of jobs the state still records as active or
+// paused are always kept, however old; logs with no job in the state are
+// pruned by age alone.
+func PruneEventLogs(opts EventLogOptions, olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, fmt.Errorf("prune age must not be negative")
+	}
+	root, err := paths.ResolveWithDefault(opts.EventsDir, paths.DefaultJobEventsDir)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read job events dir: %w", err)
+	}
+
+	stateDir, err := resolveStateDir(OpenOptions{StateDir: opts.StateDir})
+	if err != nil {
+		return 0, err
+	}
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		return 0, fmt.Errorf("load state: %w", err)
+	}
+	unfinished := make(map[string]bool)
+	for _, item := range st.Jobs {
+		if item.Status == StatusActive || item.Status == StatusPaused {
+			unfinished[item.ID] = true
+		}
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	pruned := 0
+	for _, entry := range entries {
+		jobID, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() || unfinished[jobID] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return pruned, fmt.Errorf("stat job event log: %w", err)
+		}
+		if !info.ModTime().Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(root, entry.Name())
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return pruned, fmt.Errorf("remove job event log: %w", err)
+		}
+		if err := os.Remove(path + ".1"); err != nil && !os.IsNotExist(err) {
+			return pruned, fmt.Errorf("remove rotated job event log: %w", err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
@@ -0,0 +1,158 @@
+package job
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPruneEventLogsKeepsUnfinishedJobs(t *testing.T) {
+	stateDir := t.TempDir()
+	opts := EventLogOptions{EventsDir: t.TempDir(), StateDir: stateDir}
+	now := time.Now()
+
+	manager, err := Open("/tmp/prune-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	create := func(todoID string, status Status) Job {
+		t.Helper()
+		created, err := manager.Create(todoID, now, CreateOptions{})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		if status != StatusActive {
+			created, err = manager.Update(created.ID, UpdateOptions{Status: &status}, now)
+			if err != nil {
+				t.Fatalf("update job: %v", err)
+			}
+		}
+		return created
+	}
+	writeLog := func(jobID string, age time.Duration) string {
+		t.Helper()
+		log, err := OpenEventLog(jobID, opts)
+		if err != nil {
+			t.Fatalf("open event log: %v", err)
+		}
+		if err := appendJobEvent(log, jobEventStage, map[string]string{"stage": "implementing"}); err != nil {
+			t.Fatalf("append event: %v", err)
+		}
+		if err := log.Close(); err != nil {
+			t.Fatalf("close event log: %v", err)
+		}
+		path, err := EventLogPath(jobID, opts)
+		if err != nil {
+			t.Fatalf("event log path: %v", err)
+		}
+		modTime := now.Add(-age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("age event log: %v", err)
+		}
+		return path
+	}
+
+	completed := writeLog(create("todo-done", StatusCompleted).ID, 48*time.Hour)
+	if err := os.WriteFile(completed+".1", []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write rotated log: %v", err)
+	}
+	recent := writeLog(create("todo-recent", StatusFailed).ID, time.Minute)
+	active := writeLog(create("todo-active", StatusActive).ID, 48*time.Hour)
+	paused := writeLog(create("todo-paused", StatusPaused).ID, 48*time.Hour)
+	orphaned := writeLog("job-without-state", 48*time.Hour)
+
+	pruned, err := PruneEventLogs(opts, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("prune event logs: %v", err)
+	}
+	if pruned != 2 {
+		t.Fatalf("expected 2 pruned logs, got %d", pruned)
+	}
+	for _, path := range []string{completed, completed + ".1", orphaned} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", filepath.Base(path), err)
+		}
+	}
+	for _, path := range []string{recent, active, paused} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be kept, got %v", filepath.Base(path), err)
+		}
+	}
+
+	missing := EventLogOptions{EventsDir: filepath.Join(t.TempDir(), "missing"), StateDir: stateDir}
+	if pruned, err := PruneEventLogs(missing, 0); err != nil || pruned != 0 {
+		t.Fatalf("expected a missing events dir to prune nothing, got %d, %v", pruned, err)
+	}
+}
+
+func TestEventLogRotatesPastMaxSize(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir(), MaxSizeBytes: 200}
+	jobID := "job-rotate"
+
+	log, err := OpenEventLog(jobID, opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+	output := strings.Repeat("x", 80)
+	for i := 0; i < 2; i++ {
+		if err := appendJobEvent(log, jobEventOpencodeOutput, map[string]string{"output": output}); err != nil {
+			t.Fatalf("append event: %v", err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(jobID, opts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 3 || events[0].Name != jobEventOpencodeOutput || events[2].Name != jobEventLogRotated {
+		t.Fatalf("expected the rotated events then the rotation marker, got %+v", events)
+	}
+	data, err := decodeEventData[logRotatedEventData](events[2].Data)
+	if err != nil {
+		t.Fatalf("decode rotation marker: %v", err)
+	}
+	path, err := EventLogPath(jobID, opts)
+	if err != nil {
+		t.Fatalf("event log path: %v", err)
+	}
+	if data.RotatedPath != path+".1" || data.MaxSizeBytes != 200 || data.RotatedBytes <= 200 {
+		t.Fatalf("unexpected rotation marker %+v", data)
+	}
+	rotated, err := os.Open(data.RotatedPath)
+	if err != nil {
+		t.Fatalf("open rotated log: %v", err)
+	}
+	defer rotated.Close()
+	rotatedEvents, err := ReadEvents(rotated)
+	if err != nil {
+		t.Fatalf("read rotated log: %v", err)
+	}
+	if len(rotatedEvents) != 2 {
+		t.Fatalf("expected the rotated log to keep both events, got %d", len(rotatedEvents))
+	}
+
+	// Reopening continues the event IDs past the marker.
+	reopened, err := ReopenEventLog(jobID, opts)
+	if err != nil {
+		t.Fatalf("reopen event log: %v", err)
+	}
+	if got := reopened.nextJobEventID(); got != "job-4" {
+		t.Fatalf("expected job-4 after the marker, got %s", got)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("close reopened log: %v", err)
+	}
+
+	rendered, err := LogSnapshot(jobID, opts)
+	if err != nil {
+		t.Fatalf("render logs: %v", err)
+	}
+	if !strings.Contains(rendered, "Log rotated:") {
+		t.Fatalf("expected the rotation in rendered logs, got %q", rendered)
+	}
+}
@@ -9,21 +9,41 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/amonks/incrementum/internal/config"
 	"github.com/amonks/incrementum/internal/paths"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
 
 const (
-	jobEventStage         = "job.stage"
-	jobEventPrompt        = "job.prompt"
-	jobEventTranscript    = "job.transcript"
-	jobEventCommitMessage = "job.commit_message"
-	jobEventReview        = "job.review"
-	jobEventTests         = "job.tests"
-	jobEventOpencodeStart = "job.opencode.start"
-	jobEventOpencodeEnd   = "job.opencode.end"
-	jobEventOpencodeError = "job.opencode.error"
+	jobEventStage           = "job.stage"
+	jobEventPrompt          = "job.prompt"
+	jobEventTranscript      = "job.transcript"
+	jobEventCommitMessage   = "job.commit_message"
+	jobEventReview          = "job.review"
+	jobEventTests           = "job.tests"
+	jobEventTestsSkipped    = "job.tests_skipped"
+	jobEventOpencodeStart   = "job.opencode.start"
+	jobEventOpencodeEnd     = "job.opencode.end"
+	jobEventOpencodeError   = "job.opencode.error"
+	jobEventOpencodeTimeout = "job.opencode.timeout"
+	jobEventOpencodeOutput  = "job.opencode.output"
+	jobEventWarning         = "job.warning"
+	jobEventResult          = "job.result"
+	jobEventMaxIterations   = "job.max_iterations"
+	jobEventDryRun          = "job.dry_run"
+	jobEventStageDuration   = "job.stage_duration"
+	jobEventAbandoned       = "job.abandoned"
+	jobEventPaused          = "job.paused"
+	jobEventUsage           = "job.usage"
+	jobEventWorkspaceDirty  = "job.workspace_dirty"
+	jobEventDiffPreview     = "job.diff_preview"
+	jobEventDiffTooLarge    = "job.diff_too_large"
+	jobEventLogRotated      = "job.log_rotated"
+	jobEventBookmarkUpdated = "job.bookmark_updated"
+	jobEventConflicted      = "job.conflict_detected"
+	jobEventCommitInvalid   = "job.commit_message_invalid"
 )
 
 // Event captures a job log event.
@@ -37,6 +57,16 @@ type Event struct {
 type EventLogOptions struct {
 	EventsDir string
 	RepoPath  string
+
+	// StateDir is where job state is stored. PruneEventLogs reads it to keep
+	// the logs of unfinished jobs. Empty uses the default state dir.
+	StateDir string
+
+	// MaxSizeBytes rotates a job's log once it grows past this many bytes:
+	// the full log moves to <jobID>.jsonl.1, replacing any earlier rotation,
+	// and a fresh log starts with a job.log_rotated marker. Zero means
+	// unlimited.
+	MaxSizeBytes int64
 }
 
 // EventLog writes job events to a JSONL log.
@@ -45,7 +75,20 @@ type EventLog struct {
 	file    *os.File
 	encoder *json.Encoder
 	stream  chan<- Event
+	nextID  int
 	mu      sync.Mutex
+
+	size    int64
+	maxSize int64
+}
+
+// withConfig fills MaxSizeBytes from job.event-log-max-bytes when the
+// options leave it unset.
+func (opts EventLogOptions) withConfig(cfg *config.Config) EventLogOptions {
+	if opts.MaxSizeBytes == 0 && cfg != nil {
+		opts.MaxSizeBytes = int64(cfg.Job.EventLogMaxBytes)
+	}
+	return opts
 }
 
 // OpenEventLog creates a job event log.
@@ -61,7 +104,44 @@ func OpenEventLog(jobID string, opts EventLogOptions) (*EventLog, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create job event log: %w", err)
 	}
-	return &EventLog{path: path, file: file, encoder: json.NewEncoder(file)}, nil
+	log := &EventLog{path: path, maxSize: opts.MaxSizeBytes}
+	log.setFile(file, 0)
+	return log, nil
+}
+
+// ReopenEventLog opens an existing job event log for appending, creating it
+// if it is missing. Job-generated event IDs continue from the last one
+// already in the log.
+func ReopenEventLog(jobID string, opts EventLogOptions) (*EventLog, error) {
+	existing, err := readEventLog(jobID, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	path, err := eventLogPath(jobID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create job events dir: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open job event log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("stat job event log: %w", err)
+	}
+	log := &EventLog{path: path, maxSize: opts.MaxSizeBytes}
+	log.setFile(file, info.Size())
+	for _, event := range existing {
+		var n int
+		if _, err := fmt.Sscanf(event.ID, "job-%d", &n); err == nil && n > log.nextID {
+			log.nextID = n
+		}
+	}
+	return log, nil
 }
 
 // SetStream attaches an event channel for streaming events.
@@ -90,9 +170,70 @@ func (log *EventLog) Append(event Event) error {
 	if log.stream != nil {
 		log.stream <- event
 	}
+	if log.maxSize > 0 && log.size > log.maxSize {
+		return log.rotate()
+	}
 	return nil
 }
 
+// setFile points the log at file, which already holds size bytes.
+func (log *EventLog) setFile(file *os.File, size int64) {
+	log.file = file
+	log.size = size
+	log.encoder = json.NewEncoder(&countingWriter{w: file, n: &log.size})
+}
+
+// rotate moves the oversized log aside and starts a fresh one that opens
+// with a job.log_rotated marker. The caller must hold log.mu.
+func (log *EventLog) rotate() error {
+	rotatedBytes := log.size
+	if err := log.file.Close(); err != nil {
+		return fmt.Errorf("close job event log: %w", err)
+	}
+	log.file = nil
+	log.encoder = nil
+	rotatedPath := log.path + ".1"
+	if err := os.Rename(log.path, rotatedPath); err != nil {
+		return fmt.Errorf("rotate job event log: %w", err)
+	}
+	file, err := os.Create(log.path)
+	if err != nil {
+		return fmt.Errorf("create job event log: %w", err)
+	}
+	log.setFile(file, 0)
+
+	data, err := marshalJobEventData(logRotatedEventData{
+		RotatedBytes: rotatedBytes,
+		MaxSizeBytes: log.maxSize,
+		RotatedPath:  rotatedPath,
+	})
+	if err != nil {
+		return err
+	}
+	log.nextID++
+	return log.encoder.Encode(Event{ID: fmt.Sprintf("job-%d", log.nextID), Name: jobEventLogRotated, Data: data})
+}
+
+// countingWriter adds the bytes written through it to n.
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	written, err := cw.w.Write(p)
+	*cw.n += int64(written)
+	return written, err
+}
+
+// nextJobEventID returns a sequential ID for job-generated events.
+func (log *EventLog) nextJobEventID() string {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.nextID++
+	return fmt.Sprintf("job-%d", log.nextID)
+}
+
 // Close flushes and closes the event log.
 func (log *EventLog) Close() error {
 	if log == nil {
@@ -164,6 +305,8 @@ func ReadEvents(reader io.Reader) ([]Event, error) {
 	return events, nil
 }
 
+// readEventLog reads a job's events, starting with its rotated segment when
+// there is one.
 func readEventLog(jobID string, opts EventLogOptions, allowMissing bool) ([]Event, error) {
 	file, err := openEventLogFile(jobID, opts)
 	if err != nil {
@@ -175,7 +318,26 @@ func readEventLog(jobID string, opts EventLogOptions, allowMissing bool) ([]Even
 	defer func() {
 		_ = file.Close()
 	}()
-	return ReadEvents(file)
+	events, err := ReadEvents(file)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := os.Open(file.Name() + ".1")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return events, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = rotated.Close()
+	}()
+	earlier, err := ReadEvents(rotated)
+	if err != nil {
+		return nil, err
+	}
+	return append(earlier, events...), nil
 }
 
 // EventSnapshot returns the stored job events.
@@ -183,6 +345,29 @@ func EventSnapshot(jobID string, opts EventLogOptions) ([]Event, error) {
 	return readEventLog(jobID, opts, true)
 }
 
+// EventSnapshotSince returns the stored job events recorded after the event
+// with the given ID. An empty or unknown ID returns every stored event so
+// callers resuming from a stale cursor still catch up.
+func EventSnapshotSince(jobID, sinceID string, opts EventLogOptions) ([]Event, error) {
+	events, err := readEventLog(jobID, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return eventsAfterID(events, sinceID), nil
+}
+
+func eventsAfterID(events []Event, sinceID string) []Event {
+	if sinceID == "" {
+		return events
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].ID == sinceID {
+			return events[i+1:]
+		}
+	}
+	return events
+}
+
 func appendJobEvent(log *EventLog, name string, payload any) error {
 	if log == nil {
 		return nil
@@ -191,7 +376,67 @@ func appendJobEvent(log *EventLog, name string, payload any) error {
 	if err != nil {
 		return err
 	}
-	return log.Append(Event{Name: name, Data: data})
+	return log.Append(Event{ID: log.nextJobEventID(), Name: name, Data: data})
+}
+
+// recordStageDuration appends a job.stage_duration event for a pass through
+// stage that began at start.
+func recordStageDuration(log *EventLog, now func() time.Time, stage Stage, start time.Time, interrupted bool) error {
+	elapsed := now().Sub(start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return appendJobEvent(log, jobEventStageDuration, stageDurationEventData{
+		Stage:       stage,
+		DurationMS:  elapsed.Milliseconds(),
+		Interrupted: interrupted,
+	})
+}
+
+// recordRunResult appends the terminal job.result event for a run that began
+// at start. Paused jobs are not finished, so they get none.
+func recordRunResult(log *EventLog, now func() time.Time, final Job, didWork bool, commitLog []CommitLogEntry, start time.Time) error {
+	if final.Status == StatusPaused {
+		return nil
+	}
+	elapsed := now().Sub(start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	data := resultEventData{
+		Status:     string(final.Status),
+		DidWork:    didWork,
+		DurationMS: elapsed.Milliseconds(),
+	}
+	for _, entry := range commitLog {
+		data.CommitIDs = append(data.CommitIDs, entry.ID)
+	}
+	if final.Status == StatusAbandoned {
+		data.AbandonReason = final.Reason
+	}
+	return appendJobEvent(log, jobEventResult, data)
+}
+
+// StageTimings totals the wall-clock time a job spent in each stage, summed
+// across iterations, from its event log. Interrupted stages contribute the
+// time until the interrupt. A missing log yields an empty map.
+func StageTimings(jobID string, opts EventLogOptions) (map[Stage]time.Duration, error) {
+	events, err := readEventLog(jobID, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	timings := make(map[Stage]time.Duration)
+	for _, event := range events {
+		if event.Name != jobEventStageDuration {
+			continue
+		}
+		var data stageDurationEventData
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			return nil, fmt.Errorf("decode stage duration event: %w", err)
+		}
+		timings[data.Stage] += time.Duration(data.DurationMS) * time.Millisecond
+	}
+	return timings, nil
 }
 
 func marshalJobEventData(payload any) (string, error) {
@@ -209,13 +454,51 @@ func marshalJobEventData(payload any) (string, error) {
 }
 
 type stageEventData struct {
+	Stage     Stage `json:"stage"`
+	Iteration int   `json:"iteration,omitempty"`
+}
+
+// dryRunEventData records an action a dry run skipped.
+type dryRunEventData struct {
+	// Action is "opencode" or "commit".
+	Action  string `json:"action"`
+	Purpose string `json:"purpose,omitempty"`
+	Prompt  string `json:"prompt,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// stageDurationEventData records how long one pass through a stage took.
+type stageDurationEventData struct {
+	Stage      Stage `json:"stage"`
+	DurationMS int64 `json:"duration_ms"`
+	// Interrupted marks a stage cut short by an interrupt; the duration
+	// covers the time until the interrupt.
+	Interrupted bool `json:"interrupted,omitempty"`
+}
+
+type pausedEventData struct {
+	// Stage is the stage the job will run next when resumed.
 	Stage Stage `json:"stage"`
 }
 
+type testsSkippedEventData struct {
+	Reason string `json:"reason"`
+}
+
+type abandonedEventData struct {
+	Reason string `json:"reason"`
+}
+
+type maxIterationsEventData struct {
+	MaxIterations int `json:"max_iterations"`
+}
+
 type promptEventData struct {
-	Purpose  string `json:"purpose"`
-	Template string `json:"template"`
-	Prompt   string `json:"prompt"`
+	Purpose  string    `json:"purpose"`
+	Template string    `json:"template"`
+	Prompt   string    `json:"prompt"`
+	Stage    Stage     `json:"stage,omitempty"`
+	At       time.Time `json:"at,omitzero"`
 }
 
 type transcriptEventData struct {
@@ -260,6 +543,25 @@ type opencodeErrorEventData struct {
 	Error   string `json:"error"`
 }
 
+type opencodeTimeoutEventData struct {
+	Purpose   string `json:"purpose"`
+	SessionID string `json:"session_id,omitempty"`
+	Timeout   string `json:"timeout"`
+}
+
+type warningEventData struct {
+	Message string `json:"message"`
+}
+
+type resultEventData struct {
+	Status  string `json:"status"`
+	DidWork bool   `json:"did_work"`
+	// CommitIDs lists the commits the run produced, in order.
+	CommitIDs     []string `json:"commit_ids,omitempty"`
+	AbandonReason string   `json:"abandon_reason,omitempty"`
+	DurationMS    int64    `json:"duration_ms"`
+}
+
 func buildTestsEventData(results []TestCommandResult) testsEventData {
 	data := testsEventData{Results: make([]testResultEventData, 0, len(results))}
 	for _, result := range results {
@@ -118,6 +118,65 @@ func TestEventSnapshotMissingFileReturnsEmpty(t *testing.T) {
 	}
 }
 
+func TestEventSnapshotSinceReturnsEventsAfterID(t *testing.T) {
+	eventsDir := t.TempDir()
+	log, err := OpenEventLog("job-since", EventLogOptions{EventsDir: eventsDir})
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+	if err := appendJobEvent(log, jobEventStage, stageEventData{Stage: StageImplementing}); err != nil {
+		_ = log.Close()
+		t.Fatalf("append event: %v", err)
+	}
+	if err := log.Append(Event{ID: "opencode-1", Name: "message.updated", Data: "{}"}); err != nil {
+		_ = log.Close()
+		t.Fatalf("append event: %v", err)
+	}
+	if err := appendJobEvent(log, jobEventStage, stageEventData{Stage: StageTesting}); err != nil {
+		_ = log.Close()
+		t.Fatalf("append event: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	opts := EventLogOptions{EventsDir: eventsDir}
+	all, err := EventSnapshot("job-since", opts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(all))
+	}
+	if all[0].ID != "job-1" || all[2].ID != "job-2" {
+		t.Fatalf("expected sequential job event ids, got %q and %q", all[0].ID, all[2].ID)
+	}
+
+	since, err := EventSnapshotSince("job-since", "opencode-1", opts)
+	if err != nil {
+		t.Fatalf("event snapshot since: %v", err)
+	}
+	if len(since) != 1 || since[0].ID != "job-2" {
+		t.Fatalf("expected only the last event, got %#v", since)
+	}
+
+	latest, err := EventSnapshotSince("job-since", "job-2", opts)
+	if err != nil {
+		t.Fatalf("event snapshot since: %v", err)
+	}
+	if len(latest) != 0 {
+		t.Fatalf("expected no events after latest id, got %#v", latest)
+	}
+
+	unknown, err := EventSnapshotSince("job-since", "missing", opts)
+	if err != nil {
+		t.Fatalf("event snapshot since: %v", err)
+	}
+	if len(unknown) != 3 {
+		t.Fatalf("expected unknown cursor to return all events, got %d", len(unknown))
+	}
+}
+
 func readEventLogFile(t *testing.T, path string) []Event {
 	t.Helper()
 	file, err := os.Open(path)
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/amonks/incrementum/internal/config"
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
 
@@ -13,11 +15,58 @@ import (
 type ReviewFeedback struct {
 	Outcome ReviewOutcome
 	Details string
+
+	// FileComments are the file-scoped change requests listed in fenced
+	// yaml blocks within Details. Details still contains the blocks.
+	FileComments []ReviewFileComment
+}
+
+// ReviewOutcomeAliases maps custom first-line feedback tokens, compared
+// case-insensitively, to the canonical outcome they stand for.
+type ReviewOutcomeAliases map[string]ReviewOutcome
+
+// ParseReviewOutcomeAliases validates a token-to-outcome mapping such as
+// job.review-outcomes. Targets are matched case-insensitively against the
+// canonical outcomes.
+func ParseReviewOutcomeAliases(values map[string]string) (ReviewOutcomeAliases, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	aliases := make(ReviewOutcomeAliases, len(values))
+	for token, target := range values {
+		token = internalstrings.NormalizeLowerTrimSpace(token)
+		if token == "" {
+			return nil, fmt.Errorf("review outcome alias token is required")
+		}
+		outcome, ok := canonicalReviewOutcome(target)
+		if !ok {
+			return nil, fmt.Errorf("review outcome alias %q: unknown outcome %q (valid: %s)", token, target, joinReviewOutcomes(ValidReviewOutcomes()))
+		}
+		aliases[token] = outcome
+	}
+	return aliases, nil
+}
+
+func reviewOutcomeAliasesFromConfig(cfg *config.Config) (ReviewOutcomeAliases, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	aliases, err := ParseReviewOutcomeAliases(cfg.Job.ReviewOutcomes)
+	if err != nil {
+		return nil, fmt.Errorf("job.review-outcomes: %w", err)
+	}
+	return aliases, nil
 }
 
 // ReadReviewFeedback loads feedback from a file.
 // Missing files are treated as ACCEPT.
 func ReadReviewFeedback(path string) (ReviewFeedback, error) {
+	return ReadReviewFeedbackWithAliases(path, nil)
+}
+
+// ReadReviewFeedbackWithAliases loads feedback from a file, also accepting
+// the aliased outcome tokens. Missing files are treated as ACCEPT.
+func ReadReviewFeedbackWithAliases(path string, aliases ReviewOutcomeAliases) (ReviewFeedback, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -30,7 +79,7 @@ func ReadReviewFeedback(path string) (ReviewFeedback, error) {
 		removeErr = fmt.Errorf("remove feedback: %w", removeErr)
 	}
 
-	feedback, parseErr := ParseReviewFeedback(string(data))
+	feedback, parseErr := ParseReviewFeedbackWithAliases(string(data), aliases)
 	if removeErr != nil {
 		if parseErr != nil {
 			return ReviewFeedback{}, errors.Join(parseErr, removeErr)
@@ -42,6 +91,12 @@ func ReadReviewFeedback(path string) (ReviewFeedback, error) {
 
 // ParseReviewFeedback parses the feedback file contents.
 func ParseReviewFeedback(contents string) (ReviewFeedback, error) {
+	return ParseReviewFeedbackWithAliases(contents, nil)
+}
+
+// ParseReviewFeedbackWithAliases parses the feedback file contents, also
+// accepting the aliased outcome tokens on the first line.
+func ParseReviewFeedbackWithAliases(contents string, aliases ReviewOutcomeAliases) (ReviewFeedback, error) {
 	lines := strings.Split(contents, "\n")
 
 	for i, line := range lines {
@@ -53,16 +108,12 @@ func ParseReviewFeedback(contents string) (ReviewFeedback, error) {
 		return ReviewFeedback{}, ErrInvalidFeedbackFormat
 	}
 
-	var outcome ReviewOutcome
-	switch {
-	case strings.EqualFold(firstLine, string(ReviewOutcomeAccept)):
-		outcome = ReviewOutcomeAccept
-	case strings.EqualFold(firstLine, string(ReviewOutcomeAbandon)):
-		outcome = ReviewOutcomeAbandon
-	case strings.EqualFold(firstLine, string(ReviewOutcomeRequestChanges)):
-		outcome = ReviewOutcomeRequestChanges
-	default:
-		return ReviewFeedback{}, ErrInvalidFeedbackFormat
+	outcome, ok := canonicalReviewOutcome(firstLine)
+	if !ok {
+		outcome, ok = aliases[strings.ToLower(firstLine)]
+	}
+	if !ok {
+		return ReviewFeedback{}, fmt.Errorf("%w: unknown outcome %q (accepted: %s)", ErrInvalidFeedbackFormat, firstLine, acceptedReviewTokens(aliases))
 	}
 
 	blankIndex := -1
@@ -89,5 +140,42 @@ func ParseReviewFeedback(contents string) (ReviewFeedback, error) {
 		return ReviewFeedback{}, ErrInvalidFeedbackFormat
 	}
 
-	return ReviewFeedback{Outcome: outcome, Details: details}, nil
+	_, fileComments := splitReviewFileComments(details)
+	return ReviewFeedback{Outcome: outcome, Details: details, FileComments: fileComments}, nil
+}
+
+func canonicalReviewOutcome(token string) (ReviewOutcome, bool) {
+	token = internalstrings.TrimSpace(token)
+	for _, outcome := range ValidReviewOutcomes() {
+		if strings.EqualFold(token, string(outcome)) {
+			return outcome, true
+		}
+	}
+	return "", false
+}
+
+// acceptedReviewTokens lists the canonical outcomes followed by the aliases,
+// sorted, each alias annotated with the outcome it maps to.
+func acceptedReviewTokens(aliases ReviewOutcomeAliases) string {
+	tokens := joinReviewOutcomes(ValidReviewOutcomes())
+	if len(aliases) == 0 {
+		return tokens
+	}
+	keys := make([]string, 0, len(aliases))
+	for token := range aliases {
+		keys = append(keys, token)
+	}
+	sort.Strings(keys)
+	for _, token := range keys {
+		tokens += fmt.Sprintf(", %s (%s)", token, aliases[token])
+	}
+	return tokens
+}
+
+func joinReviewOutcomes(outcomes []ReviewOutcome) string {
+	names := make([]string, len(outcomes))
+	for i, outcome := range outcomes {
+		names[i] = string(outcome)
+	}
+	return strings.Join(names, ", ")
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -124,3 +125,95 @@ func TestAbandonedErrorAs(t *testing.T) {
 		t.Fatalf("expected reason %q, got %q", "the approach is flawed", abandonedErr.Reason)
 	}
 }
+
+func TestParseReviewFeedbackWithAliases(t *testing.T) {
+	aliases, err := ParseReviewOutcomeAliases(map[string]string{
+		"Änderungen": "request_changes",
+		"lgtm":       "ACCEPT",
+	})
+	if err != nil {
+		t.Fatalf("parse aliases: %v", err)
+	}
+
+	feedback, err := ParseReviewFeedbackWithAliases("ÄNDERUNGEN\n\nBitte Tests ergänzen.", aliases)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if feedback.Outcome != ReviewOutcomeRequestChanges {
+		t.Fatalf("expected REQUEST_CHANGES, got %q", feedback.Outcome)
+	}
+	if feedback.Details != "Bitte Tests ergänzen." {
+		t.Fatalf("expected details, got %q", feedback.Details)
+	}
+
+	feedback, err = ParseReviewFeedbackWithAliases("ACCEPT", aliases)
+	if err != nil || feedback.Outcome != ReviewOutcomeAccept {
+		t.Fatalf("expected canonical tokens to still parse, got %+v, %v", feedback, err)
+	}
+}
+
+func TestParseReviewFeedbackUnknownTokenListsAliases(t *testing.T) {
+	aliases, err := ParseReviewOutcomeAliases(map[string]string{"lgtm": "ACCEPT"})
+	if err != nil {
+		t.Fatalf("parse aliases: %v", err)
+	}
+
+	_, err = ParseReviewFeedbackWithAliases("SHIP_IT", aliases)
+	if !errors.Is(err, ErrInvalidFeedbackFormat) {
+		t.Fatalf("expected invalid feedback error, got %v", err)
+	}
+	for _, token := range []string{"ACCEPT", "REQUEST_CHANGES", "ABANDON", "lgtm (ACCEPT)"} {
+		if !strings.Contains(err.Error(), token) {
+			t.Fatalf("expected error to list %q, got %v", token, err)
+		}
+	}
+}
+
+func TestParseReviewOutcomeAliasesRejectsUnknownOutcome(t *testing.T) {
+	if _, err := ParseReviewOutcomeAliases(map[string]string{"lgtm": "MERGE"}); err == nil {
+		t.Fatal("expected error for unknown alias target")
+	}
+}
+
+func TestParseReviewFeedbackFileComments(t *testing.T) {
+	contents := "REQUEST_CHANGES\n\nA couple of fixes needed.\n\n```yaml\n- file: job/feedback.go\n  comment: Handle the empty case.\n- file: \"specs/job.md\"\n  comment: Document the new\n    structured block.\n```\n\nThanks!\n"
+	feedback, err := ParseReviewFeedback(contents)
+	if err != nil {
+		t.Fatalf("parse feedback: %v", err)
+	}
+	if !strings.Contains(feedback.Details, "```yaml") {
+		t.Fatalf("expected details to keep the structured block, got %q", feedback.Details)
+	}
+	expected := []ReviewFileComment{
+		{File: "job/feedback.go", Comment: "Handle the empty case."},
+		{File: "specs/job.md", Comment: "Document the new structured block."},
+	}
+	if len(feedback.FileComments) != len(expected) {
+		t.Fatalf("expected %d file comments, got %v", len(expected), feedback.FileComments)
+	}
+	for i, want := range expected {
+		if feedback.FileComments[i] != want {
+			t.Fatalf("file comment %d: expected %+v, got %+v", i, want, feedback.FileComments[i])
+		}
+	}
+
+	rest, _ := splitReviewFileComments(feedback.Details)
+	if rest != "A couple of fixes needed.\n\nThanks!" {
+		t.Fatalf("expected block removed from free text, got %q", rest)
+	}
+}
+
+func TestParseReviewFeedbackWithoutFileCommentsIsPlainText(t *testing.T) {
+	contents := "REQUEST_CHANGES\n\nUse this config:\n\n```yaml\nname: example\n```\n"
+	feedback, err := ParseReviewFeedback(contents)
+	if err != nil {
+		t.Fatalf("parse feedback: %v", err)
+	}
+	if len(feedback.FileComments) != 0 {
+		t.Fatalf("expected no file comments, got %v", feedback.FileComments)
+	}
+	rest, _ := splitReviewFileComments(feedback.Details)
+	if rest != feedback.Details {
+		t.Fatalf("expected ordinary yaml to stay in the text, got %q", rest)
+	}
+}
@@ -27,20 +27,35 @@ type HabitRunOptions struct {
 	// Interrupts delivers signals that should interrupt the job.
 	// If nil, os.Interrupt is used.
 	Interrupts <-chan os.Signal
-	Now        func() time.Time
-	LoadConfig func(string) (*config.Config, error)
+	// RequireCleanWorkspace fails the run with ErrWorkspaceNotClean when the
+	// workspace has uncommitted changes; see RunOptions.
+	RequireCleanWorkspace bool
+	// RestoreOnInterrupt restores the workspace when the job is interrupted
+	// during the implementing stage; see RunOptions. Nil means true.
+	RestoreOnInterrupt *bool
+	Now                func() time.Time
+	LoadConfig         func(string) (*config.Config, error)
 	// Config provides loaded configuration for the job run.
 	// When nil, LoadConfig is used.
 	Config      *config.Config
 	RunTests    func(string, []string) ([]TestCommandResult, error)
 	RunOpencode func(opencodeRunOptions) (OpencodeRunResult, error)
 	// OpencodeAgent overrides agent selection for all stages when set.
-	OpencodeAgent       string
+	OpencodeAgent string
+	// StageTimeout and StageTimeouts bound each opencode run; see RunOptions.
+	StageTimeout  time.Duration
+	StageTimeouts map[string]time.Duration
+	// CaptureOutput and CaptureOutputLimit record opencode's output in the
+	// event log; see RunOptions.
+	CaptureOutput       bool
+	CaptureOutputLimit  int
 	CurrentCommitID     func(string) (string, error)
 	CurrentChangeEmpty  func(string) (bool, error)
 	DiffStat            func(string, string, string) (string, error)
+	DiffPreview         func(workspacePath, from, to string, maxBytes int) (string, error)
 	CommitIDAt          func(string, string) (string, error)
 	Commit              func(string, string) error
+	NewChange           func(string, string) (string, error)
 	RestoreWorkspace    func(string, string) error
 	UpdateStale         func(string) error
 	Snapshot            func(string) error
@@ -48,6 +63,9 @@ type HabitRunOptions struct {
 	EventLog            *EventLog
 	EventLogOptions     EventLogOptions
 	Logger              Logger
+	// CompletionWebhook is notified when the habit run finishes; see
+	// RunOptions.
+	CompletionWebhook *CompletionWebhook
 }
 
 // HabitRunResult captures the output of running a habit.
@@ -56,6 +74,13 @@ type HabitRunResult struct {
 	CommitMessage string
 	Artifact      *todo.Todo
 	Abandoned     bool
+	// DidWork reports whether the habit run committed changes.
+	DidWork bool
+	// CommitLog holds the commit the habit run created, if any.
+	CommitLog []CommitLogEntry
+	// WebhookDone receives the completion webhook's delivery result; see
+	// RunResult.WebhookDone.
+	WebhookDone <-chan error
 }
 
 // HabitStartInfo captures context when starting a habit run.
@@ -66,7 +91,7 @@ type HabitStartInfo struct {
 }
 
 // RunHabit runs a habit job for the given habit name.
-func RunHabit(repoPath, habitName string, opts HabitRunOptions) (*HabitRunResult, error) {
+func RunHabit(repoPath, habitName string, opts HabitRunOptions) (_ *HabitRunResult, runErr error) {
 	if internalstrings.IsBlank(habitName) {
 		return nil, fmt.Errorf("habit name is required")
 	}
@@ -138,7 +163,7 @@ func RunHabit(repoPath, habitName string, opts HabitRunOptions) (*HabitRunResult
 
 	createdEventLog := false
 	if opts.EventLog == nil {
-		eventLog, err := OpenEventLog(created.ID, opts.EventLogOptions)
+		eventLog, err := OpenEventLog(created.ID, opts.EventLogOptions.withConfig(opts.Config))
 		if err != nil {
 			status := StatusFailed
 			updated, updateErr := manager.Update(created.ID, UpdateOptions{Status: &status}, opts.Now())
@@ -156,6 +181,25 @@ func RunHabit(repoPath, habitName string, opts HabitRunOptions) (*HabitRunResult
 	if opts.EventStream != nil {
 		opts.EventLog.SetStream(opts.EventStream)
 	}
+	defer func() {
+		runErr = errors.Join(runErr, recordRunResult(opts.EventLog, opts.Now, result.Job, result.DidWork, result.CommitLog, startedAt))
+	}()
+	preflight := workspacePreflight{
+		log:           opts.EventLog,
+		workspacePath: workspacePath,
+		requireClean:  opts.RequireCleanWorkspace,
+		changeEmpty:   opts.CurrentChangeEmpty,
+		diffStat:      opts.DiffStat,
+		snapshot:      opts.Snapshot,
+		commitID:      opts.CurrentCommitID,
+		newChange:     opts.NewChange,
+	}
+	if err := preflight.run(); err != nil {
+		status := StatusFailed
+		updated, updateErr := manager.Update(created.ID, UpdateOptions{Status: &status}, opts.Now())
+		result.Job = updated
+		return result, errors.Join(err, updateErr)
+	}
 	if err := appendJobEvent(opts.EventLog, jobEventStage, stageEventData{Stage: created.Stage}); err != nil {
 		status := StatusFailed
 		updated, updateErr := manager.Update(created.ID, UpdateOptions{Status: &status}, opts.Now())
@@ -184,6 +228,8 @@ func RunHabit(repoPath, habitName string, opts HabitRunOptions) (*HabitRunResult
 	}
 	finalJob, err := runHabitStages(&habitCtx, created, interrupts)
 	result.Job = finalJob
+	result.DidWork = !result.Abandoned && result.CommitMessage != ""
+	result.WebhookDone = notifyCompletion(opts.toRunOptions(), finalJob, result.CommitLog)
 	if err != nil {
 		return result, err
 	}
@@ -199,9 +245,15 @@ type habitRunContext struct {
 	result         *HabitRunResult
 	commitMessage  string
 	reviewComments string
+	// baseline is the commit the running stage started from, if it
+	// recorded one.
+	baseline stageBaseline
+	// stop interrupts the running stage's opencode runs.
+	stop stageStop
 }
 
 func runHabitStages(ctx *habitRunContext, current Job, interrupts <-chan os.Signal) (Job, error) {
+	ctx.opts.RunOpencode = ctx.stop.wrap(ctx.opts.RunOpencode)
 	for current.Status == StatusActive {
 		if current.Stage != StageImplementing {
 			return current, fmt.Errorf("invalid job stage: %s", current.Stage)
@@ -281,38 +333,48 @@ func runHabitStages(ctx *habitRunContext, current Job, interrupts <-chan os.Sign
 }
 
 func (ctx *habitRunContext) runStageWithInterrupt(current Job, stageFn func() (Job, error), interrupts <-chan os.Signal) (Job, error) {
-	stageResult := make(chan struct {
-		job Job
-		err error
-	}, 1)
+	ctx.baseline.set("")
+	ctx.stop.start()
+	start := ctx.opts.Now()
+	stageResult := make(chan stageOutcome, 1)
 	go func() {
 		job, err := stageFn()
-		stageResult <- struct {
-			job Job
-			err error
-		}{job: job, err: err}
+		stageResult <- stageOutcome{job: job, err: err}
 	}()
 
 	select {
 	case <-interrupts:
-		return ctx.handleInterrupt(current)
+		durationErr := recordStageDuration(ctx.opts.EventLog, ctx.opts.Now, current.Stage, start, true)
+		ctx.stop.stop()
+		updated, err := ctx.handleInterrupt(current, waitForStage(stageResult))
+		return updated, errors.Join(err, durationErr)
 	case res := <-stageResult:
+		if err := recordStageDuration(ctx.opts.EventLog, ctx.opts.Now, current.Stage, start, false); err != nil {
+			return res.job, errors.Join(res.err, err)
+		}
 		return res.job, res.err
 	}
 }
 
-func (ctx *habitRunContext) handleInterrupt(current Job) (Job, error) {
+func (ctx *habitRunContext) handleInterrupt(current Job, stopErr error) (Job, error) {
+	var restoreErr error
+	if stopErr != nil {
+		restoreErr = fmt.Errorf("restore workspace: %w", stopErr)
+	} else if err := restoreInterruptedWorkspace(ctx.opts.RestoreOnInterrupt, ctx.opts.RestoreWorkspace, ctx.workspacePath, &ctx.baseline); err != nil {
+		restoreErr = fmt.Errorf("restore workspace: %w", err)
+	}
 	status := StatusFailed
 	updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
-	return updated, errors.Join(ErrJobInterrupted, updateErr)
+	return updated, errors.Join(ErrJobInterrupted, restoreErr, updateErr)
 }
 
 func (ctx *habitRunContext) handleStageOutcome(current, next Job, stageErr error) (Job, error) {
 	if stageErr != nil {
 		if next.Status == StatusAbandoned {
-			ctx.result.Job = next
+			recorded, recordErr := recordAbandon(ctx.manager, ctx.opts.EventLog, ctx.opts.Now(), next, stageErr)
+			ctx.result.Job = recorded
 			ctx.result.Abandoned = true
-			return next, nil // Abandon is successful for habits
+			return recorded, recordErr // Abandon is successful for habits
 		}
 		status := StatusFailed
 		updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
@@ -341,7 +403,8 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 	return func() (Job, error) {
 		logger := resolveLogger(ctx.opts.Logger)
 		updateStaleWorkspace(ctx.opts.UpdateStale, ctx.workspacePath)
-		feedbackPath := filepath.Join(ctx.workspacePath, feedbackFilename)
+		files := resolveOutputFiles(ctx.opts.Config)
+		feedbackPath := filepath.Join(ctx.workspacePath, files.Feedback)
 		if err := removeFileIfExists(feedbackPath); err != nil {
 			return Job{}, err
 		}
@@ -350,22 +413,26 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 		if err != nil {
 			return Job{}, err
 		}
+		ctx.baseline.set(beforeCommitID)
 
 		promptName := "prompt-habit-implementation.tmpl"
 		if !internalstrings.IsBlank(current.Feedback) {
 			promptName = "prompt-feedback.tmpl"
 		}
-		prompt, err := renderHabitPromptTemplate(ctx.habit, current.Feedback, ctx.commitMessage, nil, nil, promptName, ctx.workspacePath)
+		prompt, err := renderHabitPromptTemplate(ctx.habit, current.Feedback, ctx.commitMessage, nil, nil, promptName, ctx.workspacePath, files)
 		if err != nil {
 			return Job{}, err
 		}
-		if err := appendJobEvent(ctx.opts.EventLog, jobEventPrompt, promptEventData{Purpose: "implement", Template: promptName, Prompt: prompt}); err != nil {
+		if err := appendJobEvent(ctx.opts.EventLog, jobEventPrompt, promptEventData{Purpose: "implement", Template: promptName, Prompt: prompt, Stage: current.Stage, At: ctx.opts.Now()}); err != nil {
 			return Job{}, err
 		}
 
 		updated := current
 		agent := resolveHabitModel(ctx.opts.Config, ctx.opts.OpencodeAgent, ctx.habit.ImplementationModel, "implement")
+		restorePoint := beforeCommitID
+		snapshotInterval := resolveSnapshotInterval(ctx.opts.Config)
 		runAttempt := func() (OpencodeRunResult, error) {
+			stopSnapshots := startPeriodicSnapshots(ctx.opts.Snapshot, ctx.opts.CurrentCommitID, ctx.workspacePath, snapshotInterval, ctx.baseline.set)
 			result, err := runOpencodeWithEvents(ctx.opts.toRunOptions(), opencodeRunOptions{
 				RepoPath:      ctx.repoPath,
 				WorkspacePath: ctx.workspacePath,
@@ -373,8 +440,11 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 				Agent:         agent,
 				StartedAt:     ctx.opts.Now(),
 				EventLog:      ctx.opts.EventLog,
-				Env:           applyOpencodeConfigEnv(nil),
+				Env:           applyOpencodeConfigEnv(nil, ctx.opts.Config),
 			}, "implement")
+			if snapshotID := stopSnapshots(); snapshotID != "" {
+				restorePoint = snapshotID
+			}
 			if err != nil {
 				return OpencodeRunResult{}, err
 			}
@@ -399,6 +469,7 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 			return Job{}, err
 		}
 
+		maxRetries := resolveImplementRetries(ctx.opts.Config)
 		retryCount := 0
 		for opencodeResult.ExitCode != 0 {
 			afterCommitID := ""
@@ -410,13 +481,13 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 			var restoreErr error
 			if opencodeResult.ExitCode < 0 && afterCommitErr == nil && afterCommitID != "" && beforeCommitID != "" && afterCommitID != beforeCommitID {
 				if ctx.opts.RestoreWorkspace != nil {
-					restoreErr = ctx.opts.RestoreWorkspace(ctx.workspacePath, beforeCommitID)
+					restoreErr = ctx.opts.RestoreWorkspace(ctx.workspacePath, restorePoint)
 					if restoreErr == nil {
 						restored = true
 					}
 				}
 			}
-			if restored && retryCount == 0 {
+			if restored && retryCount < maxRetries {
 				retryCount++
 				opencodeResult, err = runAttempt()
 				if err != nil {
@@ -429,7 +500,7 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 				WorkspacePath: ctx.workspacePath,
 				Prompt:        prompt,
 				Agent:         agent,
-			}, beforeCommitID, afterCommitID, afterCommitErr, restored, restoreErr, retryCount))
+			}, beforeCommitID, afterCommitID, restorePoint, afterCommitErr, restored, restoreErr, retryCount))
 		}
 
 		afterCommitID, err := ctx.opts.CurrentCommitID(ctx.workspacePath)
@@ -452,7 +523,7 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 		}
 		message := ""
 		if changed {
-			messagePath := filepath.Join(ctx.workspacePath, commitMessageFilename)
+			messagePath := filepath.Join(ctx.workspacePath, files.CommitMessage)
 			message, err = readCommitMessage(messagePath)
 			if err != nil {
 				if errors.Is(err, os.ErrNotExist) {
@@ -470,7 +541,7 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 			}
 		}
 		if !changed {
-			messagePath := filepath.Join(ctx.workspacePath, commitMessageFilename)
+			messagePath := filepath.Join(ctx.workspacePath, files.CommitMessage)
 			if err := removeFileIfExists(messagePath); err != nil {
 				return Job{}, err
 			}
@@ -478,9 +549,9 @@ func (ctx *habitRunContext) runHabitImplementingStage(current Job) func() (Job,
 
 		ctx.commitMessage = message
 
-		nextStage := StageTesting
-		if !changed {
-			nextStage = StageReviewing
+		nextStage, err := stageAfterImplementing(ctx.opts.Config, ctx.opts.EventLog, changed)
+		if err != nil {
+			return Job{}, err
 		}
 		updated, err = ctx.manager.Update(updated.ID, UpdateOptions{Stage: &nextStage}, ctx.opts.Now())
 		if err != nil {
@@ -501,11 +572,17 @@ func (ctx *habitRunContext) runHabitTestingStage(current Job) func() (Job, error
 				return Job{}, fmt.Errorf("load config: %w", err)
 			}
 		}
+		if testsSkipped(cfg) {
+			if err := recordTestsSkipped(ctx.opts.EventLog); err != nil {
+				return Job{}, err
+			}
+			return skipTestingStage(ctx.manager, current.ID, ctx.opts.Now())
+		}
 		if len(cfg.Job.TestCommands) < 1 {
 			return Job{}, fmt.Errorf("job test-commands must be configured")
 		}
 
-		results, err := ctx.opts.RunTests(ctx.workspacePath, cfg.Job.TestCommands)
+		results, err := runConfiguredTests(ctx.opts.RunTests, ctx.workspacePath, cfg)
 		if err != nil {
 			return Job{}, err
 		}
@@ -534,12 +611,14 @@ func (ctx *habitRunContext) runHabitReviewingStage(current Job) func() (Job, err
 	return func() (Job, error) {
 		logger := resolveLogger(ctx.opts.Logger)
 		updateStaleWorkspace(ctx.opts.UpdateStale, ctx.workspacePath)
-		feedbackPath := filepath.Join(ctx.workspacePath, feedbackFilename)
+		snapshotWorkspace(ctx.opts.Snapshot, ctx.workspacePath)
+		files := resolveOutputFiles(ctx.opts.Config)
+		feedbackPath := filepath.Join(ctx.workspacePath, files.Feedback)
 		if err := removeFileIfExists(feedbackPath); err != nil {
 			return Job{}, err
 		}
 
-		message, err := resolveReviewCommitMessage(ctx.commitMessage, ctx.workspacePath, true)
+		message, err := resolveReviewCommitMessage(ctx.commitMessage, ctx.workspacePath, files.CommitMessage, true)
 		if err != nil {
 			return Job{}, err
 		}
@@ -552,12 +631,12 @@ func (ctx *habitRunContext) runHabitReviewingStage(current Job) func() (Job, err
 			return Job{}, err
 		}
 		promptTemplate = ensureCommitMessageInPrompt(promptTemplate, message)
-		data := newHabitPromptData(ctx.habit.Name, ctx.habit.Instructions, "", message, nil, nil, ctx.workspacePath)
+		data := newHabitPromptData(ctx.habit.Name, ctx.habit.Instructions, "", message, nil, nil, ctx.workspacePath).withOutputFiles(files)
 		prompt, err := RenderPrompt(ctx.workspacePath, promptTemplate, data)
 		if err != nil {
 			return Job{}, err
 		}
-		if err := appendJobEvent(ctx.opts.EventLog, jobEventPrompt, promptEventData{Purpose: "review", Template: promptName, Prompt: prompt}); err != nil {
+		if err := appendJobEvent(ctx.opts.EventLog, jobEventPrompt, promptEventData{Purpose: "review", Template: promptName, Prompt: prompt, Stage: current.Stage, At: ctx.opts.Now()}); err != nil {
 			return Job{}, err
 		}
 
@@ -568,7 +647,7 @@ func (ctx *habitRunContext) runHabitReviewingStage(current Job) func() (Job, err
 			Agent:         agent,
 			StartedAt:     ctx.opts.Now(),
 			EventLog:      ctx.opts.EventLog,
-			Env:           applyOpencodeConfigEnv(nil),
+			Env:           applyOpencodeConfigEnv(nil, ctx.opts.Config),
 		}, "review")
 		if err != nil {
 			return Job{}, err
@@ -591,7 +670,11 @@ func (ctx *habitRunContext) runHabitReviewingStage(current Job) func() (Job, err
 			return Job{}, fmt.Errorf("opencode review failed with exit code %d", opencodeResult.ExitCode)
 		}
 
-		feedback, err := ReadReviewFeedback(feedbackPath)
+		aliases, err := reviewOutcomeAliasesFromConfig(ctx.opts.Config)
+		if err != nil {
+			return Job{}, err
+		}
+		feedback, err := ReadReviewFeedbackWithAliases(feedbackPath, aliases)
 		if err != nil {
 			return Job{}, err
 		}
@@ -634,6 +717,7 @@ func (ctx *habitRunContext) runHabitCommittingStage(current Job) func() (Job, er
 	return func() (Job, error) {
 		logger := resolveLogger(ctx.opts.Logger)
 		updateStaleWorkspace(ctx.opts.UpdateStale, ctx.workspacePath)
+		snapshotWorkspace(ctx.opts.Snapshot, ctx.workspacePath)
 		if ctx.opts.DiffStat == nil {
 			return Job{}, fmt.Errorf("diff stat is required")
 		}
@@ -649,13 +733,20 @@ func (ctx *habitRunContext) runHabitCommittingStage(current Job) func() (Job, er
 			}
 			return updated, nil
 		}
+		if err := checkDiffSize(ctx.opts.EventLog, ctx.opts.Config, diffStat); err != nil {
+			return Job{}, err
+		}
+		if err := recordDiffPreview(ctx.opts.EventLog, ctx.opts.DiffPreview, ctx.workspacePath); err != nil {
+			return Job{}, err
+		}
 		message := internalstrings.TrimSpace(ctx.commitMessage)
 		if message == "" {
 			return Job{}, fmt.Errorf("commit message is required")
 		}
 
-		finalMessage := formatHabitCommitMessage(ctx.habit, message, ctx.reviewComments)
-		logMessage := formatHabitCommitMessageWithWidth(ctx.habit, message, ctx.reviewComments, lineWidth-subdocumentIndent)
+		width := commitMessageWidth(ctx.opts.Config)
+		finalMessage := formatHabitCommitMessageWithWidth(ctx.habit, message, ctx.reviewComments, width)
+		logMessage := formatHabitCommitMessageWithWidth(ctx.habit, message, ctx.reviewComments, width-subdocumentIndent)
 		ctx.result.CommitMessage = finalMessage
 		logger.CommitMessage(CommitMessageLog{Label: "Final", Message: logMessage, Preformatted: true})
 		if err := appendJobEvent(ctx.opts.EventLog, jobEventCommitMessage, commitMessageEventData{Label: "Final", Message: logMessage, Preformatted: true}); err != nil {
@@ -666,6 +757,11 @@ func (ctx *habitRunContext) runHabitCommittingStage(current Job) func() (Job, er
 		if err := ctx.opts.Commit(ctx.workspacePath, finalMessage); err != nil {
 			return Job{}, err
 		}
+		commitID, err := ctx.opts.CommitIDAt(ctx.workspacePath, "@-")
+		if err != nil {
+			return Job{}, err
+		}
+		ctx.result.CommitLog = append(ctx.result.CommitLog, CommitLogEntry{ID: commitID, Message: message})
 
 		// Create artifact todo
 		artifact, err := createHabitArtifact(ctx.repoPath, ctx.habit.Name, message)
@@ -691,17 +787,24 @@ func (opts *HabitRunOptions) toRunOptions() RunOptions {
 		RunTests:            opts.RunTests,
 		RunOpencode:         opts.RunOpencode,
 		OpencodeAgent:       opts.OpencodeAgent,
+		StageTimeout:        opts.StageTimeout,
+		StageTimeouts:       opts.StageTimeouts,
+		CaptureOutput:       opts.CaptureOutput,
+		CaptureOutputLimit:  opts.CaptureOutputLimit,
 		CurrentCommitID:     opts.CurrentCommitID,
 		CurrentChangeEmpty:  opts.CurrentChangeEmpty,
 		DiffStat:            opts.DiffStat,
+		DiffPreview:         opts.DiffPreview,
 		CommitIDAt:          opts.CommitIDAt,
 		Commit:              opts.Commit,
+		NewChange:           opts.NewChange,
 		RestoreWorkspace:    opts.RestoreWorkspace,
 		UpdateStale:         opts.UpdateStale,
 		Snapshot:            opts.Snapshot,
 		OpencodeTranscripts: opts.OpencodeTranscripts,
 		EventLog:            opts.EventLog,
 		Logger:              opts.Logger,
+		CompletionWebhook:   opts.CompletionWebhook,
 	}
 }
 
@@ -716,8 +819,10 @@ func normalizeHabitRunOptions(opts HabitRunOptions) HabitRunOptions {
 		CurrentCommitID:     opts.CurrentCommitID,
 		CurrentChangeEmpty:  opts.CurrentChangeEmpty,
 		DiffStat:            opts.DiffStat,
+		DiffPreview:         opts.DiffPreview,
 		CommitIDAt:          opts.CommitIDAt,
 		Commit:              opts.Commit,
+		NewChange:           opts.NewChange,
 		RestoreWorkspace:    opts.RestoreWorkspace,
 		UpdateStale:         opts.UpdateStale,
 		Snapshot:            opts.Snapshot,
@@ -733,8 +838,10 @@ func normalizeHabitRunOptions(opts HabitRunOptions) HabitRunOptions {
 	opts.CurrentCommitID = runOpts.CurrentCommitID
 	opts.CurrentChangeEmpty = runOpts.CurrentChangeEmpty
 	opts.DiffStat = runOpts.DiffStat
+	opts.DiffPreview = runOpts.DiffPreview
 	opts.CommitIDAt = runOpts.CommitIDAt
 	opts.Commit = runOpts.Commit
+	opts.NewChange = runOpts.NewChange
 	opts.RestoreWorkspace = runOpts.RestoreWorkspace
 	opts.UpdateStale = runOpts.UpdateStale
 	opts.Snapshot = runOpts.Snapshot
@@ -768,12 +875,12 @@ func resolveHabitModel(cfg *config.Config, override, habitModel, purpose string)
 	return internalstrings.TrimSpace(model)
 }
 
-func renderHabitPromptTemplate(h *habit.Habit, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, name, workspacePath string) (string, error) {
+func renderHabitPromptTemplate(h *habit.Habit, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, name, workspacePath string, files outputFiles) (string, error) {
 	prompt, err := LoadPrompt(workspacePath, name)
 	if err != nil {
 		return "", err
 	}
-	return RenderPrompt(workspacePath, prompt, newHabitPromptData(h.Name, h.Instructions, feedback, message, commitLog, transcripts, workspacePath))
+	return RenderPrompt(workspacePath, prompt, newHabitPromptData(h.Name, h.Instructions, feedback, message, commitLog, transcripts, workspacePath).withOutputFiles(files))
 }
 
 // formatHabitCommitMessage formats a commit message for a habit commit.
@@ -839,7 +946,7 @@ func createHabitArtifact(repoPath, habitName, commitMessage string) (*todo.Todo,
 	// We explicitly set StartedAt because Create doesn't set it when creating
 	// with StatusInProgress (since there's no status transition). Per spec,
 	// artifact started_at should be set to creation time.
-	source := fmt.Sprintf("habit:%s", habitName)
+	source := habit.ArtifactSourcePrefix + habitName
 	startedAt := artifact.CreatedAt
 	_, err = store.Update([]string{artifact.ID}, todo.UpdateOptions{
 		Source:    &source,
@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/amonks/incrementum/internal/config"
 	"github.com/amonks/incrementum/todo"
 )
 
@@ -55,7 +57,7 @@ func TestRunImplementingStage_MissingCommitMessageExplainsContext(t *testing.T)
 		},
 	}
 
-	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err == nil {
 		t.Fatal("expected missing commit message error")
 	}
@@ -146,7 +148,7 @@ func TestRunImplementingStageFailedOpencodeRestoresRetriesAndReportsContext(t *t
 		OpencodeAgent: "gpt-5.2-codex",
 	}
 
-	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err == nil {
 		t.Fatal("expected opencode failure error")
 	}
@@ -233,7 +235,7 @@ func TestRunImplementingStageSetsOpencodeConfigEnv(t *testing.T) {
 			if !ok {
 				return OpencodeRunResult{}, fmt.Errorf("expected %s to be set", opencodeConfigEnvVar)
 			}
-			expected := opencodeConfigJSON()
+			expected := opencodeConfigJSON(nil)
 			if value != expected {
 				return OpencodeRunResult{}, fmt.Errorf("expected %s to be %q, got %q", opencodeConfigEnvVar, expected, value)
 			}
@@ -245,7 +247,7 @@ func TestRunImplementingStageSetsOpencodeConfigEnv(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, current, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, current, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -308,7 +310,7 @@ func TestRunImplementingStageRetriesOpencodeAfterRestore(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("expected retry to succeed, got %v", err)
 	}
@@ -326,6 +328,208 @@ func TestRunImplementingStageRetriesOpencodeAfterRestore(t *testing.T) {
 	}
 }
 
+func TestRunImplementingStageHonorsImplementRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		retries     int
+		crashes     int
+		wantRuns    int
+		wantRetry   string
+		wantSuccess bool
+	}{
+		{name: "recovers within limit", retries: 3, crashes: 2, wantRuns: 3, wantSuccess: true},
+		{name: "gives up after limit", retries: 2, crashes: 5, wantRuns: 3, wantRetry: "retry 2"},
+		{name: "zero disables retries", retries: 0, crashes: 1, wantRuns: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repoPath := t.TempDir()
+			manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("open manager: %v", err)
+			}
+
+			now := time.Date(2026, time.January, 2, 3, 4, 6, 30, time.UTC)
+			current, err := manager.Create("todo-retries", now, CreateOptions{})
+			if err != nil {
+				t.Fatalf("create job: %v", err)
+			}
+			item := todo.Todo{ID: "todo-retries", Title: "Example", Type: todo.TypeTask, Priority: todo.PriorityLow}
+
+			retries := tc.retries
+			runCalls := 0
+			restoreCalls := 0
+			opts := RunOptions{
+				Now:    func() time.Time { return now },
+				Config: &config.Config{Job: config.Job{ImplementRetries: &retries}},
+				CurrentCommitID: func(string) (string, error) {
+					// A crashed run leaves the workspace changed; a clean run
+					// leaves it where it started.
+					if runCalls <= tc.crashes && restoreCalls < runCalls {
+						return "after-bad", nil
+					}
+					return "before", nil
+				},
+				CurrentChangeID: func(string) (string, error) {
+					return "change-retries", nil
+				},
+				RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+					runCalls++
+					if runCalls <= tc.crashes {
+						return OpencodeRunResult{SessionID: fmt.Sprintf("ses-%d", runCalls), ExitCode: -1}, nil
+					}
+					return OpencodeRunResult{SessionID: fmt.Sprintf("ses-%d", runCalls), ExitCode: 0}, nil
+				},
+				RestoreWorkspace: func(string, string) error {
+					restoreCalls++
+					return nil
+				},
+			}
+
+			_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
+			if runCalls != tc.wantRuns {
+				t.Fatalf("expected %d opencode runs, got %d", tc.wantRuns, runCalls)
+			}
+			if tc.wantSuccess {
+				if err != nil {
+					t.Fatalf("expected retries to succeed, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected failure after exhausting retries")
+			}
+			if tc.wantRetry != "" && !strings.Contains(err.Error(), tc.wantRetry) {
+				t.Fatalf("expected %q in failure message, got %v", tc.wantRetry, err)
+			}
+		})
+	}
+}
+
+func TestRunImplementingStageDoesNotRetryCleanFailure(t *testing.T) {
+	repoPath := t.TempDir()
+	manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	now := time.Date(2026, time.January, 2, 3, 4, 6, 30, time.UTC)
+	current, err := manager.Create("todo-clean-failure", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	item := todo.Todo{ID: "todo-clean-failure", Title: "Example", Type: todo.TypeTask, Priority: todo.PriorityLow}
+
+	retries := 3
+	commitCalls := 0
+	runCalls := 0
+	opts := RunOptions{
+		Now:    func() time.Time { return now },
+		Config: &config.Config{Job: config.Job{ImplementRetries: &retries}},
+		CurrentCommitID: func(string) (string, error) {
+			commitCalls++
+			if commitCalls == 1 {
+				return "before", nil
+			}
+			return "after", nil
+		},
+		CurrentChangeID: func(string) (string, error) {
+			return "change-clean-failure", nil
+		},
+		RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+			runCalls++
+			return OpencodeRunResult{SessionID: "ses-1", ExitCode: 1}, nil
+		},
+		RestoreWorkspace: func(string, string) error {
+			t.Fatalf("expected no restore for a clean non-zero exit")
+			return nil
+		},
+	}
+
+	if _, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{}); err == nil {
+		t.Fatalf("expected failure for a non-zero exit")
+	}
+	if runCalls != 1 {
+		t.Fatalf("expected a single opencode run, got %d", runCalls)
+	}
+}
+
+func TestRunImplementingStageRestoresToLatestPeriodicSnapshot(t *testing.T) {
+	repoPath := t.TempDir()
+	stateDir := t.TempDir()
+
+	manager, err := Open(repoPath, OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	now := time.Date(2026, time.January, 2, 3, 4, 6, 30, time.UTC)
+	current, err := manager.Create("todo-snapshot", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	item := todo.Todo{
+		ID:       "todo-snapshot",
+		Title:    "Example",
+		Type:     todo.TypeTask,
+		Priority: todo.PriorityLow,
+	}
+
+	var mu sync.Mutex
+	running := false
+	restored := false
+	snapshotted := make(chan struct{})
+	var snapshotOnce sync.Once
+	var restoreTarget string
+	runCalls := 0
+	opts := RunOptions{
+		Now:    func() time.Time { return now },
+		Config: &config.Config{Job: config.Job{SnapshotInterval: config.Duration(time.Millisecond)}},
+		CurrentCommitID: func(string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if running && !restored {
+				snapshotOnce.Do(func() { close(snapshotted) })
+				return "snapshot", nil
+			}
+			return "before", nil
+		},
+		CurrentChangeID: func(string) (string, error) {
+			return "change-snapshot", nil
+		},
+		Snapshot: func(string) error { return nil },
+		RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+			runCalls++
+			if runCalls > 1 {
+				return OpencodeRunResult{SessionID: "ses-2", ExitCode: 0}, nil
+			}
+			mu.Lock()
+			running = true
+			mu.Unlock()
+			select {
+			case <-snapshotted:
+			case <-time.After(5 * time.Second):
+				return OpencodeRunResult{}, fmt.Errorf("timed out waiting for periodic snapshot")
+			}
+			return OpencodeRunResult{SessionID: "ses-1", ExitCode: -1}, nil
+		},
+		RestoreWorkspace: func(_ string, commitID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			restoreTarget = commitID
+			restored = true
+			return nil
+		},
+	}
+
+	if _, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{}); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if restoreTarget != "snapshot" {
+		t.Fatalf("expected restore to latest snapshot, got %q", restoreTarget)
+	}
+}
+
 func TestRunImplementingStageTreatsEmptyChangeAsNoChange(t *testing.T) {
 	repoPath := t.TempDir()
 	stateDir := t.TempDir()
@@ -374,7 +578,7 @@ func TestRunImplementingStageTreatsEmptyChangeAsNoChange(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -434,7 +638,7 @@ func TestRunImplementingStageTreatsEmptyChangeAsNoChangeAfterCommit(t *testing.T
 		},
 	}
 
-	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	result, err := runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -493,7 +697,7 @@ func TestRunImplementingStageFailedOpencodeIncludesStderrInMessage(t *testing.T)
 		},
 	}
 
-	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "")
+	_, err = runImplementingStage(manager, current, item, repoPath, repoPath, opts, nil, "", implementingStageState{})
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -0,0 +1,132 @@
+package job
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stageStopTimeout bounds how long an interrupt waits for the running stage
+// to return before restoring the workspace. It outlasts opencode's interrupt
+// grace period, after which the run is killed.
+const stageStopTimeout = 10 * time.Second
+
+// stageOutcome is what a stage goroutine reports when it returns.
+type stageOutcome struct {
+	job Job
+	err error
+}
+
+// stageStop lets an interrupt stop the stage in progress. Each stage starts
+// with a fresh channel that stop closes; opencode runs started through wrap
+// treat it as their Interrupt.
+type stageStop struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// start gives the next stage a fresh, open channel.
+func (s *stageStop) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ch = make(chan struct{})
+}
+
+// stop closes the current stage's channel, interrupting its opencode runs.
+func (s *stageStop) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		return
+	}
+	select {
+	case <-s.ch:
+	default:
+		close(s.ch)
+	}
+}
+
+func (s *stageStop) channel() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ch
+}
+
+// wrap returns run with each opencode run also interrupted when the stage it
+// belongs to is stopped, in addition to any Interrupt it already has.
+func (s *stageStop) wrap(run func(opencodeRunOptions) (OpencodeRunResult, error)) func(opencodeRunOptions) (OpencodeRunResult, error) {
+	if run == nil {
+		return nil
+	}
+	return func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+		stop := s.channel()
+		if stop == nil {
+			return run(runOpts)
+		}
+		interrupt := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+		go func(existing <-chan struct{}) {
+			select {
+			case <-existing:
+			case <-stop:
+			case <-done:
+				return
+			}
+			close(interrupt)
+		}(runOpts.Interrupt)
+		runOpts.Interrupt = interrupt
+		return run(runOpts)
+	}
+}
+
+// waitForStage waits up to stageStopTimeout for a stopped stage to return.
+func waitForStage(outcomes <-chan stageOutcome) error {
+	select {
+	case <-outcomes:
+		return nil
+	case <-time.After(stageStopTimeout):
+		return fmt.Errorf("stage did not stop within %s", stageStopTimeout)
+	}
+}
+
+// stageBaseline holds the commit an interrupt should put the workspace back
+// to: the one the running stage started from, or its latest periodic
+// snapshot. Stages run in their own goroutine, so access is guarded.
+type stageBaseline struct {
+	mu       sync.Mutex
+	commitID string
+}
+
+func (b *stageBaseline) set(commitID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commitID = commitID
+}
+
+func (b *stageBaseline) get() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.commitID
+}
+
+// restoreOnInterruptEnabled reports whether interrupts should restore the
+// workspace. A nil setting means enabled.
+func restoreOnInterruptEnabled(setting *bool) bool {
+	return setting == nil || *setting
+}
+
+// restoreInterruptedWorkspace restores workspacePath to the stage baseline
+// when enabled. Stages that did not record a baseline leave the workspace
+// alone. Callers stop the stage first, so nothing writes to the workspace
+// while it is restored.
+func restoreInterruptedWorkspace(setting *bool, restore func(string, string) error, workspacePath string, baseline *stageBaseline) error {
+	if !restoreOnInterruptEnabled(setting) || restore == nil || baseline == nil {
+		return nil
+	}
+	commitID := baseline.get()
+	if commitID == "" {
+		return nil
+	}
+	return restore(workspacePath, commitID)
+}
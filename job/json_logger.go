@@ -0,0 +1,102 @@
+package job
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLogEntry is the line schema NewJSONLogger writes. Kind names the Logger
+// method that produced the entry; only that kind's fields are set.
+type JSONLogEntry struct {
+	Time time.Time `json:"time"`
+	// Kind is "prompt", "commit_message", "review", or "tests".
+	Kind string `json:"kind"`
+
+	Purpose      string `json:"purpose,omitempty"`
+	Template     string `json:"template,omitempty"`
+	Prompt       string `json:"prompt,omitempty"`
+	Transcript   string `json:"transcript,omitempty"`
+	Label        string `json:"label,omitempty"`
+	Message      string `json:"message,omitempty"`
+	Preformatted bool   `json:"preformatted,omitempty"`
+	Outcome      string `json:"outcome,omitempty"`
+	Details      string `json:"details,omitempty"`
+
+	Results []JSONLogTestResult `json:"results,omitempty"`
+}
+
+// JSONLogTestResult is one test command result in a "tests" entry.
+type JSONLogTestResult struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+type jsonLogger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+}
+
+// NewJSONLogger builds a Logger that writes one JSONLogEntry per line to w,
+// for ingestion by log pipelines. Write errors are ignored, as with
+// ConsoleLogger.
+func NewJSONLogger(w io.Writer) Logger {
+	if w == nil {
+		w = io.Discard
+	}
+	return &jsonLogger{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// Prompt logs a prompt entry.
+func (logger *jsonLogger) Prompt(entry PromptLog) {
+	logger.write(JSONLogEntry{
+		Kind:       "prompt",
+		Purpose:    entry.Purpose,
+		Template:   entry.Template,
+		Prompt:     entry.Prompt,
+		Transcript: entry.Transcript,
+	})
+}
+
+// CommitMessage logs a commit message entry.
+func (logger *jsonLogger) CommitMessage(entry CommitMessageLog) {
+	logger.write(JSONLogEntry{
+		Kind:         "commit_message",
+		Label:        entry.Label,
+		Message:      entry.Message,
+		Preformatted: entry.Preformatted,
+	})
+}
+
+// Review logs review feedback.
+func (logger *jsonLogger) Review(entry ReviewLog) {
+	logger.write(JSONLogEntry{
+		Kind:    "review",
+		Purpose: entry.Purpose,
+		Outcome: string(entry.Feedback.Outcome),
+		Details: entry.Feedback.Details,
+	})
+}
+
+// Tests logs test results.
+func (logger *jsonLogger) Tests(entry TestLog) {
+	results := make([]JSONLogTestResult, 0, len(entry.Results))
+	for _, result := range entry.Results {
+		results = append(results, JSONLogTestResult{
+			Command:  result.Command,
+			ExitCode: result.ExitCode,
+			Output:   result.Output,
+		})
+	}
+	logger.write(JSONLogEntry{Kind: "tests", Results: results})
+}
+
+func (logger *jsonLogger) write(entry JSONLogEntry) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	entry.Time = logger.now().UTC()
+	_ = logger.encoder.Encode(entry)
+}
@@ -0,0 +1,50 @@
+package job
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONLoggerWritesOneObjectPerEntry(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.(*jsonLogger).now = func() time.Time { return now }
+
+	logger.Prompt(PromptLog{Purpose: "implement", Template: "prompt-implementation.tmpl", Prompt: "Do it.", Transcript: "Done."})
+	logger.CommitMessage(CommitMessageLog{Label: "Final", Message: "feat: ship", Preformatted: true})
+	logger.Review(ReviewLog{Purpose: "review", Feedback: ReviewFeedback{Outcome: ReviewOutcomeRequestChanges, Details: "Add tests."}})
+	logger.Tests(TestLog{Results: []TestCommandResult{{Command: "go test ./...", ExitCode: 1, Output: "FAIL"}}})
+
+	stamp := "2026-01-02T03:04:05Z"
+	want := []map[string]any{
+		{"time": stamp, "kind": "prompt", "purpose": "implement", "template": "prompt-implementation.tmpl", "prompt": "Do it.", "transcript": "Done."},
+		{"time": stamp, "kind": "commit_message", "label": "Final", "message": "feat: ship", "preformatted": true},
+		{"time": stamp, "kind": "review", "purpose": "review", "outcome": string(ReviewOutcomeRequestChanges), "details": "Add tests."},
+		{"time": stamp, "kind": "tests", "results": []any{
+			map[string]any{"command": "go test ./...", "exit_code": float64(1), "output": "FAIL"},
+		}},
+	}
+
+	var got []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, entry)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %s", len(want), len(got), buf.String())
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Fatalf("entry %d:\nexpected %#v\ngot      %#v", i, want[i], got[i])
+		}
+	}
+}
@@ -94,7 +94,124 @@ func (writer *logSnapshotWriter) Append(event Event) error {
 				formatLogLabel(opencodeErrorLabel(data.Purpose), documentIndent),
 				formatLogBody(data.Error, subdocumentIndent, false),
 			)
-		case jobEventOpencodeStart, jobEventOpencodeEnd:
+		case jobEventOpencodeTimeout:
+			data, err := decodeEventData[opencodeTimeoutEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel(opencodeTimeoutLabel(data.Purpose), documentIndent),
+				formatLogBody(fmt.Sprintf("Interrupted after %s.", data.Timeout), subdocumentIndent, true),
+			)
+		case jobEventTestsSkipped:
+			data, err := decodeEventData[testsSkippedEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Tests skipped:", documentIndent),
+				formatLogBody(data.Reason, subdocumentIndent, true),
+			)
+		case jobEventWarning:
+			data, err := decodeEventData[warningEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Warning:", documentIndent),
+				formatLogBody(data.Message, subdocumentIndent, true),
+			)
+		case jobEventMaxIterations:
+			data, err := decodeEventData[maxIterationsEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Max iterations exceeded:", documentIndent),
+				formatLogBody(fmt.Sprintf("Stopped after %d implement/review iterations.", data.MaxIterations), subdocumentIndent, true),
+			)
+		case jobEventAbandoned:
+			data, err := decodeEventData[abandonedEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Job abandoned:", documentIndent),
+				formatLogBody(data.Reason, subdocumentIndent, true),
+			)
+		case jobEventWorkspaceDirty:
+			data, err := decodeEventData[workspaceDirtyEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Workspace not clean:", documentIndent),
+				formatLogBody(workspaceDirtyLogBody(data), subdocumentIndent, true),
+			)
+		case jobEventDiffTooLarge:
+			data, err := decodeEventData[diffTooLargeEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Diff too large:", documentIndent),
+				formatLogBody(fmt.Sprintf("Changed %d lines; the limit is %d.", data.ChangedLines, data.MaxDiffLines), subdocumentIndent, true),
+			)
+		case jobEventLogRotated:
+			data, err := decodeEventData[logRotatedEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Log rotated:", documentIndent),
+				formatLogBody(fmt.Sprintf("Earlier events (%d bytes) moved to %s.", data.RotatedBytes, data.RotatedPath), subdocumentIndent, true),
+			)
+		case jobEventCommitInvalid:
+			data, err := decodeEventData[commitMessageInvalidEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Commit message invalid:", documentIndent),
+				formatLogBody(commitMessageInvalidLogBody(data), subdocumentIndent, true),
+			)
+		case jobEventConflicted:
+			data, err := decodeEventData[conflictDetectedEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Conflicts detected:", documentIndent),
+				formatLogBody(conflictDetectedLogBody(data), subdocumentIndent, true),
+			)
+		case jobEventBookmarkUpdated:
+			data, err := decodeEventData[bookmarkUpdatedEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Bookmark updated:", documentIndent),
+				formatLogBody(fmt.Sprintf("Moved %s to %s.", data.Bookmark, data.CommitID), subdocumentIndent, true),
+			)
+		case jobEventDiffPreview:
+			data, err := decodeEventData[diffPreviewEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel("Diff preview:", documentIndent),
+				formatLogBody(data.Diff, subdocumentIndent, false),
+			)
+		case jobEventDryRun:
+			data, err := decodeEventData[dryRunEventData](event.Data)
+			if err != nil {
+				return err
+			}
+			writer.writeBlock(
+				formatLogLabel(dryRunLabel(data), documentIndent),
+				formatLogBody(dryRunBody(data), subdocumentIndent, true),
+			)
+		case jobEventOpencodeStart, jobEventOpencodeEnd, jobEventOpencodeOutput, jobEventResult, jobEventStageDuration, jobEventUsage:
 			return nil
 		default:
 			return nil
@@ -127,6 +244,34 @@ func opencodeErrorLabel(purpose string) string {
 	return fmt.Sprintf("Opencode %s error:", label)
 }
 
+func opencodeTimeoutLabel(purpose string) string {
+	trimmed, ok := trimmedLabelValue(purpose)
+	if !ok {
+		return "Opencode timed out:"
+	}
+	label := strings.ReplaceAll(trimmed, "-", " ")
+	return fmt.Sprintf("Opencode %s timed out:", label)
+}
+
+func dryRunLabel(data dryRunEventData) string {
+	if data.Action == "commit" {
+		return "Dry run skipped commit:"
+	}
+	trimmed, ok := trimmedLabelValue(data.Purpose)
+	if !ok {
+		return "Dry run skipped opencode:"
+	}
+	label := strings.ReplaceAll(trimmed, "-", " ")
+	return fmt.Sprintf("Dry run skipped opencode %s:", label)
+}
+
+func dryRunBody(data dryRunEventData) string {
+	if data.Action == "commit" {
+		return "Would have committed with the final commit message."
+	}
+	return "Would have run opencode with the prompt above."
+}
+
 func trimmedLabelValue(value string) (string, bool) {
 	trimmed := internalstrings.TrimSpace(value)
 	if trimmed == "" {
@@ -1,6 +1,7 @@
 package job
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -12,21 +13,27 @@ import (
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
 
-// StaleJobTimeout is the duration after which an active job is considered stale
-// and should be marked as failed. Jobs that haven't been updated within this
-// duration are assumed to be orphaned (e.g., the process crashed or was killed).
+// StaleJobTimeout is the default duration after which an active job is
+// considered stale and should be marked as failed. Jobs that haven't been
+// updated within this duration are assumed to be orphaned (e.g., the process
+// crashed or was killed).
 const StaleJobTimeout = 10 * time.Minute
 
 // OpenOptions configures a job manager.
 type OpenOptions struct {
 	// StateDir is the directory where job state is stored.
 	StateDir string
+
+	// StaleAfter overrides StaleJobTimeout for MarkStaleJobsFailed.
+	// Zero uses StaleJobTimeout.
+	StaleAfter time.Duration
 }
 
 // Manager provides access to job state for a repo.
 type Manager struct {
 	repoPath   string
 	stateStore *statestore.Store
+	staleAfter time.Duration
 }
 
 // Open opens a job manager for the given repo.
@@ -36,9 +43,15 @@ func Open(repoPath string, opts OpenOptions) (*Manager, error) {
 		return nil, err
 	}
 
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = StaleJobTimeout
+	}
+
 	return &Manager{
 		repoPath:   repoPath,
 		stateStore: statestore.NewStore(stateDir),
+		staleAfter: staleAfter,
 	}, nil
 }
 
@@ -48,6 +61,8 @@ type CreateOptions struct {
 	ImplementationModel string
 	CodeReviewModel     string
 	ProjectReviewModel  string
+	// WorkspacePath records where the job runs so it can be resumed there.
+	WorkspacePath string
 }
 
 // Create stores a new job with active status and implementing stage.
@@ -70,6 +85,7 @@ func (m *Manager) Create(todoID string, startedAt time.Time, opts CreateOptions)
 		ImplementationModel: internalstrings.TrimSpace(opts.ImplementationModel),
 		CodeReviewModel:     internalstrings.TrimSpace(opts.CodeReviewModel),
 		ProjectReviewModel:  internalstrings.TrimSpace(opts.ProjectReviewModel),
+		Workspace:           internalstrings.TrimSpace(opts.WorkspacePath),
 		Stage:               StageImplementing,
 		Status:              StatusActive,
 		CreatedAt:           startedAt,
@@ -94,11 +110,19 @@ type UpdateOptions struct {
 	Stage                 *Stage
 	Status                *Status
 	Feedback              *string
+	Reason                *string
 	AppendOpencodeSession *OpencodeSession
 }
 
 // Update updates an existing job by id or prefix.
 func (m *Manager) Update(jobID string, opts UpdateOptions, updatedAt time.Time) (Job, error) {
+	return m.UpdateContext(context.Background(), jobID, opts, updatedAt)
+}
+
+// UpdateContext is Update with cancellation while waiting for the state lock
+// and between reading and writing state. When ctx ends the update, the
+// error is ctx.Err().
+func (m *Manager) UpdateContext(ctx context.Context, jobID string, opts UpdateOptions, updatedAt time.Time) (Job, error) {
 	if internalstrings.IsBlank(jobID) {
 		return Job{}, ErrJobNotFound
 	}
@@ -118,7 +142,7 @@ func (m *Manager) Update(jobID string, opts UpdateOptions, updatedAt time.Time)
 		}
 	}
 
-	found, err := m.Find(jobID)
+	found, err := m.FindContext(ctx, jobID)
 	if err != nil {
 		return Job{}, err
 	}
@@ -128,7 +152,7 @@ func (m *Manager) Update(jobID string, opts UpdateOptions, updatedAt time.Time)
 	}
 
 	var updated Job
-	err = m.stateStore.Update(func(st *statestore.State) error {
+	err = m.stateStore.UpdateContext(ctx, func(st *statestore.State) error {
 		key := found.Repo + "/" + found.ID
 		job, ok := st.Jobs[key]
 		if !ok {
@@ -139,13 +163,18 @@ func (m *Manager) Update(jobID string, opts UpdateOptions, updatedAt time.Time)
 		}
 		if opts.Status != nil {
 			job.Status = *opts.Status
-			if job.Status != StatusActive {
+			if job.Status == StatusActive || job.Status == StatusPaused {
+				job.CompletedAt = time.Time{}
+			} else {
 				job.CompletedAt = updatedAt
 			}
 		}
 		if opts.Feedback != nil {
 			job.Feedback = *opts.Feedback
 		}
+		if opts.Reason != nil {
+			job.Reason = *opts.Reason
+		}
 		if opts.AppendOpencodeSession != nil {
 			job.OpencodeSessions = append(job.OpencodeSessions, *opts.AppendOpencodeSession)
 		}
@@ -161,6 +190,23 @@ func (m *Manager) Update(jobID string, opts UpdateOptions, updatedAt time.Time)
 	return updated, nil
 }
 
+// Delete removes a job record by id or prefix. Dry runs use it so preview
+// jobs never show up in job listings.
+func (m *Manager) Delete(jobID string) error {
+	found, err := m.Find(jobID)
+	if err != nil {
+		return err
+	}
+	return m.stateStore.Update(func(st *statestore.State) error {
+		key := found.Repo + "/" + found.ID
+		if _, ok := st.Jobs[key]; !ok {
+			return ErrJobNotFound
+		}
+		delete(st.Jobs, key)
+		return nil
+	})
+}
+
 // JobCommitUpdate describes in-place updates to the current commit.
 // Nil fields mean "do not update".
 type JobCommitUpdate struct {
@@ -338,12 +384,24 @@ func (m *Manager) SetProjectReview(jobID string, review JobReview, now time.Time
 type ListFilter struct {
 	// Status filters by exact status match.
 	Status *Status
-	// IncludeAll includes jobs regardless of status.
+	// IncludeAll includes jobs regardless of status. Otherwise only active
+	// and paused jobs are listed.
 	IncludeAll bool
+	// TodoID limits the list to jobs for one todo, given its full ID or a
+	// prefix of it. A prefix shared by several todos' jobs is an error.
+	TodoID string
+	// Stage filters by exact stage match.
+	Stage *Stage
 }
 
 // List returns jobs for the repo.
 func (m *Manager) List(filter ListFilter) ([]Job, error) {
+	return m.ListContext(context.Background(), filter)
+}
+
+// ListContext is List with cancellation while waiting for the state lock and
+// before reading state. When ctx ends the call, the error is ctx.Err().
+func (m *Manager) ListContext(ctx context.Context, filter ListFilter) ([]Job, error) {
 	if filter.Status != nil {
 		normalized := normalizeStatus(*filter.Status)
 		filter.Status = &normalized
@@ -351,11 +409,24 @@ func (m *Manager) List(filter ListFilter) ([]Job, error) {
 			return nil, formatInvalidStatusError(*filter.Status)
 		}
 	}
+	if filter.Stage != nil {
+		normalized := normalizeStage(*filter.Stage)
+		filter.Stage = &normalized
+		if !filter.Stage.IsValid() {
+			return nil, formatInvalidStageError(*filter.Stage)
+		}
+	}
 
-	repoName, err := m.stateStore.GetOrCreateRepoName(m.repoPath)
+	repoName, err := m.stateStore.GetOrCreateRepoNameContext(ctx, m.repoPath)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, fmt.Errorf("get repo name: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	st, err := m.stateStore.Load()
 	if err != nil {
@@ -371,12 +442,22 @@ func (m *Manager) List(filter ListFilter) ([]Job, error) {
 			if job.Status != *filter.Status {
 				continue
 			}
-		} else if !filter.IncludeAll && job.Status != StatusActive {
+		} else if !filter.IncludeAll && job.Status != StatusActive && job.Status != StatusPaused {
+			continue
+		}
+		if filter.Stage != nil && job.Stage != *filter.Stage {
 			continue
 		}
 		items = append(items, job)
 	}
 
+	if !internalstrings.IsBlank(filter.TodoID) {
+		items, err = filterJobsByTodoID(items, filter.TodoID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sort.Slice(items, func(i, j int) bool {
 		if items[i].StartedAt.Equal(items[j].StartedAt) {
 			return items[i].ID < items[j].ID
@@ -387,16 +468,51 @@ func (m *Manager) List(filter ListFilter) ([]Job, error) {
 	return items, nil
 }
 
+// filterJobsByTodoID keeps the jobs whose todo ID matches todoID exactly or by
+// prefix.
+func filterJobsByTodoID(items []Job, todoID string) ([]Job, error) {
+	todoIDs := make([]string, 0, len(items))
+	for _, job := range items {
+		todoIDs = append(todoIDs, job.TodoID)
+	}
+	matchID, found, ambiguous := ids.MatchPrefix(todoIDs, todoID)
+	if ambiguous {
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousTodoIDPrefix, todoID)
+	}
+	if !found {
+		return []Job{}, nil
+	}
+	filtered := make([]Job, 0, len(items))
+	for _, job := range items {
+		if strings.EqualFold(job.TodoID, matchID) {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered, nil
+}
+
 // Find returns the job with the given id or prefix for the repo.
 func (m *Manager) Find(jobID string) (Job, error) {
+	return m.FindContext(context.Background(), jobID)
+}
+
+// FindContext is Find with cancellation while waiting for the state lock and
+// before reading state. When ctx ends the call, the error is ctx.Err().
+func (m *Manager) FindContext(ctx context.Context, jobID string) (Job, error) {
 	if jobID == "" {
 		return Job{}, ErrJobNotFound
 	}
 
-	repoName, err := m.stateStore.GetOrCreateRepoName(m.repoPath)
+	repoName, err := m.stateStore.GetOrCreateRepoNameContext(ctx, m.repoPath)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Job{}, ctxErr
+		}
 		return Job{}, fmt.Errorf("get repo name: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return Job{}, err
+	}
 
 	st, err := m.stateStore.Load()
 	if err != nil {
@@ -424,15 +540,36 @@ func (m *Manager) Find(jobID string) (Job, error) {
 	return jobsByID[matchID], nil
 }
 
+// ChangeIDs returns the jj change IDs of the job's changes in the order they
+// were created, so callers can find the exact changes a job produced. Changes
+// recorded without an ID are skipped, and each ID appears once.
+func (m *Manager) ChangeIDs(jobID string) ([]string, error) {
+	found, err := m.Find(jobID)
+	if err != nil {
+		return nil, err
+	}
+	changeIDs := make([]string, 0, len(found.Changes))
+	seen := make(map[string]bool, len(found.Changes))
+	for _, change := range found.Changes {
+		if change.ChangeID == "" || seen[change.ChangeID] {
+			continue
+		}
+		seen[change.ChangeID] = true
+		changeIDs = append(changeIDs, change.ChangeID)
+	}
+	return changeIDs, nil
+}
+
 // MarkStaleJobsFailed finds active jobs that haven't been updated within the
-// StaleJobTimeout and marks them as failed. Returns the number of jobs marked.
+// manager's stale threshold (OpenOptions.StaleAfter, or StaleJobTimeout) and
+// marks them as failed. Returns the number of jobs marked.
 func (m *Manager) MarkStaleJobsFailed(now time.Time) (int, error) {
 	repoName, err := m.stateStore.GetOrCreateRepoName(m.repoPath)
 	if err != nil {
 		return 0, fmt.Errorf("get repo name: %w", err)
 	}
 
-	cutoff := now.Add(-StaleJobTimeout)
+	cutoff := now.Add(-m.staleAfter)
 	marked := 0
 
 	err = m.stateStore.Update(func(st *statestore.State) error {
@@ -465,10 +602,19 @@ func (m *Manager) MarkStaleJobsFailed(now time.Time) (int, error) {
 // IsJobStale returns true if the job is active but hasn't been updated within
 // the StaleJobTimeout.
 func IsJobStale(job Job, now time.Time) bool {
+	return IsJobStaleAfter(job, now, StaleJobTimeout)
+}
+
+// IsJobStaleAfter returns true if the job is active but hasn't been updated
+// within staleAfter. Zero uses StaleJobTimeout.
+func IsJobStaleAfter(job Job, now time.Time, staleAfter time.Duration) bool {
 	if job.Status != StatusActive {
 		return false
 	}
-	cutoff := now.Add(-StaleJobTimeout)
+	if staleAfter <= 0 {
+		staleAfter = StaleJobTimeout
+	}
+	cutoff := now.Add(-staleAfter)
 	return !job.UpdatedAt.After(cutoff)
 }
 
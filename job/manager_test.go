@@ -1,7 +1,12 @@
 package job
 
 import (
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -64,6 +69,24 @@ func TestManager_CreateAndFind(t *testing.T) {
 	}
 }
 
+func TestManager_Delete(t *testing.T) {
+	manager, err := Open("/Users/test/my-repo", OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	created, err := manager.Create("todo-123", time.Date(2025, 4, 10, 8, 30, 0, 0, time.UTC), CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if err := manager.Delete(created.ID); err != nil {
+		t.Fatalf("delete job: %v", err)
+	}
+	if _, err := manager.Find(created.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound after delete, got %v", err)
+	}
+}
+
 func TestManager_Find_PrefixAmbiguous(t *testing.T) {
 	tmpDir := t.TempDir()
 	repoPath := "/Users/test/ambiguous"
@@ -206,6 +229,191 @@ func TestManager_List_Filtering(t *testing.T) {
 	}
 }
 
+func TestManager_List_FiltersByTodoIDAndStage(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := "/Users/test/todo-filter"
+	manager, err := Open(repoPath, OpenOptions{StateDir: tmpDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	store := statestore.NewStore(tmpDir)
+	repoSlug, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo slug: %v", err)
+	}
+
+	startedAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	jobs := []statestore.Job{
+		{ID: "job-a2", TodoID: "abc123", Stage: statestore.JobStageReviewing, Status: statestore.JobStatusFailed, StartedAt: startedAt.Add(time.Hour)},
+		{ID: "job-a1", TodoID: "abc123", Stage: statestore.JobStageImplementing, Status: statestore.JobStatusActive, StartedAt: startedAt},
+		{ID: "job-b1", TodoID: "abd456", Stage: statestore.JobStageReviewing, Status: statestore.JobStatusActive, StartedAt: startedAt.Add(30 * time.Minute)},
+	}
+	for _, item := range jobs {
+		item.Repo = repoSlug
+		item.CreatedAt = item.StartedAt
+		item.UpdatedAt = item.StartedAt
+		if err := insertJob(store, repoSlug, item); err != nil {
+			t.Fatalf("insert job %s: %v", item.ID, err)
+		}
+	}
+
+	byPrefix, err := manager.List(ListFilter{IncludeAll: true, TodoID: "ABC"})
+	if err != nil {
+		t.Fatalf("list by todo prefix: %v", err)
+	}
+	if len(byPrefix) != 2 || byPrefix[0].ID != "job-a1" || byPrefix[1].ID != "job-a2" {
+		t.Fatalf("expected both abc123 jobs in start order, got %v", byPrefix)
+	}
+
+	exact, err := manager.List(ListFilter{TodoID: "abd456"})
+	if err != nil {
+		t.Fatalf("list by todo id: %v", err)
+	}
+	if len(exact) != 1 || exact[0].ID != "job-b1" {
+		t.Fatalf("expected the abd456 job, got %v", exact)
+	}
+
+	if _, err := manager.List(ListFilter{IncludeAll: true, TodoID: "ab"}); !errors.Is(err, ErrAmbiguousTodoIDPrefix) {
+		t.Fatalf("expected ambiguous todo prefix error, got %v", err)
+	}
+
+	missing, err := manager.List(ListFilter{IncludeAll: true, TodoID: "zzz"})
+	if err != nil {
+		t.Fatalf("list by missing todo: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("expected no jobs for unknown todo, got %v", missing)
+	}
+
+	stage := Stage("REVIEWING")
+	reviewing, err := manager.List(ListFilter{IncludeAll: true, Stage: &stage})
+	if err != nil {
+		t.Fatalf("list by stage: %v", err)
+	}
+	if len(reviewing) != 2 || reviewing[0].ID != "job-b1" || reviewing[1].ID != "job-a2" {
+		t.Fatalf("expected reviewing jobs in start order, got %v", reviewing)
+	}
+
+	combined, err := manager.List(ListFilter{IncludeAll: true, TodoID: "abc", Stage: &stage})
+	if err != nil {
+		t.Fatalf("list by todo and stage: %v", err)
+	}
+	if len(combined) != 1 || combined[0].ID != "job-a2" {
+		t.Fatalf("expected the reviewing abc123 job, got %v", combined)
+	}
+
+	invalid := Stage("shipping")
+	if _, err := manager.List(ListFilter{Stage: &invalid}); !errors.Is(err, ErrInvalidStage) {
+		t.Fatalf("expected invalid stage error, got %v", err)
+	}
+}
+
+func TestManager_List_SummarizesTestResults(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := "/Users/test/tests-passed"
+	manager, err := Open(repoPath, OpenOptions{StateDir: tmpDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	store := statestore.NewStore(tmpDir)
+	repoSlug, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo slug: %v", err)
+	}
+
+	passed, failed := true, false
+	startedAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	jobs := []statestore.Job{
+		{ID: "job-mixed", Changes: []statestore.JobChange{
+			{ChangeID: "a", Commits: []statestore.JobCommit{{CommitID: "a1", TestsPassed: &passed}, {CommitID: "a2", TestsPassed: &failed}}},
+			{ChangeID: "b", Commits: []statestore.JobCommit{{CommitID: "b1", TestsPassed: &passed}}},
+		}},
+		{ID: "job-passed", Changes: []statestore.JobChange{
+			{ChangeID: "c", Commits: []statestore.JobCommit{{CommitID: "c1", TestsPassed: &passed}, {CommitID: "c2"}}},
+		}},
+		{ID: "job-untested", Changes: []statestore.JobChange{
+			{ChangeID: "d", Commits: []statestore.JobCommit{{CommitID: "d1"}}},
+		}},
+		{ID: "job-empty"},
+	}
+	for i, item := range jobs {
+		item.Repo = repoSlug
+		item.TodoID = "todo-" + item.ID
+		item.Stage = statestore.JobStageImplementing
+		item.Status = statestore.JobStatusActive
+		item.StartedAt = startedAt.Add(time.Duration(i) * time.Minute)
+		if err := insertJob(store, repoSlug, item); err != nil {
+			t.Fatalf("insert job %s: %v", item.ID, err)
+		}
+	}
+
+	listed, err := manager.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(listed) != len(jobs) {
+		t.Fatalf("expected %d jobs, got %d", len(jobs), len(listed))
+	}
+
+	want := map[string]*bool{
+		"job-mixed":    &failed,
+		"job-passed":   &passed,
+		"job-untested": nil,
+		"job-empty":    nil,
+	}
+	for _, item := range listed {
+		expected := want[item.ID]
+		got := item.AllTestsPassed()
+		switch {
+		case expected == nil && got != nil:
+			t.Errorf("%s: expected nil, got %v", item.ID, *got)
+		case expected != nil && got == nil:
+			t.Errorf("%s: expected %v, got nil", item.ID, *expected)
+		case expected != nil && *got != *expected:
+			t.Errorf("%s: expected %v, got %v", item.ID, *expected, *got)
+		}
+	}
+}
+
+func TestManager_UpdateContextCanceledWhileStateLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := Open("/Users/test/update-context", OpenOptions{StateDir: tmpDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	startedAt := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	created, err := manager.Create("todo-456", startedAt, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	// Another process holds the state lock.
+	holder, err := os.OpenFile(filepath.Join(tmpDir, "state.lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		t.Fatalf("open lock file: %v", err)
+	}
+	defer holder.Close()
+	if err := syscall.Flock(int(holder.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatalf("hold lock: %v", err)
+	}
+	defer syscall.Flock(int(holder.Fd()), syscall.LOCK_UN)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	status := StatusFailed
+	start := time.Now()
+	_, err = manager.UpdateContext(ctx, created.ID, UpdateOptions{Status: &status}, startedAt.Add(time.Hour))
+	if err != context.Canceled {
+		t.Fatalf("expected bare context canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected canceled update to return promptly, took %s", elapsed)
+	}
+}
+
 func TestManager_Update(t *testing.T) {
 	tmpDir := t.TempDir()
 	repoPath := "/Users/test/update"
@@ -533,6 +741,47 @@ func insertJob(store *statestore.Store, repoSlug string, item statestore.Job) er
 	})
 }
 
+func TestManager_ChangeIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := "/Users/test/change-ids"
+	manager, err := Open(repoPath, OpenOptions{StateDir: tmpDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	now := time.Date(2026, 1, 18, 10, 0, 0, 0, time.UTC)
+	created, err := manager.Create("todo-change-ids", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	changeIDs, err := manager.ChangeIDs(created.ID)
+	if err != nil {
+		t.Fatalf("change ids: %v", err)
+	}
+	if len(changeIDs) != 0 {
+		t.Fatalf("expected no change ids for a new job, got %v", changeIDs)
+	}
+
+	for i, changeID := range []string{"chg-1", "", "chg-2", "chg-1", "chg-3"} {
+		if _, err := manager.AppendChange(created.ID, JobChange{ChangeID: changeID}, now.Add(time.Duration(i+1)*time.Minute)); err != nil {
+			t.Fatalf("append change: %v", err)
+		}
+	}
+
+	changeIDs, err = manager.ChangeIDs(created.ID[:4])
+	if err != nil {
+		t.Fatalf("change ids: %v", err)
+	}
+	if got := strings.Join(changeIDs, ","); got != "chg-1,chg-2,chg-3" {
+		t.Fatalf("expected change ids in creation order, got %v", changeIDs)
+	}
+
+	if _, err := manager.ChangeIDs("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
 func TestManager_MarkStaleJobsFailed(t *testing.T) {
 	tmpDir := t.TempDir()
 	repoPath := "/Users/test/stale"
@@ -757,6 +1006,70 @@ func TestIsJobStale(t *testing.T) {
 	}
 }
 
+func TestManager_MarkStaleJobsFailed_CustomThreshold(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := "/Users/test/stale-custom"
+	store := statestore.NewStore(tmpDir)
+	repoSlug, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo slug: %v", err)
+	}
+
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	for _, item := range []statestore.Job{
+		{ID: "two-minutes", UpdatedAt: now.Add(-2 * time.Minute)},
+		{ID: "fifteen-minutes", UpdatedAt: now.Add(-15 * time.Minute)},
+	} {
+		item.Repo = repoSlug
+		item.TodoID = "todo-" + item.ID
+		item.Stage = statestore.JobStageImplementing
+		item.Status = statestore.JobStatusActive
+		item.CreatedAt = item.UpdatedAt
+		item.StartedAt = item.UpdatedAt
+		if err := insertJob(store, repoSlug, item); err != nil {
+			t.Fatalf("insert job: %v", err)
+		}
+	}
+
+	// A longer threshold spares the job the default would mark.
+	patient, err := Open(repoPath, OpenOptions{StateDir: tmpDir, StaleAfter: time.Hour})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	marked, err := patient.MarkStaleJobsFailed(now)
+	if err != nil {
+		t.Fatalf("mark stale jobs: %v", err)
+	}
+	if marked != 0 {
+		t.Fatalf("expected no jobs marked with a 1h threshold, got %d", marked)
+	}
+
+	// A shorter threshold catches the job the default would spare.
+	eager, err := Open(repoPath, OpenOptions{StateDir: tmpDir, StaleAfter: time.Minute})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	marked, err = eager.MarkStaleJobsFailed(now)
+	if err != nil {
+		t.Fatalf("mark stale jobs: %v", err)
+	}
+	if marked != 2 {
+		t.Fatalf("expected both jobs marked with a 1m threshold, got %d", marked)
+	}
+}
+
+func TestIsJobStaleAfter(t *testing.T) {
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	job := Job{Status: StatusActive, UpdatedAt: now.Add(-5 * time.Minute)}
+
+	if !IsJobStaleAfter(job, now, 2*time.Minute) {
+		t.Fatalf("expected job idle for 5m to be stale after 2m")
+	}
+	if IsJobStaleAfter(job, now, 0) {
+		t.Fatalf("expected zero threshold to use the 10m default")
+	}
+}
+
 func TestManager_CountByHabit(t *testing.T) {
 	tmpDir := t.TempDir()
 	repoPath := "/Users/test/count-repo"
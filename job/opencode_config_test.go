@@ -0,0 +1,55 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+func TestOpencodeConfigJSONMergesConfiguredPolicy(t *testing.T) {
+	cfg := &config.Config{Job: config.Job{Opencode: config.JobOpencode{
+		Config: `{"permission": {"question": "ask", "bash": {"jj status": "allow", "jj *": "ask"}}, "model": "local/test"}`,
+	}}}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(opencodeConfigJSON(cfg)), &got); err != nil {
+		t.Fatalf("decode opencode config: %v", err)
+	}
+	if got["model"] != "local/test" {
+		t.Fatalf("expected configured top-level key, got %v", got["model"])
+	}
+	permission := got["permission"].(map[string]any)
+	if permission["question"] != "ask" {
+		t.Fatalf("expected question override, got %v", permission["question"])
+	}
+	bash := permission["bash"].(map[string]any)
+	for pattern, want := range map[string]string{
+		"*":         "allow",
+		"jj *":      "ask",
+		"jj status": "allow",
+		"jj diff":   "allow",
+	} {
+		if bash[pattern] != want {
+			t.Fatalf("expected bash[%q] = %q, got %v", pattern, want, bash[pattern])
+		}
+	}
+
+	if opencodeConfigJSON(&config.Config{}) != opencodeConfigJSON(nil) {
+		t.Fatalf("expected an unset config to use the defaults")
+	}
+	if _, ok := opencodeConfig["model"]; ok {
+		t.Fatalf("expected merging not to modify the defaults")
+	}
+}
+
+func TestEnsureOpencodeConfigEnvKeepsStageValue(t *testing.T) {
+	env := ensureOpencodeConfigEnv([]string{opencodeConfigEnvVar + "={}"})
+	if value, _ := lookupEnvVar(env, opencodeConfigEnvVar); value != "{}" {
+		t.Fatalf("expected stage config to be kept, got %q", value)
+	}
+	env = ensureOpencodeConfigEnv([]string{"HOME=/tmp"})
+	if value, _ := lookupEnvVar(env, opencodeConfigEnvVar); value != opencodeConfigJSON(nil) {
+		t.Fatalf("expected default config, got %q", value)
+	}
+}
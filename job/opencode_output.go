@@ -0,0 +1,114 @@
+package job
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultCaptureOutputLimit caps how many bytes of opencode output one
+	// run records when RunOptions.CaptureOutput is set.
+	DefaultCaptureOutputLimit = 1 << 20
+
+	// opencodeOutputChunkSize caps the output carried by a single
+	// job.opencode.output event.
+	opencodeOutputChunkSize = 4 << 10
+)
+
+// opencodeOutputEventData carries a chunk of opencode's stdout or stderr.
+type opencodeOutputEventData struct {
+	Purpose string `json:"purpose"`
+	// Stream is "stdout" or "stderr".
+	Stream string `json:"stream"`
+	Output string `json:"output,omitempty"`
+	// Truncated marks the final event of a run whose output reached the
+	// capture limit; later output is dropped.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// outputCapture records one opencode run's output as job.opencode.output
+// events, up to a byte limit shared by both streams.
+type outputCapture struct {
+	log     *EventLog
+	purpose string
+	limit   int
+
+	mu        sync.Mutex
+	written   int
+	truncated bool
+	err       error
+}
+
+func newOutputCapture(log *EventLog, purpose string, limit int) *outputCapture {
+	if limit <= 0 {
+		limit = DefaultCaptureOutputLimit
+	}
+	return &outputCapture{log: log, purpose: purpose, limit: limit}
+}
+
+// writer returns an io.Writer that records output for the named stream.
+// Writes never fail, so a logging problem can't break the opencode run; the
+// first error is reported by Err.
+func (c *outputCapture) writer(stream string) *outputCaptureWriter {
+	return &outputCaptureWriter{capture: c, stream: stream}
+}
+
+// Err returns the first error encountered while recording output.
+func (c *outputCapture) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *outputCapture) record(stream string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.truncated || c.err != nil {
+		return
+	}
+	for len(data) > 0 {
+		if c.written >= c.limit {
+			c.truncated = true
+			c.append(opencodeOutputEventData{Purpose: c.purpose, Stream: stream, Truncated: true})
+			return
+		}
+		size := min(len(data), opencodeOutputChunkSize, c.limit-c.written)
+		size = runeBoundary(data, size)
+		c.append(opencodeOutputEventData{Purpose: c.purpose, Stream: stream, Output: string(data[:size])})
+		if c.err != nil {
+			return
+		}
+		c.written += size
+		data = data[size:]
+	}
+}
+
+func (c *outputCapture) append(data opencodeOutputEventData) {
+	if err := appendJobEvent(c.log, jobEventOpencodeOutput, data); err != nil {
+		c.err = err
+	}
+}
+
+// runeBoundary shortens size so data[:size] doesn't end mid-rune, unless
+// that would leave nothing.
+func runeBoundary(data []byte, size int) int {
+	if size >= len(data) {
+		return size
+	}
+	for end := size; end > 0 && size-end < utf8.UTFMax; end-- {
+		if utf8.RuneStart(data[end]) {
+			return end
+		}
+	}
+	return size
+}
+
+type outputCaptureWriter struct {
+	capture *outputCapture
+	stream  string
+}
+
+func (w *outputCaptureWriter) Write(p []byte) (int, error) {
+	w.capture.record(w.stream, p)
+	return len(p), nil
+}
@@ -0,0 +1,122 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunOpencodeWithEventsCapturesOutput(t *testing.T) {
+	eventLogOpts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog("job-capture", eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	opts := RunOptions{
+		Now:                func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) },
+		EventLog:           eventLog,
+		CaptureOutput:      true,
+		CaptureOutputLimit: 10,
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			fmt.Fprint(runOpts.Stdout, "hello ")
+			fmt.Fprint(runOpts.Stderr, "warn: ")
+			fmt.Fprint(runOpts.Stdout, "dropped")
+			return OpencodeRunResult{SessionID: "ses-1"}, nil
+		},
+	}
+
+	if _, err := runOpencodeWithEvents(opts, opencodeRunOptions{}, "implement"); err != nil {
+		t.Fatalf("run opencode: %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("job-capture", eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	var outputs []opencodeOutputEventData
+	for _, event := range events {
+		if event.Name != jobEventOpencodeOutput {
+			continue
+		}
+		var data opencodeOutputEventData
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			t.Fatalf("decode output event: %v", err)
+		}
+		outputs = append(outputs, data)
+	}
+
+	want := []opencodeOutputEventData{
+		{Purpose: "implement", Stream: "stdout", Output: "hello "},
+		{Purpose: "implement", Stream: "stderr", Output: "warn"},
+		{Purpose: "implement", Stream: "stderr", Truncated: true},
+	}
+	if len(outputs) != len(want) {
+		t.Fatalf("expected %d output events, got %+v", len(want), outputs)
+	}
+	for i := range want {
+		if outputs[i] != want[i] {
+			t.Fatalf("output event %d: expected %+v, got %+v", i, want[i], outputs[i])
+		}
+	}
+}
+
+func TestRunOpencodeWithEventsSkipsOutputByDefault(t *testing.T) {
+	opts := RunOptions{
+		Now: time.Now,
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			if runOpts.Stdout != nil || runOpts.Stderr != nil {
+				t.Error("expected no output writers without CaptureOutput")
+			}
+			return OpencodeRunResult{}, nil
+		},
+	}
+	if _, err := runOpencodeWithEvents(opts, opencodeRunOptions{}, "implement"); err != nil {
+		t.Fatalf("run opencode: %v", err)
+	}
+}
+
+func TestOutputCaptureChunksOnRuneBoundaries(t *testing.T) {
+	eventLogOpts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog("job-chunks", eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	// Offset by one byte so a chunk boundary lands inside a 3-byte rune.
+	input := "x" + strings.Repeat("€", opencodeOutputChunkSize)
+	capture := newOutputCapture(eventLog, "review", 0)
+	if _, err := capture.writer("stdout").Write([]byte(input)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := capture.Err(); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("job-chunks", eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	var rebuilt strings.Builder
+	for _, event := range events {
+		var data opencodeOutputEventData
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			t.Fatalf("decode output event: %v", err)
+		}
+		if len(data.Output) > opencodeOutputChunkSize {
+			t.Fatalf("expected chunks of at most %d bytes, got %d", opencodeOutputChunkSize, len(data.Output))
+		}
+		rebuilt.WriteString(data.Output)
+	}
+	if len(events) < 2 || rebuilt.String() != input {
+		t.Fatalf("expected %d bytes split across events intact, got %d bytes in %d events", len(input), rebuilt.Len(), len(events))
+	}
+}
@@ -0,0 +1,164 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/opencode"
+)
+
+// OpencodeUsage is the token counts and cost an opencode session reported.
+// Sessions that report no usage leave every field zero.
+type OpencodeUsage struct {
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	ReasoningTokens  int64   `json:"reasoning_tokens"`
+	CacheReadTokens  int64   `json:"cache_read_tokens"`
+	CacheWriteTokens int64   `json:"cache_write_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Add returns the sum of u and other.
+func (u OpencodeUsage) Add(other OpencodeUsage) OpencodeUsage {
+	return OpencodeUsage{
+		InputTokens:      u.InputTokens + other.InputTokens,
+		OutputTokens:     u.OutputTokens + other.OutputTokens,
+		ReasoningTokens:  u.ReasoningTokens + other.ReasoningTokens,
+		CacheReadTokens:  u.CacheReadTokens + other.CacheReadTokens,
+		CacheWriteTokens: u.CacheWriteTokens + other.CacheWriteTokens,
+		Cost:             u.Cost + other.Cost,
+	}
+}
+
+// IsZero reports whether no usage was recorded.
+func (u OpencodeUsage) IsZero() bool {
+	return u == OpencodeUsage{}
+}
+
+// JobUsage totals the opencode usage recorded for a job.
+type JobUsage struct {
+	Total OpencodeUsage
+	// ByPurpose totals usage per opencode purpose (implement, review,
+	// project-review).
+	ByPurpose map[string]OpencodeUsage
+}
+
+// UsageSummary totals the opencode usage recorded in a job's event log. A
+// missing log yields an empty summary.
+func UsageSummary(jobID string, opts EventLogOptions) (JobUsage, error) {
+	events, err := readEventLog(jobID, opts, true)
+	if err != nil {
+		return JobUsage{}, err
+	}
+	summary := JobUsage{ByPurpose: make(map[string]OpencodeUsage)}
+	for _, event := range events {
+		if event.Name != jobEventUsage {
+			continue
+		}
+		var data usageEventData
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			return JobUsage{}, fmt.Errorf("decode usage event: %w", err)
+		}
+		summary.Total = summary.Total.Add(data.Usage)
+		summary.ByPurpose[data.Purpose] = summary.ByPurpose[data.Purpose].Add(data.Usage)
+	}
+	return summary, nil
+}
+
+type usageEventData struct {
+	Purpose   string        `json:"purpose"`
+	SessionID string        `json:"session_id,omitempty"`
+	Usage     OpencodeUsage `json:"usage"`
+}
+
+// recordOpencodeUsage appends a job.usage event for a finished opencode run
+// that reported usage.
+func recordOpencodeUsage(log *EventLog, purpose string, result OpencodeRunResult) error {
+	if result.Usage.IsZero() {
+		return nil
+	}
+	return appendJobEvent(log, jobEventUsage, usageEventData{Purpose: purpose, SessionID: result.SessionID, Usage: result.Usage})
+}
+
+// usageTracker collects usage from opencode message.updated events. Each
+// assistant message reports cumulative usage, so the latest report per
+// message wins and messages are summed.
+type usageTracker struct {
+	mu       sync.Mutex
+	messages map[string]OpencodeUsage
+	order    []string
+}
+
+type opencodeUsageMessageUpdated struct {
+	Info struct {
+		ID     string  `json:"id"`
+		Role   string  `json:"role"`
+		Cost   float64 `json:"cost"`
+		Tokens struct {
+			Input     int64 `json:"input"`
+			Output    int64 `json:"output"`
+			Reasoning int64 `json:"reasoning"`
+			Cache     struct {
+				Read  int64 `json:"read"`
+				Write int64 `json:"write"`
+			} `json:"cache"`
+		} `json:"tokens"`
+	} `json:"info"`
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{messages: make(map[string]OpencodeUsage)}
+}
+
+// observe records usage from event. Events without usage, or that do not
+// decode, are ignored.
+func (t *usageTracker) observe(event opencode.Event) {
+	if t == nil || internalstrings.IsBlank(event.Data) {
+		return
+	}
+	payload, err := parseOpencodeEventPayload(event.Data)
+	if err != nil || payload.Type != "message.updated" {
+		return
+	}
+	var update opencodeUsageMessageUpdated
+	if err := json.Unmarshal(payload.Properties, &update); err != nil {
+		return
+	}
+	info := update.Info
+	if internalstrings.IsBlank(info.ID) || internalstrings.NormalizeLowerTrimSpace(info.Role) == "user" {
+		return
+	}
+	usage := OpencodeUsage{
+		InputTokens:      info.Tokens.Input,
+		OutputTokens:     info.Tokens.Output,
+		ReasoningTokens:  info.Tokens.Reasoning,
+		CacheReadTokens:  info.Tokens.Cache.Read,
+		CacheWriteTokens: info.Tokens.Cache.Write,
+		Cost:             info.Cost,
+	}
+	if usage.IsZero() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.messages[info.ID]; !ok {
+		t.order = append(t.order, info.ID)
+	}
+	t.messages[info.ID] = usage
+}
+
+// total sums the latest usage reported by each message.
+func (t *usageTracker) total() OpencodeUsage {
+	if t == nil {
+		return OpencodeUsage{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var total OpencodeUsage
+	for _, id := range t.order {
+		total = total.Add(t.messages[id])
+	}
+	return total
+}
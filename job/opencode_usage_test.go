@@ -0,0 +1,115 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/amonks/incrementum/opencode"
+)
+
+func TestUsageTrackerKeepsLatestReportPerMessage(t *testing.T) {
+	events := make(chan opencode.Event, 5)
+	events <- opencode.Event{Data: `{"type":"message.updated","properties":{"info":{"id":"msg-1","role":"assistant","cost":0.01,"tokens":{"input":100,"output":10,"reasoning":0,"cache":{"read":0,"write":0}}}}}`}
+	events <- opencode.Event{Data: `{"type":"message.updated","properties":{"info":{"id":"msg-1","role":"assistant","cost":0.02,"tokens":{"input":100,"output":40,"reasoning":5,"cache":{"read":50,"write":2}}}}}`}
+	events <- opencode.Event{Data: `{"type":"message.updated","properties":{"info":{"id":"msg-2","role":"assistant","cost":0.03,"tokens":{"input":200,"output":20}}}}`}
+	events <- opencode.Event{Data: `{"type":"message.updated","properties":{"info":{"id":"msg-0","role":"user"}}}`}
+	events <- opencode.Event{Data: `not json`}
+	close(events)
+
+	usage := newUsageTracker()
+	if err := <-recordOpencodeEvents(nil, events, usage); err != nil {
+		t.Fatalf("record events: %v", err)
+	}
+
+	want := OpencodeUsage{
+		InputTokens:      300,
+		OutputTokens:     60,
+		ReasoningTokens:  5,
+		CacheReadTokens:  50,
+		CacheWriteTokens: 2,
+		Cost:             0.05,
+	}
+	got := usage.total()
+	if got.InputTokens != want.InputTokens || got.OutputTokens != want.OutputTokens ||
+		got.ReasoningTokens != want.ReasoningTokens || got.CacheReadTokens != want.CacheReadTokens ||
+		got.CacheWriteTokens != want.CacheWriteTokens || !approxEqual(got.Cost, want.Cost) {
+		t.Fatalf("expected usage %+v, got %+v", want, got)
+	}
+}
+
+func TestUsageTrackerWithoutUsageIsZero(t *testing.T) {
+	events := make(chan opencode.Event, 1)
+	events <- opencode.Event{Data: `{"type":"message.part.updated","properties":{"part":{"id":"part-1","messageID":"msg-1","type":"text","text":"hi"}}}`}
+	close(events)
+
+	usage := newUsageTracker()
+	if err := <-recordOpencodeEvents(nil, events, usage); err != nil {
+		t.Fatalf("record events: %v", err)
+	}
+	if total := usage.total(); !total.IsZero() {
+		t.Fatalf("expected zero usage, got %+v", total)
+	}
+}
+
+func TestUsageSummaryTotalsPerPurpose(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog("job-usage", opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	runOpts := RunOptions{
+		EventLog: eventLog,
+		RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+			return OpencodeRunResult{SessionID: "ses-1", Usage: OpencodeUsage{InputTokens: 100, OutputTokens: 10, Cost: 0.5}}, nil
+		},
+	}
+	for _, purpose := range []string{"implement", "review", "implement"} {
+		if _, err := runOpencodeWithEvents(runOpts, opencodeRunOptions{}, purpose); err != nil {
+			t.Fatalf("run opencode: %v", err)
+		}
+	}
+	runOpts.RunOpencode = func(opencodeRunOptions) (OpencodeRunResult, error) {
+		return OpencodeRunResult{SessionID: "ses-2"}, nil
+	}
+	if _, err := runOpencodeWithEvents(runOpts, opencodeRunOptions{}, "project-review"); err != nil {
+		t.Fatalf("run opencode: %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	summary, err := UsageSummary("job-usage", opts)
+	if err != nil {
+		t.Fatalf("usage summary: %v", err)
+	}
+	if summary.Total.InputTokens != 300 || summary.Total.OutputTokens != 30 || !approxEqual(summary.Total.Cost, 1.5) {
+		t.Fatalf("unexpected total usage: %+v", summary.Total)
+	}
+	if implement := summary.ByPurpose["implement"]; implement.InputTokens != 200 {
+		t.Fatalf("expected implement usage to sum both runs, got %+v", implement)
+	}
+	if review := summary.ByPurpose["review"]; review.InputTokens != 100 {
+		t.Fatalf("unexpected review usage: %+v", review)
+	}
+	if projectReview, ok := summary.ByPurpose["project-review"]; ok {
+		t.Fatalf("expected no project-review usage, got %+v", projectReview)
+	}
+}
+
+func TestUsageSummaryMissingLogIsEmpty(t *testing.T) {
+	summary, err := UsageSummary("job-missing", EventLogOptions{EventsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("usage summary: %v", err)
+	}
+	if !summary.Total.IsZero() || len(summary.ByPurpose) != 0 {
+		t.Fatalf("expected empty summary, got %+v", summary)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff < 1e-9
+}
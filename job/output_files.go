@@ -0,0 +1,26 @@
+package job
+
+import "github.com/amonks/incrementum/internal/config"
+
+// outputFiles names the workspace files opencode writes for the runner to
+// read back: the review verdict and the draft commit message.
+type outputFiles struct {
+	Feedback      string
+	CommitMessage string
+}
+
+// resolveOutputFiles returns the configured output file names, defaulting
+// to feedbackFilename and commitMessageFilename.
+func resolveOutputFiles(cfg *config.Config) outputFiles {
+	files := outputFiles{Feedback: feedbackFilename, CommitMessage: commitMessageFilename}
+	if cfg == nil {
+		return files
+	}
+	if cfg.Job.FeedbackFile != "" {
+		files.Feedback = cfg.Job.FeedbackFile
+	}
+	if cfg.Job.CommitMessageFile != "" {
+		files.CommitMessage = cfg.Job.CommitMessageFile
+	}
+	return files
+}
@@ -0,0 +1,63 @@
+package job
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestResolveOutputFilesDefaults(t *testing.T) {
+	files := resolveOutputFiles(&config.Config{Job: config.Job{CommitMessageFile: "agent-1.msg"}})
+	if files.Feedback != feedbackFilename {
+		t.Fatalf("expected default feedback file %q, got %q", feedbackFilename, files.Feedback)
+	}
+	if files.CommitMessage != "agent-1.msg" {
+		t.Fatalf("expected configured commit message file, got %q", files.CommitMessage)
+	}
+	if files := resolveOutputFiles(nil); files.Feedback != feedbackFilename || files.CommitMessage != commitMessageFilename {
+		t.Fatalf("expected defaults without config, got %+v", files)
+	}
+}
+
+func TestPromptsNameConfiguredOutputFiles(t *testing.T) {
+	files := outputFiles{Feedback: "agent-1.feedback", CommitMessage: "agent-1.msg"}
+	item := todo.Todo{ID: "todo-1", Title: "Build it", Type: todo.TypeTask}
+
+	implementation, err := renderPromptTemplate(item, "", "", nil, nil, "prompt-implementation.tmpl", t.TempDir(), files)
+	if err != nil {
+		t.Fatalf("render implementation prompt: %v", err)
+	}
+	if !strings.Contains(implementation, "./agent-1.msg") || strings.Contains(implementation, commitMessageFilename) {
+		t.Fatalf("expected the implementation prompt to name the configured file, got:\n%s", implementation)
+	}
+
+	review, err := renderPromptTemplate(item, "", "feat: build it", nil, nil, "prompt-commit-review.tmpl", t.TempDir(), files)
+	if err != nil {
+		t.Fatalf("render review prompt: %v", err)
+	}
+	if !strings.Contains(review, "./agent-1.feedback") || strings.Contains(review, feedbackFilename) {
+		t.Fatalf("expected the review prompt to name the configured file, got:\n%s", review)
+	}
+}
+
+func TestResolveReviewCommitMessageReadsConfiguredFile(t *testing.T) {
+	workspacePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspacePath, "agent-1.msg"), []byte("feat: from agent one\n"), 0o644); err != nil {
+		t.Fatalf("write commit message: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspacePath, commitMessageFilename), []byte("feat: someone else's\n"), 0o644); err != nil {
+		t.Fatalf("write commit message: %v", err)
+	}
+
+	message, err := resolveReviewCommitMessage("", workspacePath, "agent-1.msg", true)
+	if err != nil {
+		t.Fatalf("resolve commit message: %v", err)
+	}
+	if message != "feat: from agent one" {
+		t.Fatalf("expected the configured file's message, got %q", message)
+	}
+}
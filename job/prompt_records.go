@@ -0,0 +1,61 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PromptRecord is a rendered prompt a job sent to opencode.
+type PromptRecord struct {
+	// Stage is the job stage the prompt was sent from.
+	Stage Stage `json:"stage"`
+	// Purpose is the opencode purpose (implement, review, project-review).
+	Purpose string `json:"purpose"`
+	// Template is the prompt template the prompt was rendered from.
+	Template string `json:"template"`
+	// Prompt is the rendered prompt text.
+	Prompt string `json:"prompt"`
+	// At is when the prompt was recorded. It is zero for logs written
+	// before prompts were timestamped.
+	At time.Time `json:"at,omitzero"`
+}
+
+// Prompts returns the prompts recorded in a job's event log, in the order
+// they were sent. Prompts from older logs without a recorded stage take the
+// stage of the most recent stage event. A missing log yields no prompts.
+func Prompts(jobID string, opts EventLogOptions) ([]PromptRecord, error) {
+	events, err := readEventLog(jobID, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]PromptRecord, 0)
+	var stage Stage
+	for _, event := range events {
+		switch event.Name {
+		case jobEventStage:
+			var data stageEventData
+			if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+				return nil, fmt.Errorf("decode stage event: %w", err)
+			}
+			stage = data.Stage
+		case jobEventPrompt:
+			var data promptEventData
+			if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+				return nil, fmt.Errorf("decode prompt event: %w", err)
+			}
+			record := PromptRecord{
+				Stage:    data.Stage,
+				Purpose:  data.Purpose,
+				Template: data.Template,
+				Prompt:   data.Prompt,
+				At:       data.At,
+			}
+			if record.Stage == "" {
+				record.Stage = stage
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,65 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromptsReturnsRecordsInOrder(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	log, err := OpenEventLog("job-prompts", opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	at := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	events := []struct {
+		name string
+		data any
+	}{
+		{jobEventStage, stageEventData{Stage: StageImplementing}},
+		// Older logs recorded prompts without a stage or time.
+		{jobEventPrompt, promptEventData{Purpose: "implement", Template: "prompt-implementation.tmpl", Prompt: "Do the thing."}},
+		{jobEventStage, stageEventData{Stage: StageReviewing}},
+		{jobEventPrompt, promptEventData{Purpose: "review", Template: "prompt-commit-review.tmpl", Prompt: "Review it.", Stage: StageReviewing, At: at}},
+		{jobEventPrompt, promptEventData{Purpose: "project-review", Template: "prompt-project-review.tmpl", Prompt: "Review all of it.", Stage: StageReviewing, At: at.Add(time.Minute)}},
+	}
+	for _, event := range events {
+		if err := appendJobEvent(log, event.name, event.data); err != nil {
+			t.Fatalf("append %s: %v", event.name, err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	records, err := Prompts("job-prompts", opts)
+	if err != nil {
+		t.Fatalf("prompts: %v", err)
+	}
+	want := []PromptRecord{
+		{Stage: StageImplementing, Purpose: "implement", Template: "prompt-implementation.tmpl", Prompt: "Do the thing."},
+		{Stage: StageReviewing, Purpose: "review", Template: "prompt-commit-review.tmpl", Prompt: "Review it.", At: at},
+		{Stage: StageReviewing, Purpose: "project-review", Template: "prompt-project-review.tmpl", Prompt: "Review all of it.", At: at.Add(time.Minute)},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), records)
+	}
+	for i := range want {
+		got := records[i]
+		if got.Stage != want[i].Stage || got.Purpose != want[i].Purpose || got.Template != want[i].Template ||
+			got.Prompt != want[i].Prompt || !got.At.Equal(want[i].At) {
+			t.Fatalf("record %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+}
+
+func TestPromptsMissingLogIsEmpty(t *testing.T) {
+	records, err := Prompts("job-missing", EventLogOptions{EventsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("prompts: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("expected no prompts, got %+v", records)
+	}
+}
@@ -67,5 +67,8 @@ func promptTemplateVariables() []PromptTemplateVariable {
 		{Name: "TodoBlock", Type: "string"},
 		{Name: "FeedbackBlock", Type: "string"},
 		{Name: "CommitMessageBlock", Type: "string"},
+		{Name: "DesignDocBlock", Type: "string"},
+		{Name: "FeedbackFile", Type: "string"},
+		{Name: "CommitMessageFile", Type: "string"},
 	}
 }
@@ -22,7 +22,7 @@ const (
 //go:embed templates/*.tmpl
 var defaultTemplates embed.FS
 
-var reviewInstructionsText = mustReadDefaultPromptTemplate(reviewInstructionsTemplateName)
+var reviewInstructionsTemplate = template.Must(template.New("review-instructions").Option("missingkey=error").Parse(mustReadDefaultPromptTemplate(reviewInstructionsTemplateName)))
 
 // PromptData supplies values for job prompt templates.
 type PromptData struct {
@@ -35,7 +35,12 @@ type PromptData struct {
 	ReviewInstructions  string
 	TodoBlock           string
 	FeedbackBlock       string
+	FileComments        []ReviewFileComment
+	FileCommentsBlock   string
 	CommitMessageBlock  string
+	DesignDocBlock      string
+	FeedbackFile        string
+	CommitMessageFile   string
 
 	// Habit fields (empty for regular todo jobs)
 	HabitName         string
@@ -43,6 +48,7 @@ type PromptData struct {
 }
 
 func newPromptData(item todo.Todo, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, workspacePath string) PromptData {
+	feedbackText, fileComments := splitReviewFileComments(feedback)
 	return PromptData{
 		Todo:                item,
 		Feedback:            feedback,
@@ -50,26 +56,54 @@ func newPromptData(item todo.Todo, feedback, message string, commitLog []CommitL
 		CommitLog:           commitLog,
 		OpencodeTranscripts: transcripts,
 		WorkspacePath:       workspacePath,
-		ReviewInstructions:  reviewInstructionsText,
+		ReviewInstructions:  renderReviewInstructions(feedbackFilename),
 		TodoBlock:           formatTodoBlock(item),
-		FeedbackBlock:       formatFeedbackBlock(feedback),
+		FeedbackBlock:       formatFeedbackBlock(feedbackText),
+		FileComments:        fileComments,
+		FileCommentsBlock:   formatFileCommentsBlock(fileComments),
 		CommitMessageBlock:  formatPromptBlock("Commit message", message),
+		DesignDocBlock:      formatDesignDocBlock(item, workspacePath),
+		FeedbackFile:        feedbackFilename,
+		CommitMessageFile:   commitMessageFilename,
 	}
 }
 
+// withOutputFiles points the prompt at the configured output files.
+func (d PromptData) withOutputFiles(files outputFiles) PromptData {
+	d.FeedbackFile = files.Feedback
+	d.CommitMessageFile = files.CommitMessage
+	d.ReviewInstructions = renderReviewInstructions(files.Feedback)
+	return d
+}
+
+// renderReviewInstructions renders the review output instructions for
+// reviewers writing to feedbackFile.
+func renderReviewInstructions(feedbackFile string) string {
+	var out bytes.Buffer
+	if err := reviewInstructionsTemplate.Execute(&out, struct{ FeedbackFile string }{feedbackFile}); err != nil {
+		panic(fmt.Sprintf("render review instructions: %v", err))
+	}
+	return out.String()
+}
+
 // newHabitPromptData creates prompt data for a habit run.
 func newHabitPromptData(habitName, habitInstructions, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, workspacePath string) PromptData {
+	feedbackText, fileComments := splitReviewFileComments(feedback)
 	return PromptData{
 		Feedback:            feedback,
 		Message:             message,
 		CommitLog:           commitLog,
 		OpencodeTranscripts: transcripts,
 		WorkspacePath:       workspacePath,
-		ReviewInstructions:  reviewInstructionsText,
-		FeedbackBlock:       formatFeedbackBlock(feedback),
+		ReviewInstructions:  renderReviewInstructions(feedbackFilename),
+		FeedbackBlock:       formatFeedbackBlock(feedbackText),
+		FileComments:        fileComments,
+		FileCommentsBlock:   formatFileCommentsBlock(fileComments),
 		CommitMessageBlock:  formatPromptBlock("Commit message", message),
 		HabitName:           habitName,
 		HabitInstructions:   formatHabitInstructions(habitInstructions),
+		FeedbackFile:        feedbackFilename,
+		CommitMessageFile:   commitMessageFilename,
 	}
 }
 
@@ -122,6 +156,19 @@ func formatFeedbackBlock(body string) string {
 	return formatPromptBlock("Previous feedback", body)
 }
 
+// formatFileCommentsBlock renders a reviewer's file comments as a checklist,
+// or returns "" when there are none.
+func formatFileCommentsBlock(comments []ReviewFileComment) string {
+	if len(comments) == 0 {
+		return ""
+	}
+	items := make([]string, len(comments))
+	for i, comment := range comments {
+		items[i] = fmt.Sprintf("- [ ] %s: %s", comment.File, comment.Comment)
+	}
+	return fmt.Sprintf("Requested file changes\n\n%s", IndentBlock(strings.Join(items, "\n"), documentIndent))
+}
+
 func formatPromptMarkdownBlock(label, body string) string {
 	body = internalstrings.TrimTrailingNewlines(body)
 	if internalstrings.IsBlank(body) {
@@ -209,3 +209,17 @@ func TestRenderPrompt_UsesReviewQuestionsOverride(t *testing.T) {
 		t.Fatalf("expected override content, got %q", rendered)
 	}
 }
+
+func TestFeedbackPromptRendersFileCommentsChecklist(t *testing.T) {
+	feedback := "Tighten this up.\n\n```yaml\n- file: job/feedback.go\n  comment: Handle the empty case.\n```"
+	prompt, err := renderPromptTemplate(todo.Todo{ID: "todo-1", Title: "Title"}, feedback, "", nil, nil, "prompt-feedback.tmpl", t.TempDir(), resolveOutputFiles(nil))
+	if err != nil {
+		t.Fatalf("render prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Requested file changes\n\n    - [ ] job/feedback.go: Handle the empty case.") {
+		t.Fatalf("expected file comments checklist, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "```yaml") {
+		t.Fatalf("expected structured block removed from feedback text, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/amonks/incrementum/todo"
+)
+
+// TodoMismatch describes a finished job whose todo was left in_progress,
+// typically because the job process died before finalizing the todo.
+type TodoMismatch struct {
+	Job Job
+	// TodoStatus is the todo's current status.
+	TodoStatus todo.Status
+	// ExpectedStatus is the todo status the job's outcome implies.
+	ExpectedStatus todo.Status
+}
+
+// FindTodoMismatches compares the most recent job for each todo against the
+// todo's status and reports todos still in_progress after their job finished.
+// Habit jobs and todos without a terminal job are ignored.
+func FindTodoMismatches(jobs []Job, todos []todo.Todo) []TodoMismatch {
+	latest := make(map[string]Job, len(jobs))
+	for _, job := range jobs {
+		if strings.HasPrefix(job.TodoID, "habit:") {
+			continue
+		}
+		current, ok := latest[job.TodoID]
+		if !ok || job.StartedAt.After(current.StartedAt) ||
+			(job.StartedAt.Equal(current.StartedAt) && job.ID > current.ID) {
+			latest[job.TodoID] = job
+		}
+	}
+
+	mismatches := make([]TodoMismatch, 0)
+	for _, item := range todos {
+		if item.Status != todo.StatusInProgress {
+			continue
+		}
+		job, ok := latest[item.ID]
+		if !ok {
+			continue
+		}
+		expected, ok := expectedTodoStatus(job.Status)
+		if !ok {
+			continue
+		}
+		mismatches = append(mismatches, TodoMismatch{
+			Job:            job,
+			TodoStatus:     item.Status,
+			ExpectedStatus: expected,
+		})
+	}
+	return mismatches
+}
+
+// FixTodoMismatches applies the todo status each mismatched job implies.
+func FixTodoMismatches(repoPath string, mismatches []TodoMismatch) error {
+	var errs []error
+	for _, mismatch := range mismatches {
+		errs = append(errs, finalizeTodo(repoPath, mismatch.Job.TodoID, mismatch.Job.Status))
+	}
+	return errors.Join(errs...)
+}
+
+func expectedTodoStatus(status Status) (todo.Status, bool) {
+	switch status {
+	case StatusCompleted:
+		return todo.StatusDone, true
+	case StatusFailed, StatusAbandoned:
+		return todo.StatusOpen, true
+	default:
+		return "", false
+	}
+}
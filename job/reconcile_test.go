@@ -0,0 +1,41 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestFindTodoMismatches(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	jobs := []Job{
+		{ID: "job-old", TodoID: "retried", Status: StatusFailed, StartedAt: base},
+		{ID: "job-new", TodoID: "retried", Status: StatusActive, StartedAt: base.Add(time.Minute)},
+		{ID: "job-failed", TodoID: "failed", Status: StatusFailed, StartedAt: base},
+		{ID: "job-done", TodoID: "completed", Status: StatusCompleted, StartedAt: base},
+		{ID: "job-reopened", TodoID: "reopened", Status: StatusAbandoned, StartedAt: base},
+		{ID: "job-habit", TodoID: "habit:cleanup", Status: StatusFailed, StartedAt: base},
+	}
+	todos := []todo.Todo{
+		{ID: "retried", Status: todo.StatusInProgress},
+		{ID: "failed", Status: todo.StatusInProgress},
+		{ID: "completed", Status: todo.StatusInProgress},
+		{ID: "reopened", Status: todo.StatusOpen},
+		{ID: "manual", Status: todo.StatusInProgress},
+	}
+
+	mismatches := FindTodoMismatches(jobs, todos)
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %d: %+v", len(mismatches), mismatches)
+	}
+	if mismatches[0].Job.ID != "job-failed" || mismatches[0].ExpectedStatus != todo.StatusOpen {
+		t.Fatalf("expected failed job to expect open todo, got %+v", mismatches[0])
+	}
+	if mismatches[1].Job.ID != "job-done" || mismatches[1].ExpectedStatus != todo.StatusDone {
+		t.Fatalf("expected completed job to expect done todo, got %+v", mismatches[1])
+	}
+	if mismatches[0].TodoStatus != todo.StatusInProgress {
+		t.Fatalf("expected todo status in_progress, got %q", mismatches[0].TodoStatus)
+	}
+}
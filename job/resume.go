@@ -0,0 +1,190 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+	"github.com/amonks/incrementum/todo"
+)
+
+// Resume continues a failed or paused job from the stage it stopped in, in the
+// workspace it ran in. The job's todo is marked in progress again rather
+// than re-created, and events are appended to the job's existing log.
+//
+// opts.WorkspacePath overrides the recorded workspace. Resume returns
+// ErrWorkspaceMissing, without changing the job or todo, when the workspace
+// no longer exists. Like Run, it fails before touching the job or todo when a
+// supported jj is not installed.
+func Resume(repoPath, jobID string, opts RunOptions) (_ *RunResult, runErr error) {
+	if internalstrings.IsBlank(jobID) {
+		return nil, fmt.Errorf("job id is required")
+	}
+
+	opts = normalizeRunOptions(opts)
+	if opts.EventStream != nil {
+		defer close(opts.EventStream)
+	}
+	result := &RunResult{}
+	repoPath = filepath.Clean(repoPath)
+	if abs, absErr := filepath.Abs(repoPath); absErr == nil {
+		repoPath = abs
+	}
+
+	manager, err := Open(repoPath, OpenOptions{})
+	if err != nil {
+		return result, err
+	}
+	current, err := manager.Find(jobID)
+	if err != nil {
+		return result, err
+	}
+	result.Job = current
+	if current.Status != StatusFailed && current.Status != StatusPaused {
+		return result, fmt.Errorf("%w: job %s is %s", ErrJobNotResumable, current.ID, current.Status)
+	}
+
+	workspacePath, err := resolveResumeWorkspace(repoPath, current, opts.WorkspacePath)
+	if err != nil {
+		return result, err
+	}
+	if err := prepareRunConfig(repoPath, &opts); err != nil {
+		return result, err
+	}
+
+	item, err := restartTodo(repoPath, current.TodoID)
+	if err != nil {
+		return result, err
+	}
+
+	resumedAt := opts.Now()
+	status := StatusActive
+	current, err = manager.Update(current.ID, UpdateOptions{Status: &status}, resumedAt)
+	if err != nil {
+		reopenErr := reopenTodo(repoPath, item.ID)
+		return result, errors.Join(err, reopenErr)
+	}
+	result.Job = current
+
+	return result, executeJob(jobExecution{
+		repoPath:      repoPath,
+		workspacePath: workspacePath,
+		item:          item,
+		job:           current,
+		manager:       manager,
+		startedAt:     resumedAt,
+		ctx:           resumeContext(current, result),
+		openEventLog:  ReopenEventLog,
+		// runJobStages announces an implementing entry once the iteration
+		// cap has allowed it.
+		prepare: func(ctx *runContext) error {
+			if current.Stage == StageImplementing {
+				return nil
+			}
+			if err := appendJobEvent(ctx.opts.EventLog, jobEventStage, stageEventData{Stage: current.Stage, Iteration: ctx.iteration}); err != nil {
+				return err
+			}
+			if opts.OnStageChange != nil {
+				opts.OnStageChange(StageChange{Stage: current.Stage, Iteration: ctx.iteration})
+			}
+			return nil
+		},
+	}, opts, result)
+}
+
+// resolveResumeWorkspace picks the directory a resumed job runs in and
+// checks that it still exists. Jobs recorded before workspaces were tracked
+// fall back to the repo, matching Run's default.
+func resolveResumeWorkspace(repoPath string, current Job, override string) (string, error) {
+	workspacePath := current.Workspace
+	if !internalstrings.IsBlank(override) {
+		workspacePath = override
+	}
+	if internalstrings.IsBlank(workspacePath) {
+		workspacePath = repoPath
+	}
+	workspacePath = filepath.Clean(workspacePath)
+	if abs, absErr := filepath.Abs(workspacePath); absErr == nil {
+		workspacePath = abs
+	}
+
+	info, err := os.Stat(workspacePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s (job %s)", ErrWorkspaceMissing, workspacePath, current.ID)
+		}
+		return "", fmt.Errorf("stat job workspace: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: %s is not a directory (job %s)", ErrWorkspaceMissing, workspacePath, current.ID)
+	}
+	return workspacePath, nil
+}
+
+// resumeContext rebuilds the in-memory stage state of a job from its
+// persisted changes, filling result's commit log with the commits the job
+// already made.
+//
+// Every accepted change has been committed, except that a job which stopped
+// in the committing stage still owes the commit for its last accepted change;
+// that change supplies the commit message and review comments instead of a
+// commit log entry. Commit log IDs are the IDs recorded when each change was
+// implemented.
+func resumeContext(current Job, result *RunResult) *runContext {
+	ctx := &runContext{result: result}
+	pending := -1
+	if current.Stage == StageCommitting && current.CurrentChange() == nil {
+		pending = len(current.Changes) - 1
+	}
+
+	for i, change := range current.Changes {
+		ctx.iteration += len(change.Commits)
+		if !change.IsComplete() {
+			continue
+		}
+		last := change.Commits[len(change.Commits)-1]
+		if i == pending {
+			ctx.commitMessage = last.DraftMessage
+			ctx.reviewComments = last.Review.Comments
+			continue
+		}
+		result.CommitLog = append(result.CommitLog, CommitLogEntry{ID: last.CommitID, Message: last.DraftMessage})
+	}
+
+	if commit := current.CurrentCommit(); commit != nil {
+		ctx.commitMessage = commit.DraftMessage
+	}
+	if current.Stage == StageReviewing && current.CurrentChange() == nil {
+		ctx.reviewScope = reviewScopeProject
+	}
+	if ctx.iteration == 0 && current.Stage != StageImplementing {
+		ctx.iteration = 1
+	}
+	return ctx
+}
+
+// restartTodo marks a todo in progress again and returns it.
+func restartTodo(repoPath, todoID string) (todo.Todo, error) {
+	store, err := todo.Open(repoPath, todo.OpenOptions{
+		CreateIfMissing: false,
+		PromptToCreate:  false,
+		Purpose:         fmt.Sprintf("todo store (job resume %s)", todoID),
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	started, err := store.Start([]string{todoID})
+	releaseErr := store.Release()
+	if err != nil {
+		return todo.Todo{}, errors.Join(err, releaseErr)
+	}
+	if releaseErr != nil {
+		return todo.Todo{}, releaseErr
+	}
+	if len(started) == 0 {
+		return todo.Todo{}, fmt.Errorf("todo not found: %s", todoID)
+	}
+	return started[0], nil
+}
@@ -0,0 +1,179 @@
+package job
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResumeErrorsWhenWorkspaceMissing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoPath := t.TempDir()
+	workspacePath := filepath.Join(t.TempDir(), "ws-001")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open(repoPath, OpenOptions{})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{WorkspacePath: workspacePath})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	status := StatusFailed
+	if _, err := manager.Update(created.ID, UpdateOptions{Status: &status}, now); err != nil {
+		t.Fatalf("fail job: %v", err)
+	}
+
+	_, err = Resume(repoPath, created.ID, RunOptions{Now: func() time.Time { return now }})
+	if !errors.Is(err, ErrWorkspaceMissing) {
+		t.Fatalf("expected ErrWorkspaceMissing, got %v", err)
+	}
+
+	found, err := manager.Find(created.ID)
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if found.Status != StatusFailed {
+		t.Fatalf("expected job to stay failed, got %q", found.Status)
+	}
+}
+
+func TestResumeAcceptsPausedJobs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoPath := t.TempDir()
+	workspacePath := filepath.Join(t.TempDir(), "ws-001")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open(repoPath, OpenOptions{})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{WorkspacePath: workspacePath})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	status := StatusPaused
+	if _, err := manager.Update(created.ID, UpdateOptions{Status: &status}, now); err != nil {
+		t.Fatalf("pause job: %v", err)
+	}
+
+	// Getting as far as the workspace check means the status was accepted.
+	_, err = Resume(repoPath, created.ID, RunOptions{Now: func() time.Time { return now }})
+	if !errors.Is(err, ErrWorkspaceMissing) {
+		t.Fatalf("expected ErrWorkspaceMissing, got %v", err)
+	}
+}
+
+func TestResumeRejectsJobsThatDidNotFail(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoPath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open(repoPath, OpenOptions{})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{WorkspacePath: repoPath})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	_, err = Resume(repoPath, created.ID, RunOptions{Now: func() time.Time { return now }})
+	if !errors.Is(err, ErrJobNotResumable) {
+		t.Fatalf("expected ErrJobNotResumable, got %v", err)
+	}
+}
+
+func TestResumeContextRebuildsCommittingStage(t *testing.T) {
+	accepted := &JobReview{Outcome: ReviewOutcomeAccept, Comments: "looks good"}
+	current := Job{
+		Stage:  StageCommitting,
+		Status: StatusFailed,
+		Changes: []JobChange{
+			{ChangeID: "change-1", Commits: []JobCommit{
+				{CommitID: "commit-1", DraftMessage: "first draft", Review: &JobReview{Outcome: ReviewOutcomeRequestChanges}},
+				{CommitID: "commit-2", DraftMessage: "first", Review: accepted},
+			}},
+			{ChangeID: "change-2", Commits: []JobCommit{
+				{CommitID: "commit-3", DraftMessage: "second", Review: accepted},
+			}},
+		},
+	}
+
+	result := &RunResult{}
+	ctx := resumeContext(current, result)
+	if len(result.CommitLog) != 1 || result.CommitLog[0].ID != "commit-2" || result.CommitLog[0].Message != "first" {
+		t.Fatalf("expected only the committed change in the log, got %+v", result.CommitLog)
+	}
+	if ctx.commitMessage != "second" {
+		t.Fatalf("expected pending commit message, got %q", ctx.commitMessage)
+	}
+	if ctx.reviewComments != "looks good" {
+		t.Fatalf("expected pending review comments, got %q", ctx.reviewComments)
+	}
+	if ctx.reviewScope != reviewScopeStep {
+		t.Fatalf("expected step review scope")
+	}
+	if ctx.iteration != 3 {
+		t.Fatalf("expected iteration 3, got %d", ctx.iteration)
+	}
+}
+
+func TestResumeContextRebuildsProjectReview(t *testing.T) {
+	current := Job{
+		Stage:  StageReviewing,
+		Status: StatusFailed,
+		Changes: []JobChange{
+			{ChangeID: "change-1", Commits: []JobCommit{
+				{CommitID: "commit-1", DraftMessage: "only", Review: &JobReview{Outcome: ReviewOutcomeAccept}},
+			}},
+		},
+	}
+
+	result := &RunResult{}
+	ctx := resumeContext(current, result)
+	if ctx.reviewScope != reviewScopeProject {
+		t.Fatalf("expected project review scope")
+	}
+	if len(result.CommitLog) != 1 || result.CommitLog[0].ID != "commit-1" {
+		t.Fatalf("expected committed change in the log, got %+v", result.CommitLog)
+	}
+}
+
+func TestReopenEventLogAppendsAfterExistingEvents(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	first, err := OpenEventLog("job-1", opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+	if err := appendJobEvent(first, jobEventStage, stageEventData{Stage: StageImplementing}); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	second, err := ReopenEventLog("job-1", opts)
+	if err != nil {
+		t.Fatalf("reopen event log: %v", err)
+	}
+	if err := appendJobEvent(second, jobEventStage, stageEventData{Stage: StageTesting}); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("job-1", opts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].ID == events[1].ID {
+		t.Fatalf("expected distinct event IDs, got %q twice", events[0].ID)
+	}
+}
@@ -0,0 +1,119 @@
+package job
+
+import (
+	"strings"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// splitReviewFileComments extracts structured file comments from review
+// details. Reviewers list them in fenced yaml blocks:
+//
+//	```yaml
+//	- file: job/feedback.go
+//	  comment: Handle the empty case.
+//	```
+//
+// It returns the details with those blocks removed, plus the comments they
+// held. Fenced blocks that are not such a list are left in place, so
+// reviewers can still quote ordinary yaml.
+func splitReviewFileComments(details string) (string, []ReviewFileComment) {
+	lines := strings.Split(internalstrings.NormalizeNewlines(details), "\n")
+	kept := make([]string, 0, len(lines))
+	var comments []ReviewFileComment
+	for i := 0; i < len(lines); i++ {
+		if !isYAMLFence(lines[i]) {
+			kept = append(kept, lines[i])
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && internalstrings.TrimSpace(lines[end]) != "```" {
+			end++
+		}
+		if end == len(lines) {
+			kept = append(kept, lines[i])
+			continue
+		}
+		parsed, ok := parseReviewFileComments(lines[i+1 : end])
+		if !ok {
+			kept = append(kept, lines[i:end+1]...)
+			i = end
+			continue
+		}
+		comments = append(comments, parsed...)
+		i = end
+		// Drop the blank line after the block when one already precedes it.
+		if i+1 < len(lines) && internalstrings.IsBlank(lines[i+1]) && (len(kept) == 0 || internalstrings.IsBlank(kept[len(kept)-1])) {
+			i++
+		}
+	}
+	if len(comments) == 0 {
+		return details, nil
+	}
+	rest := strings.Join(kept, "\n")
+	rest = internalstrings.TrimLeadingNewlines(internalstrings.TrimTrailingNewlines(rest))
+	return rest, comments
+}
+
+func isYAMLFence(line string) bool {
+	trimmed := internalstrings.NormalizeLowerTrimSpace(line)
+	return trimmed == "```yaml" || trimmed == "```yml"
+}
+
+// parseReviewFileComments parses a list of file/comment mappings. It reports
+// false unless every line belongs to an item with both keys. Values may be
+// quoted, and a comment may continue on more deeply indented lines.
+func parseReviewFileComments(lines []string) ([]ReviewFileComment, bool) {
+	var comments []ReviewFileComment
+	var current *ReviewFileComment
+	var lastKey string
+	itemIndent := 0
+	for _, line := range lines {
+		if internalstrings.IsBlank(line) {
+			continue
+		}
+		trimmed := internalstrings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(trimmed, "- "); ok {
+			comments = append(comments, ReviewFileComment{})
+			current = &comments[len(comments)-1]
+			itemIndent = internalstrings.LeadingSpaces(line)
+			lastKey = ""
+			trimmed = internalstrings.TrimSpace(rest)
+		} else if current == nil {
+			return nil, false
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		switch {
+		case ok && key == "file" && current.File == "":
+			current.File = unquoteYAMLScalar(value)
+			lastKey = key
+		case ok && key == "comment" && current.Comment == "":
+			current.Comment = unquoteYAMLScalar(value)
+			lastKey = key
+		case lastKey == "comment" && current.Comment != "" && internalstrings.LeadingSpaces(line) > itemIndent+2:
+			current.Comment += " " + trimmed
+		default:
+			return nil, false
+		}
+	}
+	if len(comments) == 0 {
+		return nil, false
+	}
+	for _, comment := range comments {
+		if comment.File == "" || comment.Comment == "" {
+			return nil, false
+		}
+	}
+	return comments, true
+}
+
+func unquoteYAMLScalar(value string) string {
+	value = internalstrings.TrimSpace(value)
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
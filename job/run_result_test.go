@@ -0,0 +1,98 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/habit"
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/internal/testsupport"
+)
+
+func TestRecordRunResult(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog("job-result", opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := func() time.Time { return start.Add(90 * time.Second) }
+	commitLog := []CommitLogEntry{{ID: "commit-1", Message: "one"}, {ID: "commit-2", Message: "two"}}
+	if err := recordRunResult(eventLog, now, Job{Status: StatusAbandoned, Reason: "out of scope"}, true, commitLog, start); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+	if err := recordRunResult(eventLog, now, Job{Status: StatusPaused}, false, nil, start); err != nil {
+		t.Fatalf("record paused result: %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("job-result", opts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventResult {
+		t.Fatalf("expected one result event and none for the paused job, got %#v", events)
+	}
+	var data resultEventData
+	if err := json.Unmarshal([]byte(events[0].Data), &data); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if data.Status != string(StatusAbandoned) || !data.DidWork || data.DurationMS != 90000 {
+		t.Fatalf("unexpected result: %+v", data)
+	}
+	if len(data.CommitIDs) != 2 || data.CommitIDs[0] != "commit-1" || data.CommitIDs[1] != "commit-2" {
+		t.Fatalf("expected commit ids in order, got %v", data.CommitIDs)
+	}
+	if data.AbandonReason != "out of scope" {
+		t.Fatalf("expected abandon reason, got %q", data.AbandonReason)
+	}
+}
+
+func TestRunHabitStreamsResultOnFailure(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	repoPath := t.TempDir()
+	habitsDir := filepath.Join(repoPath, habit.HabitsDir)
+	if err := os.MkdirAll(habitsDir, 0o755); err != nil {
+		t.Fatalf("create habits dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(habitsDir, "cleanup.md"), []byte("Clean up.\n"), 0o644); err != nil {
+		t.Fatalf("write habit: %v", err)
+	}
+
+	stream := make(chan Event, 16)
+	_, err := RunHabit(repoPath, "cleanup", HabitRunOptions{
+		Config:                &config.Config{},
+		EventStream:           stream,
+		EventLogOptions:       EventLogOptions{EventsDir: t.TempDir()},
+		RequireCleanWorkspace: true,
+		CurrentChangeEmpty:    func(string) (bool, error) { return false, nil },
+		DiffStat: func(string, string, string) (string, error) {
+			return "junk.txt | 1 +\n", nil
+		},
+	})
+	if !errors.Is(err, ErrWorkspaceNotClean) {
+		t.Fatalf("expected ErrWorkspaceNotClean, got %v", err)
+	}
+
+	var last Event
+	for event := range stream {
+		last = event
+	}
+	if last.Name != jobEventResult {
+		t.Fatalf("expected the result to be the last streamed event, got %#v", last)
+	}
+	var data resultEventData
+	if err := json.Unmarshal([]byte(last.Data), &data); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if data.Status != string(StatusFailed) || data.DidWork {
+		t.Fatalf("unexpected result: %+v", data)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/amonks/incrementum/internal/config"
@@ -49,10 +50,50 @@ var opencodeConfig = map[string]any{
 
 var promptMessagePattern = regexp.MustCompile(`\{\{[^}]*\.(Message|CommitMessageBlock)[^}]*\}\}`)
 
+// StageChange describes a job entering a new stage.
+type StageChange struct {
+	Stage Stage
+	// Iteration counts implementing stage entries, starting at 1. Later
+	// stages report the iteration they belong to.
+	Iteration int
+}
+
 // RunOptions configures job execution.
 type RunOptions struct {
 	OnStart       func(StartInfo)
-	OnStageChange func(Stage)
+	OnStageChange func(StageChange)
+	// StageTimeout bounds each opencode run. When it elapses (measured with
+	// Now), the opencode process is interrupted and the run is treated as a
+	// non-zero exit. Zero means no timeout.
+	StageTimeout time.Duration
+	// StageTimeouts overrides StageTimeout per opencode purpose ("implement",
+	// "review", "project-review").
+	StageTimeouts map[string]time.Duration
+	// MaxIterations caps how many times the job may enter the implementing
+	// stage. When the cap is reached the job fails with
+	// ErrMaxIterationsExceeded. Zero means unlimited.
+	MaxIterations int
+	// DryRun runs the stages without invoking opencode, committing, or
+	// changing the todo's status. Each skipped action is recorded as a
+	// job.dry_run event. Opencode runs report success without touching the
+	// workspace; the first implementing run is treated as having changed it,
+	// so testing, reviewing, and committing run too. The job record is
+	// deleted when Run returns.
+	DryRun bool
+	// Pause requests that the job stop at the next stage boundary. Stages
+	// already running, including opencode sessions, finish first. The job is
+	// then marked paused, its todo stays in progress, and Run returns
+	// ErrJobPaused; Resume continues it.
+	Pause <-chan struct{}
+	// CaptureOutput records opencode's stdout and stderr in the event log
+	// as job.opencode.output events. Off by default to keep logs small.
+	CaptureOutput bool
+	// CaptureOutputLimit caps the bytes of output captured per opencode
+	// run. Zero uses DefaultCaptureOutputLimit.
+	CaptureOutputLimit int
+	// CompletionWebhook is notified when the job finishes. When nil, the
+	// job.completion-webhook config is used, if set.
+	CompletionWebhook *CompletionWebhook
 	// EventStream receives job events as they are recorded. The channel is closed
 	// when Run completes.
 	EventStream chan<- Event
@@ -62,21 +103,36 @@ type RunOptions struct {
 	// Interrupts delivers signals that should interrupt the job.
 	// If nil, os.Interrupt is used.
 	Interrupts <-chan os.Signal
-	Now        func() time.Time
-	LoadConfig func(string) (*config.Config, error)
+	// RequireCleanWorkspace makes Run fail with ErrWorkspaceNotClean when the
+	// workspace's working-copy change is not empty at the start of the job.
+	// When false, leftovers are kept in that change and the job starts in a
+	// new empty change on top of it.
+	RequireCleanWorkspace bool
+	// RestoreOnInterrupt restores the workspace to the commit the
+	// implementing stage started from when the job is interrupted during
+	// that stage. Nil means true.
+	RestoreOnInterrupt *bool
+	Now                func() time.Time
+	LoadConfig         func(string) (*config.Config, error)
 	// Config provides loaded configuration for the job run.
 	// When nil, LoadConfig is used.
-	Config      *config.Config
+	Config *config.Config
+	// RunTests runs the configured test commands. When nil,
+	// RunTestCommandsParallel runs them honoring job.test-parallelism.
 	RunTests    func(string, []string) ([]TestCommandResult, error)
 	RunOpencode func(opencodeRunOptions) (OpencodeRunResult, error)
 	// OpencodeAgent overrides agent selection for all stages when set.
-	OpencodeAgent       string
+	OpencodeAgent string
+	// Profile names the config profile supplying stage models, taking
+	// precedence over the todo's profile. Per-todo models still win.
+	Profile             string
 	CurrentCommitID     func(string) (string, error)
 	CurrentChangeID     func(string) (string, error)
 	CurrentChangeEmpty  func(string) (bool, error)
 	DiffStat            func(string, string, string) (string, error)
 	CommitIDAt          func(string, string) (string, error)
 	Commit              func(string, string) error
+	NewChange           func(string, string) (string, error)
 	RestoreWorkspace    func(string, string) error
 	UpdateStale         func(string) error
 	Snapshot            func(string) error
@@ -84,6 +140,27 @@ type RunOptions struct {
 	EventLog            *EventLog
 	EventLogOptions     EventLogOptions
 	Logger              Logger
+
+	// DiffPreview returns the diff between two revisions in a workspace,
+	// capped at maxBytes, for the job.diff_preview event recorded before
+	// each commit. When nil, the jj client's DiffPreview is used.
+	DiffPreview func(workspacePath, from, to string, maxBytes int) (string, error)
+
+	// Bookmark names a jj bookmark to move to each commit the job makes. The
+	// bookmark is created if it does not exist. Empty leaves bookmarks alone.
+	Bookmark string
+	// MoveBookmark points a bookmark at a commit. When nil, the jj client's
+	// BookmarkSet is used.
+	MoveBookmark func(workspacePath, bookmark, commit string) error
+
+	// HasConflicts reports whether the working copy has unresolved
+	// conflicts after implementing. When nil, the jj client's HasConflicts
+	// is used.
+	HasConflicts func(workspacePath string) (bool, error)
+	// ConflictedPaths lists the conflicted paths for the
+	// job.conflict_detected event. When nil, the jj client's
+	// ConflictedPaths at @ is used.
+	ConflictedPaths func(workspacePath string) ([]string, error)
 }
 
 // RunResult captures the output of running a job.
@@ -91,6 +168,13 @@ type RunResult struct {
 	Job           Job
 	CommitMessage string
 	CommitLog     []CommitLogEntry
+	// DidWork reports whether the run produced at least one commit.
+	DidWork bool
+	// WebhookDone receives the completion webhook's delivery result and is
+	// then closed. It is nil when no webhook was notified. Delivery runs in
+	// the background; callers that exit right after the run should wait on
+	// it.
+	WebhookDone <-chan error
 }
 
 // OpencodeRunResult captures output from running opencode.
@@ -100,6 +184,8 @@ type OpencodeRunResult struct {
 	ServeCommand string
 	RunCommand   string
 	Stderr       string
+	// Usage is the token counts and cost the session reported.
+	Usage OpencodeUsage
 }
 
 type reviewScope int
@@ -109,10 +195,29 @@ const (
 	reviewScopeProject
 )
 
+// implementingStageState is what the runner carries into an implementing
+// run from earlier stages of the same job.
+type implementingStageState struct {
+	// recordBaseline receives the commit the stage started from, then each
+	// periodic snapshot, for restoring the workspace on interrupt.
+	recordBaseline func(string)
+	// conflictRetries counts the implementing runs in a row that unresolved
+	// conflicts sent back to implementing, for job.conflict-retries.
+	conflictRetries int
+	// dryRunChanged reports whether a dry run already simulated a change.
+	dryRunChanged bool
+	// messageRetry reports that committing sent the job back only to rewrite
+	// an invalid commit message.
+	messageRetry bool
+}
+
 type ImplementingStageResult struct {
 	Job           Job
 	CommitMessage string
 	Changed       bool
+	// Conflicted reports that unresolved conflicts sent the job back to
+	// implementing.
+	Conflicted bool
 }
 
 type ReviewingStageResult struct {
@@ -128,10 +233,20 @@ type opencodeRunOptions struct {
 	StartedAt     time.Time
 	EventLog      *EventLog
 	Env           []string
+	// Interrupt is closed when the run should be stopped early.
+	Interrupt <-chan struct{}
+	// Stdout and Stderr receive opencode's output when set.
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
+// stageTimeoutPollInterval is how often a running opencode stage compares
+// Now against its deadline.
+const stageTimeoutPollInterval = 100 * time.Millisecond
+
 // Run creates and executes a job for the given todo.
-func Run(repoPath, todoID string, opts RunOptions) (*RunResult, error) {
+// It fails before doing any work when a supported jj is not installed.
+func Run(repoPath, todoID string, opts RunOptions) (_ *RunResult, runErr error) {
 	if internalstrings.IsBlank(todoID) {
 		return nil, fmt.Errorf("todo id is required")
 	}
@@ -145,15 +260,8 @@ func Run(repoPath, todoID string, opts RunOptions) (*RunResult, error) {
 	if abs, absErr := filepath.Abs(repoPath); absErr == nil {
 		repoPath = abs
 	}
-	if opts.Config == nil {
-		cfg, err := opts.LoadConfig(repoPath)
-		if err != nil {
-			return result, fmt.Errorf("load config: %w", err)
-		}
-		if cfg == nil {
-			cfg = &config.Config{}
-		}
-		opts.Config = cfg
+	if err := prepareRunConfig(repoPath, &opts); err != nil {
+		return result, err
 	}
 
 	store, err := todo.Open(repoPath, todo.OpenOptions{
@@ -175,7 +283,13 @@ func Run(repoPath, todoID string, opts RunOptions) (*RunResult, error) {
 		return result, errors.Join(fmt.Errorf("todo not found: %s", todoID), releaseErr)
 	}
 	item := items[0]
-	_, err = store.Start([]string{item.ID})
+	if err := checkProfile(opts.Config, opts.Profile, item); err != nil {
+		releaseErr := store.Release()
+		return result, errors.Join(err, releaseErr)
+	}
+	if !opts.DryRun {
+		_, err = store.Start([]string{item.ID})
+	}
 	releaseErr := store.Release()
 	if err != nil {
 		return result, errors.Join(err, releaseErr)
@@ -196,63 +310,152 @@ func Run(repoPath, todoID string, opts RunOptions) (*RunResult, error) {
 	workspacePath = workspaceAbs
 	manager, err := Open(repoPath, OpenOptions{})
 	if err != nil {
-		reopenErr := reopenTodo(repoPath, item.ID)
+		reopenErr := finalizeRunTodo(opts, repoPath, item.ID, StatusFailed)
 		return result, errors.Join(err, reopenErr)
 	}
 
-	implementModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, "implement", item)
-	codeReviewModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, "review", item)
-	projectReviewModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, "project-review", item)
+	implementModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, opts.Profile, "implement", item)
+	codeReviewModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, opts.Profile, "review", item)
+	projectReviewModel := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, opts.Profile, "project-review", item)
 	created, err := manager.Create(item.ID, startedAt, CreateOptions{
 		Agent:               implementModel,
 		ImplementationModel: implementModel,
 		CodeReviewModel:     codeReviewModel,
 		ProjectReviewModel:  projectReviewModel,
+		WorkspacePath:       workspaceAbs,
 	})
 	if err != nil {
-		reopenErr := reopenTodo(repoPath, item.ID)
+		reopenErr := finalizeRunTodo(opts, repoPath, item.ID, StatusFailed)
 		return result, errors.Join(err, reopenErr)
 	}
 	result.Job = created
 
+	return result, executeJob(jobExecution{
+		repoPath:      repoPath,
+		workspacePath: workspacePath,
+		item:          item,
+		job:           created,
+		manager:       manager,
+		startedAt:     startedAt,
+		ctx:           &runContext{},
+		openEventLog:  OpenEventLog,
+		prepare: func(ctx *runContext) error {
+			preflight := workspacePreflight{
+				log:           ctx.opts.EventLog,
+				workspacePath: workspacePath,
+				requireClean:  opts.RequireCleanWorkspace,
+				dryRun:        opts.DryRun,
+				changeEmpty:   opts.CurrentChangeEmpty,
+				diffStat:      opts.DiffStat,
+				snapshot:      opts.Snapshot,
+				commitID:      opts.CurrentCommitID,
+				newChange:     opts.NewChange,
+			}
+			return preflight.run()
+		},
+		dryRun: opts.DryRun,
+	}, opts, result)
+}
+
+// prepareRunConfig checks that a supported jj is installed and loads the
+// repo config into opts when the caller did not supply one.
+func prepareRunConfig(repoPath string, opts *RunOptions) error {
+	if _, err := jj.CheckInstalled(); err != nil {
+		return err
+	}
+	if opts.Config != nil {
+		return nil
+	}
+	cfg, err := opts.LoadConfig(repoPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	opts.Config = cfg
+	return nil
+}
+
+// jobExecution is a job ready to run its stages: created by Run or
+// reactivated by Resume, with its todo in progress.
+type jobExecution struct {
+	repoPath      string
+	workspacePath string
+	item          todo.Todo
+	job           Job
+	manager       *Manager
+	startedAt     time.Time
+	// ctx holds the stage state the job starts from.
+	ctx *runContext
+	// openEventLog opens the job's event log when opts.EventLog is nil.
+	openEventLog func(string, EventLogOptions) (*EventLog, error)
+	// prepare runs once the event log is ready, before any stage. An error
+	// fails the job.
+	prepare func(ctx *runContext) error
+	// dryRun deletes the job once it ends and leaves its todo unchanged.
+	dryRun bool
+}
+
+// executeJob runs a prepared job's stages and finalizes the job and its todo.
+// The result event is the last event written on every path.
+func executeJob(exec jobExecution, opts RunOptions, result *RunResult) (runErr error) {
+	if exec.dryRun {
+		defer func() {
+			runErr = errors.Join(runErr, exec.manager.Delete(exec.job.ID))
+		}()
+	}
+	finalize := func(status Status) error {
+		if exec.dryRun {
+			return nil
+		}
+		return finalizeTodo(exec.repoPath, exec.item.ID, status)
+	}
+	fail := func(err error) error {
+		status := StatusFailed
+		updated, updateErr := exec.manager.Update(exec.job.ID, UpdateOptions{Status: &status}, opts.Now())
+		result.Job = updated
+		return errors.Join(err, updateErr, finalize(StatusFailed))
+	}
+
 	if opts.OnStart != nil {
 		opts.OnStart(StartInfo{
-			JobID:   created.ID,
-			Workdir: workspaceAbs,
-			Todo:    item,
+			JobID:   exec.job.ID,
+			Workdir: exec.workspacePath,
+			Todo:    exec.item,
 		})
 	}
 
-	createdEventLog := false
 	if opts.EventLog == nil {
-		eventLog, err := OpenEventLog(created.ID, opts.EventLogOptions)
+		eventLog, err := exec.openEventLog(exec.job.ID, opts.EventLogOptions.withConfig(opts.Config))
 		if err != nil {
-			status := StatusFailed
-			updated, updateErr := manager.Update(created.ID, UpdateOptions{Status: &status}, opts.Now())
-			result.Job = updated
-			finalizeErr := finalizeTodo(repoPath, item.ID, StatusFailed)
-			return result, errors.Join(err, updateErr, finalizeErr)
+			return fail(err)
 		}
 		opts.EventLog = eventLog
-		createdEventLog = true
-	}
-	if createdEventLog {
 		defer func() {
-			_ = opts.EventLog.Close()
+			_ = eventLog.Close()
 		}()
 	}
 	if opts.EventStream != nil {
 		opts.EventLog.SetStream(opts.EventStream)
 	}
-	if err := appendJobEvent(opts.EventLog, jobEventStage, stageEventData{Stage: created.Stage}); err != nil {
-		status := StatusFailed
-		updated, updateErr := manager.Update(created.ID, UpdateOptions{Status: &status}, opts.Now())
-		result.Job = updated
-		finalizeErr := finalizeTodo(repoPath, item.ID, StatusFailed)
-		return result, errors.Join(err, updateErr, finalizeErr)
-	}
-	if opts.OnStageChange != nil {
-		opts.OnStageChange(created.Stage)
+	// Registered after the event log and stream, so the result is the last
+	// event streamed on every path, including failures and interrupts.
+	defer func() {
+		runErr = errors.Join(runErr, recordRunResult(opts.EventLog, opts.Now, result.Job, result.DidWork, result.CommitLog, exec.startedAt))
+	}()
+
+	ctx := exec.ctx
+	ctx.repoPath = exec.repoPath
+	ctx.workspacePath = exec.workspacePath
+	ctx.item = exec.item
+	ctx.opts = opts
+	ctx.manager = exec.manager
+	ctx.result = result
+	if exec.prepare != nil {
+		if err := exec.prepare(ctx); err != nil {
+			return fail(err)
+		}
 	}
 
 	interrupts := opts.Interrupts
@@ -263,24 +466,13 @@ func Run(repoPath, todoID string, opts RunOptions) (*RunResult, error) {
 		interrupts = localInterrupts
 	}
 
-	runCtx := runContext{
-		repoPath:      repoPath,
-		workspacePath: workspacePath,
-		item:          item,
-		opts:          opts,
-		manager:       manager,
-		result:        result,
-	}
-	finalJob, err := runJobStages(&runCtx, created, interrupts)
+	finalJob, err := runJobStages(ctx, exec.job, interrupts)
 	result.Job = finalJob
-	statusErr := finalizeTodo(repoPath, item.ID, finalJob.Status)
-	if err != nil {
-		return result, errors.Join(err, statusErr)
-	}
-	if statusErr != nil {
-		return result, statusErr
-	}
-	return result, nil
+	result.DidWork = len(result.CommitLog) > 0
+	result.WebhookDone = notifyCompletion(opts, finalJob, result.CommitLog)
+	statusErr := finalize(finalJob.Status)
+	statusErr = errors.Join(statusErr, noteCompletion(opts, exec.repoPath, exec.item.ID, finalJob, result.CommitLog))
+	return errors.Join(err, statusErr)
 }
 
 type runContext struct {
@@ -294,33 +486,69 @@ type runContext struct {
 	commitMessage  string
 	reviewComments string
 	workComplete   bool
+	// iteration counts implementing stage entries so far.
+	iteration int
+	// commitsSinceProjectReview counts commits made since the last project
+	// review, for job.project-review-every.
+	commitsSinceProjectReview int
+	// baseline is the commit the running stage started from, if it
+	// recorded one.
+	baseline stageBaseline
+	// stop interrupts the running stage's opencode runs.
+	stop stageStop
+	// conflictRetries counts consecutive implementing runs sent back for
+	// unresolved conflicts.
+	conflictRetries int
+	// dryRunChanged is set once a dry run simulated a change, so later
+	// implementing runs report none and the job can finish.
+	dryRunChanged bool
+	// messageRetry is set when committing rejected the commit message, so
+	// the next implementing run keeps the uncommitted change even if only
+	// the message file was rewritten.
+	messageRetry bool
+	// messageRetries counts commit messages rejected in a row.
+	messageRetries int
 }
 
+// runJobStages drives the job from its current stage until it is no longer
+// active. A fresh job starts at the implementing stage; a resumed job may
+// enter at any stage, with ctx already holding the state that stage needs.
 func runJobStages(ctx *runContext, current Job, interrupts <-chan os.Signal) (Job, error) {
-	ctx.reviewScope = reviewScopeStep
+	ctx.opts.RunOpencode = ctx.stop.wrap(ctx.opts.RunOpencode)
 	for current.Status == StatusActive {
-		if current.Stage != StageImplementing {
-			return current, fmt.Errorf("invalid job stage: %s", current.Stage)
-		}
+		var next Job
+		var stageErr error
+		if current.Stage == StageImplementing {
+			ctx.iteration++
+			if ctx.opts.MaxIterations > 0 && ctx.iteration > ctx.opts.MaxIterations {
+				return ctx.failMaxIterations(current)
+			}
+			if announced, err := ctx.announceStage(current, ctx.iteration); err != nil {
+				return announced, err
+			}
 
-		next, stageErr := ctx.runStageWithInterrupt(current, ctx.runImplementingStage(current), interrupts)
-		if stageErr != nil && errors.Is(stageErr, ErrJobInterrupted) {
-			return next, stageErr
-		}
-		current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
-		if stageErr != nil {
-			return current, stageErr
-		}
-		if current.Status != StatusActive {
-			break
-		}
-		if ctx.workComplete {
-			ctx.reviewScope = reviewScopeProject
+			next, stageErr = ctx.runStageWithInterrupt(current, ctx.runImplementingStage(current), interrupts)
+			if stageErr != nil && isJobStopped(stageErr) {
+				return next, stageErr
+			}
+			current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
+			if stageErr != nil {
+				return current, stageErr
+			}
+			if current.Status != StatusActive {
+				break
+			}
+			if current.Stage == StageImplementing {
+				continue
+			}
+			if ctx.workComplete {
+				ctx.reviewScope = reviewScopeProject
+			}
 		}
 
 		if current.Stage == StageTesting {
 			next, stageErr = ctx.runStageWithInterrupt(current, ctx.runTestingStage(current), interrupts)
-			if stageErr != nil && errors.Is(stageErr, ErrJobInterrupted) {
+			if stageErr != nil && isJobStopped(stageErr) {
 				return next, stageErr
 			}
 			current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
@@ -336,70 +564,172 @@ func runJobStages(ctx *runContext, current Job, interrupts <-chan os.Signal) (Jo
 			}
 		}
 
-		next, stageErr = ctx.runStageWithInterrupt(current, ctx.runReviewingStage(current), interrupts)
-		if stageErr != nil && errors.Is(stageErr, ErrJobInterrupted) {
-			return next, stageErr
-		}
-		current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
-		if stageErr != nil {
-			return current, stageErr
-		}
-		if current.Status != StatusActive {
-			break
-		}
-		if current.Stage == StageImplementing {
-			ctx.reviewScope = reviewScopeStep
-			continue
-		}
-		if ctx.reviewScope == reviewScopeProject {
-			continue
+		if current.Stage == StageReviewing {
+			next, stageErr = ctx.runStageWithInterrupt(current, ctx.runReviewingStage(current), interrupts)
+			if stageErr != nil && isJobStopped(stageErr) {
+				return next, stageErr
+			}
+			current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
+			if stageErr != nil {
+				return current, stageErr
+			}
+			if current.Status != StatusActive {
+				break
+			}
+			if ctx.reviewScope == reviewScopeProject {
+				ctx.commitsSinceProjectReview = 0
+			}
+			if current.Stage == StageImplementing {
+				ctx.reviewScope = reviewScopeStep
+				continue
+			}
+			if ctx.reviewScope == reviewScopeProject {
+				continue
+			}
 		}
 
+		if current.Stage != StageCommitting {
+			return current, fmt.Errorf("invalid job stage: %s", current.Stage)
+		}
+		commits := len(ctx.result.CommitLog)
 		next, stageErr = ctx.runStageWithInterrupt(current, ctx.runCommittingStage(current), interrupts)
-		if stageErr != nil && errors.Is(stageErr, ErrJobInterrupted) {
+		if stageErr != nil && isJobStopped(stageErr) {
 			return next, stageErr
 		}
 		current, stageErr = ctx.handleStageOutcome(current, next, stageErr)
 		if stageErr != nil {
 			return current, stageErr
 		}
+		ctx.commitsSinceProjectReview += len(ctx.result.CommitLog) - commits
+		if current.Status == StatusActive && current.Stage == StageImplementing && ctx.projectReviewDue() {
+			current, stageErr = ctx.escalateToProjectReview(current)
+			if stageErr != nil {
+				return current, stageErr
+			}
+		}
 	}
 
 	return current, nil
 }
 
 func (ctx *runContext) runStageWithInterrupt(current Job, stageFn func() (Job, error), interrupts <-chan os.Signal) (Job, error) {
-	stageResult := make(chan struct {
-		job Job
-		err error
-	}, 1)
+	if ctx.pauseRequested() {
+		return ctx.handlePause(current)
+	}
+	ctx.baseline.set("")
+	ctx.stop.start()
+	start := ctx.opts.Now()
+	stageResult := make(chan stageOutcome, 1)
 	go func() {
 		job, err := stageFn()
-		stageResult <- struct {
-			job Job
-			err error
-		}{job: job, err: err}
+		stageResult <- stageOutcome{job: job, err: err}
 	}()
 
 	select {
 	case <-interrupts:
-		return ctx.handleInterrupt(current)
+		durationErr := recordStageDuration(ctx.opts.EventLog, ctx.opts.Now, current.Stage, start, true)
+		ctx.stop.stop()
+		updated, err := ctx.handleInterrupt(current, waitForStage(stageResult))
+		return updated, errors.Join(err, durationErr)
 	case res := <-stageResult:
+		if err := recordStageDuration(ctx.opts.EventLog, ctx.opts.Now, current.Stage, start, false); err != nil {
+			return res.job, errors.Join(res.err, err)
+		}
 		return res.job, res.err
 	}
 }
 
-func (ctx *runContext) handleInterrupt(current Job) (Job, error) {
+// handleInterrupt restores the workspace to the stage baseline, when enabled
+// and recorded, then marks the job failed. stopErr reports that the stage
+// did not stop in time, in which case the workspace is left alone rather
+// than restored under a still-running agent.
+func (ctx *runContext) handleInterrupt(current Job, stopErr error) (Job, error) {
+	var restoreErr error
+	if stopErr != nil {
+		restoreErr = fmt.Errorf("restore workspace: %w", stopErr)
+	} else if err := restoreInterruptedWorkspace(ctx.opts.RestoreOnInterrupt, ctx.opts.RestoreWorkspace, ctx.workspacePath, &ctx.baseline); err != nil {
+		restoreErr = fmt.Errorf("restore workspace: %w", err)
+	}
+	status := StatusFailed
+	updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
+	return updated, errors.Join(ErrJobInterrupted, restoreErr, updateErr)
+}
+
+// pauseRequested reports whether RunOptions.Pause has fired.
+func (ctx *runContext) pauseRequested() bool {
+	select {
+	case <-ctx.opts.Pause:
+		return true
+	default:
+		return false
+	}
+}
+
+// handlePause marks the job paused before it enters its next stage.
+func (ctx *runContext) handlePause(current Job) (Job, error) {
+	eventErr := appendJobEvent(ctx.opts.EventLog, jobEventPaused, pausedEventData{Stage: current.Stage})
+	status := StatusPaused
+	updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
+	return updated, errors.Join(ErrJobPaused, eventErr, updateErr)
+}
+
+// isJobStopped reports whether a stage error means the job stopped early and
+// its status is already recorded.
+func isJobStopped(err error) bool {
+	return errors.Is(err, ErrJobInterrupted) || errors.Is(err, ErrJobPaused)
+}
+
+// projectReviewDue reports whether job.project-review-every commits have
+// been made since the last project review.
+func (ctx *runContext) projectReviewDue() bool {
+	if ctx.opts.Config == nil || ctx.opts.Config.Job.ProjectReviewEvery <= 0 {
+		return false
+	}
+	return ctx.commitsSinceProjectReview >= ctx.opts.Config.Job.ProjectReviewEvery
+}
+
+// escalateToProjectReview sends a job that just committed to a project
+// review instead of back to implementing. The review sees the job's commits
+// the same way as when implementing finds nothing left to do.
+func (ctx *runContext) escalateToProjectReview(current Job) (Job, error) {
+	ctx.reviewScope = reviewScopeProject
+	ctx.commitMessage = ""
+	stage := StageReviewing
+	next, err := ctx.manager.Update(current.ID, UpdateOptions{Stage: &stage}, ctx.opts.Now())
+	return ctx.handleStageOutcome(current, next, err)
+}
+
+// failMaxIterations fails a job that would exceed its iteration cap.
+func (ctx *runContext) failMaxIterations(current Job) (Job, error) {
+	limitErr := fmt.Errorf("%w: stopped after %d iterations", ErrMaxIterationsExceeded, ctx.opts.MaxIterations)
+	eventErr := appendJobEvent(ctx.opts.EventLog, jobEventMaxIterations, maxIterationsEventData{MaxIterations: ctx.opts.MaxIterations})
 	status := StatusFailed
 	updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
-	return updated, errors.Join(ErrJobInterrupted, updateErr)
+	ctx.result.Job = updated
+	return updated, errors.Join(limitErr, eventErr, updateErr)
+}
+
+// announceStage records a job.stage event for the job's stage and reports it
+// to OnStageChange. A failure to record the event fails the job.
+func (ctx *runContext) announceStage(current Job, iteration int) (Job, error) {
+	if err := appendJobEvent(ctx.opts.EventLog, jobEventStage, stageEventData{Stage: current.Stage, Iteration: iteration}); err != nil {
+		status := StatusFailed
+		updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
+		ctx.result.Job = updated
+		return updated, errors.Join(err, updateErr)
+	}
+	if ctx.opts.OnStageChange != nil {
+		ctx.opts.OnStageChange(StageChange{Stage: current.Stage, Iteration: iteration})
+	}
+	return current, nil
 }
 
 func (ctx *runContext) handleStageOutcome(current, next Job, stageErr error) (Job, error) {
 	if stageErr != nil {
 		if next.Status == StatusAbandoned {
-			ctx.result.Job = next
-			return next, stageErr
+			recorded, recordErr := recordAbandon(ctx.manager, ctx.opts.EventLog, ctx.opts.Now(), next, stageErr)
+			ctx.result.Job = recorded
+			return recorded, errors.Join(stageErr, recordErr)
 		}
 		status := StatusFailed
 		updated, updateErr := ctx.manager.Update(current.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
@@ -407,15 +737,11 @@ func (ctx *runContext) handleStageOutcome(current, next Job, stageErr error) (Jo
 		return updated, errors.Join(stageErr, updateErr)
 	}
 	if next.ID != "" {
-		if next.Stage != current.Stage {
-			if err := appendJobEvent(ctx.opts.EventLog, jobEventStage, stageEventData{Stage: next.Stage}); err != nil {
-				status := StatusFailed
-				updated, updateErr := ctx.manager.Update(next.ID, UpdateOptions{Status: &status}, ctx.opts.Now())
-				ctx.result.Job = updated
-				return updated, errors.Join(err, updateErr)
-			}
-			if ctx.opts.OnStageChange != nil {
-				ctx.opts.OnStageChange(next.Stage)
+		// Implementing entries are announced by runJobStages once the
+		// iteration cap has allowed them.
+		if next.Stage != current.Stage && next.Stage != StageImplementing {
+			if failed, err := ctx.announceStage(next, ctx.iteration); err != nil {
+				return failed, err
 			}
 		}
 		current = next
@@ -424,12 +750,45 @@ func (ctx *runContext) handleStageOutcome(current, next Job, stageErr error) (Jo
 	return current, nil
 }
 
+// recordAbandon stores the reason carried by an AbandonedError on the
+// abandoned job and logs it as a job.abandoned event.
+func recordAbandon(manager *Manager, log *EventLog, now time.Time, abandoned Job, stageErr error) (Job, error) {
+	reason := ""
+	var abandonedErr *AbandonedError
+	if errors.As(stageErr, &abandonedErr) {
+		reason = abandonedErr.Reason
+	}
+	updated, err := manager.Update(abandoned.ID, UpdateOptions{Reason: &reason}, now)
+	if err != nil {
+		return abandoned, err
+	}
+	if err := appendJobEvent(log, jobEventAbandoned, abandonedEventData{Reason: reason}); err != nil {
+		return updated, err
+	}
+	return updated, nil
+}
+
 func (ctx *runContext) runImplementingStage(current Job) func() (Job, error) {
 	return func() (Job, error) {
-		result, err := runImplementingStage(ctx.manager, current, ctx.item, ctx.repoPath, ctx.workspacePath, ctx.opts, ctx.result.CommitLog, ctx.commitMessage)
+		state := implementingStageState{
+			recordBaseline:  ctx.baseline.set,
+			conflictRetries: ctx.conflictRetries,
+			dryRunChanged:   ctx.dryRunChanged,
+			messageRetry:    ctx.messageRetry,
+		}
+		result, err := runImplementingStage(ctx.manager, current, ctx.item, ctx.repoPath, ctx.workspacePath, ctx.opts, ctx.result.CommitLog, ctx.commitMessage, state)
 		if err != nil {
 			return Job{}, err
 		}
+		if result.Conflicted {
+			ctx.conflictRetries++
+		} else {
+			ctx.conflictRetries = 0
+		}
+		if ctx.opts.DryRun && result.Changed {
+			ctx.dryRunChanged = true
+		}
+		ctx.messageRetry = false
 		ctx.commitMessage = result.CommitMessage
 		ctx.workComplete = !result.Changed
 		return result.Job, nil
@@ -455,7 +814,7 @@ func (ctx *runContext) runReviewingStage(current Job) func() (Job, error) {
 
 func (ctx *runContext) runCommittingStage(current Job) func() (Job, error) {
 	return func() (Job, error) {
-		return runCommittingStage(CommittingStageOptions{
+		result, err := runCommittingStage(CommittingStageOptions{
 			Manager:        ctx.manager,
 			Current:        current,
 			Item:           ctx.item,
@@ -465,7 +824,18 @@ func (ctx *runContext) runCommittingStage(current Job) func() (Job, error) {
 			Result:         ctx.result,
 			CommitMessage:  ctx.commitMessage,
 			ReviewComments: ctx.reviewComments,
+			MessageRetries: ctx.messageRetries,
 		})
+		if err != nil {
+			return Job{}, err
+		}
+		ctx.messageRetry = result.MessageRetry
+		if result.MessageRetry {
+			ctx.messageRetries++
+		} else {
+			ctx.messageRetries = 0
+		}
+		return result.Job, nil
 	}
 }
 
@@ -476,9 +846,6 @@ func normalizeRunOptions(opts RunOptions) RunOptions {
 	if opts.LoadConfig == nil {
 		opts.LoadConfig = config.Load
 	}
-	if opts.RunTests == nil {
-		opts.RunTests = RunTestCommands
-	}
 	if opts.RunOpencode == nil {
 		opts.RunOpencode = func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
 			store, err := opencode.Open()
@@ -507,12 +874,18 @@ func normalizeRunOptions(opts RunOptions) RunOptions {
 	if opts.DiffStat == nil {
 		opts.DiffStat = getJJ().DiffStat
 	}
+	if opts.DiffPreview == nil {
+		opts.DiffPreview = getJJ().DiffPreview
+	}
 	if opts.CommitIDAt == nil {
 		opts.CommitIDAt = getJJ().CommitIDAt
 	}
 	if opts.Commit == nil {
 		opts.Commit = getJJ().Commit
 	}
+	if opts.NewChange == nil {
+		opts.NewChange = getJJ().NewChange
+	}
 	if opts.RestoreWorkspace == nil {
 		opts.RestoreWorkspace = getJJ().Edit
 	}
@@ -522,6 +895,17 @@ func normalizeRunOptions(opts RunOptions) RunOptions {
 	if opts.Snapshot == nil {
 		opts.Snapshot = getJJ().Snapshot
 	}
+	if opts.MoveBookmark == nil {
+		opts.MoveBookmark = getJJ().BookmarkSet
+	}
+	if opts.HasConflicts == nil {
+		opts.HasConflicts = getJJ().HasConflicts
+	}
+	if opts.ConflictedPaths == nil {
+		opts.ConflictedPaths = func(workspacePath string) ([]string, error) {
+			return getJJ().ConflictedPaths(workspacePath, "@")
+		}
+	}
 	if opts.OpencodeTranscripts == nil {
 		opts.OpencodeTranscripts = opencodeTranscripts
 	}
@@ -529,7 +913,10 @@ func normalizeRunOptions(opts RunOptions) RunOptions {
 	return opts
 }
 
-func resolveOpencodeAgentForPurpose(cfg *config.Config, override, purpose string, item todo.Todo) string {
+// resolveOpencodeAgentForPurpose picks the model for a stage: the explicit
+// override, then the todo's model, then the profile's model, then the
+// configured per-type and global defaults.
+func resolveOpencodeAgentForPurpose(cfg *config.Config, override, profile, purpose string, item todo.Todo) string {
 	if !internalstrings.IsBlank(override) {
 		return internalstrings.TrimSpace(override)
 	}
@@ -540,23 +927,74 @@ func resolveOpencodeAgentForPurpose(cfg *config.Config, override, purpose string
 	if cfg == nil {
 		return ""
 	}
+	if selected, ok := cfg.LookupProfile(profileName(profile, item)); ok {
+		if model := profileModelForPurpose(selected, purpose); !internalstrings.IsBlank(model) {
+			return internalstrings.TrimSpace(model)
+		}
+	}
+	todoType := internalstrings.NormalizeLowerTrimSpace(string(item.Type))
 	model := ""
+	byType := map[string]string(nil)
 	switch purpose {
 	case "implement":
 		model = cfg.Job.ImplementationModel
+		byType = cfg.Job.ImplementationModelByType
 	case "review":
 		model = cfg.Job.CodeReviewModel
+		byType = cfg.Job.CodeReviewModelByType
 	case "project-review":
 		model = cfg.Job.ProjectReviewModel
+		byType = cfg.Job.ProjectReviewModelByType
 	default:
 		model = cfg.Job.Agent
 	}
+	if typed := byType[todoType]; !internalstrings.IsBlank(typed) {
+		return internalstrings.TrimSpace(typed)
+	}
+	if typed := cfg.Job.AgentByType[todoType]; !internalstrings.IsBlank(typed) {
+		return internalstrings.TrimSpace(typed)
+	}
 	if internalstrings.IsBlank(model) {
 		model = cfg.Job.Agent
 	}
 	return internalstrings.TrimSpace(model)
 }
 
+// profileName returns the run's profile, or the todo's when the run names
+// none.
+func profileName(profile string, item todo.Todo) string {
+	if !internalstrings.IsBlank(profile) {
+		return internalstrings.TrimSpace(profile)
+	}
+	return internalstrings.TrimSpace(item.Profile)
+}
+
+// checkProfile reports ErrUnknownProfile when the run or todo selects a
+// profile the config does not define.
+func checkProfile(cfg *config.Config, profile string, item todo.Todo) error {
+	name := profileName(profile, item)
+	if name == "" {
+		return nil
+	}
+	if _, ok := cfg.LookupProfile(name); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+	return nil
+}
+
+func profileModelForPurpose(profile config.Profile, purpose string) string {
+	switch purpose {
+	case "implement":
+		return profile.ImplementationModel
+	case "review":
+		return profile.CodeReviewModel
+	case "project-review":
+		return profile.ProjectReviewModel
+	default:
+		return ""
+	}
+}
+
 func todoModelForPurpose(item todo.Todo, purpose string) string {
 	switch purpose {
 	case "implement":
@@ -570,10 +1008,11 @@ func todoModelForPurpose(item todo.Todo, purpose string) string {
 	}
 }
 
-func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPath, workspacePath string, opts RunOptions, commitLog []CommitLogEntry, previousMessage string) (ImplementingStageResult, error) {
+func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPath, workspacePath string, opts RunOptions, commitLog []CommitLogEntry, previousMessage string, state implementingStageState) (ImplementingStageResult, error) {
 	logger := resolveLogger(opts.Logger)
 	updateStaleWorkspace(opts.UpdateStale, workspacePath)
-	feedbackPath := filepath.Join(workspacePath, feedbackFilename)
+	files := resolveOutputFiles(opts.Config)
+	feedbackPath := filepath.Join(workspacePath, files.Feedback)
 	if err := removeFileIfExists(feedbackPath); err != nil {
 		return ImplementingStageResult{}, err
 	}
@@ -582,6 +1021,9 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 	if err != nil {
 		return ImplementingStageResult{}, err
 	}
+	if state.recordBaseline != nil {
+		state.recordBaseline(beforeCommitID)
+	}
 
 	// Ensure we have a current change to track commits against.
 	// Create a new JobChange if there's no in-progress change.
@@ -601,17 +1043,23 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 	if !internalstrings.IsBlank(current.Feedback) {
 		promptName = "prompt-feedback.tmpl"
 	}
-	prompt, err := renderPromptTemplate(item, current.Feedback, previousMessage, commitLog, nil, promptName, workspacePath)
+	if err := recordDesignDocWarning(opts.EventLog, item, workspacePath); err != nil {
+		return ImplementingStageResult{}, err
+	}
+	prompt, err := renderPromptTemplate(item, current.Feedback, previousMessage, commitLog, nil, promptName, workspacePath, files)
 	if err != nil {
 		return ImplementingStageResult{}, err
 	}
-	if err := appendJobEvent(opts.EventLog, jobEventPrompt, promptEventData{Purpose: "implement", Template: promptName, Prompt: prompt}); err != nil {
+	if err := appendJobEvent(opts.EventLog, jobEventPrompt, promptEventData{Purpose: "implement", Template: promptName, Prompt: prompt, Stage: current.Stage, At: opts.Now()}); err != nil {
 		return ImplementingStageResult{}, err
 	}
 
-	agent := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, "implement", item)
+	agent := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, opts.Profile, "implement", item)
 	var lastSessionID string
+	restorePoint := beforeCommitID
+	snapshotInterval := resolveSnapshotInterval(opts.Config)
 	runAttempt := func() (OpencodeRunResult, error) {
+		stopSnapshots := startPeriodicSnapshots(opts.Snapshot, opts.CurrentCommitID, workspacePath, snapshotInterval, state.recordBaseline)
 		result, err := runOpencodeWithEvents(opts, opencodeRunOptions{
 			RepoPath:      repoPath,
 			WorkspacePath: workspacePath,
@@ -619,8 +1067,11 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 			Agent:         agent,
 			StartedAt:     opts.Now(),
 			EventLog:      opts.EventLog,
-			Env:           applyOpencodeConfigEnv(nil),
+			Env:           applyOpencodeConfigEnv(nil, opts.Config),
 		}, "implement")
+		if snapshotID := stopSnapshots(); snapshotID != "" {
+			restorePoint = snapshotID
+		}
 		if err != nil {
 			return OpencodeRunResult{}, err
 		}
@@ -646,6 +1097,7 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 		return ImplementingStageResult{}, err
 	}
 
+	maxRetries := resolveImplementRetries(opts.Config)
 	retryCount := 0
 	for opencodeResult.ExitCode != 0 {
 		afterCommitID := ""
@@ -657,13 +1109,13 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 		var restoreErr error
 		if opencodeResult.ExitCode < 0 && afterCommitErr == nil && afterCommitID != "" && beforeCommitID != "" && afterCommitID != beforeCommitID {
 			if opts.RestoreWorkspace != nil {
-				restoreErr = opts.RestoreWorkspace(workspacePath, beforeCommitID)
+				restoreErr = opts.RestoreWorkspace(workspacePath, restorePoint)
 				if restoreErr == nil {
 					restored = true
 				}
 			}
 		}
-		if restored && retryCount == 0 {
+		if restored && retryCount < maxRetries {
 			retryCount++
 			opencodeResult, err = runAttempt()
 			if err != nil {
@@ -676,7 +1128,7 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 			WorkspacePath: workspacePath,
 			Prompt:        prompt,
 			Agent:         agent,
-		}, beforeCommitID, afterCommitID, afterCommitErr, restored, restoreErr, retryCount))
+		}, beforeCommitID, afterCommitID, restorePoint, afterCommitErr, restored, restoreErr, retryCount))
 	}
 
 	afterCommitID, err := opts.CurrentCommitID(workspacePath)
@@ -685,7 +1137,9 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 	}
 
 	changed := beforeCommitID != afterCommitID
-	if changed {
+	if opts.DryRun {
+		changed = !state.dryRunChanged
+	} else if changed {
 		if opts.CurrentChangeEmpty == nil {
 			return ImplementingStageResult{}, fmt.Errorf("current change empty check is required")
 		}
@@ -697,22 +1151,39 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 			changed = false
 		}
 	}
+	if !changed && state.messageRetry && !opts.DryRun {
+		// Committing rejected only the message. The change it was about to
+		// commit is still in the working copy, so a run that rewrote just
+		// the message file still has work to commit.
+		if opts.CurrentChangeEmpty == nil {
+			return ImplementingStageResult{}, fmt.Errorf("current change empty check is required")
+		}
+		empty, err := opts.CurrentChangeEmpty(workspacePath)
+		if err != nil {
+			return ImplementingStageResult{}, err
+		}
+		changed = !empty
+	}
 	message := ""
 	if changed {
-		messagePath := filepath.Join(workspacePath, commitMessageFilename)
-		message, err = readCommitMessage(messagePath)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return ImplementingStageResult{}, fmt.Errorf(
-					"commit message missing after opencode implementation; opencode session %s was instructed to write %s because the workspace changed from %s to %s: %w",
-					opencodeResult.SessionID,
-					messagePath,
-					beforeCommitID,
-					afterCommitID,
-					err,
-				)
+		messagePath := filepath.Join(workspacePath, files.CommitMessage)
+		if opts.DryRun {
+			message = dryRunCommitMessage(item)
+		} else {
+			message, err = readCommitMessage(messagePath)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return ImplementingStageResult{}, fmt.Errorf(
+						"commit message missing after opencode implementation; opencode session %s was instructed to write %s because the workspace changed from %s to %s: %w",
+						opencodeResult.SessionID,
+						messagePath,
+						beforeCommitID,
+						afterCommitID,
+						err,
+					)
+				}
+				return ImplementingStageResult{}, err
 			}
-			return ImplementingStageResult{}, err
 		}
 		logger.CommitMessage(CommitMessageLog{Label: "Draft", Message: message})
 		if err := appendJobEvent(opts.EventLog, jobEventCommitMessage, commitMessageEventData{Label: "Draft", Message: message}); err != nil {
@@ -730,15 +1201,30 @@ func runImplementingStage(manager *Manager, current Job, item todo.Todo, repoPat
 			return ImplementingStageResult{}, fmt.Errorf("append commit to change: %w", err)
 		}
 	} else {
-		messagePath := filepath.Join(workspacePath, commitMessageFilename)
+		messagePath := filepath.Join(workspacePath, files.CommitMessage)
 		if err := removeFileIfExists(messagePath); err != nil {
 			return ImplementingStageResult{}, err
 		}
 	}
 
-	nextStage := StageTesting
-	if !changed {
-		nextStage = StageReviewing
+	// Conflicts are checked even when the run changed nothing, since the
+	// working copy may still hold conflicts from an earlier run.
+	feedback, err := checkConflicts(opts, workspacePath, state.conflictRetries)
+	if err != nil {
+		return ImplementingStageResult{}, err
+	}
+	if feedback != "" {
+		nextStage := StageImplementing
+		updated, err = manager.Update(updated.ID, UpdateOptions{Stage: &nextStage, Feedback: &feedback}, opts.Now())
+		if err != nil {
+			return ImplementingStageResult{}, err
+		}
+		return ImplementingStageResult{Job: updated, CommitMessage: message, Changed: changed, Conflicted: true}, nil
+	}
+
+	nextStage, err := stageAfterImplementing(opts.Config, opts.EventLog, changed)
+	if err != nil {
+		return ImplementingStageResult{}, err
 	}
 	updated, err = manager.Update(updated.ID, UpdateOptions{Stage: &nextStage}, opts.Now())
 	if err != nil {
@@ -757,11 +1243,17 @@ func runTestingStage(manager *Manager, current Job, repoPath, workspacePath stri
 			return Job{}, fmt.Errorf("load config: %w", err)
 		}
 	}
+	if testsSkipped(cfg) {
+		if err := recordTestsSkipped(opts.EventLog); err != nil {
+			return Job{}, err
+		}
+		return skipTestingStage(manager, current.ID, opts.Now())
+	}
 	if len(cfg.Job.TestCommands) < 1 {
 		return Job{}, fmt.Errorf("job test-commands must be configured")
 	}
 
-	results, err := opts.RunTests(workspacePath, cfg.Job.TestCommands)
+	results, err := runConfiguredTests(opts.RunTests, workspacePath, cfg)
 	if err != nil {
 		return Job{}, err
 	}
@@ -796,18 +1288,62 @@ func runTestingStage(manager *Manager, current Job, repoPath, workspacePath stri
 	return updated, nil
 }
 
+// testsSkipped reports whether jobs should bypass the testing stage: no test
+// commands are configured and the config does not require them.
+func testsSkipped(cfg *config.Config) bool {
+	return cfg != nil && !cfg.Job.TestsRequired() && len(cfg.Job.TestCommands) == 0
+}
+
+func recordTestsSkipped(log *EventLog) error {
+	return appendJobEvent(log, jobEventTestsSkipped, testsSkippedEventData{
+		Reason: "no test-commands are configured and require-tests is false",
+	})
+}
+
+// stageAfterImplementing picks the stage that follows implementing: testing
+// when the workspace changed, unless tests are skipped, and reviewing
+// otherwise.
+func stageAfterImplementing(cfg *config.Config, log *EventLog, changed bool) (Stage, error) {
+	if !changed {
+		return StageReviewing, nil
+	}
+	if testsSkipped(cfg) {
+		if err := recordTestsSkipped(log); err != nil {
+			return "", err
+		}
+		return StageReviewing, nil
+	}
+	return StageTesting, nil
+}
+
+// skipTestingStage moves a job that reached the testing stage straight to
+// reviewing, clearing any stale feedback.
+func skipTestingStage(manager *Manager, jobID string, now time.Time) (Job, error) {
+	nextStage := StageReviewing
+	empty := ""
+	return manager.Update(jobID, UpdateOptions{Stage: &nextStage, Feedback: &empty}, now)
+}
+
 func runReviewingStage(manager *Manager, current Job, item todo.Todo, repoPath, workspacePath string, opts RunOptions, commitMessage string, commitLog []CommitLogEntry, scope reviewScope) (ReviewingStageResult, error) {
 	logger := resolveLogger(opts.Logger)
 	updateStaleWorkspace(opts.UpdateStale, workspacePath)
-	feedbackPath := filepath.Join(workspacePath, feedbackFilename)
+	// Snapshot first so the review sees files written since opencode last ran.
+	snapshotWorkspace(opts.Snapshot, workspacePath)
+	files := resolveOutputFiles(opts.Config)
+	feedbackPath := filepath.Join(workspacePath, files.Feedback)
 	if err := removeFileIfExists(feedbackPath); err != nil {
 		return ReviewingStageResult{}, err
 	}
 
-	message, err := resolveReviewCommitMessage(commitMessage, workspacePath, scope == reviewScopeStep)
+	message, err := resolveReviewCommitMessage(commitMessage, workspacePath, files.CommitMessage, scope == reviewScopeStep)
 	if err != nil {
 		return ReviewingStageResult{}, err
 	}
+	if scope == reviewScopeProject && internalstrings.IsBlank(message) && len(commitLog) > 0 {
+		// Without a pending message, the last commit gives the project
+		// reviewer a summary of the accumulated work.
+		message = commitLog[len(commitLog)-1].Message
+	}
 
 	promptName := "prompt-commit-review.tmpl"
 	purpose := "review"
@@ -815,18 +1351,21 @@ func runReviewingStage(manager *Manager, current Job, item todo.Todo, repoPath,
 		promptName = "prompt-project-review.tmpl"
 		purpose = "project-review"
 	}
-	agent := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, purpose, item)
+	agent := resolveOpencodeAgentForPurpose(opts.Config, opts.OpencodeAgent, opts.Profile, purpose, item)
 
 	promptTemplate, err := LoadPrompt(workspacePath, promptName)
 	if err != nil {
 		return ReviewingStageResult{}, err
 	}
 	promptTemplate = ensureCommitMessageInPrompt(promptTemplate, message)
-	prompt, err := RenderPrompt(workspacePath, promptTemplate, newPromptData(item, "", message, commitLog, nil, workspacePath))
+	if err := recordDesignDocWarning(opts.EventLog, item, workspacePath); err != nil {
+		return ReviewingStageResult{}, err
+	}
+	prompt, err := RenderPrompt(workspacePath, promptTemplate, newPromptData(item, "", message, commitLog, nil, workspacePath).withOutputFiles(files))
 	if err != nil {
 		return ReviewingStageResult{}, err
 	}
-	if err := appendJobEvent(opts.EventLog, jobEventPrompt, promptEventData{Purpose: purpose, Template: promptName, Prompt: prompt}); err != nil {
+	if err := appendJobEvent(opts.EventLog, jobEventPrompt, promptEventData{Purpose: purpose, Template: promptName, Prompt: prompt, Stage: current.Stage, At: opts.Now()}); err != nil {
 		return ReviewingStageResult{}, err
 	}
 
@@ -837,7 +1376,7 @@ func runReviewingStage(manager *Manager, current Job, item todo.Todo, repoPath,
 		Agent:         agent,
 		StartedAt:     opts.Now(),
 		EventLog:      opts.EventLog,
-		Env:           applyOpencodeConfigEnv(nil),
+		Env:           applyOpencodeConfigEnv(nil, opts.Config),
 	}, purpose)
 	if err != nil {
 		return ReviewingStageResult{}, err
@@ -860,7 +1399,11 @@ func runReviewingStage(manager *Manager, current Job, item todo.Todo, repoPath,
 		return ReviewingStageResult{}, fmt.Errorf("opencode review failed with exit code %d", opencodeResult.ExitCode)
 	}
 
-	feedback, err := ReadReviewFeedback(feedbackPath)
+	aliases, err := reviewOutcomeAliasesFromConfig(opts.Config)
+	if err != nil {
+		return ReviewingStageResult{}, err
+	}
+	feedback, err := ReadReviewFeedbackWithAliases(feedbackPath, aliases)
 	if err != nil {
 		return ReviewingStageResult{}, err
 	}
@@ -873,6 +1416,7 @@ func runReviewingStage(manager *Manager, current Job, item todo.Todo, repoPath,
 	review := JobReview{
 		Outcome:           feedback.Outcome,
 		Comments:          feedback.Details,
+		FileComments:      feedback.FileComments,
 		OpencodeSessionID: opencodeResult.SessionID,
 	}
 	if scope == reviewScopeProject {
@@ -933,56 +1477,103 @@ type CommittingStageOptions struct {
 	Result         *RunResult
 	CommitMessage  string
 	ReviewComments string
+	// MessageRetries counts the commit messages rejected in a row, for
+	// job.commit-message-retries.
+	MessageRetries int
+}
+
+type CommittingStageResult struct {
+	Job Job
+	// MessageRetry reports that the commit message was rejected and the job
+	// went back to implementing to rewrite it, with the change uncommitted.
+	MessageRetry bool
 }
 
-func runCommittingStage(opts CommittingStageOptions) (Job, error) {
+func runCommittingStage(opts CommittingStageOptions) (CommittingStageResult, error) {
 	logger := resolveLogger(opts.RunOptions.Logger)
 	updateStaleWorkspace(opts.RunOptions.UpdateStale, opts.WorkspacePath)
+	// Snapshot first so the diff reflects the latest working copy.
+	snapshotWorkspace(opts.RunOptions.Snapshot, opts.WorkspacePath)
 	if opts.RunOptions.DiffStat == nil {
-		return Job{}, fmt.Errorf("diff stat is required")
+		return CommittingStageResult{}, fmt.Errorf("diff stat is required")
 	}
 	diffStat, err := opts.RunOptions.DiffStat(opts.WorkspacePath, "@-", "@")
 	if err != nil {
-		return Job{}, err
+		return CommittingStageResult{}, err
 	}
-	if !diffStatHasChanges(diffStat) {
+	// A dry run's change is simulated, so its empty diff still commits.
+	if !diffStatHasChanges(diffStat) && !opts.RunOptions.DryRun {
 		nextStage := StageImplementing
 		updated, err := opts.Manager.Update(opts.Current.ID, UpdateOptions{Stage: &nextStage}, opts.RunOptions.Now())
 		if err != nil {
-			return Job{}, err
+			return CommittingStageResult{}, err
 		}
-		return updated, nil
+		return CommittingStageResult{Job: updated}, nil
+	}
+	if err := checkDiffSize(opts.RunOptions.EventLog, opts.RunOptions.Config, diffStat); err != nil {
+		return CommittingStageResult{}, err
+	}
+	if err := recordDiffPreview(opts.RunOptions.EventLog, opts.RunOptions.DiffPreview, opts.WorkspacePath); err != nil {
+		return CommittingStageResult{}, err
 	}
 	message := internalstrings.TrimSpace(opts.CommitMessage)
 	if message == "" {
-		return Job{}, fmt.Errorf("commit message is required")
+		return CommittingStageResult{}, fmt.Errorf("commit message is required")
+	}
+	feedback, err := checkCommitMessagePattern(opts.RunOptions.EventLog, opts.RunOptions.Config, message, opts.MessageRetries)
+	if err != nil {
+		return CommittingStageResult{}, err
+	}
+	if feedback != "" {
+		nextStage := StageImplementing
+		updated, err := opts.Manager.Update(opts.Current.ID, UpdateOptions{Stage: &nextStage, Feedback: &feedback}, opts.RunOptions.Now())
+		if err != nil {
+			return CommittingStageResult{}, err
+		}
+		return CommittingStageResult{Job: updated, MessageRetry: true}, nil
 	}
 
-	finalMessage := formatCommitMessage(opts.Item, message, opts.ReviewComments)
-	logMessage := formatCommitMessageWithWidth(opts.Item, message, opts.ReviewComments, lineWidth-subdocumentIndent)
+	width := commitMessageWidth(opts.RunOptions.Config)
+	finalMessage, err := renderCommitMessage(opts.WorkspacePath, opts.Item, message, opts.ReviewComments, width)
+	if err != nil {
+		return CommittingStageResult{}, err
+	}
+	logMessage, err := renderCommitMessage(opts.WorkspacePath, opts.Item, message, opts.ReviewComments, width-subdocumentIndent)
+	if err != nil {
+		return CommittingStageResult{}, err
+	}
 	opts.Result.CommitMessage = finalMessage
 	logger.CommitMessage(CommitMessageLog{Label: "Final", Message: logMessage, Preformatted: true})
 	if err := appendJobEvent(opts.RunOptions.EventLog, jobEventCommitMessage, commitMessageEventData{Label: "Final", Message: logMessage, Preformatted: true}); err != nil {
-		return Job{}, err
+		return CommittingStageResult{}, err
 	}
 
-	updateStaleWorkspace(opts.RunOptions.UpdateStale, opts.WorkspacePath)
-	if err := opts.RunOptions.Commit(opts.WorkspacePath, finalMessage); err != nil {
-		return Job{}, err
-	}
+	if opts.RunOptions.DryRun {
+		if err := appendJobEvent(opts.RunOptions.EventLog, jobEventDryRun, dryRunEventData{Action: "commit", Message: finalMessage}); err != nil {
+			return CommittingStageResult{}, err
+		}
+	} else {
+		updateStaleWorkspace(opts.RunOptions.UpdateStale, opts.WorkspacePath)
+		if err := opts.RunOptions.Commit(opts.WorkspacePath, finalMessage); err != nil {
+			return CommittingStageResult{}, err
+		}
 
-	commitID, err := opts.RunOptions.CommitIDAt(opts.WorkspacePath, "@-")
-	if err != nil {
-		return Job{}, err
+		commitID, err := opts.RunOptions.CommitIDAt(opts.WorkspacePath, "@-")
+		if err != nil {
+			return CommittingStageResult{}, err
+		}
+		if err := moveJobBookmark(opts.RunOptions, opts.WorkspacePath, commitID); err != nil {
+			return CommittingStageResult{}, err
+		}
+		opts.Result.CommitLog = append(opts.Result.CommitLog, CommitLogEntry{ID: commitID, Message: message})
 	}
-	opts.Result.CommitLog = append(opts.Result.CommitLog, CommitLogEntry{ID: commitID, Message: message})
 
 	nextStage := StageImplementing
 	updated, err := opts.Manager.Update(opts.Current.ID, UpdateOptions{Stage: &nextStage}, opts.RunOptions.Now())
 	if err != nil {
-		return Job{}, err
+		return CommittingStageResult{}, err
 	}
-	return updated, nil
+	return CommittingStageResult{Job: updated}, nil
 }
 
 type opencodeTranscriptEntry struct {
@@ -991,6 +1582,37 @@ type opencodeTranscriptEntry struct {
 	Transcript string
 }
 
+// transcriptPurposeOrder ranks session purposes in pipeline order. Unknown
+// purposes sort after the known ones.
+var transcriptPurposeOrder = map[string]int{
+	"implement":      0,
+	"review":         1,
+	"project-review": 2,
+}
+
+func transcriptPurposeRank(purpose string) int {
+	if rank, ok := transcriptPurposeOrder[purpose]; ok {
+		return rank
+	}
+	return len(transcriptPurposeOrder)
+}
+
+// sortOpencodeTranscriptEntries orders transcripts by start time. Sessions
+// that started at the same instant read in pipeline order (implement, then
+// review, then project review), then by session ID.
+func sortOpencodeTranscriptEntries(entries []opencodeTranscriptEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Session.StartedAt.Equal(entries[j].Session.StartedAt) {
+			return entries[i].Session.StartedAt.Before(entries[j].Session.StartedAt)
+		}
+		rankI, rankJ := transcriptPurposeRank(entries[i].Purpose), transcriptPurposeRank(entries[j].Purpose)
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return entries[i].Session.ID < entries[j].Session.ID
+	})
+}
+
 func loadOpencodeTranscript(fetch func(string, []OpencodeSession) ([]OpencodeTranscript, error), repoPath string, session OpencodeSession) string {
 	if fetch == nil {
 		return ""
@@ -1025,12 +1647,7 @@ func opencodeTranscripts(repoPath string, sessions []OpencodeSession) ([]Opencod
 		entries = append(entries, opencodeTranscriptEntry{Purpose: session.Purpose, Session: opencodeSession, Transcript: transcript})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		if entries[i].Session.StartedAt.Equal(entries[j].Session.StartedAt) {
-			return entries[i].Session.ID < entries[j].Session.ID
-		}
-		return entries[i].Session.StartedAt.Before(entries[j].Session.StartedAt)
-	})
+	sortOpencodeTranscriptEntries(entries)
 
 	transcripts := make([]OpencodeTranscript, 0, len(entries))
 	for _, entry := range entries {
@@ -1057,53 +1674,163 @@ func testingStageOutcome(results []TestCommandResult) (Stage, string) {
 }
 
 func diffStatHasChanges(diffStat string) bool {
+	return parseDiffStat(diffStat).Changed
+}
+
+// diffStatTotals summarizes a `jj diff --stat` output.
+type diffStatTotals struct {
+	// Changed reports whether the diff touches any file.
+	Changed bool
+	// Files is the number of files changed.
+	Files int
+	// Insertions and Deletions count changed lines.
+	Insertions int
+	Deletions  int
+}
+
+// ChangedLines returns the total number of inserted and deleted lines.
+func (t diffStatTotals) ChangedLines() int {
+	return t.Insertions + t.Deletions
+}
+
+// parseDiffStat reads a diff stat. Totals come from the summary line when
+// there is one; otherwise the per-file counts are summed.
+func parseDiffStat(diffStat string) diffStatTotals {
 	lines := strings.Split(diffStat, "\n")
 	seenChangeLine := false
 	seenSummary := false
-	changedSummary := false
+	var summary, fileSum diffStatTotals
 	for _, line := range lines {
 		line = internalstrings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 		if strings.HasPrefix(line, "No changes") {
-			return false
+			return diffStatTotals{}
 		}
 		if strings.Contains(line, " file changed") || strings.Contains(line, " files changed") {
-			fields := strings.Fields(line)
-			if len(fields) > 0 {
-				count, err := strconv.Atoi(fields[0])
-				if err == nil {
-					seenSummary = true
-					changedSummary = count != 0
-				}
+			if parsed, ok := parseDiffStatSummary(line); ok {
+				seenSummary = true
+				summary = parsed
 			}
 			continue
 		}
-		if strings.Contains(line, " | ") {
+		if _, stat, ok := strings.Cut(line, " | "); ok {
 			seenChangeLine = true
+			fileSum.Files++
+			fields := strings.Fields(stat)
+			if len(fields) == 0 {
+				continue
+			}
+			count, err := strconv.Atoi(fields[0])
+			if err != nil {
+				// Binary files report sizes rather than line counts.
+				continue
+			}
+			if len(fields) > 1 {
+				plus := strings.Count(fields[1], "+")
+				minus := strings.Count(fields[1], "-")
+				if plus+minus > 0 {
+					// The bar may be scaled; split the count in its proportion.
+					inserted := count * plus / (plus + minus)
+					fileSum.Insertions += inserted
+					fileSum.Deletions += count - inserted
+					continue
+				}
+			}
+			fileSum.Insertions += count
 		}
 	}
 	if seenSummary {
-		return changedSummary || seenChangeLine
+		summary.Changed = summary.Files != 0 || seenChangeLine
+		return summary
+	}
+	fileSum.Changed = seenChangeLine
+	return fileSum
+}
+
+// parseDiffStatSummary parses a line such as
+// "2 files changed, 3 insertions(+), 1 deletion(-)".
+func parseDiffStatSummary(line string) (diffStatTotals, bool) {
+	var totals diffStatTotals
+	seenFiles := false
+	for _, part := range strings.Split(line, ",") {
+		fields := strings.Fields(part)
+		if len(fields) < 2 {
+			continue
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(fields[1], "file"):
+			totals.Files = count
+			seenFiles = true
+		case strings.HasPrefix(fields[1], "insertion"):
+			totals.Insertions = count
+		case strings.HasPrefix(fields[1], "deletion"):
+			totals.Deletions = count
+		}
 	}
-	return seenChangeLine
+	return totals, seenFiles
 }
 
-func renderPromptTemplate(item todo.Todo, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, name, workspacePath string) (string, error) {
+func renderPromptTemplate(item todo.Todo, feedback, message string, commitLog []CommitLogEntry, transcripts []OpencodeTranscript, name, workspacePath string, files outputFiles) (string, error) {
 	prompt, err := LoadPrompt(workspacePath, name)
 	if err != nil {
 		return "", err
 	}
-	return RenderPrompt(workspacePath, prompt, newPromptData(item, feedback, message, commitLog, transcripts, workspacePath))
+	return RenderPrompt(workspacePath, prompt, newPromptData(item, feedback, message, commitLog, transcripts, workspacePath).withOutputFiles(files))
 }
 
 func runOpencodeWithEvents(opts RunOptions, runOpts opencodeRunOptions, purpose string) (OpencodeRunResult, error) {
+	if opts.DryRun {
+		if err := appendJobEvent(opts.EventLog, jobEventDryRun, dryRunEventData{Action: "opencode", Purpose: purpose, Prompt: runOpts.Prompt}); err != nil {
+			return OpencodeRunResult{}, err
+		}
+		return OpencodeRunResult{}, nil
+	}
 	snapshotWorkspace(opts.Snapshot, runOpts.WorkspacePath)
 	if err := appendJobEvent(opts.EventLog, jobEventOpencodeStart, opencodeStartEventData{Purpose: purpose}); err != nil {
 		return OpencodeRunResult{}, err
 	}
+	timeout := resolveStageTimeout(opts, purpose)
+	var stopTimer func() bool
+	if timeout > 0 {
+		interrupt := make(chan struct{})
+		runOpts.Interrupt = interrupt
+		stopTimer = startStageTimer(opts.Now, timeout, interrupt)
+	}
+	var capture *outputCapture
+	if opts.CaptureOutput {
+		capture = newOutputCapture(opts.EventLog, purpose, opts.CaptureOutputLimit)
+		runOpts.Stdout = capture.writer("stdout")
+		runOpts.Stderr = capture.writer("stderr")
+	}
 	result, err := opts.RunOpencode(runOpts)
+	if capture != nil {
+		if captureErr := capture.Err(); captureErr != nil {
+			return OpencodeRunResult{}, errors.Join(err, captureErr)
+		}
+	}
+	if stopTimer != nil && stopTimer() {
+		if logErr := appendJobEvent(opts.EventLog, jobEventOpencodeTimeout, opencodeTimeoutEventData{Purpose: purpose, SessionID: result.SessionID, Timeout: timeout.String()}); logErr != nil {
+			return OpencodeRunResult{}, errors.Join(err, logErr)
+		}
+		if err != nil {
+			return OpencodeRunResult{}, fmt.Errorf("opencode %s timed out after %s: %w", purpose, timeout, err)
+		}
+		// A timed-out run is reported as a failed exit so callers restore and
+		// retry as they would for any other opencode failure.
+		if result.ExitCode == 0 {
+			result.ExitCode = -1
+		}
+		if err := recordOpencodeUsage(opts.EventLog, purpose, result); err != nil {
+			return OpencodeRunResult{}, err
+		}
+		return result, nil
+	}
 	if err != nil {
 		logErr := appendJobEvent(opts.EventLog, jobEventOpencodeError, opencodeErrorEventData{Purpose: purpose, Error: err.Error()})
 		if logErr != nil {
@@ -1114,10 +1841,60 @@ func runOpencodeWithEvents(opts RunOptions, runOpts opencodeRunOptions, purpose
 	if err := appendJobEvent(opts.EventLog, jobEventOpencodeEnd, opencodeEndEventData{Purpose: purpose, SessionID: result.SessionID, ExitCode: result.ExitCode}); err != nil {
 		return OpencodeRunResult{}, err
 	}
+	if err := recordOpencodeUsage(opts.EventLog, purpose, result); err != nil {
+		return OpencodeRunResult{}, err
+	}
 	return result, nil
 }
 
-func buildOpencodeFailureMessage(purpose, promptName string, result OpencodeRunResult, runOpts opencodeRunOptions, beforeCommitID, afterCommitID string, afterCommitErr error, restored bool, restoreErr error, retryCount int) string {
+// runConfiguredTests runs the configured test commands with runTests, or with
+// the built-in runner (honoring test-parallelism) when runTests is nil.
+func runConfiguredTests(runTests func(string, []string) ([]TestCommandResult, error), workspacePath string, cfg *config.Config) ([]TestCommandResult, error) {
+	if runTests != nil {
+		return runTests(workspacePath, cfg.Job.TestCommands)
+	}
+	return RunTestCommandsParallel(workspacePath, cfg.Job.TestCommands, cfg.Job.TestParallelism)
+}
+
+func resolveStageTimeout(opts RunOptions, purpose string) time.Duration {
+	if timeout, ok := opts.StageTimeouts[purpose]; ok {
+		return timeout
+	}
+	return opts.StageTimeout
+}
+
+// startStageTimer closes interrupt once now() passes timeout from the start.
+// The returned stop function reports whether the timer fired.
+func startStageTimer(now func() time.Time, timeout time.Duration, interrupt chan<- struct{}) func() bool {
+	deadline := now().Add(timeout)
+	done := make(chan struct{})
+	fired := make(chan bool, 1)
+	go func() {
+		ticker := time.NewTicker(stageTimeoutPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				fired <- false
+				return
+			case <-ticker.C:
+				if now().Before(deadline) {
+					continue
+				}
+				close(interrupt)
+				<-done
+				fired <- true
+				return
+			}
+		}
+	}()
+	return func() bool {
+		close(done)
+		return <-fired
+	}
+}
+
+func buildOpencodeFailureMessage(purpose, promptName string, result OpencodeRunResult, runOpts opencodeRunOptions, beforeCommitID, afterCommitID, restorePoint string, afterCommitErr error, restored bool, restoreErr error, retryCount int) string {
 	parts := []string{}
 	if !internalstrings.IsBlank(result.SessionID) {
 		parts = append(parts, fmt.Sprintf("session %s", result.SessionID))
@@ -1150,7 +1927,7 @@ func buildOpencodeFailureMessage(purpose, promptName string, result OpencodeRunR
 		parts = append(parts, fmt.Sprintf("after_commit_error %v", afterCommitErr))
 	}
 	if restored {
-		parts = append(parts, fmt.Sprintf("restored %s", beforeCommitID))
+		parts = append(parts, fmt.Sprintf("restored %s", restorePoint))
 	}
 	if restoreErr != nil {
 		parts = append(parts, fmt.Sprintf("restore_error %v", restoreErr))
@@ -1217,14 +1994,14 @@ func readCommitMessage(path string) (string, error) {
 	return message, nil
 }
 
-func resolveReviewCommitMessage(commitMessage, workspacePath string, requireMessage bool) (string, error) {
+func resolveReviewCommitMessage(commitMessage, workspacePath, commitMessageFile string, requireMessage bool) (string, error) {
 	if !internalstrings.IsBlank(commitMessage) {
 		return commitMessage, nil
 	}
 	if internalstrings.IsBlank(workspacePath) {
 		return "", nil
 	}
-	messagePath := filepath.Join(workspacePath, commitMessageFilename)
+	messagePath := filepath.Join(workspacePath, commitMessageFile)
 	message, err := readCommitMessage(messagePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -1266,25 +2043,148 @@ func snapshotWorkspace(snapshot func(string) error, workspacePath string) {
 	_ = snapshot(workspacePath)
 }
 
-func applyOpencodeConfigEnv(env []string) []string {
+// resolveImplementRetries returns how many times a crashed implementation
+// run is restored and retried.
+func resolveImplementRetries(cfg *config.Config) int {
+	if cfg == nil {
+		return config.Job{}.ImplementRetryLimit()
+	}
+	return cfg.Job.ImplementRetryLimit()
+}
+
+func resolveSnapshotInterval(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	return time.Duration(cfg.Job.SnapshotInterval)
+}
+
+// startPeriodicSnapshots snapshots the workspace every interval until the
+// returned stop function is called. Stop returns the commit ID recorded after
+// the most recent successful snapshot, or "" if none completed. onSnapshot,
+// when set, also receives each recorded commit ID as it is taken.
+func startPeriodicSnapshots(snapshot func(string) error, currentCommitID func(string) (string, error), workspacePath string, interval time.Duration, onSnapshot func(string)) func() string {
+	if interval <= 0 || snapshot == nil || currentCommitID == nil {
+		return func() string { return "" }
+	}
+
+	var (
+		mu     sync.Mutex
+		latest string
+	)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := snapshot(workspacePath); err != nil {
+					continue
+				}
+				commitID, err := currentCommitID(workspacePath)
+				if err != nil || internalstrings.IsBlank(commitID) {
+					continue
+				}
+				mu.Lock()
+				latest = commitID
+				mu.Unlock()
+				if onSnapshot != nil {
+					onSnapshot(commitID)
+				}
+			}
+		}
+	}()
+
+	return func() string {
+		close(done)
+		<-finished
+		mu.Lock()
+		defer mu.Unlock()
+		return latest
+	}
+}
+
+// applyOpencodeConfigEnv sets OPENCODE_CONFIG_CONTENT in env to the opencode
+// config for cfg. A nil env starts from the process environment.
+func applyOpencodeConfigEnv(env []string, cfg *config.Config) []string {
 	if env == nil {
 		env = os.Environ()
 	}
-	return replaceEnvVar(env, opencodeConfigEnvVar, opencodeConfigJSON())
+	return replaceEnvVar(env, opencodeConfigEnvVar, opencodeConfigJSON(cfg))
+}
+
+// ensureOpencodeConfigEnv sets the default opencode config in env unless a
+// stage already chose one.
+func ensureOpencodeConfigEnv(env []string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+	if _, ok := lookupEnvVar(env, opencodeConfigEnvVar); ok {
+		return env
+	}
+	return applyOpencodeConfigEnv(env, nil)
 }
 
-// opencodeConfigJSON returns the JSON encoding of the opencode configuration.
-// This is used internally and exported for test assertions.
-func opencodeConfigJSON() string {
-	configJSON, err := json.Marshal(opencodeConfig)
+// opencodeConfigJSON returns the JSON encoding of the opencode configuration:
+// job.opencode.config merged over opencodeConfig. Objects merge key by key;
+// any other configured value replaces the default.
+func opencodeConfigJSON(cfg *config.Config) string {
+	merged := any(opencodeConfig)
+	if cfg != nil {
+		// Load has already validated the config, so a decode error only
+		// comes from a hand-built Config; fall back to the defaults.
+		if overrides, err := cfg.Job.Opencode.Config.Object(); err == nil && overrides != nil {
+			merged = mergeOpencodeConfigValue(opencodeConfig, overrides)
+		}
+	}
+	configJSON, err := json.Marshal(merged)
 	if err != nil {
-		// This should never happen since opencodeConfig is a static map.
-		// Fall back to minimal config if marshaling fails.
+		// This should never happen since the config decoded from JSON or is
+		// a static map. Fall back to minimal config if marshaling fails.
 		return `{"permission":{"question":"deny"}}`
 	}
 	return string(configJSON)
 }
 
+func mergeOpencodeConfigValue(base, override any) any {
+	overrideMap, ok := override.(map[string]any)
+	if !ok {
+		return override
+	}
+	baseMap := opencodeConfigMap(base)
+	if baseMap == nil {
+		return overrideMap
+	}
+	merged := make(map[string]any, len(baseMap)+len(overrideMap))
+	for key, value := range baseMap {
+		merged[key] = value
+	}
+	for key, value := range overrideMap {
+		merged[key] = mergeOpencodeConfigValue(merged[key], value)
+	}
+	return merged
+}
+
+func opencodeConfigMap(value any) map[string]any {
+	switch typed := value.(type) {
+	case map[string]any:
+		return typed
+	case map[string]string:
+		converted := make(map[string]any, len(typed))
+		for key, entry := range typed {
+			converted[key] = entry
+		}
+		return converted
+	default:
+		return nil
+	}
+}
+
 func replaceEnvVar(env []string, key, value string) []string {
 	prefix := key + "="
 	updated := make([]string, 0, len(env)+1)
@@ -1298,24 +2198,54 @@ func replaceEnvVar(env []string, key, value string) []string {
 	return updated
 }
 
+func lookupEnvVar(env []string, key string) (string, bool) {
+	prefix := key + "="
+	for _, entry := range env {
+		if value, ok := strings.CutPrefix(entry, prefix); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func runOpencodeSession(store *opencode.Store, opts opencodeRunOptions) (OpencodeRunResult, error) {
 	var stderrBuf strings.Builder
+	var stdout io.Writer = io.Discard
+	if opts.Stdout != nil {
+		stdout = opts.Stdout
+	}
+	var stderr io.Writer = &stderrBuf
+	if opts.Stderr != nil {
+		stderr = io.MultiWriter(&stderrBuf, opts.Stderr)
+	}
 	handle, err := store.Run(opencode.RunOptions{
 		RepoPath:  opts.RepoPath,
 		WorkDir:   opts.WorkspacePath,
 		Prompt:    opts.Prompt,
 		Agent:     opts.Agent,
 		StartedAt: opts.StartedAt,
-		Stdout:    io.Discard,
-		Stderr:    &stderrBuf,
-		Env:       applyOpencodeConfigEnv(opts.Env),
+		Stdout:    stdout,
+		Stderr:    stderr,
+		Env:       ensureOpencodeConfigEnv(opts.Env),
 	})
 	if err != nil {
 		return OpencodeRunResult{}, err
 	}
 
-	eventErrCh := recordOpencodeEvents(opts.EventLog, handle.Events)
+	usage := newUsageTracker()
+	eventErrCh := recordOpencodeEvents(opts.EventLog, handle.Events, usage)
+	waitDone := make(chan struct{})
+	if opts.Interrupt != nil {
+		go func() {
+			select {
+			case <-opts.Interrupt:
+				_ = handle.Interrupt()
+			case <-waitDone:
+			}
+		}()
+	}
 	result, err := handle.Wait()
+	close(waitDone)
 	eventErr := <-eventErrCh
 	if err != nil {
 		return OpencodeRunResult{}, errors.Join(err, eventErr)
@@ -1329,10 +2259,11 @@ func runOpencodeSession(store *opencode.Store, opts opencodeRunOptions) (Opencod
 		ServeCommand: result.ServeCommand,
 		RunCommand:   result.RunCommand,
 		Stderr:       stderrBuf.String(),
+		Usage:        usage.total(),
 	}, nil
 }
 
-func recordOpencodeEvents(log *EventLog, events <-chan opencode.Event) <-chan error {
+func recordOpencodeEvents(log *EventLog, events <-chan opencode.Event, usage *usageTracker) <-chan error {
 	done := make(chan error, 1)
 	if events == nil {
 		done <- nil
@@ -1341,6 +2272,7 @@ func recordOpencodeEvents(log *EventLog, events <-chan opencode.Event) <-chan er
 	go func() {
 		var recordErr error
 		for event := range events {
+			usage.observe(event)
 			if log == nil || recordErr != nil {
 				continue
 			}
@@ -1362,6 +2294,54 @@ func finalizeTodo(repoPath, todoID string, status Status) error {
 	}
 }
 
+// dryRunCommitMessage is the draft message for the change a dry run
+// simulates, since opencode never wrote one.
+func dryRunCommitMessage(item todo.Todo) string {
+	return item.Title
+}
+
+// finalizeRunTodo is finalizeTodo, except that dry runs leave the todo alone.
+func finalizeRunTodo(opts RunOptions, repoPath, todoID string, status Status) error {
+	if opts.DryRun {
+		return nil
+	}
+	return finalizeTodo(repoPath, todoID, status)
+}
+
+// noteCompletion appends a note summarizing the commit log to the todo when
+// job.completion-note is set and the job completed. Dry runs never note.
+func noteCompletion(opts RunOptions, repoPath, todoID string, finished Job, commitLog []CommitLogEntry) error {
+	if opts.DryRun || finished.Status != StatusCompleted || opts.Config == nil || !opts.Config.Job.CompletionNote {
+		return nil
+	}
+	return updateTodoStatus(repoPath, todoID, func(store *todo.Store, id string) ([]todo.Todo, error) {
+		_, err := store.AddNote(id, "job "+finished.ID, completionNoteText(commitLog))
+		return nil, err
+	})
+}
+
+// completionNoteText lists each commit's ID and subject line.
+func completionNoteText(commitLog []CommitLogEntry) string {
+	if len(commitLog) == 0 {
+		return "Completed without changes."
+	}
+	var b strings.Builder
+	if len(commitLog) == 1 {
+		b.WriteString("Completed with 1 commit:")
+	} else {
+		fmt.Fprintf(&b, "Completed with %d commits:", len(commitLog))
+	}
+	for _, entry := range commitLog {
+		subject, _, _ := strings.Cut(internalstrings.TrimSpace(entry.Message), "\n")
+		b.WriteString("\n- ")
+		if entry.ID != "" {
+			b.WriteString(entry.ID + " ")
+		}
+		b.WriteString(internalstrings.TrimSpace(subject))
+	}
+	return b.String()
+}
+
 func updateTodoStatus(repoPath, todoID string, update func(*todo.Store, string) ([]todo.Todo, error)) error {
 	store, err := todo.Open(repoPath, todo.OpenOptions{CreateIfMissing: false, PromptToCreate: false})
 	if err != nil {
@@ -1,6 +1,7 @@
 package job
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/amonks/incrementum/internal/config"
@@ -11,7 +12,7 @@ func TestResolveOpencodeAgentForPurposePrefersOverride(t *testing.T) {
 	cfg := &config.Config{Job: config.Job{Agent: "default", ImplementationModel: "impl"}}
 	item := todo.Todo{ImplementationModel: "todo-impl"}
 
-	got := resolveOpencodeAgentForPurpose(cfg, "override", "implement", item)
+	got := resolveOpencodeAgentForPurpose(cfg, "override", "", "implement", item)
 
 	if got != "override" {
 		t.Fatalf("expected override, got %q", got)
@@ -42,7 +43,7 @@ func TestResolveOpencodeAgentForPurposeUsesTodoModels(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		if got := resolveOpencodeAgentForPurpose(cfg, "", tc.purpose, item); got != tc.want {
+		if got := resolveOpencodeAgentForPurpose(cfg, "", "", tc.purpose, item); got != tc.want {
 			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
 		}
 	}
@@ -52,9 +53,108 @@ func TestResolveOpencodeAgentForPurposeFallsBackToAgent(t *testing.T) {
 	cfg := &config.Config{Job: config.Job{Agent: "default"}}
 	item := todo.Todo{}
 
-	got := resolveOpencodeAgentForPurpose(cfg, "", "review", item)
+	got := resolveOpencodeAgentForPurpose(cfg, "", "", "review", item)
 
 	if got != "default" {
 		t.Fatalf("expected default, got %q", got)
 	}
 }
+
+func TestResolveOpencodeAgentForPurposeUsesTypeModels(t *testing.T) {
+	cfg := &config.Config{Job: config.Job{
+		Agent:                     "default",
+		ImplementationModel:       "impl",
+		CodeReviewModel:           "review",
+		AgentByType:               map[string]string{"bug": "bug-agent"},
+		ImplementationModelByType: map[string]string{"bug": "bug-impl"},
+	}}
+	bug := todo.Todo{Type: todo.TypeBug}
+
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "implement", bug); got != "bug-impl" {
+		t.Fatalf("expected bug-impl, got %q", got)
+	}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "review", bug); got != "bug-agent" {
+		t.Fatalf("expected bug-agent, got %q", got)
+	}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "project-review", bug); got != "bug-agent" {
+		t.Fatalf("expected bug-agent, got %q", got)
+	}
+
+	bug.CodeReviewModel = "todo-review"
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "review", bug); got != "todo-review" {
+		t.Fatalf("expected todo-review, got %q", got)
+	}
+
+	task := todo.Todo{Type: todo.TypeTask}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "implement", task); got != "impl" {
+		t.Fatalf("expected impl, got %q", got)
+	}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "", "project-review", task); got != "default" {
+		t.Fatalf("expected default, got %q", got)
+	}
+}
+
+func TestResolveOpencodeAgentForPurposeUsesProfiles(t *testing.T) {
+	cfg := &config.Config{
+		Job: config.Job{
+			Agent:                 "default",
+			ImplementationModel:   "impl",
+			CodeReviewModel:       "review",
+			CodeReviewModelByType: map[string]string{"bug": "bug-review"},
+		},
+		Profiles: map[string]config.Profile{
+			"thorough": {
+				ImplementationModel: "thorough-impl",
+				CodeReviewModel:     "thorough-review",
+				ProjectReviewModel:  "thorough-project",
+			},
+			"review-only": {CodeReviewModel: "review-only-review"},
+		},
+	}
+	item := todo.Todo{Type: todo.TypeBug, Profile: "thorough", ImplementationModel: "todo-impl"}
+
+	cases := []struct {
+		name     string
+		override string
+		profile  string
+		purpose  string
+		want     string
+	}{
+		{name: "override beats everything", override: "override", profile: "review-only", purpose: "implement", want: "override"},
+		{name: "todo model beats profile", purpose: "implement", want: "todo-impl"},
+		{name: "todo profile beats type model", purpose: "review", want: "thorough-review"},
+		{name: "run profile beats todo profile", profile: "review-only", purpose: "review", want: "review-only-review"},
+		{name: "unset profile model falls through", profile: "review-only", purpose: "project-review", want: "default"},
+		{name: "todo model still wins under a run profile", profile: "review-only", purpose: "implement", want: "todo-impl"},
+	}
+	for _, tc := range cases {
+		if got := resolveOpencodeAgentForPurpose(cfg, tc.override, tc.profile, tc.purpose, item); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	bug := todo.Todo{Type: todo.TypeBug}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "review-only", "implement", bug); got != "impl" {
+		t.Errorf("expected implementation to fall through to impl, got %q", got)
+	}
+	if got := resolveOpencodeAgentForPurpose(cfg, "", "review-only", "review", bug); got != "review-only-review" {
+		t.Errorf("expected profile review model over type model, got %q", got)
+	}
+}
+
+func TestCheckProfileRejectsUnknownProfiles(t *testing.T) {
+	cfg := &config.Config{Profiles: map[string]config.Profile{"cheap": {}}}
+
+	if err := checkProfile(cfg, "", todo.Todo{}); err != nil {
+		t.Fatalf("expected no profile to be accepted, got %v", err)
+	}
+	if err := checkProfile(cfg, "cheap", todo.Todo{Profile: "missing"}); err != nil {
+		t.Fatalf("expected the run profile to take precedence, got %v", err)
+	}
+	if err := checkProfile(cfg, "", todo.Todo{Profile: "missing"}); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile, got %v", err)
+	}
+	if err := checkProfile(nil, "cheap", todo.Todo{}); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile without config, got %v", err)
+	}
+}
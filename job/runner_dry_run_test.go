@@ -0,0 +1,167 @@
+package job
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/todo"
+)
+
+func TestRunJobStagesDryRunSkipsOpencode(t *testing.T) {
+	stateDir := t.TempDir()
+	eventsDir := t.TempDir()
+	workspacePath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/dry-run-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	eventLogOpts := EventLogOptions{EventsDir: eventsDir}
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	var stages []Stage
+	var testRuns int
+	ctx := runContext{
+		workspacePath: workspacePath,
+		item:          todo.Todo{ID: "todo-123", Title: "Preview the prompts"},
+		opts: RunOptions{
+			DryRun:          true,
+			Config:          &config.Config{Job: config.Job{TestCommands: []string{"go test ./..."}}},
+			Now:             func() time.Time { return now },
+			EventLog:        eventLog,
+			CurrentCommitID: func(string) (string, error) { return "commit-1", nil },
+			CurrentChangeID: func(string) (string, error) { return "change-1", nil },
+			OnStageChange:   func(change StageChange) { stages = append(stages, change.Stage) },
+			RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+				t.Fatal("expected no opencode run during a dry run")
+				return OpencodeRunResult{}, nil
+			},
+			RunTests: func(_ string, commands []string) ([]TestCommandResult, error) {
+				testRuns++
+				return []TestCommandResult{{Command: commands[0]}}, nil
+			},
+			DiffStat: func(string, string, string) (string, error) {
+				return "", nil
+			},
+			Commit: func(string, string) error {
+				t.Fatal("expected no commit during a dry run")
+				return nil
+			},
+		},
+		manager: manager,
+		result:  &RunResult{},
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if err != nil {
+		t.Fatalf("run stages: %v", err)
+	}
+	if finalJob.Status != StatusCompleted {
+		t.Fatalf("expected completed job, got %q", finalJob.Status)
+	}
+	want := []Stage{StageImplementing, StageTesting, StageReviewing, StageCommitting, StageImplementing, StageReviewing}
+	if !slices.Equal(stages, want) {
+		t.Fatalf("expected stages %v, got %v", want, stages)
+	}
+	if testRuns != 1 {
+		t.Fatalf("expected tests to run once, got %d", testRuns)
+	}
+	if len(ctx.result.CommitLog) != 0 {
+		t.Fatalf("expected no commit log entries, got %+v", ctx.result.CommitLog)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	var purposes []string
+	var actions []string
+	for _, event := range events {
+		if event.Name == jobEventOpencodeStart {
+			t.Fatalf("expected no opencode start events, got %+v", event)
+		}
+		if event.Name != jobEventDryRun {
+			continue
+		}
+		data, err := decodeEventData[dryRunEventData](event.Data)
+		if err != nil {
+			t.Fatalf("decode dry run event: %v", err)
+		}
+		actions = append(actions, data.Action)
+		if data.Action != "opencode" {
+			continue
+		}
+		if data.Prompt == "" {
+			t.Fatalf("expected opencode dry run event with prompt, got %+v", data)
+		}
+		purposes = append(purposes, data.Purpose)
+	}
+	wantPurposes := []string{"implement", "review", "implement", "project-review"}
+	if !slices.Equal(purposes, wantPurposes) {
+		t.Fatalf("expected dry run purposes %v, got %v", wantPurposes, purposes)
+	}
+	if !slices.Contains(actions, "commit") {
+		t.Fatalf("expected a commit dry run event, got %v", actions)
+	}
+}
+
+func TestRunCommittingStageDryRunSkipsCommit(t *testing.T) {
+	stateDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/dry-run-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	result := &RunResult{}
+	stageResult, err := runCommittingStage(CommittingStageOptions{
+		Manager:       manager,
+		Current:       created,
+		WorkspacePath: t.TempDir(),
+		RunOptions: RunOptions{
+			DryRun: true,
+			Now:    func() time.Time { return now },
+			DiffStat: func(string, string, string) (string, error) {
+				return "main.go | 1 +\n1 file changed, 1 insertion(+)", nil
+			},
+			Commit: func(string, string) error {
+				t.Fatal("expected no commit during a dry run")
+				return nil
+			},
+			CommitIDAt: func(string, string) (string, error) {
+				t.Fatal("expected no commit lookup during a dry run")
+				return "", nil
+			},
+		},
+		Result:        result,
+		CommitMessage: "feat: dry run",
+	})
+	if err != nil {
+		t.Fatalf("run committing stage: %v", err)
+	}
+	if stageResult.Job.Stage != StageImplementing {
+		t.Fatalf("expected implementing stage after commit, got %q", stageResult.Job.Stage)
+	}
+	if len(result.CommitLog) != 0 {
+		t.Fatalf("expected no commit log entries, got %+v", result.CommitLog)
+	}
+}
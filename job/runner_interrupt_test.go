@@ -4,9 +4,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/amonks/incrementum/internal/config"
 	"github.com/amonks/incrementum/internal/jj"
 	"github.com/amonks/incrementum/internal/testsupport"
 	"github.com/amonks/incrementum/todo"
@@ -109,3 +111,157 @@ func setupJobRepo(t *testing.T) string {
 
 	return tmpDir
 }
+
+func TestRunJobStagesRestoresWorkspaceOnInterrupt(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		restore     *bool
+		wantRestore bool
+	}{
+		{name: "default", wantRestore: true},
+		{name: "opted out", restore: new(bool), wantRestore: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			workspacePath := t.TempDir()
+			now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			manager, err := Open(workspacePath, OpenOptions{StateDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("open manager: %v", err)
+			}
+			created, err := manager.Create("todo-interrupt", now, CreateOptions{})
+			if err != nil {
+				t.Fatalf("create job: %v", err)
+			}
+
+			interrupts := make(chan os.Signal, 1)
+			var mu sync.Mutex
+			opencodeStopped := false
+			var restoredPath, restoredCommit string
+			restoreCalls := 0
+			ctx := runContext{
+				repoPath:      workspacePath,
+				workspacePath: workspacePath,
+				item:          todo.Todo{ID: "todo-interrupt", Title: "Interrupt job", Type: todo.TypeTask},
+				opts: RunOptions{
+					Now:                func() time.Time { return now },
+					RestoreOnInterrupt: tc.restore,
+					CurrentCommitID: func(string) (string, error) {
+						return "before-commit", nil
+					},
+					CurrentChangeID: func(string) (string, error) {
+						return "change-1", nil
+					},
+					RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+						interrupts <- os.Interrupt
+						select {
+						case <-runOpts.Interrupt:
+						case <-time.After(5 * time.Second):
+							return OpencodeRunResult{}, errors.New("opencode was not interrupted")
+						}
+						mu.Lock()
+						opencodeStopped = true
+						mu.Unlock()
+						return OpencodeRunResult{}, errors.New("opencode interrupted")
+					},
+					RestoreWorkspace: func(path, commitID string) error {
+						mu.Lock()
+						defer mu.Unlock()
+						if !opencodeStopped {
+							t.Error("expected opencode to stop before the restore")
+						}
+						restoreCalls++
+						restoredPath, restoredCommit = path, commitID
+						return nil
+					},
+				},
+				manager: manager,
+				result:  &RunResult{},
+			}
+
+			interrupted, err := runJobStages(&ctx, created, interrupts)
+			if !errors.Is(err, ErrJobInterrupted) {
+				t.Fatalf("expected ErrJobInterrupted, got %v", err)
+			}
+			if interrupted.Status != StatusFailed {
+				t.Fatalf("expected failed status, got %q", interrupted.Status)
+			}
+			if !tc.wantRestore {
+				if restoreCalls != 0 {
+					t.Fatalf("expected no restore, got %d", restoreCalls)
+				}
+				return
+			}
+			if restoreCalls != 1 || restoredPath != workspacePath || restoredCommit != "before-commit" {
+				t.Fatalf("expected one restore of %q to %q, got %d to %q at %q", workspacePath, "before-commit", restoreCalls, restoredCommit, restoredPath)
+			}
+		})
+	}
+}
+
+func TestRunJobStagesRestoresLatestSnapshotOnInterrupt(t *testing.T) {
+	workspacePath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	manager, err := Open(workspacePath, OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-interrupt", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	var mu sync.Mutex
+	running := false
+	var restoredCommit string
+	var ctx runContext
+	ctx = runContext{
+		repoPath:      workspacePath,
+		workspacePath: workspacePath,
+		item:          todo.Todo{ID: "todo-interrupt", Title: "Interrupt job", Type: todo.TypeTask},
+		opts: RunOptions{
+			Now:    func() time.Time { return now },
+			Config: &config.Config{Job: config.Job{SnapshotInterval: config.Duration(time.Millisecond)}},
+			CurrentCommitID: func(string) (string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if running {
+					return "snapshot-commit", nil
+				}
+				return "before-commit", nil
+			},
+			CurrentChangeID: func(string) (string, error) {
+				return "change-1", nil
+			},
+			Snapshot: func(string) error { return nil },
+			RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+				mu.Lock()
+				running = true
+				mu.Unlock()
+				deadline := time.Now().Add(5 * time.Second)
+				for ctx.baseline.get() != "snapshot-commit" {
+					if time.Now().After(deadline) {
+						return OpencodeRunResult{}, errors.New("timed out waiting for periodic snapshot")
+					}
+					time.Sleep(time.Millisecond)
+				}
+				interrupts <- os.Interrupt
+				<-runOpts.Interrupt
+				return OpencodeRunResult{}, errors.New("opencode interrupted")
+			},
+			RestoreWorkspace: func(_ string, commitID string) error {
+				restoredCommit = commitID
+				return nil
+			},
+		},
+		manager: manager,
+		result:  &RunResult{},
+	}
+
+	if _, err := runJobStages(&ctx, created, interrupts); !errors.Is(err, ErrJobInterrupted) {
+		t.Fatalf("expected ErrJobInterrupted, got %v", err)
+	}
+	if restoredCommit != "snapshot-commit" {
+		t.Fatalf("expected restore to the latest snapshot, got %q", restoredCommit)
+	}
+}
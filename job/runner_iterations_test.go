@@ -0,0 +1,130 @@
+package job
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRunJobStagesStopsAtMaxIterations(t *testing.T) {
+	stateDir := t.TempDir()
+	eventsDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/iterations-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	eventLogOpts := EventLogOptions{EventsDir: eventsDir}
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	// Two implement/review passes have already run; the third implementing
+	// entry must be refused without calling opencode.
+	ctx := runContext{
+		opts: RunOptions{
+			MaxIterations: 2,
+			Now:           func() time.Time { return now },
+			EventLog:      eventLog,
+			RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+				t.Fatal("expected no opencode run after the iteration cap")
+				return OpencodeRunResult{}, nil
+			},
+		},
+		manager:   manager,
+		result:    &RunResult{},
+		iteration: 2,
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if !errors.Is(err, ErrMaxIterationsExceeded) {
+		t.Fatalf("expected ErrMaxIterationsExceeded, got %v", err)
+	}
+	if finalJob.Status != StatusFailed {
+		t.Fatalf("expected failed job, got %q", finalJob.Status)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	path, err := EventLogPath(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("event log path: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open event log file: %v", err)
+	}
+	defer file.Close()
+	events, err := ReadEvents(file)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventMaxIterations {
+		t.Fatalf("expected a single max iterations event, got %+v", events)
+	}
+	if events[0].Data != `{"max_iterations":2}` {
+		t.Fatalf("unexpected event data: %s", events[0].Data)
+	}
+}
+
+func TestHandleStageOutcomeLeavesImplementingAnnouncementToCapCheck(t *testing.T) {
+	stateDir := t.TempDir()
+	eventsDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/iterations-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	reviewing := created
+	reviewing.Stage = StageReviewing
+
+	eventLogOpts := EventLogOptions{EventsDir: eventsDir}
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	var changes []StageChange
+	ctx := runContext{
+		opts: RunOptions{
+			MaxIterations: 1,
+			Now:           func() time.Time { return now },
+			EventLog:      eventLog,
+			OnStageChange: func(change StageChange) { changes = append(changes, change) },
+		},
+		manager:   manager,
+		result:    &RunResult{},
+		iteration: 1,
+	}
+
+	if _, err := ctx.handleStageOutcome(reviewing, created, nil); err != nil {
+		t.Fatalf("handle stage outcome: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no stage change before the cap check, got %+v", changes)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+	events, err := EventSnapshot(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no stage event before the cap check, got %+v", events)
+	}
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+func TestRunJobStagesPausesAtStageBoundary(t *testing.T) {
+	stateDir := t.TempDir()
+	eventLogOpts := EventLogOptions{EventsDir: t.TempDir()}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/pause-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	stage := StageTesting
+	created, err = manager.Update(created.ID, UpdateOptions{Stage: &stage}, now)
+	if err != nil {
+		t.Fatalf("set stage: %v", err)
+	}
+
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	// The pause arrives while tests run; the testing stage finishes and the
+	// job stops before review starts.
+	pause := make(chan struct{})
+	ctx := runContext{
+		opts: RunOptions{
+			Now:      func() time.Time { return now },
+			EventLog: eventLog,
+			Pause:    pause,
+			Config:   &config.Config{Job: config.Job{TestCommands: []string{"go test ./..."}}},
+			RunTests: func(string, []string) ([]TestCommandResult, error) {
+				close(pause)
+				return []TestCommandResult{{Command: "go test ./...", ExitCode: 0}}, nil
+			},
+			RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+				t.Error("expected no opencode run after pausing")
+				return OpencodeRunResult{}, nil
+			},
+		},
+		manager: manager,
+		result:  &RunResult{},
+	}
+
+	paused, err := runJobStages(&ctx, created, nil)
+	if !errors.Is(err, ErrJobPaused) {
+		t.Fatalf("expected ErrJobPaused, got %v", err)
+	}
+	if paused.Status != StatusPaused || paused.Stage != StageReviewing {
+		t.Fatalf("expected job paused before reviewing, got %q in %q", paused.Status, paused.Stage)
+	}
+	if !paused.CompletedAt.IsZero() {
+		t.Fatalf("expected paused job to have no completion time, got %v", paused.CompletedAt)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Name != jobEventPaused {
+		t.Fatalf("expected a final paused event, got %+v", events)
+	}
+	var data pausedEventData
+	if err := json.Unmarshal([]byte(last.Data), &data); err != nil {
+		t.Fatalf("decode paused event: %v", err)
+	}
+	if data.Stage != StageReviewing {
+		t.Fatalf("expected paused event to name the next stage, got %q", data.Stage)
+	}
+
+	listed, err := manager.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Status != StatusPaused {
+		t.Fatalf("expected paused job in the default listing, got %+v", listed)
+	}
+}
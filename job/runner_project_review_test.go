@@ -0,0 +1,116 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+func TestRunJobStagesEscalatesToProjectReviewAfterCommits(t *testing.T) {
+	stateDir := t.TempDir()
+	eventLogOpts := EventLogOptions{EventsDir: t.TempDir()}
+	workspacePath := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/project-review-every-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	eventLog, err := OpenEventLog(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	requireTests := false
+	cfg := &config.Config{Job: config.Job{
+		RequireTests:        &requireTests,
+		ImplementationModel: "implement",
+		CodeReviewModel:     "review",
+		ProjectReviewModel:  "project-review",
+		ProjectReviewEvery:  2,
+	}}
+	files := resolveOutputFiles(cfg)
+	writeWorkspaceFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(workspacePath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	// The agent would keep making changes forever; only the project review
+	// can end the job.
+	commit := 0
+	var runs []string
+	ctx := runContext{
+		workspacePath: workspacePath,
+		opts: RunOptions{
+			Config:             cfg,
+			Now:                func() time.Time { return now },
+			EventLog:           eventLog,
+			CurrentCommitID:    func(string) (string, error) { return fmt.Sprintf("commit-%d", commit), nil },
+			CurrentChangeID:    func(string) (string, error) { return fmt.Sprintf("change-%d", commit), nil },
+			CurrentChangeEmpty: func(string) (bool, error) { return false, nil },
+			DiffStat: func(string, string, string) (string, error) {
+				return "main.go | 1 +\n1 file changed, 1 insertion(+)\n", nil
+			},
+			Commit:     func(string, string) error { return nil },
+			CommitIDAt: func(string, string) (string, error) { return fmt.Sprintf("commit-%d", commit), nil },
+			RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+				runs = append(runs, runOpts.Agent)
+				switch runOpts.Agent {
+				case "implement":
+					commit++
+					writeWorkspaceFile(files.CommitMessage, fmt.Sprintf("feat: step %d", commit))
+				default:
+					writeWorkspaceFile(files.Feedback, "ACCEPT\n")
+				}
+				return OpencodeRunResult{SessionID: fmt.Sprintf("session-%d", len(runs))}, nil
+			},
+		},
+		manager: manager,
+		result:  &RunResult{},
+	}
+
+	finalJob, err := runJobStages(&ctx, created, nil)
+	if err != nil {
+		t.Fatalf("run stages: %v", err)
+	}
+	if finalJob.Status != StatusCompleted {
+		t.Fatalf("expected completed job, got %q", finalJob.Status)
+	}
+	want := []string{"implement", "review", "implement", "review", "project-review"}
+	if fmt.Sprint(runs) != fmt.Sprint(want) {
+		t.Fatalf("expected runs %v, got %v", want, runs)
+	}
+	if len(ctx.result.CommitLog) != 2 {
+		t.Fatalf("expected 2 commits before the project review, got %+v", ctx.result.CommitLog)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot(created.ID, eventLogOpts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	var last promptEventData
+	for _, event := range events {
+		if event.Name != jobEventPrompt {
+			continue
+		}
+		last, err = decodeEventData[promptEventData](event.Data)
+		if err != nil {
+			t.Fatalf("decode prompt event: %v", err)
+		}
+	}
+	if last.Purpose != "project-review" || last.Template != "prompt-project-review.tmpl" {
+		t.Fatalf("expected the last review to use the project-review prompt, got %s (%s)", last.Purpose, last.Template)
+	}
+}
@@ -76,7 +76,7 @@ func TestRunImplementingStageUpdatesStaleWorkspace(t *testing.T) {
 		Priority:    todo.PriorityMedium,
 	}
 
-	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -153,7 +153,7 @@ func TestRunImplementingStageSnapshotsWorkspaceBeforeOpencode(t *testing.T) {
 		Priority:    todo.PriorityMedium,
 	}
 
-	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
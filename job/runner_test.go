@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/opencode"
 	"github.com/amonks/incrementum/todo"
 )
 
@@ -144,7 +145,7 @@ func TestRunImplementingStageReadsCommitMessage(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -217,7 +218,7 @@ func TestRunImplementingStageNoChangesSkipsTesting(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -290,7 +291,7 @@ func TestRunImplementingStageIncludesCommitMessageInstructionWithFeedback(t *tes
 		},
 	}
 
-	_, err = runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, previousMessage)
+	_, err = runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, previousMessage, implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -359,7 +360,7 @@ func TestRunImplementingStageIncludesCommitLog(t *testing.T) {
 		},
 	}
 
-	_, err = runImplementingStage(manager, created, item, repoPath, workspacePath, opts, commitLog, "")
+	_, err = runImplementingStage(manager, created, item, repoPath, workspacePath, opts, commitLog, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -478,7 +479,7 @@ func TestRunReviewingStageReadsCommitMessageFile(t *testing.T) {
 			if !ok {
 				return OpencodeRunResult{}, fmt.Errorf("expected %s to be set", opencodeConfigEnvVar)
 			}
-			expected := opencodeConfigJSON()
+			expected := opencodeConfigJSON(nil)
 			if value != expected {
 				return OpencodeRunResult{}, fmt.Errorf("expected %s to be %q, got %q", opencodeConfigEnvVar, expected, value)
 			}
@@ -860,7 +861,7 @@ func TestRunCommittingStageSkipsEmptyChange(t *testing.T) {
 		},
 	}
 
-	updated, err := runCommittingStage(CommittingStageOptions{
+	stageResult, err := runCommittingStage(CommittingStageOptions{
 		Manager:       manager,
 		Current:       current,
 		Item:          item,
@@ -876,8 +877,8 @@ func TestRunCommittingStageSkipsEmptyChange(t *testing.T) {
 	if commitCalls != 0 {
 		t.Fatalf("expected no commit attempt, got %d", commitCalls)
 	}
-	if updated.Stage != StageImplementing {
-		t.Fatalf("expected stage %q, got %q", StageImplementing, updated.Stage)
+	if stageResult.Job.Stage != StageImplementing {
+		t.Fatalf("expected stage %q, got %q", StageImplementing, stageResult.Job.Stage)
 	}
 }
 
@@ -1201,7 +1202,7 @@ func TestRunImplementingStageCreatesJobChange(t *testing.T) {
 		},
 	}
 
-	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "")
+	result, err := runImplementingStage(manager, created, item, repoPath, workspacePath, opts, nil, "", implementingStageState{})
 	if err != nil {
 		t.Fatalf("run implementing stage: %v", err)
 	}
@@ -1497,3 +1498,242 @@ func TestRunReviewingStageProjectSetsProjectReview(t *testing.T) {
 		t.Fatalf("expected project review session id %q, got %q", "oc-project-review", result.Job.ProjectReview.OpencodeSessionID)
 	}
 }
+
+func TestRunReviewingStageProjectFallsBackToLastCommitMessage(t *testing.T) {
+	repoPath := "/Users/test/repo"
+	workspacePath := t.TempDir()
+
+	manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	startedAt := time.Date(2026, 1, 20, 13, 0, 0, 0, time.UTC)
+	created, err := manager.Create("todo-project-message", startedAt, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	item := todo.Todo{ID: "todo-project-message", Title: "Project review", Type: todo.TypeTask, Priority: todo.PriorityMedium}
+
+	templateDir := filepath.Join(workspacePath, promptOverrideDir)
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		t.Fatalf("create template dir: %v", err)
+	}
+	template := "Review the todo.\n\n{{.CommitMessageBlock}}\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "prompt-project-review.tmpl"), []byte(template), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	feedbackPath := filepath.Join(workspacePath, feedbackFilename)
+	var prompt string
+	opts := RunOptions{
+		Now:         func() time.Time { return startedAt },
+		UpdateStale: func(string) error { return nil },
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			prompt = runOpts.Prompt
+			if err := os.WriteFile(feedbackPath, []byte("ACCEPT"), 0o644); err != nil {
+				return OpencodeRunResult{}, err
+			}
+			return OpencodeRunResult{SessionID: "oc-project-review", ExitCode: 0}, nil
+		},
+	}
+	commitLog := []CommitLogEntry{
+		{ID: "commit-1", Message: "feat: first step"},
+		{ID: "commit-2", Message: "feat: finish the todo"},
+	}
+
+	if _, err := runReviewingStage(manager, created, item, repoPath, workspacePath, opts, "", commitLog, reviewScopeProject); err != nil {
+		t.Fatalf("run reviewing stage: %v", err)
+	}
+	if !strings.Contains(prompt, "Commit message\n\n") || !strings.Contains(prompt, "feat: finish the todo") {
+		t.Fatalf("expected prompt to include the last commit message, got %q", prompt)
+	}
+	if strings.Contains(prompt, "feat: first step") {
+		t.Fatalf("expected only the last commit message, got %q", prompt)
+	}
+
+	created, err = manager.Create("todo-step-message", startedAt, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if _, err := runReviewingStage(manager, created, item, repoPath, workspacePath, opts, "", commitLog, reviewScopeStep); err == nil {
+		t.Fatalf("expected step review to still require a commit message")
+	}
+}
+
+func TestCompletionNoteText(t *testing.T) {
+	got := completionNoteText([]CommitLogEntry{
+		{ID: "f00dcafe", Message: "feat: add thing\n\nLonger body."},
+		{ID: "deadbeef", Message: "fix: tidy up"},
+	})
+	want := "Completed with 2 commits:\n- f00dcafe feat: add thing\n- deadbeef fix: tidy up"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if got := completionNoteText(nil); got != "Completed without changes." {
+		t.Fatalf("unexpected note for an empty commit log: %q", got)
+	}
+}
+
+func TestNoteCompletionSkipsWhenDisabled(t *testing.T) {
+	// The repo path does not exist, so any attempt to open the store fails.
+	repoPath := filepath.Join(t.TempDir(), "missing")
+	completed := Job{ID: "job1", Status: StatusCompleted}
+
+	for name, opts := range map[string]RunOptions{
+		"disabled": {Config: &config.Config{}},
+		"dry run":  {Config: &config.Config{Job: config.Job{CompletionNote: true}}, DryRun: true},
+	} {
+		if err := noteCompletion(opts, repoPath, "todo1", completed, nil); err != nil {
+			t.Fatalf("%s: expected no note, got %v", name, err)
+		}
+	}
+
+	failed := Job{ID: "job1", Status: StatusFailed}
+	opts := RunOptions{Config: &config.Config{Job: config.Job{CompletionNote: true}}}
+	if err := noteCompletion(opts, repoPath, "todo1", failed, nil); err != nil {
+		t.Fatalf("expected failed jobs not to be noted, got %v", err)
+	}
+	if err := noteCompletion(opts, repoPath, "todo1", completed, nil); err == nil {
+		t.Fatalf("expected enabled completion note to open the todo store")
+	}
+}
+
+func TestRunCommittingStageWrapsToConfiguredWidth(t *testing.T) {
+	item := todo.Todo{
+		ID:       "todo-width",
+		Title:    "Wrap commit messages",
+		Type:     todo.TypeTask,
+		Priority: todo.PriorityLow,
+	}
+	draft := "feat: wrap commit messages to the configured width\n\n" +
+		"This body is long enough that it wraps differently at seventy-two columns than it does at one hundred columns, which is the point."
+
+	commitAt := func(width int) (string, []CommitLogEntry) {
+		t.Helper()
+		repoPath := t.TempDir()
+		manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("open manager: %v", err)
+		}
+		startedAt := time.Date(2026, 1, 12, 13, 10, 0, 0, time.UTC)
+		current, err := manager.Create(item.ID, startedAt, CreateOptions{})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+
+		var captured string
+		result := &RunResult{}
+		_, err = runCommittingStage(CommittingStageOptions{
+			Manager:       manager,
+			Current:       current,
+			Item:          item,
+			RepoPath:      repoPath,
+			WorkspacePath: t.TempDir(),
+			RunOptions: RunOptions{
+				Now:         func() time.Time { return startedAt },
+				Config:      &config.Config{Job: config.Job{CommitMessageWidth: width}},
+				UpdateStale: func(string) error { return nil },
+				DiffStat: func(string, string, string) (string, error) {
+					return "file.txt | 1 +\n", nil
+				},
+				CommitIDAt: func(string, string) (string, error) { return "commit-new", nil },
+				Commit: func(_ string, message string) error {
+					captured = message
+					return nil
+				},
+			},
+			Result:        result,
+			CommitMessage: draft,
+		})
+		if err != nil {
+			t.Fatalf("run committing stage: %v", err)
+		}
+		for _, line := range strings.Split(captured, "\n") {
+			if len(line) > width {
+				t.Fatalf("expected lines of at most %d columns, got %d: %q", width, len(line), line)
+			}
+		}
+		return captured, result.CommitLog
+	}
+
+	narrow, narrowLog := commitAt(72)
+	wide, wideLog := commitAt(100)
+	if narrow == wide {
+		t.Fatalf("expected the wrap to differ between widths, got %q", narrow)
+	}
+	for _, commitLog := range [][]CommitLogEntry{narrowLog, wideLog} {
+		if len(commitLog) != 1 || commitLog[0].Message != draft {
+			t.Fatalf("expected the commit log to keep the draft message, got %+v", commitLog)
+		}
+	}
+}
+
+func TestRunCommittingStageSnapshotsBeforeDiff(t *testing.T) {
+	repoPath := t.TempDir()
+	workspacePath := t.TempDir()
+	manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	startedAt := time.Date(2026, 1, 12, 13, 10, 0, 0, time.UTC)
+	current, err := manager.Create("todo-snapshot", startedAt, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	var calls []string
+	_, err = runCommittingStage(CommittingStageOptions{
+		Manager:       manager,
+		Current:       current,
+		Item:          todo.Todo{ID: "todo-snapshot", Title: "Snapshot first", Type: todo.TypeTask},
+		RepoPath:      repoPath,
+		WorkspacePath: workspacePath,
+		RunOptions: RunOptions{
+			Now:         func() time.Time { return startedAt },
+			UpdateStale: func(string) error { return nil },
+			Snapshot: func(path string) error {
+				if path != workspacePath {
+					t.Fatalf("expected snapshot of %q, got %q", workspacePath, path)
+				}
+				calls = append(calls, "snapshot")
+				return nil
+			},
+			DiffStat: func(string, string, string) (string, error) {
+				calls = append(calls, "diff")
+				return "file.txt | 1 +\n", nil
+			},
+			CommitIDAt: func(string, string) (string, error) { return "commit-new", nil },
+			Commit:     func(string, string) error { return nil },
+		},
+		Result:        &RunResult{},
+		CommitMessage: "feat: snapshot first",
+	})
+	if err != nil {
+		t.Fatalf("run committing stage: %v", err)
+	}
+	if len(calls) < 2 || calls[0] != "snapshot" || calls[1] != "diff" {
+		t.Fatalf("expected snapshot before diff stat, got %v", calls)
+	}
+}
+
+func TestSortOpencodeTranscriptEntriesOrdersSameTimePurposes(t *testing.T) {
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []opencodeTranscriptEntry{
+		{Purpose: "review", Session: opencode.OpencodeSession{ID: "aaa-review", StartedAt: startedAt}},
+		{Purpose: "project-review", Session: opencode.OpencodeSession{ID: "bbb-project", StartedAt: startedAt}},
+		{Purpose: "implement", Session: opencode.OpencodeSession{ID: "zzz-implement", StartedAt: startedAt}},
+		{Purpose: "implement", Session: opencode.OpencodeSession{ID: "earlier", StartedAt: startedAt.Add(-time.Second)}},
+	}
+
+	sortOpencodeTranscriptEntries(entries)
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Session.ID)
+	}
+	want := []string{"earlier", "zzz-implement", "aaa-review", "bbb-project"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,141 @@
+package job
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunOpencodeWithEventsInterruptsOnStageTimeout(t *testing.T) {
+	eventsDir := t.TempDir()
+	eventLogOpts := EventLogOptions{EventsDir: eventsDir}
+	eventLog, err := OpenEventLog("job-timeout", eventLogOpts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	// The clock jumps past the deadline after the timer starts, so the test
+	// does not wait for real time to pass.
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var mu sync.Mutex
+	calls := 0
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return base
+		}
+		return base.Add(time.Hour)
+	}
+
+	opts := RunOptions{
+		Now:           now,
+		EventLog:      eventLog,
+		StageTimeout:  time.Hour,
+		StageTimeouts: map[string]time.Duration{"implement": 10 * time.Minute},
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			select {
+			case <-runOpts.Interrupt:
+			case <-time.After(5 * time.Second):
+				t.Error("expected opencode run to be interrupted")
+			}
+			return OpencodeRunResult{SessionID: "ses-1"}, nil
+		},
+	}
+
+	result, err := runOpencodeWithEvents(opts, opencodeRunOptions{}, "implement")
+	if err != nil {
+		t.Fatalf("run opencode: %v", err)
+	}
+	if result.ExitCode == 0 {
+		t.Fatal("expected timed-out run to report a non-zero exit code")
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	path, err := EventLogPath("job-timeout", eventLogOpts)
+	if err != nil {
+		t.Fatalf("event log path: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open event log file: %v", err)
+	}
+	defer file.Close()
+	events, err := ReadEvents(file)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 2 || events[1].Name != jobEventOpencodeTimeout {
+		t.Fatalf("expected start and timeout events, got %+v", events)
+	}
+	want := `{"purpose":"implement","session_id":"ses-1","timeout":"10m0s"}`
+	if events[1].Data != want {
+		t.Fatalf("expected timeout data %s, got %s", want, events[1].Data)
+	}
+}
+
+func TestRunOpencodeWithEventsWithoutTimeout(t *testing.T) {
+	opts := RunOptions{
+		Now: time.Now,
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			if runOpts.Interrupt != nil {
+				t.Error("expected no interrupt channel without a timeout")
+			}
+			return OpencodeRunResult{SessionID: "ses-1"}, nil
+		},
+	}
+
+	result, err := runOpencodeWithEvents(opts, opencodeRunOptions{}, "review")
+	if err != nil {
+		t.Fatalf("run opencode: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+}
+
+func TestRunOpencodeWithEventsWrapsErrorOnStageTimeout(t *testing.T) {
+	eventLog, err := OpenEventLog("job-timeout-error", EventLogOptions{EventsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+	defer eventLog.Close()
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var mu sync.Mutex
+	calls := 0
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return base
+		}
+		return base.Add(time.Hour)
+	}
+
+	runErr := errors.New("wait: signal: killed")
+	opts := RunOptions{
+		Now:          now,
+		EventLog:     eventLog,
+		StageTimeout: time.Minute,
+		RunOpencode: func(runOpts opencodeRunOptions) (OpencodeRunResult, error) {
+			<-runOpts.Interrupt
+			return OpencodeRunResult{}, runErr
+		},
+	}
+
+	_, err = runOpencodeWithEvents(opts, opencodeRunOptions{}, "review")
+	if !errors.Is(err, runErr) {
+		t.Fatalf("expected the run error to be wrapped, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "opencode review timed out after 1m0s") {
+		t.Fatalf("expected the timeout in the error, got %v", err)
+	}
+}
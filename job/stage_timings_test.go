@@ -0,0 +1,122 @@
+package job
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStageTimingsSumsDurationsPerStage(t *testing.T) {
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	eventLog, err := OpenEventLog("job-timings", opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := func(stage Stage, elapsed time.Duration) {
+		now := func() time.Time { return base.Add(elapsed) }
+		if err := recordStageDuration(eventLog, now, stage, base, false); err != nil {
+			t.Fatalf("record stage duration: %v", err)
+		}
+	}
+	record(StageImplementing, 2*time.Minute)
+	record(StageReviewing, 30*time.Second)
+	record(StageImplementing, time.Minute)
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	timings, err := StageTimings("job-timings", opts)
+	if err != nil {
+		t.Fatalf("stage timings: %v", err)
+	}
+	if timings[StageImplementing] != 3*time.Minute {
+		t.Fatalf("expected 3m implementing, got %v", timings[StageImplementing])
+	}
+	if timings[StageReviewing] != 30*time.Second {
+		t.Fatalf("expected 30s reviewing, got %v", timings[StageReviewing])
+	}
+	if _, ok := timings[StageTesting]; ok {
+		t.Fatalf("expected no testing timing, got %v", timings)
+	}
+}
+
+func TestStageTimingsMissingLogIsEmpty(t *testing.T) {
+	timings, err := StageTimings("job-missing", EventLogOptions{EventsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("stage timings: %v", err)
+	}
+	if len(timings) != 0 {
+		t.Fatalf("expected no timings, got %v", timings)
+	}
+}
+
+func TestRunStageWithInterruptRecordsPartialDuration(t *testing.T) {
+	stateDir := t.TempDir()
+	opts := EventLogOptions{EventsDir: t.TempDir()}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manager, err := Open("/tmp/timings-repo", OpenOptions{StateDir: stateDir})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+	created, err := manager.Create("todo-123", base, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	eventLog, err := OpenEventLog(created.ID, opts)
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	// The clock reads the start time once, then 45 seconds later.
+	var mu sync.Mutex
+	calls := 0
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return base
+		}
+		return base.Add(45 * time.Second)
+	}
+
+	ctx := runContext{
+		opts:    RunOptions{Now: now, EventLog: eventLog},
+		manager: manager,
+		result:  &RunResult{},
+	}
+	interrupts := make(chan os.Signal, 1)
+	interrupts <- os.Interrupt
+
+	// The stage runs until the interrupt stops it.
+	_, err = ctx.runStageWithInterrupt(created, func() (Job, error) {
+		<-ctx.stop.channel()
+		return Job{}, nil
+	}, interrupts)
+	if !errors.Is(err, ErrJobInterrupted) {
+		t.Fatalf("expected ErrJobInterrupted, got %v", err)
+	}
+	if err := eventLog.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	timings, err := StageTimings(created.ID, opts)
+	if err != nil {
+		t.Fatalf("stage timings: %v", err)
+	}
+	if timings[StageImplementing] != 45*time.Second {
+		t.Fatalf("expected 45s partial implementing duration, got %v", timings)
+	}
+	events, err := EventSnapshot(created.ID, opts)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 1 || events[0].Data != `{"stage":"implementing","duration_ms":45000,"interrupted":true}` {
+		t.Fatalf("expected one interrupted duration event, got %+v", events)
+	}
+}
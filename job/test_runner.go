@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"sync"
 
 	internalstrings "github.com/amonks/incrementum/internal/strings"
 )
@@ -20,29 +21,84 @@ func RunTestCommands(dir string, commands []string) ([]TestCommandResult, error)
 			return results, fmt.Errorf("test command is required")
 		}
 
-		cmd := exec.Command("/bin/bash", "-lc", command)
-		cmd.Dir = dir
 		var output bytes.Buffer
-		writer := io.MultiWriter(os.Stdout, &output)
-		cmd.Stdout = writer
-		cmd.Stderr = writer
-		cmd.Stdin = os.Stdin
-
-		exitCode := 0
-		if err := cmd.Run(); err != nil {
-			var exitErr *exec.ExitError
-			if !errors.As(err, &exitErr) {
-				return results, fmt.Errorf("run test command %q: %w", command, err)
-			}
-			exitCode = exitErr.ExitCode()
+		result, err := runTestCommand(dir, command, io.MultiWriter(os.Stdout, &output), os.Stdin)
+		if err != nil {
+			return results, err
 		}
+		result.Output = output.String()
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// RunTestCommandsParallel executes up to parallelism test commands at once in
+// a directory. Results are returned in command order regardless of which
+// command finishes first. Each command's output is captured separately and
+// written to stdout, in command order, once all commands finish.
+//
+// A parallelism of one or less runs the commands sequentially.
+func RunTestCommandsParallel(dir string, commands []string, parallelism int) ([]TestCommandResult, error) {
+	if parallelism <= 1 || len(commands) <= 1 {
+		return RunTestCommands(dir, commands)
+	}
+
+	trimmed := make([]string, len(commands))
+	for i, command := range commands {
+		trimmed[i] = internalstrings.TrimSpace(command)
+		if trimmed[i] == "" {
+			return nil, fmt.Errorf("test command is required")
+		}
+	}
 
-		results = append(results, TestCommandResult{
-			Command:  command,
-			ExitCode: exitCode,
-			Output:   output.String(),
-		})
+	results := make([]TestCommandResult, len(trimmed))
+	errs := make([]error, len(trimmed))
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, command := range trimmed {
+		wg.Add(1)
+		go func(i int, command string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			var output bytes.Buffer
+			result, err := runTestCommand(dir, command, &output, nil)
+			result.Output = output.String()
+			results[i] = result
+			errs[i] = err
+		}(i, command)
 	}
+	wg.Wait()
 
+	for i, result := range results {
+		if errs[i] != nil {
+			return results[:i], errs[i]
+		}
+		fmt.Fprint(os.Stdout, result.Output)
+	}
 	return results, nil
 }
+
+func runTestCommand(dir, command string, output io.Writer, stdin io.Reader) (TestCommandResult, error) {
+	cmd := exec.Command("/bin/bash", "-lc", command)
+	cmd.Dir = dir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.Stdin = stdin
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return TestCommandResult{Command: command}, fmt.Errorf("run test command %q: %w", command, err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	return TestCommandResult{
+		Command:  command,
+		ExitCode: exitCode,
+	}, nil
+}
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -30,3 +32,46 @@ func TestRunTestCommandsRejectsBlankCommand(t *testing.T) {
 		t.Fatalf("expected error for blank command")
 	}
 }
+
+func TestRunTestCommandsParallelKeepsCommandOrder(t *testing.T) {
+	// The first command only succeeds if the third runs while it waits.
+	commands := []string{
+		"for i in $(seq 200); do [ -f ready ] && break; sleep 0.05; done; [ -f ready ] && printf 'first\\n'",
+		"printf 'second\\n'; exit 3",
+		"touch ready; printf 'third\\n'",
+	}
+
+	results, err := RunTestCommandsParallel(t.TempDir(), commands, 3)
+	if err != nil {
+		t.Fatalf("run test commands: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, want := range []struct {
+		output   string
+		exitCode int
+	}{
+		{"first\n", 0},
+		{"second\n", 3},
+		{"third\n", 0},
+	} {
+		if results[i].Command != commands[i] {
+			t.Fatalf("expected result %d for %q, got %q", i, commands[i], results[i].Command)
+		}
+		if !strings.HasSuffix(results[i].Output, want.output) || results[i].ExitCode != want.exitCode {
+			t.Fatalf("expected result %d output %q exit %d, got %+v", i, want.output, want.exitCode, results[i])
+		}
+	}
+}
+
+func TestRunTestCommandsParallelRejectsBlankCommandBeforeRunning(t *testing.T) {
+	dir := t.TempDir()
+	_, err := RunTestCommandsParallel(dir, []string{"touch ran", " "}, 2)
+	if err == nil {
+		t.Fatal("expected error for blank command")
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "ran")); statErr == nil {
+		t.Fatal("expected no commands to run")
+	}
+}
@@ -0,0 +1,93 @@
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/internal/config"
+)
+
+func TestStageAfterImplementingSkipsTestsWhenNotRequired(t *testing.T) {
+	eventsDir := t.TempDir()
+	log, err := OpenEventLog("tests-skipped", EventLogOptions{EventsDir: eventsDir})
+	if err != nil {
+		t.Fatalf("open event log: %v", err)
+	}
+
+	notRequired := false
+	skipping := &config.Config{Job: config.Job{RequireTests: &notRequired}}
+
+	if stage, err := stageAfterImplementing(&config.Config{}, log, true); err != nil || stage != StageTesting {
+		t.Fatalf("expected %q when tests are required, got %q (%v)", StageTesting, stage, err)
+	}
+	configured := &config.Config{Job: config.Job{RequireTests: &notRequired, TestCommands: []string{"go test ./..."}}}
+	if stage, err := stageAfterImplementing(configured, log, true); err != nil || stage != StageTesting {
+		t.Fatalf("expected %q when test commands are configured, got %q (%v)", StageTesting, stage, err)
+	}
+	if stage, err := stageAfterImplementing(skipping, log, false); err != nil || stage != StageReviewing {
+		t.Fatalf("expected %q without changes, got %q (%v)", StageReviewing, stage, err)
+	}
+	if stage, err := stageAfterImplementing(skipping, log, true); err != nil || stage != StageReviewing {
+		t.Fatalf("expected %q when tests are skipped, got %q (%v)", StageReviewing, stage, err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close event log: %v", err)
+	}
+
+	events, err := EventSnapshot("tests-skipped", EventLogOptions{EventsDir: eventsDir})
+	if err != nil {
+		t.Fatalf("event snapshot: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != jobEventTestsSkipped {
+		t.Fatalf("expected one tests-skipped event, got %#v", events)
+	}
+	if !strings.Contains(events[0].Data, "require-tests") {
+		t.Fatalf("expected event to explain the skip, got %q", events[0].Data)
+	}
+}
+
+func TestRunTestingStageSkipsWhenTestsNotRequired(t *testing.T) {
+	repoPath := t.TempDir()
+	manager, err := Open(repoPath, OpenOptions{StateDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open manager: %v", err)
+	}
+
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	current, err := manager.Create("todo-1", now, CreateOptions{})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	stage := StageTesting
+	current, err = manager.Update(current.ID, UpdateOptions{Stage: &stage}, now)
+	if err != nil {
+		t.Fatalf("update job: %v", err)
+	}
+
+	notRequired := false
+	opts := RunOptions{
+		Now:    func() time.Time { return now },
+		Config: &config.Config{Job: config.Job{RequireTests: &notRequired}},
+		RunTests: func(string, []string) ([]TestCommandResult, error) {
+			t.Fatalf("expected tests not to run")
+			return nil, nil
+		},
+	}
+	updated, err := runTestingStage(manager, current, repoPath, repoPath, opts)
+	if err != nil {
+		t.Fatalf("run testing stage: %v", err)
+	}
+	if updated.Stage != StageReviewing {
+		t.Fatalf("expected stage %q, got %q", StageReviewing, updated.Stage)
+	}
+
+	opts.Config = &config.Config{}
+	current, err = manager.Update(current.ID, UpdateOptions{Stage: &stage}, now)
+	if err != nil {
+		t.Fatalf("update job: %v", err)
+	}
+	if _, err := runTestingStage(manager, current, repoPath, repoPath, opts); err == nil {
+		t.Fatalf("expected an error when tests are required but not configured")
+	}
+}
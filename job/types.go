@@ -17,6 +17,9 @@ const (
 	StatusFailed Status = statestore.JobStatusFailed
 	// StatusAbandoned indicates the job was abandoned.
 	StatusAbandoned Status = statestore.JobStatusAbandoned
+	// StatusPaused indicates the job stopped at a stage boundary and can be
+	// resumed.
+	StatusPaused Status = statestore.JobStatusPaused
 )
 
 // ValidStatuses returns all valid job status values.
@@ -55,6 +58,11 @@ const (
 	ReviewOutcomeRequestChanges ReviewOutcome = statestore.ReviewOutcomeRequestChanges
 )
 
+// ValidReviewOutcomes returns all valid review outcome values.
+func ValidReviewOutcomes() []ReviewOutcome {
+	return statestore.ValidReviewOutcomes()
+}
+
 // JobChange represents a change being built up during a job.
 type JobChange = statestore.JobChange
 
@@ -64,6 +72,9 @@ type JobCommit = statestore.JobCommit
 // JobReview captures a review decision for a commit or the project.
 type JobReview = statestore.JobReview
 
+// ReviewFileComment is a reviewer's request to change one file.
+type ReviewFileComment = statestore.ReviewFileComment
+
 // OpencodeTranscript captures formatted opencode transcripts for job output.
 type OpencodeTranscript struct {
 	Purpose    string
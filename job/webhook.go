@@ -0,0 +1,178 @@
+package job
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// CompletionSignatureHeader carries the hex HMAC-SHA256 of the request body,
+// prefixed with "sha256=", when a webhook secret is configured.
+const CompletionSignatureHeader = "X-Incrementum-Signature"
+
+const (
+	defaultWebhookAttempts = 3
+	defaultWebhookBackoff  = time.Second
+	defaultWebhookTimeout  = 10 * time.Second
+)
+
+// CompletionPayload is the JSON body POSTed to a completion webhook when a
+// job finishes.
+type CompletionPayload struct {
+	// JobID is the finished job's id.
+	JobID string `json:"job_id"`
+	// TodoID is the todo the job ran for.
+	TodoID string `json:"todo_id"`
+	// Status is the job's final status: completed, failed, or abandoned.
+	Status Status `json:"status"`
+	// Reason explains why the job was abandoned, when it was.
+	Reason string `json:"reason,omitempty"`
+	// DurationMS is the job's wall-clock duration in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
+	// CommitLog lists the commits the job made, in order.
+	CommitLog []CompletionCommit `json:"commit_log"`
+}
+
+// CompletionCommit is one commit in a CompletionPayload.
+type CompletionCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// CompletionWebhook posts a CompletionPayload to URL when a job finishes.
+type CompletionWebhook struct {
+	URL string
+	// Secret, when set, signs each request body with HMAC-SHA256 in the
+	// CompletionSignatureHeader header.
+	Secret string
+	// Attempts caps delivery attempts. Defaults to 3.
+	Attempts int
+	// Backoff is the wait before the first retry; it doubles after each
+	// failed attempt. Defaults to one second.
+	Backoff time.Duration
+	// Client sends the requests. Defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// Deliver posts payload, retrying failed attempts with exponential backoff.
+// Any 2xx response counts as delivered.
+func (w CompletionWebhook) Deliver(payload CompletionPayload) error {
+	if internalstrings.IsBlank(w.URL) {
+		return fmt.Errorf("webhook url is required")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	attempts := w.Attempts
+	if attempts <= 0 {
+		attempts = defaultWebhookAttempts
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = defaultWebhookBackoff
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		lastErr = w.post(body)
+		if lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("deliver webhook after %d attempts: %w", attempts, lastErr)
+}
+
+func (w CompletionWebhook) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(CompletionSignatureHeader, "sha256="+signWebhookBody(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newCompletionPayload describes a finished job for its webhook.
+func newCompletionPayload(finished Job, commitLog []CommitLogEntry, now time.Time) CompletionPayload {
+	commits := make([]CompletionCommit, 0, len(commitLog))
+	for _, entry := range commitLog {
+		commits = append(commits, CompletionCommit{ID: entry.ID, Message: entry.Message})
+	}
+	return CompletionPayload{
+		JobID:      finished.ID,
+		TodoID:     finished.TodoID,
+		Status:     finished.Status,
+		Reason:     finished.Reason,
+		DurationMS: Duration(finished, now).Milliseconds(),
+		CommitLog:  commits,
+	}
+}
+
+// resolveCompletionWebhook returns the webhook a run should notify, if any.
+// RunOptions.CompletionWebhook takes precedence over job.completion-webhook.
+func resolveCompletionWebhook(opts RunOptions) *CompletionWebhook {
+	if opts.CompletionWebhook != nil {
+		return opts.CompletionWebhook
+	}
+	if opts.Config == nil || internalstrings.IsBlank(opts.Config.Job.CompletionWebhook) {
+		return nil
+	}
+	return &CompletionWebhook{
+		URL:    internalstrings.TrimSpace(opts.Config.Job.CompletionWebhook),
+		Secret: opts.Config.Job.CompletionWebhookSecret,
+	}
+}
+
+// notifyCompletion delivers the completion webhook in the background so it
+// never holds up job teardown. The returned channel receives the delivery
+// result and is then closed; it is nil when no webhook is configured, the
+// run was a dry run, or the job was paused rather than finished.
+func notifyCompletion(opts RunOptions, finished Job, commitLog []CommitLogEntry) <-chan error {
+	webhook := resolveCompletionWebhook(opts)
+	if webhook == nil || opts.DryRun || finished.Status == StatusPaused {
+		return nil
+	}
+	payload := newCompletionPayload(finished, commitLog, opts.Now())
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		done <- webhook.Deliver(payload)
+	}()
+	return done
+}
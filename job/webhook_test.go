@@ -0,0 +1,184 @@
+package job
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amonks/incrementum/habit"
+	"github.com/amonks/incrementum/internal/config"
+	"github.com/amonks/incrementum/internal/testsupport"
+)
+
+func TestCompletionWebhookDeliversSignedPayload(t *testing.T) {
+	var mu sync.Mutex
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(CompletionSignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	finished := Job{
+		ID:        "job-123",
+		TodoID:    "todo-abc",
+		Status:    StatusCompleted,
+		CreatedAt: startedAt,
+		UpdatedAt: startedAt.Add(90 * time.Second),
+	}
+	payload := newCompletionPayload(finished, []CommitLogEntry{{ID: "commit-1", Message: "feat: add thing"}}, startedAt.Add(time.Hour))
+
+	webhook := CompletionWebhook{URL: server.URL, Secret: "s3cret"}
+	if err := webhook.Deliver(payload); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var got CompletionPayload
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got.JobID != "job-123" || got.TodoID != "todo-abc" || got.Status != StatusCompleted {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+	if got.DurationMS != 90000 {
+		t.Fatalf("expected 90000ms duration, got %d", got.DurationMS)
+	}
+	if len(got.CommitLog) != 1 || got.CommitLog[0].ID != "commit-1" || got.CommitLog[0].Message != "feat: add thing" {
+		t.Fatalf("unexpected commit log %+v", got.CommitLog)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Fatalf("expected signature %q, got %q", want, signature)
+	}
+}
+
+func TestCompletionWebhookRetriesFailedDeliveries(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := CompletionWebhook{URL: server.URL, Attempts: 3, Backoff: time.Millisecond}
+	if err := webhook.Deliver(CompletionPayload{JobID: "job-123"}); err != nil {
+		t.Fatalf("expected delivery on third attempt, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestCompletionWebhookGivesUpAfterAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := CompletionWebhook{URL: server.URL, Attempts: 2, Backoff: time.Millisecond}
+	if err := webhook.Deliver(CompletionPayload{JobID: "job-123"}); err == nil {
+		t.Fatal("expected delivery error")
+	}
+}
+
+func TestNotifyCompletionUsesConfiguredWebhook(t *testing.T) {
+	delivered := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CompletionPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		delivered <- payload.JobID
+	}))
+	defer server.Close()
+
+	now := func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	if done := notifyCompletion(RunOptions{Now: now, Config: &config.Config{}}, Job{ID: "job-123"}, nil); done != nil {
+		t.Fatal("expected no delivery without a configured webhook")
+	}
+
+	cfg := &config.Config{}
+	cfg.Job.CompletionWebhook = server.URL
+	done := notifyCompletion(RunOptions{Now: now, Config: cfg}, Job{ID: "job-123", Status: StatusFailed}, nil)
+	if done == nil {
+		t.Fatal("expected delivery with a configured webhook")
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if got := <-delivered; got != "job-123" {
+		t.Fatalf("expected job-123 delivered, got %q", got)
+	}
+}
+
+func TestRunHabitNotifiesCompletionWebhook(t *testing.T) {
+	testsupport.SetupTestHome(t)
+	repoPath := t.TempDir()
+	habitsDir := filepath.Join(repoPath, habit.HabitsDir)
+	if err := os.MkdirAll(habitsDir, 0o755); err != nil {
+		t.Fatalf("create habits dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(habitsDir, "cleanup.md"), []byte("Clean up.\n"), 0o644); err != nil {
+		t.Fatalf("write habit: %v", err)
+	}
+
+	delivered := make(chan CompletionPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CompletionPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		delivered <- payload
+	}))
+	defer server.Close()
+
+	result, err := RunHabit(repoPath, "cleanup", HabitRunOptions{
+		Config:             &config.Config{},
+		EventLogOptions:    EventLogOptions{EventsDir: t.TempDir()},
+		Interrupts:         make(chan os.Signal),
+		CompletionWebhook:  &CompletionWebhook{URL: server.URL},
+		CurrentCommitID:    func(string) (string, error) { return "before", nil },
+		CurrentChangeEmpty: func(string) (bool, error) { return true, nil },
+		Snapshot:           func(string) error { return nil },
+		RunOpencode: func(opencodeRunOptions) (OpencodeRunResult, error) {
+			return OpencodeRunResult{}, fmt.Errorf("opencode unavailable")
+		},
+	})
+	if err == nil {
+		t.Fatal("expected habit run to fail")
+	}
+	if result == nil || result.WebhookDone == nil {
+		t.Fatal("expected the habit run to notify the webhook")
+	}
+	if err := <-result.WebhookDone; err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	payload := <-delivered
+	if payload.TodoID != "habit:cleanup" || payload.Status != StatusFailed {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
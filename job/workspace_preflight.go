@@ -0,0 +1,100 @@
+package job
+
+import (
+	"fmt"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// Workspace preflight actions recorded in job.workspace_dirty events.
+const (
+	workspaceDirtyActionFailed    = "failed"
+	workspaceDirtyActionNewChange = "new-change"
+	workspaceDirtyActionDryRun    = "dry-run"
+)
+
+type workspaceDirtyEventData struct {
+	// Action is "failed", "new-change", or "dry-run".
+	Action   string `json:"action"`
+	DiffStat string `json:"diff_stat,omitempty"`
+	// ChangeID is the clean change the job started in, for "new-change".
+	ChangeID string `json:"change_id,omitempty"`
+	// LeftoverCommitID is the side revision holding the snapshotted
+	// leftovers, for "new-change".
+	LeftoverCommitID string `json:"leftover_commit_id,omitempty"`
+}
+
+// workspacePreflight checks that a new job starts from an empty working-copy
+// change, so leftovers from an earlier run are not mistaken for the job's
+// work or folded into its commit.
+type workspacePreflight struct {
+	log           *EventLog
+	workspacePath string
+	requireClean  bool
+	dryRun        bool
+	changeEmpty   func(string) (bool, error)
+	diffStat      func(string, string, string) (string, error)
+	snapshot      func(string) error
+	commitID      func(string) (string, error)
+	newChange     func(string, string) (string, error)
+}
+
+// run returns ErrWorkspaceNotClean for a dirty workspace when requireClean is
+// set. Otherwise it snapshots the leftovers into the current change and starts
+// a new empty change beside it, on the leftovers' parent, so the job builds on
+// the intended base and the leftovers stay recoverable as a side revision.
+// Either way the dirty state is recorded as a job.workspace_dirty event.
+func (p workspacePreflight) run() error {
+	empty, err := p.changeEmpty(p.workspacePath)
+	if err != nil {
+		return fmt.Errorf("check workspace is clean: %w", err)
+	}
+	if empty {
+		return nil
+	}
+	stat, err := p.diffStat(p.workspacePath, "@-", "@")
+	if err != nil {
+		return fmt.Errorf("describe dirty workspace: %w", err)
+	}
+	stat = internalstrings.TrimTrailingNewlines(stat)
+
+	if p.requireClean {
+		if err := appendJobEvent(p.log, jobEventWorkspaceDirty, workspaceDirtyEventData{Action: workspaceDirtyActionFailed, DiffStat: stat}); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s has uncommitted changes:\n%s", ErrWorkspaceNotClean, p.workspacePath, stat)
+	}
+	if p.dryRun {
+		return appendJobEvent(p.log, jobEventWorkspaceDirty, workspaceDirtyEventData{Action: workspaceDirtyActionDryRun, DiffStat: stat})
+	}
+
+	if err := p.snapshot(p.workspacePath); err != nil {
+		return fmt.Errorf("snapshot dirty workspace: %w", err)
+	}
+	leftover, err := p.commitID(p.workspacePath)
+	if err != nil {
+		return fmt.Errorf("record dirty workspace: %w", err)
+	}
+	changeID, err := p.newChange(p.workspacePath, "@-")
+	if err != nil {
+		return fmt.Errorf("start clean change: %w", err)
+	}
+	return appendJobEvent(p.log, jobEventWorkspaceDirty, workspaceDirtyEventData{Action: workspaceDirtyActionNewChange, DiffStat: stat, ChangeID: changeID, LeftoverCommitID: leftover})
+}
+
+// workspaceDirtyLogBody describes a job.workspace_dirty event for job logs.
+func workspaceDirtyLogBody(data workspaceDirtyEventData) string {
+	var summary string
+	switch data.Action {
+	case workspaceDirtyActionFailed:
+		summary = "The workspace had uncommitted changes; the job did not start."
+	case workspaceDirtyActionNewChange:
+		summary = fmt.Sprintf("The workspace had uncommitted changes; they were kept in side revision %s and the job started in new change %s from their parent.", data.LeftoverCommitID, data.ChangeID)
+	default:
+		summary = "The workspace had uncommitted changes; a real run would set them aside and start a new change from their parent."
+	}
+	if internalstrings.IsBlank(data.DiffStat) {
+		return summary
+	}
+	return summary + "\n\n```\n" + data.DiffStat + "\n```"
+}
@@ -0,0 +1,102 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWorkspacePreflight(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		empty         bool
+		requireClean  bool
+		dryRun        bool
+		wantErr       error
+		wantAction    string
+		wantNewChange bool
+	}{
+		{name: "clean", empty: true},
+		{name: "dirty starts new change", wantAction: workspaceDirtyActionNewChange, wantNewChange: true},
+		{name: "dirty fails when clean required", requireClean: true, wantErr: ErrWorkspaceNotClean, wantAction: workspaceDirtyActionFailed},
+		{name: "dirty dry run", dryRun: true, wantAction: workspaceDirtyActionDryRun},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := EventLogOptions{EventsDir: t.TempDir()}
+			log, err := OpenEventLog("preflight", opts)
+			if err != nil {
+				t.Fatalf("open event log: %v", err)
+			}
+
+			var calls []string
+			preflight := workspacePreflight{
+				log:           log,
+				workspacePath: "/tmp/ws",
+				requireClean:  tc.requireClean,
+				dryRun:        tc.dryRun,
+				changeEmpty: func(string) (bool, error) {
+					return tc.empty, nil
+				},
+				diffStat: func(_, from, to string) (string, error) {
+					calls = append(calls, "diff "+from+" "+to)
+					return "junk.txt | 1 +\n1 file changed\n", nil
+				},
+				snapshot: func(string) error {
+					calls = append(calls, "snapshot")
+					return nil
+				},
+				commitID: func(string) (string, error) {
+					calls = append(calls, "commit-id")
+					return "leftover-commit", nil
+				},
+				newChange: func(_, parent string) (string, error) {
+					calls = append(calls, "new "+parent)
+					return "clean-change", nil
+				},
+			}
+			err = preflight.run()
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err := log.Close(); err != nil {
+				t.Fatalf("close event log: %v", err)
+			}
+
+			wantCalls := []string{}
+			if !tc.empty {
+				wantCalls = append(wantCalls, "diff @- @")
+			}
+			if tc.wantNewChange {
+				wantCalls = append(wantCalls, "snapshot", "commit-id", "new @-")
+			}
+			if strings.Join(calls, ",") != strings.Join(wantCalls, ",") {
+				t.Fatalf("expected calls %v, got %v", wantCalls, calls)
+			}
+
+			events, err := EventSnapshot("preflight", opts)
+			if err != nil {
+				t.Fatalf("event snapshot: %v", err)
+			}
+			if tc.wantAction == "" {
+				if len(events) != 0 {
+					t.Fatalf("expected no events, got %+v", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].Name != jobEventWorkspaceDirty {
+				t.Fatalf("expected one workspace-dirty event, got %+v", events)
+			}
+			var data workspaceDirtyEventData
+			if err := json.Unmarshal([]byte(events[0].Data), &data); err != nil {
+				t.Fatalf("decode event: %v", err)
+			}
+			if data.Action != tc.wantAction || data.DiffStat != "junk.txt | 1 +\n1 file changed" {
+				t.Fatalf("unexpected event data %+v", data)
+			}
+			if tc.wantNewChange && (data.ChangeID != "clean-change" || data.LeftoverCommitID != "leftover-commit") {
+				t.Fatalf("expected the new change and leftover ids, got %+v", data)
+			}
+		})
+	}
+}
@@ -23,8 +23,9 @@ type Event struct {
 
 // RunHandle represents an in-flight opencode run.
 type RunHandle struct {
-	Events <-chan Event
-	wait   func() (RunResult, error)
+	Events    <-chan Event
+	wait      func() (RunResult, error)
+	interrupt func() error
 }
 
 // Wait blocks until the run completes.
@@ -32,6 +33,16 @@ func (h *RunHandle) Wait() (RunResult, error) {
 	return h.wait()
 }
 
+// Interrupt asks the opencode run process to stop with SIGINT, and kills it
+// if it has not exited after a grace period. Wait still must be called to
+// collect the result, which reports a non-zero exit code.
+func (h *RunHandle) Interrupt() error {
+	if h.interrupt == nil {
+		return nil
+	}
+	return h.interrupt()
+}
+
 // DrainEvents consumes events until the channel closes.
 func DrainEvents(events <-chan Event) <-chan struct{} {
 	done := make(chan struct{})
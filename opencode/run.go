@@ -39,6 +39,10 @@ type RunResult struct {
 	RunCommand   string
 }
 
+// interruptGracePeriod is how long an interrupted opencode run may take to
+// exit on SIGINT before it is killed.
+const interruptGracePeriod = 5 * time.Second
+
 // Run executes opencode and records session state.
 func (s *Store) Run(opts RunOptions) (*RunHandle, error) {
 	startedAt := opts.StartedAt
@@ -145,10 +149,25 @@ func (s *Store) Run(opts RunOptions) (*RunHandle, error) {
 		return nil, errors.Join(err, stopErr)
 	}
 
+	exited := make(chan struct{})
 	handle := &RunHandle{
 		Events: events,
+		interrupt: func() error {
+			if err := runCmd.Process.Signal(os.Interrupt); err != nil {
+				return err
+			}
+			go func() {
+				select {
+				case <-exited:
+				case <-time.After(interruptGracePeriod):
+					_ = runCmd.Process.Kill()
+				}
+			}()
+			return nil
+		},
 		wait: func() (RunResult, error) {
 			exitCode, runErr := runExitCode(runCmd)
+			close(exited)
 			completedAt := time.Now()
 
 			sessionResult := <-sessionCh
@@ -1,6 +1,10 @@
 package todo
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Dependency represents a relationship between two todos.
 type Dependency struct {
@@ -10,15 +14,103 @@ type Dependency struct {
 	// DependsOnID is the todo that TodoID depends on.
 	DependsOnID string `json:"depends_on_id"`
 
+	// Type is how the todos are related. Empty means DepTypeBlocks.
+	Type DependencyType `json:"type,omitempty"`
+
 	// CreatedAt is when the dependency was created.
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// IsBlocking returns true when the dependency keeps TodoID from being ready.
+func (d Dependency) IsBlocking() bool {
+	return d.Type.IsBlocking()
+}
+
+// DepAddOptions configures DepAddWithOptions.
+type DepAddOptions struct {
+	// Type is the dependency type. Empty means DepTypeBlocks.
+	Type DependencyType
+}
+
 // DepTreeNode represents a node in a dependency tree.
 type DepTreeNode struct {
 	// Todo is the todo at this node.
 	Todo *Todo
 
+	// Type is the type of the dependency linking this node to its parent.
+	// It is empty for the root.
+	Type DependencyType
+
 	// Children are the todos that this todo depends on.
 	Children []*DepTreeNode
+
+	// SeeAbove is set when the todo already appears earlier in the tree.
+	// Its children are omitted rather than rendered again.
+	SeeAbove bool
+
+	// Truncated is set when the todo has dependencies that were omitted
+	// because the tree reached its depth limit.
+	Truncated bool
+}
+
+// DepTreeOptions configures DepTree.
+type DepTreeOptions struct {
+	// MaxDepth limits how many levels below the root are included.
+	// Zero means no limit.
+	MaxDepth int
+}
+
+// DependentsOptions configures DependentsWithOptions.
+type DependentsOptions struct {
+	// IncludeTombstones includes soft-deleted dependents. Default is false.
+	IncludeTombstones bool
+}
+
+// checkDependencyCycle returns ErrDependencyCycle when adding the blocking
+// edge todoID → dependsOnID to deps would close a cycle of blocking
+// dependencies. The error names the offending path, starting and ending at
+// todoID.
+func checkDependencyCycle(deps []Dependency, todoID, dependsOnID string) error {
+	path := findDependencyPath(dependencyAdjacency(deps), dependsOnID, todoID)
+	if path == nil {
+		return nil
+	}
+	path = append([]string{todoID}, path...)
+	return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(path, " → "))
+}
+
+// dependencyAdjacency maps each todo ID to the IDs it is blocked by.
+// Informational dependencies cannot deadlock, so they are left out.
+func dependencyAdjacency(deps []Dependency) map[string][]string {
+	adjacency := make(map[string][]string, len(deps))
+	for _, dep := range deps {
+		if !dep.IsBlocking() {
+			continue
+		}
+		adjacency[dep.TodoID] = append(adjacency[dep.TodoID], dep.DependsOnID)
+	}
+	return adjacency
+}
+
+// findDependencyPath walks dependencies depth-first from start and returns
+// the first path that reaches target, including both ends, or nil.
+func findDependencyPath(adjacency map[string][]string, start, target string) []string {
+	visited := make(map[string]bool)
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == target {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, next := range adjacency[id] {
+			if rest := walk(next); rest != nil {
+				return append([]string{id}, rest...)
+			}
+		}
+		return nil
+	}
+	return walk(start)
 }
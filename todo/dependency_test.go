@@ -0,0 +1,33 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDependencyCycle(t *testing.T) {
+	deps := []Dependency{
+		{TodoID: "a", DependsOnID: "b"},
+		{TodoID: "b", DependsOnID: "c"},
+	}
+
+	err := checkDependencyCycle(deps, "c", "a")
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if want := "dependency would create a cycle: c → a → b → c"; err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestCheckDependencyCycleAllowsDiamond(t *testing.T) {
+	deps := []Dependency{
+		{TodoID: "top", DependsOnID: "left"},
+		{TodoID: "top", DependsOnID: "right"},
+		{TodoID: "left", DependsOnID: "bottom"},
+	}
+
+	if err := checkDependencyCycle(deps, "right", "bottom"); err != nil {
+		t.Fatalf("expected diamond to be allowed, got %v", err)
+	}
+}
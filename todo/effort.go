@@ -0,0 +1,56 @@
+package todo
+
+// EffortSummary compares estimated and actual effort across closed todos.
+type EffortSummary struct {
+	// Count is how many closed todos matched the filter.
+	Count int
+
+	// Estimated is how many of them have an estimate, and EstimateMinutes
+	// sums those estimates.
+	Estimated       int
+	EstimateMinutes int
+
+	// Tracked is how many of them have an actual effort, and ActualMinutes
+	// sums it.
+	Tracked       int
+	ActualMinutes int
+
+	// Compared is how many have both. CompareEstimateMinutes and
+	// CompareActualMinutes sum only those todos, so they can be compared
+	// directly.
+	Compared               int
+	CompareEstimateMinutes int
+	CompareActualMinutes   int
+}
+
+// EffortReport aggregates estimated and actual effort across the closed
+// todos (closed, done, or archived) matching filter. Open todos are ignored
+// even when the filter matches them.
+func (s *Store) EffortReport(filter ListFilter) (EffortSummary, error) {
+	todos, err := s.List(filter)
+	if err != nil {
+		return EffortSummary{}, err
+	}
+	var summary EffortSummary
+	for _, item := range todos {
+		if !item.Status.IsResolved() || item.Status == StatusTombstone {
+			continue
+		}
+		summary.Count++
+		if item.EstimateMinutes > 0 {
+			summary.Estimated++
+			summary.EstimateMinutes += item.EstimateMinutes
+		}
+		actual, tracked := item.ActualMinutes()
+		if tracked {
+			summary.Tracked++
+			summary.ActualMinutes += actual
+		}
+		if item.EstimateMinutes > 0 && tracked {
+			summary.Compared++
+			summary.CompareEstimateMinutes += item.EstimateMinutes
+			summary.CompareActualMinutes += actual
+		}
+	}
+	return summary, nil
+}
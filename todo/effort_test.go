@@ -0,0 +1,127 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore_EstimateMinutes(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, err := store.Create("Estimated", CreateOptions{EstimateMinutes: 90})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	plain, err := store.Create("Plain", CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	if _, err := store.Create("Negative", CreateOptions{EstimateMinutes: -1}); !errors.Is(err, ErrInvalidEstimate) {
+		t.Fatalf("expected ErrInvalidEstimate, got %v", err)
+	}
+
+	shown, err := store.Show([]string{created.ID, plain.ID})
+	if err != nil {
+		t.Fatalf("failed to show todos: %v", err)
+	}
+	if shown[0].EstimateMinutes != 90 || shown[1].EstimateMinutes != 0 {
+		t.Fatalf("expected estimates 90 and 0, got %d and %d", shown[0].EstimateMinutes, shown[1].EstimateMinutes)
+	}
+
+	cleared := 0
+	updated, err := store.Update([]string{created.ID}, UpdateOptions{EstimateMinutes: &cleared})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if updated[0].EstimateMinutes != 0 {
+		t.Fatalf("expected estimate to be cleared, got %d", updated[0].EstimateMinutes)
+	}
+}
+
+func TestTodo_ActualMinutes(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	completed := started.Add(74*time.Minute + 40*time.Second)
+	item := Todo{ID: "abc", StartedAt: &started}
+	if _, ok := item.ActualMinutes(); ok {
+		t.Fatalf("expected no actual effort without a completion time")
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("failed to marshal todo: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode todo: %v", err)
+	}
+	if _, ok := decoded["actual_minutes"]; ok {
+		t.Fatalf("expected no actual_minutes, got %s", data)
+	}
+
+	item.CompletedAt = &completed
+	if actual, ok := item.ActualMinutes(); !ok || actual != 75 {
+		t.Fatalf("expected 75 actual minutes, got %d (%v)", actual, ok)
+	}
+	data, err = json.Marshal(item)
+	if err != nil {
+		t.Fatalf("failed to marshal todo: %v", err)
+	}
+	decoded = nil
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode todo: %v", err)
+	}
+	if decoded["actual_minutes"] != float64(75) || decoded["id"] != "abc" {
+		t.Fatalf("expected actual_minutes alongside the todo fields, got %s", data)
+	}
+}
+
+func TestStore_EffortReport(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.clock = func() time.Time { return now }
+
+	both, _ := store.Create("Both", CreateOptions{EstimateMinutes: 60, Tags: []string{"infra"}})
+	estimateOnly, _ := store.Create("Estimate only", CreateOptions{EstimateMinutes: 30, Tags: []string{"infra"}})
+	actualOnly, _ := store.Create("Actual only", CreateOptions{Tags: []string{"infra"}})
+	store.Create("Open", CreateOptions{EstimateMinutes: 120, Tags: []string{"infra"}})
+	store.Create("Other tag", CreateOptions{EstimateMinutes: 15})
+
+	if _, err := store.Start([]string{both.ID, actualOnly.ID}); err != nil {
+		t.Fatalf("failed to start todos: %v", err)
+	}
+	now = now.Add(90 * time.Minute)
+	if _, err := store.Finish([]string{both.ID, actualOnly.ID}); err != nil {
+		t.Fatalf("failed to finish todos: %v", err)
+	}
+	if _, err := store.Finish([]string{estimateOnly.ID}); err != nil {
+		t.Fatalf("failed to finish todo: %v", err)
+	}
+
+	summary, err := store.EffortReport(ListFilter{Tags: []string{"infra"}})
+	if err != nil {
+		t.Fatalf("failed to build effort report: %v", err)
+	}
+	want := EffortSummary{
+		Count:                  3,
+		Estimated:              2,
+		EstimateMinutes:        90,
+		Tracked:                2,
+		ActualMinutes:          180,
+		Compared:               1,
+		CompareEstimateMinutes: 60,
+		CompareActualMinutes:   90,
+	}
+	if summary != want {
+		t.Fatalf("expected %+v, got %+v", want, summary)
+	}
+}
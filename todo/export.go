@@ -0,0 +1,176 @@
+package todo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// ExportFormat selects how Export renders todos.
+type ExportFormat string
+
+const (
+	// FormatJSON renders a versioned ExportEnvelope.
+	FormatJSON ExportFormat = "json"
+
+	// FormatMarkdown renders a checklist grouped by status, then priority.
+	FormatMarkdown ExportFormat = "markdown"
+)
+
+// ExportSchemaVersion is the version of the ExportEnvelope layout. It
+// increases whenever the layout changes incompatibly, so importers can
+// migrate older exports.
+const ExportSchemaVersion = 1
+
+// ExportEnvelope is the JSON export layout.
+type ExportEnvelope struct {
+	// SchemaVersion is the ExportSchemaVersion the export was written with.
+	SchemaVersion int `json:"schema_version"`
+
+	// ExportedAt is when the export was written.
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Todos are the exported todos, in store order.
+	Todos []Todo `json:"todos"`
+
+	// Dependencies are the dependencies of the exported todos.
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// ParseExportFormat parses an export format name. "md" is accepted as an
+// alias for markdown.
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch internalstrings.NormalizeLowerTrimSpace(value) {
+	case "json":
+		return FormatJSON, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("invalid export format %q (valid: json, markdown)", value)
+	}
+}
+
+// Export writes the todos matching filter to w in the given format, along
+// with the dependencies those todos have. A dependency's depends-on todo may
+// fall outside the filter.
+func (s *Store) Export(w io.Writer, format ExportFormat, filter ListFilter) error {
+	if format != FormatJSON && format != FormatMarkdown {
+		return fmt.Errorf("invalid export format %q (valid: json, markdown)", format)
+	}
+
+	todos, err := s.List(filter)
+	if err != nil {
+		return err
+	}
+	allDeps, err := s.readDependenciesWithContext()
+	if err != nil {
+		return err
+	}
+
+	exported := make(map[string]struct{}, len(todos))
+	for _, item := range todos {
+		exported[item.ID] = struct{}{}
+	}
+	deps := make([]Dependency, 0)
+	for _, dep := range allDeps {
+		if _, ok := exported[dep.TodoID]; ok {
+			deps = append(deps, dep)
+		}
+	}
+
+	if format == FormatMarkdown {
+		return writeMarkdownExport(w, todos, deps)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ExportEnvelope{
+		SchemaVersion: ExportSchemaVersion,
+		ExportedAt:    s.now(),
+		Todos:         todos,
+		Dependencies:  deps,
+	})
+}
+
+// writeMarkdownExport renders todos as a checklist with a section per status
+// and a subsection per priority. Each item lists its ID and dependencies
+// beneath it, followed by its description. ImportMarkdown reads the ID and
+// dependency lines back.
+func writeMarkdownExport(w io.Writer, todos []Todo, deps []Dependency) error {
+	dependsOn := make(map[string][]string)
+	for _, dep := range deps {
+		dependsOn[dep.TodoID] = append(dependsOn[dep.TodoID], formatMarkdownDependency(dep))
+	}
+
+	byStatus := make(map[Status][]Todo)
+	for _, item := range todos {
+		byStatus[item.Status] = append(byStatus[item.Status], item)
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "# Todos")
+	for _, status := range ValidStatuses() {
+		items := byStatus[status]
+		if len(items) == 0 {
+			continue
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Priority < items[j].Priority
+		})
+
+		fmt.Fprintf(out, "\n## %s\n", status)
+		priority := -1
+		for _, item := range items {
+			if item.Priority != priority {
+				priority = item.Priority
+				fmt.Fprintf(out, "\n### %s\n\n", PriorityName(priority))
+			}
+			writeMarkdownExportItem(out, item, dependsOn[item.ID])
+		}
+	}
+	return out.Flush()
+}
+
+// formatMarkdownDependency renders a depends-on entry: the depended-on ID,
+// followed by the dependency type in parentheses unless it blocks.
+func formatMarkdownDependency(dep Dependency) string {
+	if dep.Type.IsBlocking() {
+		return dep.DependsOnID
+	}
+	return fmt.Sprintf("%s (%s)", dep.DependsOnID, dep.Type)
+}
+
+func writeMarkdownExportItem(out *bufio.Writer, item Todo, dependsOn []string) {
+	box := " "
+	if item.Status.IsResolved() {
+		box = "x"
+	}
+	line := fmt.Sprintf("- [%s] %s @priority:%s", box, item.Title, PriorityName(item.Priority))
+	for _, tag := range item.Tags {
+		line += " #" + tag
+	}
+	fmt.Fprintln(out, line)
+	fmt.Fprintf(out, "  id: %s\n", item.ID)
+	if len(dependsOn) > 0 {
+		fmt.Fprintf(out, "  depends-on: %s\n", strings.Join(dependsOn, ", "))
+	}
+
+	description := internalstrings.TrimTrailingNewlines(item.Description)
+	if description == "" {
+		return
+	}
+	fmt.Fprintln(out)
+	for _, descLine := range strings.Split(description, "\n") {
+		if descLine == "" {
+			fmt.Fprintln(out)
+			continue
+		}
+		fmt.Fprintf(out, "  %s\n", descLine)
+	}
+}
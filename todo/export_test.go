@@ -0,0 +1,183 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_ExportJSON(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.clock = func() time.Time { return now }
+
+	blocker, _ := store.Create("Blocker", CreateOptions{Tags: []string{"infra"}})
+	blocked, _ := store.Create("Blocked", CreateOptions{Dependencies: []string{blocker.ID}})
+	store.Create("Other", CreateOptions{})
+
+	var buf bytes.Buffer
+	if err := store.Export(&buf, FormatJSON, ListFilter{IDs: []string{blocked.ID}}); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	var envelope ExportEnvelope
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("failed to decode export: %v\n%s", err, buf.String())
+	}
+	if envelope.SchemaVersion != ExportSchemaVersion {
+		t.Fatalf("expected schema version %d, got %d", ExportSchemaVersion, envelope.SchemaVersion)
+	}
+	if !envelope.ExportedAt.Equal(now) {
+		t.Fatalf("expected exported_at %v, got %v", now, envelope.ExportedAt)
+	}
+	if len(envelope.Todos) != 1 || envelope.Todos[0].ID != blocked.ID {
+		t.Fatalf("expected only the filtered todo, got %+v", envelope.Todos)
+	}
+	if len(envelope.Dependencies) != 1 || envelope.Dependencies[0].DependsOnID != blocker.ID {
+		t.Fatalf("expected the filtered todo's dependency, got %+v", envelope.Dependencies)
+	}
+}
+
+func TestStore_ExportMarkdown(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	high := PriorityHigh
+	low := PriorityLow
+	blocker, _ := store.Create("Blocker", CreateOptions{Priority: &low, Description: "First line.\n\nSecond paragraph."})
+	blocked, _ := store.Create("Blocked", CreateOptions{Priority: &high, Tags: []string{"infra"}, Dependencies: []string{blocker.ID}})
+	finished, _ := store.Create("Finished", CreateOptions{})
+	if _, err := store.Finish([]string{finished.ID}); err != nil {
+		t.Fatalf("failed to finish todo: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := store.Export(&buf, FormatMarkdown, ListFilter{}); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"# Todos",
+		"",
+		"## open",
+		"",
+		"### high",
+		"",
+		"- [ ] Blocked @priority:high #infra",
+		"  id: " + blocked.ID,
+		"  depends-on: " + blocker.ID,
+		"",
+		"### low",
+		"",
+		"- [ ] Blocker @priority:low",
+		"  id: " + blocker.ID,
+		"",
+		"  First line.",
+		"",
+		"  Second paragraph.",
+		"",
+		"## done",
+		"",
+		"### medium",
+		"",
+		"- [x] Finished @priority:medium",
+		"  id: " + finished.ID,
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Fatalf("unexpected markdown export:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for input, want := range map[string]ExportFormat{
+		"json":     FormatJSON,
+		"md":       FormatMarkdown,
+		"Markdown": FormatMarkdown,
+	} {
+		got, err := ParseExportFormat(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseExportFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseExportFormat("csv"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
+
+func TestStore_MarkdownExportImportRoundTrip(t *testing.T) {
+	source, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer source.Release()
+
+	high := PriorityHigh
+	origin, _ := source.Create("Origin", CreateOptions{Description: "Where it started."})
+	blocker, _ := source.Create("Blocker", CreateOptions{})
+	blocked, _ := source.Create("Blocked", CreateOptions{Priority: &high, Tags: []string{"infra"}, Dependencies: []string{blocker.ID}})
+	if _, err := source.DepAddWithOptions(blocked.ID, origin.ID, DepAddOptions{Type: DepTypeDiscoveredFrom}); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := source.Export(&buf, FormatMarkdown, ListFilter{}); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	target, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer target.Release()
+
+	imported, err := target.ImportMarkdown(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("failed to import export:\n%s\n%v", buf.String(), err)
+	}
+	byID := make(map[string]Todo, len(imported))
+	for _, item := range imported {
+		byID[item.ID] = item
+	}
+	for _, want := range []*Todo{origin, blocker, blocked} {
+		got, ok := byID[want.ID]
+		if !ok {
+			t.Fatalf("expected %s (%s) to keep its ID, got %+v", want.ID, want.Title, imported)
+		}
+		if got.Title != want.Title || got.Priority != want.Priority || got.Description != want.Description {
+			t.Fatalf("expected %+v to round-trip, got %+v", want, got)
+		}
+	}
+
+	deps, err := target.readDependencies()
+	if err != nil {
+		t.Fatalf("failed to read dependencies: %v", err)
+	}
+	edges := make(map[string]DependencyType)
+	for _, dep := range deps {
+		edges[dep.TodoID+"->"+dep.DependsOnID] = dep.Type
+	}
+	if len(edges) != 2 {
+		t.Fatalf("expected two dependencies, got %+v", deps)
+	}
+	if depType, ok := edges[blocked.ID+"->"+blocker.ID]; !ok || !depType.IsBlocking() {
+		t.Fatalf("expected blocking dependency on blocker, got %+v", deps)
+	}
+	if edges[blocked.ID+"->"+origin.ID] != DepTypeDiscoveredFrom {
+		t.Fatalf("expected discovered-from dependency on origin, got %+v", deps)
+	}
+
+	if _, err := target.ImportMarkdown(strings.NewReader(buf.String())); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected re-importing the same IDs to fail, got %v", err)
+	}
+}
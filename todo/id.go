@@ -1,9 +1,22 @@
 package todo
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/amonks/incrementum/internal/ids"
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+const (
+	// minDistinctIDPrefix is the prefix length a new todo ID must not share
+	// with any existing ID, so short prefixes typed on the command line stay
+	// unambiguous.
+	minDistinctIDPrefix = 4
+
+	// maxIDAttempts bounds how many candidate IDs Create generates before
+	// settling for one that is merely distinct.
+	maxIDAttempts = 16
 )
 
 // GenerateID creates a unique 8-character alphanumeric ID from a title and timestamp.
@@ -11,3 +24,77 @@ import (
 func GenerateID(title string, timestamp time.Time) string {
 	return ids.GenerateWithTimestamp(title, timestamp, ids.DefaultLength)
 }
+
+// generateID returns a candidate ID using the store's generator.
+func (s *Store) generateID(title string, timestamp time.Time) string {
+	if s.idGenerator != nil {
+		return s.idGenerator(title, timestamp)
+	}
+	return GenerateID(title, timestamp)
+}
+
+// uniqueTodoID picks an ID for a new todo that does not collide with the
+// existing todos. Candidates are regenerated with the timestamp advanced a
+// nanosecond at a time; the first that shares fewer than minDistinctIDPrefix
+// characters with every existing ID wins. If none does within maxIDAttempts,
+// the first candidate that is at least distinct is used.
+func (s *Store) uniqueTodoID(todos []Todo, title string, timestamp time.Time) (string, error) {
+	existing := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		existing = append(existing, todo.ID)
+	}
+	existing = ids.NormalizeUniqueIDs(existing)
+	taken := make(map[string]struct{}, len(existing))
+	for _, id := range existing {
+		taken[id] = struct{}{}
+	}
+
+	fallback := ""
+	for attempt := 0; attempt < maxIDAttempts; attempt++ {
+		candidate := s.generateID(title, timestamp.Add(time.Duration(attempt)))
+		normalized := internalstrings.NormalizeLower(candidate)
+		if _, ok := taken[normalized]; ok {
+			continue
+		}
+		if fallback == "" {
+			fallback = candidate
+		}
+		if !sharesIDPrefix(existing, normalized, minDistinctIDPrefix) {
+			return candidate, nil
+		}
+	}
+	if fallback == "" {
+		return "", fmt.Errorf("%w after %d attempts", ErrIDCollision, maxIDAttempts)
+	}
+	return fallback, nil
+}
+
+// sharesIDPrefix reports whether any of ids has the same first n characters
+// as id.
+func sharesIDPrefix(ids []string, id string, n int) bool {
+	if len(id) < n {
+		n = len(id)
+	}
+	for _, other := range ids {
+		if len(other) >= n && other[:n] == id[:n] {
+			return true
+		}
+	}
+	return false
+}
+
+// MinUniquePrefix returns the length of the shortest prefix that identifies
+// id among all todos in the store, including tombstones. It returns len(id)
+// when id is not in the store or the store cannot be read, so callers fall
+// back to showing the whole ID.
+func (s *Store) MinUniquePrefix(id string) int {
+	index, err := s.IDIndex()
+	if err != nil {
+		return len(id)
+	}
+	length, ok := index.PrefixLengths()[internalstrings.NormalizeLower(id)]
+	if !ok {
+		return len(id)
+	}
+	return length
+}
@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -66,3 +67,72 @@ func TestGenerateID_Uniqueness(t *testing.T) {
 		seen[id] = struct{}{}
 	}
 }
+
+func TestStore_CreateRegeneratesCollidingIDs(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	// The first candidate for every todo is the same; later candidates share
+	// a short prefix with "aaaa0000" until the third attempt.
+	candidates := []string{"aaaa0000", "aaaa1111", "bbbb0000"}
+	var attempts []time.Time
+	store.idGenerator = func(_ string, timestamp time.Time) string {
+		attempts = append(attempts, timestamp)
+		return candidates[min(len(attempts)-1, len(candidates)-1)]
+	}
+
+	first, err := store.Create("First", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create first todo: %v", err)
+	}
+	if first.ID != "aaaa0000" {
+		t.Fatalf("expected the first candidate, got %q", first.ID)
+	}
+
+	attempts = nil
+	second, err := store.Create("Second", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create second todo: %v", err)
+	}
+	if second.ID != "bbbb0000" {
+		t.Fatalf("expected the prefix-distinct candidate, got %q", second.ID)
+	}
+	if len(attempts) != 3 || attempts[1].Sub(attempts[0]) != time.Nanosecond {
+		t.Fatalf("expected 3 attempts a nanosecond apart, got %v", attempts)
+	}
+	if got := store.MinUniquePrefix(second.ID); got != 1 {
+		t.Fatalf("expected prefix length 1 for %s, got %d", second.ID, got)
+	}
+
+	store.idGenerator = func(string, time.Time) string { return "aaaa0000" }
+	if _, err := store.Create("Third", CreateOptions{}); !errors.Is(err, ErrIDCollision) {
+		t.Fatalf("expected ErrIDCollision, got %v", err)
+	}
+
+	// When every distinct candidate shares a prefix, the first distinct one
+	// is used anyway.
+	calls := 0
+	store.idGenerator = func(string, time.Time) string {
+		calls++
+		if calls%2 == 1 {
+			return "aaaa0000"
+		}
+		return "aaaa2222"
+	}
+	fourth, err := store.Create("Fourth", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create fourth todo: %v", err)
+	}
+	if fourth.ID != "aaaa2222" {
+		t.Fatalf("expected the distinct fallback, got %q", fourth.ID)
+	}
+	if got := store.MinUniquePrefix("AAAA0000"); got != 5 {
+		t.Fatalf("expected prefix length 5 for aaaa0000, got %d", got)
+	}
+	if got := store.MinUniquePrefix("zzzzzzzz"); got != 8 {
+		t.Fatalf("expected the full length for an unknown ID, got %d", got)
+	}
+}
@@ -0,0 +1,355 @@
+package todo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// markdownChecklistItem matches "- [ ] Title" lines, also accepting "*" and
+// "+" bullets and checked "[x]" boxes.
+var markdownChecklistItem = regexp.MustCompile(`^[-*+] \[([ xX])\](?:\s+(.*))?$`)
+
+// markdownMetadataLine matches the "id: <id>" and "depends-on: <ids>" lines
+// Export writes directly beneath an item.
+var markdownMetadataLine = regexp.MustCompile(`^(id|depends-on):\s*(.*)$`)
+
+// markdownDependencyEntry matches one depends-on entry: an ID optionally
+// followed by a parenthesized dependency type.
+var markdownDependencyEntry = regexp.MustCompile(`^(\S+)(?:\s+\(([^)]*)\))?$`)
+
+// markdownTabWidth is the number of columns a tab counts for when measuring
+// checklist indentation.
+const markdownTabWidth = 4
+
+// importedItem is a parsed checklist item awaiting creation.
+type importedItem struct {
+	title       string
+	description []string
+	priority    int
+	tags        []string
+	// parent is the index of the enclosing item, or -1 at the top level.
+	parent int
+	// indent is the column of the item's bullet.
+	indent int
+	// line is the item's line number, for errors.
+	line int
+	// id is the ID from an "id:" line, or empty to generate one.
+	id string
+	// dependsOn are the dependencies from a "depends-on:" line, with TodoID
+	// unset.
+	dependsOn []Dependency
+}
+
+// ImportMarkdown creates a todo for each unchecked "- [ ] Title" item in a
+// markdown checklist. Lines indented under an item become its description,
+// and an item depends on the items nested beneath it.
+//
+// "@priority:<name|0-4>" sets the priority and "#tag" adds a tag; both are
+// removed from the title. Checked items are skipped, and other lines outside
+// an item's description are ignored.
+//
+// The "id:" and "depends-on:" lines Export writes directly beneath an item
+// are read back, so an exported checklist imports with the same IDs and
+// dependencies. Dependencies on todos that are neither imported nor already
+// in the store (such as checked items) are dropped.
+//
+// The import is all-or-nothing: if any item fails validation or reuses an
+// existing ID, the error names its line and no todos are created.
+func (s *Store) ImportMarkdown(r io.Reader) ([]Todo, error) {
+	items, err := parseMarkdownChecklist(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	todos, err := s.readTodosWithContext()
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.readDependenciesWithContext()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(todos)+len(items))
+	for _, existing := range todos {
+		known[existing.ID] = struct{}{}
+	}
+
+	now := s.now()
+	created := make([]Todo, 0, len(items))
+	for i, item := range items {
+		// Offset each timestamp so IDs stay distinct and ready ordering
+		// follows the checklist.
+		createdAt := now.Add(time.Duration(i) * time.Nanosecond)
+		id := item.id
+		if id == "" {
+			id = GenerateID(item.title, createdAt)
+		} else if _, ok := known[id]; ok {
+			return nil, fmt.Errorf("line %d: todo %s already exists", item.line, id)
+		}
+		known[id] = struct{}{}
+		created = append(created, Todo{
+			ID:          id,
+			Title:       item.title,
+			Description: strings.Join(item.description, "\n"),
+			Status:      StatusOpen,
+			Priority:    item.priority,
+			Type:        TypeTask,
+			Tags:        item.tags,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		})
+	}
+	for i, item := range items {
+		if item.parent >= 0 {
+			deps = append(deps, Dependency{
+				TodoID:      created[item.parent].ID,
+				DependsOnID: created[i].ID,
+				CreatedAt:   now,
+			})
+		}
+		for _, dep := range item.dependsOn {
+			if _, ok := known[dep.DependsOnID]; !ok {
+				continue
+			}
+			dep.TodoID = created[i].ID
+			dep.CreatedAt = now
+			deps = append(deps, dep)
+		}
+	}
+
+	if err := s.writeTodos(append(todos, created...)); err != nil {
+		return nil, err
+	}
+	if err := s.writeDependencies(deps); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// parseMarkdownChecklist parses and validates every checklist item in r.
+func parseMarkdownChecklist(r io.Reader) ([]importedItem, error) {
+	type level struct {
+		indent int
+		// item is the index into items, or -1 for a skipped checked item.
+		item int
+	}
+
+	var (
+		items []importedItem
+		stack []level
+		// current is the item collecting description lines, or -1.
+		current = -1
+		// metadata is true until the current item's first description or
+		// blank line, while "id:" and "depends-on:" lines are accepted.
+		metadata bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := internalstrings.TrimTrailingWhitespace(scanner.Text())
+		indent, rest := splitMarkdownIndent(line)
+
+		match := markdownChecklistItem.FindStringSubmatch(rest)
+		if match == nil {
+			if rest == "" {
+				metadata = false
+				if current >= 0 && len(items[current].description) > 0 {
+					items[current].description = append(items[current].description, "")
+				}
+				continue
+			}
+			if current >= 0 && indent > items[current].indent && metadata {
+				if meta := markdownMetadataLine.FindStringSubmatch(rest); meta != nil {
+					if err := parseMarkdownMetadata(&items[current], meta[1], meta[2]); err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+					}
+					continue
+				}
+				metadata = false
+			}
+			if current >= 0 && indent > items[current].indent {
+				items[current].description = append(items[current].description, dedentMarkdown(line, items[current].indent+2))
+				continue
+			}
+			current = -1
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].item >= 0 {
+				parent = stack[i].item
+				break
+			}
+		}
+
+		if match[1] != " " {
+			stack = append(stack, level{indent: indent, item: -1})
+			current = -1
+			continue
+		}
+
+		item, err := parseMarkdownChecklistTitle(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		item.parent = parent
+		item.indent = indent
+		item.line = lineNumber
+		items = append(items, item)
+		current = len(items) - 1
+		metadata = true
+		stack = append(stack, level{indent: indent, item: current})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read markdown: %w", err)
+	}
+
+	for i := range items {
+		items[i].description = trimTrailingBlankLines(items[i].description)
+	}
+	return items, nil
+}
+
+// parseMarkdownMetadata applies an "id:" or "depends-on:" line to item.
+func parseMarkdownMetadata(item *importedItem, key, value string) error {
+	value = internalstrings.TrimSpace(value)
+	switch key {
+	case "id":
+		if value == "" || strings.ContainsAny(value, " \t") {
+			return fmt.Errorf("invalid todo id %q", value)
+		}
+		if item.id != "" {
+			return fmt.Errorf("duplicate id line")
+		}
+		item.id = internalstrings.NormalizeLower(value)
+	case "depends-on":
+		for _, entry := range strings.Split(value, ",") {
+			entry = internalstrings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			match := markdownDependencyEntry.FindStringSubmatch(entry)
+			if match == nil {
+				return fmt.Errorf("invalid dependency %q", entry)
+			}
+			depType := DependencyType(internalstrings.NormalizeLowerTrimSpace(match[2]))
+			if !depType.IsValid() {
+				return fmt.Errorf("%w: %q", ErrInvalidDependencyType, match[2])
+			}
+			item.dependsOn = append(item.dependsOn, Dependency{
+				DependsOnID: internalstrings.NormalizeLower(match[1]),
+				Type:        depType,
+			})
+		}
+	}
+	return nil
+}
+
+// parseMarkdownChecklistTitle extracts annotations from an item's text and
+// validates what remains as its title.
+func parseMarkdownChecklistTitle(text string) (importedItem, error) {
+	item := importedItem{priority: PriorityMedium}
+	var words []string
+	var tags []string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case strings.HasPrefix(word, "@priority:"):
+			priority, err := parsePriorityAnnotation(strings.TrimPrefix(word, "@priority:"))
+			if err != nil {
+				return item, err
+			}
+			item.priority = priority
+		case len(word) > 1 && strings.HasPrefix(word, "#"):
+			tags = append(tags, strings.TrimPrefix(word, "#"))
+		default:
+			words = append(words, word)
+		}
+	}
+
+	item.title = strings.Join(words, " ")
+	if err := ValidateTitle(item.title); err != nil {
+		return item, err
+	}
+	item.tags = normalizeTags(tags)
+	return item, nil
+}
+
+// parsePriorityAnnotation accepts a priority name (critical, high, medium,
+// low, backlog) or number.
+func parsePriorityAnnotation(value string) (int, error) {
+	normalized := internalstrings.NormalizeLower(value)
+	for priority := PriorityMin; priority <= PriorityMax; priority++ {
+		if PriorityName(priority) == normalized {
+			return priority, nil
+		}
+	}
+	priority, err := strconv.Atoi(normalized)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPriority, value)
+	}
+	if err := ValidatePriority(priority); err != nil {
+		return 0, err
+	}
+	return priority, nil
+}
+
+// splitMarkdownIndent returns the column width of line's leading whitespace
+// and the text after it.
+func splitMarkdownIndent(line string) (int, string) {
+	indent := 0
+	for i, r := range line {
+		switch r {
+		case ' ':
+			indent++
+		case '\t':
+			indent += markdownTabWidth
+		default:
+			return indent, line[i:]
+		}
+	}
+	return indent, ""
+}
+
+// dedentMarkdown removes up to width columns of leading whitespace.
+func dedentMarkdown(line string, width int) string {
+	removed := 0
+	for i, r := range line {
+		if removed >= width {
+			return line[i:]
+		}
+		switch r {
+		case ' ':
+			removed++
+		case '\t':
+			removed += markdownTabWidth
+		default:
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+func trimTrailingBlankLines(lines []string) []string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
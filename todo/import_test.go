@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStore_ImportMarkdown(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	input := strings.Join([]string{
+		"# Onboarding",
+		"",
+		"- [ ] Ship the importer @priority:high #Tooling",
+		"  Parse checklists into todos.",
+		"",
+		"  Keep it all-or-nothing.",
+		"  - [ ] Write the parser #tooling",
+		"  - [x] Already done",
+		"    - [ ] Nested under a checked item",
+		"- [ ] Document it @priority:4",
+		"Some trailing prose.",
+	}, "\n")
+
+	imported, err := store.ImportMarkdown(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if len(imported) != 4 {
+		t.Fatalf("expected 4 imported todos, got %d: %+v", len(imported), imported)
+	}
+
+	ship, parser, nested, docs := imported[0], imported[1], imported[2], imported[3]
+	if ship.Title != "Ship the importer" || ship.Priority != PriorityHigh {
+		t.Fatalf("unexpected first todo: %+v", ship)
+	}
+	if got := strings.Join(ship.Tags, ","); got != "tooling" {
+		t.Fatalf("expected tooling tag, got %q", got)
+	}
+	if ship.Description != "Parse checklists into todos.\n\nKeep it all-or-nothing." {
+		t.Fatalf("unexpected description: %q", ship.Description)
+	}
+	if parser.Title != "Write the parser" || parser.Priority != PriorityMedium {
+		t.Fatalf("unexpected nested todo: %+v", parser)
+	}
+	if nested.Title != "Nested under a checked item" {
+		t.Fatalf("unexpected todo under checked item: %+v", nested)
+	}
+	if docs.Title != "Document it" || docs.Priority != PriorityBacklog || docs.Description != "" {
+		t.Fatalf("unexpected last todo: %+v", docs)
+	}
+
+	deps, err := store.readDependencies()
+	if err != nil {
+		t.Fatalf("failed to read dependencies: %v", err)
+	}
+	edges := make(map[string]bool)
+	for _, dep := range deps {
+		edges[dep.TodoID+"->"+dep.DependsOnID] = true
+	}
+	if len(deps) != 2 || !edges[ship.ID+"->"+parser.ID] || !edges[ship.ID+"->"+nested.ID] {
+		t.Fatalf("expected ship to depend on its nested items, got %+v", deps)
+	}
+
+	ready, err := store.Ready(0)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	for _, item := range ready {
+		if item.ID == ship.ID {
+			t.Fatalf("expected parent item to be blocked by its nested items")
+		}
+	}
+}
+
+func TestStore_ImportMarkdownIsAllOrNothing(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	input := strings.Join([]string{
+		"- [ ] Valid item",
+		"- [ ] Bad priority @priority:urgent",
+	}, "\n")
+
+	_, err = store.ImportMarkdown(strings.NewReader(input))
+	if !errors.Is(err, ErrInvalidPriority) {
+		t.Fatalf("expected ErrInvalidPriority, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected error to name line 2, got %v", err)
+	}
+
+	_, err = store.ImportMarkdown(strings.NewReader("- [ ] #only-tags"))
+	if !errors.Is(err, ErrEmptyTitle) {
+		t.Fatalf("expected ErrEmptyTitle, got %v", err)
+	}
+
+	todos, err := store.readTodos()
+	if err != nil {
+		t.Fatalf("failed to read todos: %v", err)
+	}
+	if len(todos) != 0 {
+		t.Fatalf("expected failed imports to create nothing, got %d todos", len(todos))
+	}
+}
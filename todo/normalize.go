@@ -6,6 +6,25 @@ func normalizeStatus(status Status) Status {
 	return Status(internalstrings.NormalizeLower(string(status)))
 }
 
+// normalizeTags lowercases and trims tags, dropping blanks and duplicates
+// while keeping first-seen order. It returns nil when no tags remain.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = internalstrings.NormalizeLowerTrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
 func normalizeTodoType(todoType TodoType) TodoType {
 	return TodoType(internalstrings.NormalizeLower(string(todoType)))
 }
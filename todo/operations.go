@@ -33,8 +33,24 @@ type CreateOptions struct {
 	// ProjectReviewModel selects the opencode model for project review.
 	ProjectReviewModel string
 
+	// Profile names the config profile supplying default models.
+	Profile string
+
+	// DesignDoc is an optional repo-relative path to a design document.
+	DesignDoc string
+
 	// Dependencies is a list of dependency IDs.
 	Dependencies []string
+
+	// Tags group the todo by area. They are lowercased and deduplicated.
+	Tags []string
+
+	// DueAt is an optional deadline.
+	DueAt *time.Time
+
+	// EstimateMinutes is the expected effort in minutes. Zero means no
+	// estimate.
+	EstimateMinutes int
 }
 
 // Create creates a new todo with the given title.
@@ -82,12 +98,19 @@ func (s *Store) Create(title string, opts CreateOptions) (*Todo, error) {
 		deps = append(deps, depID)
 	}
 
-	now := time.Now()
+	designDoc := internalstrings.TrimSpace(opts.DesignDoc)
+	if err := ValidateDesignDoc(designDoc); err != nil {
+		return nil, err
+	}
+	if err := ValidateEstimate(opts.EstimateMinutes); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
 	implementationModel := internalstrings.TrimSpace(opts.ImplementationModel)
 	codeReviewModel := internalstrings.TrimSpace(opts.CodeReviewModel)
 	projectReviewModel := internalstrings.TrimSpace(opts.ProjectReviewModel)
 	todo := Todo{
-		ID:                  GenerateID(title, now),
 		Title:               title,
 		Description:         opts.Description,
 		Status:              normalizedStatus,
@@ -96,6 +119,11 @@ func (s *Store) Create(title string, opts CreateOptions) (*Todo, error) {
 		ImplementationModel: implementationModel,
 		CodeReviewModel:     codeReviewModel,
 		ProjectReviewModel:  projectReviewModel,
+		Profile:             internalstrings.TrimSpace(opts.Profile),
+		DesignDoc:           designDoc,
+		Tags:                normalizeTags(opts.Tags),
+		DueAt:               opts.DueAt,
+		EstimateMinutes:     opts.EstimateMinutes,
 		CreatedAt:           now,
 		UpdatedAt:           now,
 	}
@@ -105,6 +133,10 @@ func (s *Store) Create(title string, opts CreateOptions) (*Todo, error) {
 	if err != nil {
 		return nil, err
 	}
+	todo.ID, err = s.uniqueTodoID(todos, title, now)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(deps) > 0 {
 		resolvedIDs, err := resolveTodoIDsWithTodos(deps, todos)
@@ -166,6 +198,11 @@ type UpdateOptions struct {
 	ImplementationModel *string
 	CodeReviewModel     *string
 	ProjectReviewModel  *string
+	Profile             *string
+	DesignDoc           *string
+	Tags                *[]string
+	DueAt               *time.Time
+	EstimateMinutes     *int
 	DeletedAt           *time.Time
 	DeleteReason        *string
 	Source              *string
@@ -208,7 +245,7 @@ func (s *Store) Update(ids []string, opts UpdateOptions) ([]Todo, error) {
 	// Build a set of IDs to update
 	idSet := idSetFromIDs(resolvedIDs)
 
-	now := time.Now()
+	now := s.now()
 	updated := make([]Todo, 0, len(resolvedIDs))
 
 	for i := range todos {
@@ -264,7 +301,7 @@ func (s *Store) Start(ids []string) ([]Todo, error) {
 // Delete tombstones one or more todos with an optional reason.
 func (s *Store) Delete(ids []string, reason string) ([]Todo, error) {
 	status := StatusTombstone
-	now := time.Now()
+	now := s.now()
 	opts := UpdateOptions{
 		Status:    &status,
 		DeletedAt: &now,
@@ -275,6 +312,103 @@ func (s *Store) Delete(ids []string, reason string) ([]Todo, error) {
 	return s.Update(ids, opts)
 }
 
+// Archive moves one or more todos out of default listings without deleting
+// them. Timestamps from before archiving are kept.
+func (s *Store) Archive(ids []string) ([]Todo, error) {
+	return s.updateStatus(ids, StatusArchived)
+}
+
+// Unarchive returns one or more archived todos to the status they had before
+// they were archived, or to open for todos archived before that status was
+// recorded. It returns ErrNotArchived without changing anything if any of the
+// todos isn't archived.
+func (s *Store) Unarchive(ids []string) ([]Todo, error) {
+	todos, resolvedIDs, err := s.readTodosAndResolveIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	idSet := idSetFromIDs(resolvedIDs)
+	now := s.now()
+	updated := make([]Todo, 0, len(resolvedIDs))
+
+	for i := range todos {
+		if _, ok := idSet[todos[i].ID]; !ok {
+			continue
+		}
+		delete(idSet, todos[i].ID)
+
+		if todos[i].Status != StatusArchived {
+			return nil, fmt.Errorf("%w: %s", ErrNotArchived, todos[i].ID)
+		}
+		restoreArchived(&todos[i], now)
+		updated = append(updated, todos[i])
+	}
+
+	if err := missingTodoIDsError(missingTodoIDsInOrder(resolvedIDs, idSet)); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeTodos(todos); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// restoreArchived puts an archived todo back in the status it was archived
+// from. Archiving kept its timestamps, so they still match that status.
+// Todos archived while in progress are reopened instead: no job owns them
+// any more. Tombstones can't be archived, so one recorded here is stale data
+// without deletion metadata and is reopened too.
+func restoreArchived(item *Todo, now time.Time) {
+	previous := item.ArchivedFrom
+	switch previous {
+	case "", StatusArchived, StatusInProgress, StatusTombstone:
+		previous = ""
+	}
+	if previous == "" || !previous.IsValid() {
+		applyStatusChange(item, StatusOpen, item.Status, UpdateOptions{}, now)
+	} else {
+		item.Status = previous
+		item.ArchivedFrom = ""
+	}
+	item.UpdatedAt = now
+}
+
+// AddNote appends a note to a todo's notes log and returns it.
+// Notes are never rewritten, so they survive status changes.
+func (s *Store) AddNote(todoID, author, text string) (TodoNote, error) {
+	text = internalstrings.TrimTrailingWhitespace(text)
+	if internalstrings.IsBlank(text) {
+		return TodoNote{}, ErrEmptyNote
+	}
+
+	todos, resolvedIDs, err := s.readTodosAndResolveIDs([]string{todoID})
+	if err != nil {
+		return TodoNote{}, err
+	}
+
+	now := s.now()
+	note := TodoNote{
+		Author:    internalstrings.TrimSpace(author),
+		CreatedAt: now,
+		Text:      text,
+	}
+	for i := range todos {
+		if todos[i].ID != resolvedIDs[0] {
+			continue
+		}
+		todos[i].Notes = append(todos[i].Notes, note)
+		todos[i].UpdatedAt = now
+		if err := s.writeTodos(todos); err != nil {
+			return TodoNote{}, err
+		}
+		return note, nil
+	}
+	return TodoNote{}, fmt.Errorf("%w: %s", ErrTodoNotFound, resolvedIDs[0])
+}
+
 // Show returns the full details of one or more todos.
 func (s *Store) Show(ids []string) ([]Todo, error) {
 	if err := validateTodoIDs(ids); err != nil {
@@ -332,8 +466,36 @@ type ListFilter struct {
 	// DescriptionSubstring filters to todos with this substring in the description.
 	DescriptionSubstring string
 
+	// Tags filters to todos carrying any of these tags, or all of them when
+	// MatchAllTags is set. Tags are matched case-insensitively.
+	Tags []string
+
+	// MatchAllTags requires every tag in Tags rather than any one of them.
+	MatchAllTags bool
+
+	// DueBefore filters to todos due before this time.
+	DueBefore *time.Time
+
+	// Overdue filters to unresolved todos whose due date has passed.
+	Overdue bool
+
+	// CreatedAfter filters to todos created at or after this time.
+	CreatedAfter *time.Time
+
+	// CreatedBefore filters to todos created before this time.
+	CreatedBefore *time.Time
+
+	// UpdatedAfter filters to todos updated at or after this time.
+	UpdatedAfter *time.Time
+
+	// UpdatedBefore filters to todos updated before this time.
+	UpdatedBefore *time.Time
+
 	// IncludeTombstones includes soft-deleted todos. Default is false.
 	IncludeTombstones bool
+
+	// IncludeArchived includes archived todos. Default is false.
+	IncludeArchived bool
 }
 
 // List returns todos matching the filter.
@@ -372,6 +534,8 @@ func (s *Store) listWithTodos(filter ListFilter) ([]Todo, []Todo, error) {
 
 	titleQuery := internalstrings.NormalizeLower(filter.TitleSubstring)
 	descriptionQuery := internalstrings.NormalizeLower(filter.DescriptionSubstring)
+	tagQuery := normalizeTags(filter.Tags)
+	now := s.now()
 
 	todos, err := s.readTodosWithContext()
 	if err != nil {
@@ -393,12 +557,20 @@ func (s *Store) listWithTodos(filter ListFilter) ([]Todo, []Todo, error) {
 		includeTombstones = true
 	}
 
+	includeArchived := filter.IncludeArchived
+	if filter.Status != nil && *filter.Status == StatusArchived {
+		includeArchived = true
+	}
+
 	result := make([]Todo, 0, len(todos))
 	for _, todo := range todos {
-		// Filter tombstones unless explicitly included
+		// Filter tombstones and archived todos unless explicitly included
 		if todo.Status == StatusTombstone && !includeTombstones {
 			continue
 		}
+		if todo.Status == StatusArchived && !includeArchived {
+			continue
+		}
 
 		// Apply filters
 		if filter.Status != nil && todo.Status != *filter.Status {
@@ -421,6 +593,21 @@ func (s *Store) listWithTodos(filter ListFilter) ([]Todo, []Todo, error) {
 		if !containsLower(todo.Description, descriptionQuery) {
 			continue
 		}
+		if !matchesTags(todo.Tags, tagQuery, filter.MatchAllTags) {
+			continue
+		}
+		if filter.DueBefore != nil && (todo.DueAt == nil || !todo.DueAt.Before(*filter.DueBefore)) {
+			continue
+		}
+		if filter.Overdue && !todo.IsOverdue(now) {
+			continue
+		}
+		if !withinTimeRange(todo.CreatedAt, filter.CreatedAfter, filter.CreatedBefore) {
+			continue
+		}
+		if !withinTimeRange(todo.UpdatedAt, filter.UpdatedAfter, filter.UpdatedBefore) {
+			continue
+		}
 
 		result = append(result, todo)
 	}
@@ -428,6 +615,18 @@ func (s *Store) listWithTodos(filter ListFilter) ([]Todo, []Todo, error) {
 	return result, todos, nil
 }
 
+// withinTimeRange reports whether t is at or after after and before before.
+// Nil bounds are open.
+func withinTimeRange(t time.Time, after, before *time.Time) bool {
+	if after != nil && t.Before(*after) {
+		return false
+	}
+	if before != nil && !t.Before(*before) {
+		return false
+	}
+	return true
+}
+
 func containsLower(haystack, needle string) bool {
 	if needle == "" {
 		return true
@@ -435,6 +634,30 @@ func containsLower(haystack, needle string) bool {
 	return strings.Contains(internalstrings.NormalizeLower(haystack), needle)
 }
 
+// matchesTags reports whether tags include any of query, or all of query
+// when matchAll is set. An empty query matches everything.
+func matchesTags(tags, query []string, matchAll bool) bool {
+	if len(query) == 0 {
+		return true
+	}
+	for _, want := range query {
+		found := false
+		for _, tag := range tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if found && !matchAll {
+			return true
+		}
+		if !found && matchAll {
+			return false
+		}
+	}
+	return matchAll
+}
+
 func idSetFromIDs(ids []string) map[string]struct{} {
 	if len(ids) == 0 {
 		return nil
@@ -539,6 +762,14 @@ func collectTodosByIDs(ids []string, lookup func(string) (Todo, bool)) ([]Todo,
 
 func applyStatusChange(item *Todo, newStatus Status, previousStatus Status, opts UpdateOptions, now time.Time) {
 	item.Status = newStatus
+	if newStatus == StatusArchived {
+		// Archiving keeps the todo's history; only deletion metadata goes.
+		item.ArchivedFrom = previousStatus
+		item.DeletedAt = nil
+		item.DeleteReason = ""
+		return
+	}
+	item.ArchivedFrom = ""
 	if newStatus != StatusDone {
 		item.StartedAt = nil
 		item.CompletedAt = nil
@@ -581,6 +812,9 @@ func applyTodoUpdates(item *Todo, opts UpdateOptions, now time.Time) error {
 	}
 	if opts.Status != nil {
 		newStatus := *opts.Status
+		if newStatus == StatusArchived && item.Status == StatusTombstone {
+			return ErrArchiveTombstone
+		}
 		if newStatus != item.Status {
 			applyStatusChange(item, newStatus, item.Status, opts, now)
 		}
@@ -600,6 +834,26 @@ func applyTodoUpdates(item *Todo, opts UpdateOptions, now time.Time) error {
 	if opts.ProjectReviewModel != nil {
 		item.ProjectReviewModel = internalstrings.TrimSpace(*opts.ProjectReviewModel)
 	}
+	if opts.Profile != nil {
+		item.Profile = internalstrings.TrimSpace(*opts.Profile)
+	}
+	if opts.DesignDoc != nil {
+		item.DesignDoc = internalstrings.TrimSpace(*opts.DesignDoc)
+	}
+	if opts.Tags != nil {
+		item.Tags = normalizeTags(*opts.Tags)
+	}
+	if opts.DueAt != nil {
+		if opts.DueAt.IsZero() {
+			item.DueAt = nil
+		} else {
+			dueAt := *opts.DueAt
+			item.DueAt = &dueAt
+		}
+	}
+	if opts.EstimateMinutes != nil {
+		item.EstimateMinutes = *opts.EstimateMinutes
+	}
 	if opts.DeletedAt != nil {
 		item.DeletedAt = opts.DeletedAt
 	}
@@ -622,6 +876,7 @@ func applyTodoUpdates(item *Todo, opts UpdateOptions, now time.Time) error {
 
 type readyHeap struct {
 	items []Todo
+	less  func(left, right Todo) bool
 }
 
 func (h readyHeap) Len() int {
@@ -629,7 +884,7 @@ func (h readyHeap) Len() int {
 }
 
 func (h readyHeap) Less(i, j int) bool {
-	return readyLess(h.items[j], h.items[i])
+	return h.less(h.items[j], h.items[i])
 }
 
 func (h readyHeap) Swap(i, j int) {
@@ -656,22 +911,63 @@ func readyLess(left, right Todo) bool {
 	return left.CreatedAt.Before(right.CreatedAt)
 }
 
+// readyLessByDue orders like readyLess, but breaks priority ties by due
+// date, earliest first, with undated todos after dated ones.
+func readyLessByDue(left, right Todo) bool {
+	if left.Priority != right.Priority {
+		return left.Priority < right.Priority
+	}
+	switch {
+	case left.DueAt != nil && right.DueAt != nil:
+		if !left.DueAt.Equal(*right.DueAt) {
+			return left.DueAt.Before(*right.DueAt)
+		}
+	case left.DueAt != nil:
+		return true
+	case right.DueAt != nil:
+		return false
+	}
+	return readyLess(left, right)
+}
+
+// ReadyOptions configures ReadyWithOptions.
+type ReadyOptions struct {
+	// Limit caps the number of todos returned. Zero means no limit.
+	Limit int
+
+	// DueDateTiebreak orders todos of equal priority by due date, earliest
+	// first, before the usual type and age ordering.
+	DueDateTiebreak bool
+}
+
 // Ready returns open todos with no unresolved blockers, sorted by priority.
 func (s *Store) Ready(limit int) ([]Todo, error) {
-	ready, _, err := s.readyWithTodos(limit)
+	ready, _, err := s.readyWithTodos(ReadyOptions{Limit: limit})
 	return ready, err
 }
 
 // ReadyWithIndex returns ready todos plus a full ID index.
 func (s *Store) ReadyWithIndex(limit int) ([]Todo, IDIndex, error) {
-	ready, todos, err := s.readyWithTodos(limit)
+	return s.ReadyWithOptions(ReadyOptions{Limit: limit})
+}
+
+// ReadyWithOptions returns ready todos ordered according to opts, plus a
+// full ID index.
+func (s *Store) ReadyWithOptions(opts ReadyOptions) ([]Todo, IDIndex, error) {
+	ready, todos, err := s.readyWithTodos(opts)
 	if err != nil {
 		return nil, IDIndex{}, err
 	}
 	return ready, NewIDIndex(todos), nil
 }
 
-func (s *Store) readyWithTodos(limit int) ([]Todo, []Todo, error) {
+func (s *Store) readyWithTodos(opts ReadyOptions) ([]Todo, []Todo, error) {
+	limit := opts.Limit
+	less := readyLess
+	if opts.DueDateTiebreak {
+		less = readyLessByDue
+	}
+
 	todos, err := s.readTodosWithContext()
 	if err != nil {
 		return nil, nil, err
@@ -689,7 +985,7 @@ func (s *Store) readyWithTodos(limit int) ([]Todo, []Todo, error) {
 	var selection readyHeap
 	useLimit := limit > 0
 	if useLimit {
-		selection = readyHeap{items: make([]Todo, 0, limit)}
+		selection = readyHeap{items: make([]Todo, 0, limit), less: less}
 	} else {
 		ready = make([]Todo, 0, len(todos))
 	}
@@ -706,7 +1002,7 @@ func (s *Store) readyWithTodos(limit int) ([]Todo, []Todo, error) {
 				heap.Push(&selection, todo)
 				continue
 			}
-			if readyLess(todo, selection.items[0]) {
+			if less(todo, selection.items[0]) {
 				selection.items[0] = todo
 				heap.Fix(&selection, 0)
 			}
@@ -721,7 +1017,7 @@ func (s *Store) readyWithTodos(limit int) ([]Todo, []Todo, error) {
 
 	// Sort by priority (0 = highest priority)
 	sort.Slice(ready, func(i, j int) bool {
-		return readyLess(ready[i], ready[j])
+		return less(ready[i], ready[j])
 	})
 
 	// Apply limit
@@ -739,7 +1035,9 @@ func blockedTodoIDs(todos []Todo, deps []Dependency) map[string]struct{} {
 
 	dependsOn := make(map[string]struct{}, len(deps))
 	for _, dep := range deps {
-		dependsOn[dep.DependsOnID] = struct{}{}
+		if dep.IsBlocking() {
+			dependsOn[dep.DependsOnID] = struct{}{}
+		}
 	}
 	unresolved := make(map[string]struct{})
 	for _, todo := range todos {
@@ -752,6 +1050,9 @@ func blockedTodoIDs(todos []Todo, deps []Dependency) map[string]struct{} {
 	}
 	blocked := make(map[string]struct{}, len(deps))
 	for _, dep := range deps {
+		if !dep.IsBlocking() {
+			continue
+		}
 		if _, ok := unresolved[dep.DependsOnID]; ok {
 			blocked[dep.TodoID] = struct{}{}
 		}
@@ -762,8 +1063,17 @@ func blockedTodoIDs(todos []Todo, deps []Dependency) map[string]struct{} {
 	return blocked
 }
 
-// DepAdd adds a dependency between two todos.
+// DepAdd adds a blocking dependency between two todos.
 func (s *Store) DepAdd(todoID, dependsOnID string) (*Dependency, error) {
+	return s.DepAddWithOptions(todoID, dependsOnID, DepAddOptions{})
+}
+
+// DepAddWithOptions adds a dependency of the given type between two todos.
+func (s *Store) DepAddWithOptions(todoID, dependsOnID string, opts DepAddOptions) (*Dependency, error) {
+	if !opts.Type.IsValid() {
+		return nil, formatInvalidDependencyTypeError(opts.Type)
+	}
+
 	resolvedIDs, err := s.resolveTodoIDs([]string{todoID, dependsOnID})
 	if err != nil {
 		return nil, err
@@ -789,11 +1099,18 @@ func (s *Store) DepAdd(todoID, dependsOnID string) (*Dependency, error) {
 		}
 	}
 
+	if opts.Type.IsBlocking() {
+		if err := checkDependencyCycle(deps, todoID, dependsOnID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add new dependency
 	dep := Dependency{
 		TodoID:      todoID,
 		DependsOnID: dependsOnID,
-		CreatedAt:   time.Now(),
+		Type:        opts.Type,
+		CreatedAt:   s.now(),
 	}
 	deps = append(deps, dep)
 
@@ -804,8 +1121,82 @@ func (s *Store) DepAdd(todoID, dependsOnID string) (*Dependency, error) {
 	return &dep, nil
 }
 
+// DepRemove removes the dependency of todoID on dependsOnID.
+// It returns ErrDependencyNotFound when no such dependency exists.
+func (s *Store) DepRemove(todoID, dependsOnID string) error {
+	resolvedIDs, err := s.resolveTodoIDs([]string{todoID, dependsOnID})
+	if err != nil {
+		return err
+	}
+	todoID = resolvedIDs[0]
+	dependsOnID = resolvedIDs[1]
+
+	deps, err := s.readDependenciesWithContext()
+	if err != nil {
+		return err
+	}
+
+	kept := deps[:0]
+	for _, d := range deps {
+		if d.TodoID == todoID && d.DependsOnID == dependsOnID {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	if len(kept) == len(deps) {
+		return fmt.Errorf("%w: %s depends on %s", ErrDependencyNotFound, todoID, dependsOnID)
+	}
+
+	return s.writeDependencies(kept)
+}
+
+// Dependents returns the todos that depend on the given todo, excluding
+// tombstoned ones.
+func (s *Store) Dependents(todoID string) ([]Todo, error) {
+	return s.DependentsWithOptions(todoID, DependentsOptions{})
+}
+
+// DependentsWithOptions returns the todos that depend on the given todo,
+// in store order.
+func (s *Store) DependentsWithOptions(todoID string, opts DependentsOptions) ([]Todo, error) {
+	todos, resolvedIDs, err := s.readTodosAndResolveIDs([]string{todoID})
+	if err != nil {
+		return nil, err
+	}
+	todoID = resolvedIDs[0]
+
+	deps, err := s.readDependenciesWithContext()
+	if err != nil {
+		return nil, err
+	}
+
+	dependentIDs := make(map[string]struct{})
+	for _, d := range deps {
+		if d.DependsOnID == todoID {
+			dependentIDs[d.TodoID] = struct{}{}
+		}
+	}
+
+	dependents := make([]Todo, 0, len(dependentIDs))
+	for _, todo := range todos {
+		if _, ok := dependentIDs[todo.ID]; !ok {
+			continue
+		}
+		if todo.Status == StatusTombstone && !opts.IncludeTombstones {
+			continue
+		}
+		dependents = append(dependents, todo)
+	}
+	return dependents, nil
+}
+
 // DepTree returns the dependency tree for a todo.
-func (s *Store) DepTree(id string) (*DepTreeNode, error) {
+// Each todo is expanded at most once; later occurrences are marked SeeAbove,
+// so shared dependencies and cycles render in bounded space.
+func (s *Store) DepTree(id string, opts DepTreeOptions) (*DepTreeNode, error) {
+	if opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("invalid depth %d: must be non-negative", opts.MaxDepth)
+	}
 	todos, resolvedIDs, err := s.readTodosAndResolveIDs([]string{id})
 	if err != nil {
 		return nil, err
@@ -838,34 +1229,60 @@ func (s *Store) DepTree(id string) (*DepTreeNode, error) {
 		return nil, ErrTodoNotFound
 	}
 
-	// Build tree recursively
-	path := make(map[string]struct{})
-	return buildDepTree(rootTodo, depsByTodo, todoMap, path), nil
+	builder := depTreeBuilder{
+		depsByTodo: depsByTodo,
+		todoMap:    todoMap,
+		maxDepth:   opts.MaxDepth,
+		visited:    make(map[string]struct{}),
+	}
+	return builder.build(rootTodo, 0), nil
+}
+
+type depTreeBuilder struct {
+	depsByTodo map[string][]Dependency
+	todoMap    map[string]*Todo
+	maxDepth   int
+	visited    map[string]struct{}
 }
 
-// buildDepTree recursively builds a dependency tree node.
+// build recursively builds a dependency tree node in depth-first order.
+func (b *depTreeBuilder) build(todo *Todo, depth int) *DepTreeNode {
+	if _, ok := b.visited[todo.ID]; ok {
+		return &DepTreeNode{Todo: todo, SeeAbove: true}
+	}
 
-func buildDepTree(todo *Todo, depsByTodo map[string][]Dependency, todoMap map[string]*Todo, path map[string]struct{}) *DepTreeNode {
-	if _, ok := path[todo.ID]; ok {
-		// Avoid cycles
-		return &DepTreeNode{Todo: todo}
+	children := b.children(todo.ID)
+	if b.maxDepth > 0 && depth >= b.maxDepth {
+		// Leave truncated todos unvisited so a shallower occurrence can
+		// still expand them.
+		return &DepTreeNode{Todo: todo, Truncated: len(children) > 0}
 	}
-	path[todo.ID] = struct{}{}
-	defer delete(path, todo.ID)
+	b.visited[todo.ID] = struct{}{}
 
 	node := &DepTreeNode{
 		Todo:     todo,
-		Children: make([]*DepTreeNode, 0, len(depsByTodo[todo.ID])),
+		Children: make([]*DepTreeNode, 0, len(children)),
 	}
-
-	for _, dep := range depsByTodo[todo.ID] {
-		childTodo, ok := todoMap[dep.DependsOnID]
-		if !ok {
-			continue
-		}
-		childNode := buildDepTree(childTodo, depsByTodo, todoMap, path)
+	for _, child := range children {
+		childNode := b.build(child.todo, depth+1)
+		childNode.Type = child.depType
 		node.Children = append(node.Children, childNode)
 	}
-
 	return node
 }
+
+type depTreeChild struct {
+	todo    *Todo
+	depType DependencyType
+}
+
+func (b *depTreeBuilder) children(id string) []depTreeChild {
+	deps := b.depsByTodo[id]
+	children := make([]depTreeChild, 0, len(deps))
+	for _, dep := range deps {
+		if child, ok := b.todoMap[dep.DependsOnID]; ok {
+			children = append(children, depTreeChild{todo: child, depType: dep.Type})
+		}
+	}
+	return children
+}
@@ -92,6 +92,73 @@ func TestStore_Create_WithModels(t *testing.T) {
 	}
 }
 
+func TestStore_Profile(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, err := store.Create("Tune prompts", CreateOptions{Profile: " thorough "})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+
+	items, err := store.Show([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	if items[0].Profile != "thorough" {
+		t.Fatalf("expected persisted trimmed profile, got %q", items[0].Profile)
+	}
+
+	empty := ""
+	updated, err := store.Update([]string{created.ID}, UpdateOptions{Profile: &empty})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if updated[0].Profile != "" {
+		t.Fatalf("expected profile to be cleared, got %q", updated[0].Profile)
+	}
+}
+
+func TestStore_DesignDoc(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, err := store.Create("Build queue", CreateOptions{DesignDoc: " docs/queue.md "})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	if created.DesignDoc != "docs/queue.md" {
+		t.Fatalf("expected trimmed design doc, got %q", created.DesignDoc)
+	}
+
+	items, err := store.Show([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	if items[0].DesignDoc != "docs/queue.md" {
+		t.Fatalf("expected persisted design doc, got %q", items[0].DesignDoc)
+	}
+
+	empty := ""
+	updated, err := store.Update([]string{created.ID}, UpdateOptions{DesignDoc: &empty})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if updated[0].DesignDoc != "" {
+		t.Fatalf("expected cleared design doc, got %q", updated[0].DesignDoc)
+	}
+
+	if _, err := store.Create("Bad doc", CreateOptions{DesignDoc: "/tmp/doc.md"}); !errors.Is(err, ErrInvalidDesignDoc) {
+		t.Fatalf("expected ErrInvalidDesignDoc, got %v", err)
+	}
+}
+
 func TestStore_Create_WithStatus(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
@@ -1119,6 +1186,47 @@ func TestStore_Ready_WithBlockers(t *testing.T) {
 	}
 }
 
+func TestStore_Ready_DiscoveredFromDoesNotBlock(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	origin, _ := store.Create("Origin", CreateOptions{})
+	discovered, _ := store.Create("Discovered", CreateOptions{})
+
+	dep, err := store.DepAddWithOptions(discovered.ID, origin.ID, DepAddOptions{Type: DepTypeDiscoveredFrom})
+	if err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+	if dep.Type != DepTypeDiscoveredFrom {
+		t.Fatalf("expected discovered-from dependency, got %q", dep.Type)
+	}
+
+	ready, err := store.Ready(10)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	foundDiscovered := false
+	for _, r := range ready {
+		if r.ID == discovered.ID {
+			foundDiscovered = true
+		}
+	}
+	if !foundDiscovered {
+		t.Error("expected todo with only a discovered-from dependency on an open todo to be ready")
+	}
+
+	deps, err := store.readDependencies()
+	if err != nil {
+		t.Fatalf("failed to read dependencies: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Type != DepTypeDiscoveredFrom {
+		t.Fatalf("expected the dependency type to be persisted, got %+v", deps)
+	}
+}
+
 func TestStore_Ready_IgnoresTombstonedBlockers(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
@@ -1225,6 +1333,241 @@ func TestStore_Ready_Limit(t *testing.T) {
 	}
 }
 
+func TestStore_Tags(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, err := store.Create("Tagged", CreateOptions{Tags: []string{"Frontend", " infra ", "frontend", ""}})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	if got := strings.Join(created.Tags, ","); got != "frontend,infra" {
+		t.Fatalf("expected normalized tags, got %q", got)
+	}
+
+	tags := []string{"INFRA", "docs", "infra"}
+	updated, err := store.Update([]string{created.ID}, UpdateOptions{Tags: &tags})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if got := strings.Join(updated[0].Tags, ","); got != "infra,docs" {
+		t.Fatalf("expected updated tags, got %q", got)
+	}
+
+	shown, err := store.Show([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	if got := strings.Join(shown[0].Tags, ","); got != "infra,docs" {
+		t.Fatalf("expected persisted tags, got %q", got)
+	}
+
+	// Title-only updates leave tags alone.
+	title := "Renamed"
+	updated, err = store.Update([]string{created.ID}, UpdateOptions{Title: &title})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if len(updated[0].Tags) != 2 {
+		t.Fatalf("expected tags to be kept, got %v", updated[0].Tags)
+	}
+}
+
+func TestStore_List_FilterByTags(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	both, _ := store.Create("Both", CreateOptions{Tags: []string{"frontend", "infra"}})
+	frontend, _ := store.Create("Frontend", CreateOptions{Tags: []string{"frontend"}})
+	store.Create("Untagged", CreateOptions{})
+
+	anyMatch, err := store.List(ListFilter{Tags: []string{"Frontend", "infra"}})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(anyMatch) != 2 || anyMatch[0].ID != both.ID || anyMatch[1].ID != frontend.ID {
+		t.Fatalf("expected both tagged todos, got %+v", anyMatch)
+	}
+
+	allMatch, err := store.List(ListFilter{Tags: []string{"frontend", "infra"}, MatchAllTags: true})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(allMatch) != 1 || allMatch[0].ID != both.ID {
+		t.Fatalf("expected only the todo with every tag, got %+v", allMatch)
+	}
+}
+
+func TestStore_DueAt(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	due := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	created, err := store.Create("Deadline", CreateOptions{DueAt: &due})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	if created.DueAt == nil || !created.DueAt.Equal(due) {
+		t.Fatalf("expected due date %v, got %v", due, created.DueAt)
+	}
+
+	later := due.Add(24 * time.Hour)
+	updated, err := store.Update([]string{created.ID}, UpdateOptions{DueAt: &later})
+	if err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	if updated[0].DueAt == nil || !updated[0].DueAt.Equal(later) {
+		t.Fatalf("expected due date %v, got %v", later, updated[0].DueAt)
+	}
+
+	updated, err = store.Update([]string{created.ID}, UpdateOptions{DueAt: &time.Time{}})
+	if err != nil {
+		t.Fatalf("failed to clear due date: %v", err)
+	}
+	if updated[0].DueAt != nil {
+		t.Fatalf("expected zero time to clear due date, got %v", updated[0].DueAt)
+	}
+}
+
+func TestStore_List_DueFilters(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store.clock = func() time.Time { return now }
+
+	past := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	later := now.Add(72 * time.Hour)
+	overdue, _ := store.Create("Overdue", CreateOptions{DueAt: &past})
+	closedLate, _ := store.Create("Closed late", CreateOptions{DueAt: &past})
+	dueSoon, _ := store.Create("Due soon", CreateOptions{DueAt: &soon})
+	store.Create("Due later", CreateOptions{DueAt: &later})
+	store.Create("No deadline", CreateOptions{})
+	if _, err := store.Close([]string{closedLate.ID}); err != nil {
+		t.Fatalf("failed to close todo: %v", err)
+	}
+
+	listed, err := store.List(ListFilter{Overdue: true})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != overdue.ID {
+		t.Fatalf("expected only the open overdue todo, got %+v", listed)
+	}
+
+	cutoff := now.Add(24 * time.Hour)
+	listed, err = store.List(ListFilter{DueBefore: &cutoff})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(listed) != 3 || listed[0].ID != overdue.ID || listed[1].ID != closedLate.ID || listed[2].ID != dueSoon.ID {
+		t.Fatalf("expected todos due before the cutoff, got %+v", listed)
+	}
+}
+
+func TestStore_List_TimeRangeFilters(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	high := PriorityHigh
+
+	store.clock = func() time.Time { return weekAgo.Add(-time.Hour) }
+	stale, _ := store.Create("Stale", CreateOptions{Priority: &high, Tags: []string{"infra"}})
+	touched, _ := store.Create("Touched", CreateOptions{Priority: &high, Tags: []string{"infra"}})
+
+	store.clock = func() time.Time { return now.Add(-time.Hour) }
+	description := "Updated this week"
+	if _, err := store.Update([]string{touched.ID}, UpdateOptions{Description: &description}); err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+	fresh, _ := store.Create("Fresh", CreateOptions{Priority: &high, Tags: []string{"infra"}})
+
+	status := StatusOpen
+	filter := ListFilter{Status: &status, Priority: &high, Tags: []string{"infra"}, UpdatedAfter: &weekAgo}
+	listed, err := store.List(filter)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	assertTodoOrder(t, listed, touched.ID, fresh.ID)
+
+	filter = ListFilter{Status: &status, Priority: &high, CreatedAfter: &weekAgo}
+	listed, err = store.List(filter)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	assertTodoOrder(t, listed, fresh.ID)
+
+	filter = ListFilter{CreatedBefore: &weekAgo, UpdatedBefore: &weekAgo}
+	listed, err = store.List(filter)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	assertTodoOrder(t, listed, stale.ID)
+}
+
+func TestStore_ReadyWithOptions_DueDateTiebreak(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	soon := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	later := soon.Add(24 * time.Hour)
+	high := PriorityHigh
+	undated, _ := store.Create("Undated", CreateOptions{})
+	dueLater, _ := store.Create("Due later", CreateOptions{DueAt: &later})
+	dueSoon, _ := store.Create("Due soon", CreateOptions{DueAt: &soon})
+	urgent, _ := store.Create("Urgent", CreateOptions{Priority: &high})
+
+	ready, err := store.Ready(0)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	assertTodoOrder(t, ready, urgent.ID, undated.ID, dueLater.ID, dueSoon.ID)
+
+	for _, limit := range []int{0, 3} {
+		ready, _, err = store.ReadyWithOptions(ReadyOptions{Limit: limit, DueDateTiebreak: true})
+		if err != nil {
+			t.Fatalf("failed to get ready: %v", err)
+		}
+		want := []string{urgent.ID, dueSoon.ID, dueLater.ID, undated.ID}
+		if limit > 0 {
+			want = want[:limit]
+		}
+		assertTodoOrder(t, ready, want...)
+	}
+}
+
+func assertTodoOrder(t *testing.T, todos []Todo, ids ...string) {
+	t.Helper()
+	got := make([]string, 0, len(todos))
+	for _, item := range todos {
+		got = append(got, item.ID)
+	}
+	if strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Fatalf("expected order %v, got %v", ids, got)
+	}
+}
+
 func TestStore_DepAdd(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
@@ -1291,83 +1634,314 @@ func TestStore_DepAdd_Duplicate(t *testing.T) {
 	}
 }
 
-func TestStore_DepTree(t *testing.T) {
+func TestStore_DepAdd_Cycle(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
 		t.Fatalf("failed to open store: %v", err)
 	}
 	defer store.Release()
 
-	// Create tree:
-	//   root
-	//   ├── child1
-	//   │   └── grandchild
-	//   └── child2
-	root, _ := store.Create("Root", CreateOptions{})
-	child1, _ := store.Create("Child 1", CreateOptions{})
-	child2, _ := store.Create("Child 2", CreateOptions{})
-	grandchild, _ := store.Create("Grandchild", CreateOptions{})
-
-	store.DepAdd(root.ID, child1.ID)
-	store.DepAdd(root.ID, child2.ID)
-	store.DepAdd(child1.ID, grandchild.ID)
-
-	// Get tree from root
-	tree, err := store.DepTree(root.ID)
-	if err != nil {
-		t.Fatalf("failed to get dep tree: %v", err)
-	}
+	a, _ := store.Create("A", CreateOptions{})
+	b, _ := store.Create("B", CreateOptions{})
+	c, _ := store.Create("C", CreateOptions{})
 
-	if tree.Todo.ID != root.ID {
-		t.Errorf("expected root ID, got %q", tree.Todo.ID)
+	if _, err := store.DepAdd(a.ID, b.ID); err != nil {
+		t.Fatalf("failed to add A -> B: %v", err)
 	}
-	if len(tree.Children) != 2 {
-		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	if _, err := store.DepAdd(b.ID, c.ID); err != nil {
+		t.Fatalf("failed to add B -> C: %v", err)
 	}
 
-	// Find child1 and verify it has grandchild
-	var child1Node *DepTreeNode
-	for _, child := range tree.Children {
-		if child.Todo.ID == child1.ID {
-			child1Node = child
-			break
-		}
+	_, err = store.DepAdd(c.ID, a.ID)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
 	}
-	if child1Node == nil {
-		t.Fatal("child1 not found in tree")
+	wantPath := strings.Join([]string{c.ID, a.ID, b.ID, c.ID}, " → ")
+	if !strings.Contains(err.Error(), wantPath) {
+		t.Errorf("expected error to name path %q, got %q", wantPath, err.Error())
 	}
-	if len(child1Node.Children) != 1 {
-		t.Fatalf("expected 1 grandchild, got %d", len(child1Node.Children))
+
+	deps, err := store.readDependencies()
+	if err != nil {
+		t.Fatalf("failed to read dependencies: %v", err)
 	}
-	if child1Node.Children[0].Todo.ID != grandchild.ID {
-		t.Errorf("expected grandchild ID, got %q", child1Node.Children[0].Todo.ID)
+	if len(deps) != 2 {
+		t.Errorf("expected rejected dependency not to be written, got %d dependencies", len(deps))
 	}
 }
 
-func TestStore_DepTree_ShowsSharedDependencies(t *testing.T) {
+func TestStore_DepAdd_DiscoveredFromIgnoresCycles(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
 		t.Fatalf("failed to open store: %v", err)
 	}
 	defer store.Release()
 
-	root, _ := store.Create("Root", CreateOptions{})
-	child1, _ := store.Create("Child 1", CreateOptions{})
-	child2, _ := store.Create("Child 2", CreateOptions{})
-	shared, _ := store.Create("Shared", CreateOptions{})
-
-	store.DepAdd(root.ID, child1.ID)
-	store.DepAdd(root.ID, child2.ID)
-	store.DepAdd(child1.ID, shared.ID)
-	store.DepAdd(child2.ID, shared.ID)
+	a, _ := store.Create("A", CreateOptions{})
+	b, _ := store.Create("B", CreateOptions{})
 
-	tree, err := store.DepTree(root.ID)
-	if err != nil {
-		t.Fatalf("failed to get dep tree: %v", err)
+	if _, err := store.DepAdd(a.ID, b.ID); err != nil {
+		t.Fatalf("failed to add A -> B: %v", err)
+	}
+	if _, err := store.DepAddWithOptions(b.ID, a.ID, DepAddOptions{Type: DepTypeDiscoveredFrom}); err != nil {
+		t.Fatalf("expected discovered-from edge back to A to be allowed, got %v", err)
 	}
 
-	if len(tree.Children) != 2 {
-		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	if _, err := store.DepAddWithOptions(a.ID, b.ID, DepAddOptions{Type: "relates-to"}); !errors.Is(err, ErrInvalidDependencyType) {
+		t.Fatalf("expected ErrInvalidDependencyType, got %v", err)
+	}
+}
+
+func TestStore_DepAdd_DiamondAllowed(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	// top depends on left and right, which both depend on bottom.
+	top, _ := store.Create("Top", CreateOptions{})
+	left, _ := store.Create("Left", CreateOptions{})
+	right, _ := store.Create("Right", CreateOptions{})
+	bottom, _ := store.Create("Bottom", CreateOptions{})
+
+	for _, edge := range [][2]string{
+		{top.ID, left.ID},
+		{top.ID, right.ID},
+		{left.ID, bottom.ID},
+		{right.ID, bottom.ID},
+	} {
+		if _, err := store.DepAdd(edge[0], edge[1]); err != nil {
+			t.Fatalf("failed to add %s -> %s: %v", edge[0], edge[1], err)
+		}
+	}
+}
+
+func TestStore_DepRemove(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	blocker, _ := store.Create("Blocker", CreateOptions{})
+	blocked, _ := store.Create("Blocked", CreateOptions{})
+
+	if _, err := store.DepAdd(blocked.ID, blocker.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	ready, err := store.Ready(10)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	for _, r := range ready {
+		if r.ID == blocked.ID {
+			t.Fatal("expected blocked todo not to be ready")
+		}
+	}
+
+	// Remove using ID prefixes.
+	if err := store.DepRemove(blocked.ID[:4], blocker.ID[:4]); err != nil {
+		t.Fatalf("failed to remove dependency: %v", err)
+	}
+
+	deps, err := store.readDependencies()
+	if err != nil {
+		t.Fatalf("failed to read dependencies: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Fatalf("expected no dependencies, got %d", len(deps))
+	}
+
+	ready, err = store.Ready(10)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	foundBlocked := false
+	for _, r := range ready {
+		if r.ID == blocked.ID {
+			foundBlocked = true
+			break
+		}
+	}
+	if !foundBlocked {
+		t.Error("expected todo to be ready after its last blocker was removed")
+	}
+}
+
+func TestStore_DepRemove_NotFound(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	todo1, _ := store.Create("Todo 1", CreateOptions{})
+	todo2, _ := store.Create("Todo 2", CreateOptions{})
+
+	if _, err := store.DepAdd(todo1.ID, todo2.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	// The reverse edge doesn't exist.
+	err = store.DepRemove(todo2.ID, todo1.ID)
+	if !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("expected ErrDependencyNotFound, got %v", err)
+	}
+}
+
+func TestStore_Dependents(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	blocker, _ := store.Create("Blocker", CreateOptions{})
+	first, _ := store.Create("First", CreateOptions{Dependencies: []string{blocker.ID}})
+	deleted, _ := store.Create("Deleted", CreateOptions{Dependencies: []string{blocker.ID}})
+	unrelated, _ := store.Create("Unrelated", CreateOptions{})
+
+	if _, err := store.Delete([]string{deleted.ID}, ""); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	dependents, err := store.Dependents(blocker.ID[:4])
+	if err != nil {
+		t.Fatalf("failed to get dependents: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].ID != first.ID {
+		t.Fatalf("expected only %q, got %+v", first.ID, dependents)
+	}
+
+	dependents, err = store.DependentsWithOptions(blocker.ID, DependentsOptions{IncludeTombstones: true})
+	if err != nil {
+		t.Fatalf("failed to get dependents: %v", err)
+	}
+	if len(dependents) != 2 {
+		t.Fatalf("expected 2 dependents including tombstones, got %d", len(dependents))
+	}
+
+	dependents, err = store.Dependents(unrelated.ID)
+	if err != nil {
+		t.Fatalf("failed to get dependents: %v", err)
+	}
+	if len(dependents) != 0 {
+		t.Fatalf("expected no dependents, got %d", len(dependents))
+	}
+}
+
+func TestStore_DepTree(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	// Create tree:
+	//   root
+	//   ├── child1
+	//   │   └── grandchild
+	//   └── child2
+	root, _ := store.Create("Root", CreateOptions{})
+	child1, _ := store.Create("Child 1", CreateOptions{})
+	child2, _ := store.Create("Child 2", CreateOptions{})
+	grandchild, _ := store.Create("Grandchild", CreateOptions{})
+
+	store.DepAdd(root.ID, child1.ID)
+	store.DepAdd(root.ID, child2.ID)
+	store.DepAdd(child1.ID, grandchild.ID)
+
+	// Get tree from root
+	tree, err := store.DepTree(root.ID, DepTreeOptions{})
+	if err != nil {
+		t.Fatalf("failed to get dep tree: %v", err)
+	}
+
+	if tree.Todo.ID != root.ID {
+		t.Errorf("expected root ID, got %q", tree.Todo.ID)
+	}
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	}
+
+	// Find child1 and verify it has grandchild
+	var child1Node *DepTreeNode
+	for _, child := range tree.Children {
+		if child.Todo.ID == child1.ID {
+			child1Node = child
+			break
+		}
+	}
+	if child1Node == nil {
+		t.Fatal("child1 not found in tree")
+	}
+	if len(child1Node.Children) != 1 {
+		t.Fatalf("expected 1 grandchild, got %d", len(child1Node.Children))
+	}
+	if child1Node.Children[0].Todo.ID != grandchild.ID {
+		t.Errorf("expected grandchild ID, got %q", child1Node.Children[0].Todo.ID)
+	}
+}
+
+func TestStore_DepTree_IncludesDiscoveredFrom(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	root, _ := store.Create("Root", CreateOptions{})
+	blocker, _ := store.Create("Blocker", CreateOptions{})
+	origin, _ := store.Create("Origin", CreateOptions{})
+
+	if _, err := store.DepAdd(root.ID, blocker.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+	if _, err := store.DepAddWithOptions(root.ID, origin.ID, DepAddOptions{Type: DepTypeDiscoveredFrom}); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	tree, err := store.DepTree(root.ID, DepTreeOptions{})
+	if err != nil {
+		t.Fatalf("failed to get dep tree: %v", err)
+	}
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	}
+	if tree.Children[0].Todo.ID != blocker.ID || !tree.Children[0].Type.IsBlocking() {
+		t.Errorf("expected blocking child %s, got %s (%q)", blocker.ID, tree.Children[0].Todo.ID, tree.Children[0].Type)
+	}
+	if tree.Children[1].Todo.ID != origin.ID || tree.Children[1].Type != DepTypeDiscoveredFrom {
+		t.Errorf("expected discovered-from child %s, got %s (%q)", origin.ID, tree.Children[1].Todo.ID, tree.Children[1].Type)
+	}
+}
+
+func TestStore_DepTree_ShowsSharedDependencies(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	root, _ := store.Create("Root", CreateOptions{})
+	child1, _ := store.Create("Child 1", CreateOptions{})
+	child2, _ := store.Create("Child 2", CreateOptions{})
+	shared, _ := store.Create("Shared", CreateOptions{})
+
+	store.DepAdd(root.ID, child1.ID)
+	store.DepAdd(root.ID, child2.ID)
+	store.DepAdd(child1.ID, shared.ID)
+	store.DepAdd(child2.ID, shared.ID)
+
+	tree, err := store.DepTree(root.ID, DepTreeOptions{})
+	if err != nil {
+		t.Fatalf("failed to get dep tree: %v", err)
+	}
+
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
 	}
 
 	var child1Node *DepTreeNode
@@ -1399,6 +1973,81 @@ func TestStore_DepTree_ShowsSharedDependencies(t *testing.T) {
 	}
 }
 
+func TestStore_DepTree_MarksRepeatedNodesSeeAbove(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	// Create cycle: a -> b -> a. DepAdd rejects cycles, so write the
+	// dependencies directly, as a store created before that check might hold.
+	a, _ := store.Create("A", CreateOptions{})
+	b, _ := store.Create("B", CreateOptions{})
+
+	now := time.Now()
+	if err := store.writeDependencies([]Dependency{
+		{TodoID: a.ID, DependsOnID: b.ID, CreatedAt: now},
+		{TodoID: b.ID, DependsOnID: a.ID, CreatedAt: now},
+	}); err != nil {
+		t.Fatalf("failed to write deps: %v", err)
+	}
+
+	tree, err := store.DepTree(a.ID, DepTreeOptions{})
+	if err != nil {
+		t.Fatalf("failed to get dep tree: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Todo.ID != b.ID {
+		t.Fatalf("expected root to depend on b, got %+v", tree.Children)
+	}
+	bNode := tree.Children[0]
+	if len(bNode.Children) != 1 {
+		t.Fatalf("expected b to have 1 child, got %d", len(bNode.Children))
+	}
+	repeated := bNode.Children[0]
+	if repeated.Todo.ID != a.ID || !repeated.SeeAbove {
+		t.Fatalf("expected repeated root marked see above, got %+v", repeated)
+	}
+	if len(repeated.Children) != 0 {
+		t.Fatalf("expected repeated node to have no children, got %d", len(repeated.Children))
+	}
+}
+
+func TestStore_DepTree_MaxDepth(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	// Create chain: root -> child -> grandchild
+	root, _ := store.Create("Root", CreateOptions{})
+	child, _ := store.Create("Child", CreateOptions{})
+	grandchild, _ := store.Create("Grandchild", CreateOptions{})
+
+	store.DepAdd(root.ID, child.ID)
+	store.DepAdd(child.ID, grandchild.ID)
+
+	tree, err := store.DepTree(root.ID, DepTreeOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("failed to get dep tree: %v", err)
+	}
+	if len(tree.Children) != 1 {
+		t.Fatalf("expected 1 child, got %d", len(tree.Children))
+	}
+	childNode := tree.Children[0]
+	if len(childNode.Children) != 0 {
+		t.Fatalf("expected depth limit to omit grandchild, got %d children", len(childNode.Children))
+	}
+	if !childNode.Truncated {
+		t.Fatal("expected child to be marked truncated")
+	}
+
+	if _, err := store.DepTree(root.ID, DepTreeOptions{MaxDepth: -1}); err == nil {
+		t.Fatal("expected error for negative depth")
+	}
+}
+
 func TestStore_DepTree_NotFound(t *testing.T) {
 	store, err := openTestStore(t)
 	if err != nil {
@@ -1406,8 +2055,238 @@ func TestStore_DepTree_NotFound(t *testing.T) {
 	}
 	defer store.Release()
 
-	_, err = store.DepTree("nonexistent")
+	_, err = store.DepTree("nonexistent", DepTreeOptions{})
 	if !errors.Is(err, ErrTodoNotFound) {
 		t.Errorf("expected ErrTodoNotFound, got %v", err)
 	}
 }
+
+func TestStore_AddNote(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	store.clock = func() time.Time { return now }
+
+	created, err := store.Create("Noted", CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	note, err := store.AddNote(created.ID[:4], " amonks ", "Looked into it.\n")
+	if err != nil {
+		t.Fatalf("failed to add note: %v", err)
+	}
+	if note.Author != "amonks" || note.Text != "Looked into it." || !note.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected note: %+v", note)
+	}
+
+	if _, err := store.Finish([]string{created.ID}); err != nil {
+		t.Fatalf("failed to finish todo: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := store.AddNote(created.ID, "job", "Shipped."); err != nil {
+		t.Fatalf("failed to add note to finished todo: %v", err)
+	}
+
+	shown, err := store.Show([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	notes := shown[0].Notes
+	if len(notes) != 2 || notes[0].Text != "Looked into it." || notes[1].Text != "Shipped." {
+		t.Fatalf("expected both notes to survive the status change, got %+v", notes)
+	}
+	if shown[0].Status != StatusDone || !shown[0].UpdatedAt.Equal(now) {
+		t.Fatalf("expected note to bump updated_at without changing status, got %+v", shown[0])
+	}
+
+	if _, err := store.AddNote(created.ID, "amonks", "  \n"); !errors.Is(err, ErrEmptyNote) {
+		t.Fatalf("expected ErrEmptyNote, got %v", err)
+	}
+	if _, err := store.AddNote("nonexistent", "amonks", "text"); !errors.Is(err, ErrTodoNotFound) {
+		t.Fatalf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestStore_ArchiveAndUnarchive(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	finished, _ := store.Create("Finished", CreateOptions{})
+	blocked, _ := store.Create("Blocked", CreateOptions{Dependencies: []string{finished.ID}})
+	if _, err := store.Start([]string{finished.ID}); err != nil {
+		t.Fatalf("failed to start todo: %v", err)
+	}
+	if _, err := store.Finish([]string{finished.ID}); err != nil {
+		t.Fatalf("failed to finish todo: %v", err)
+	}
+
+	archived, err := store.Archive([]string{finished.ID})
+	if err != nil {
+		t.Fatalf("failed to archive todo: %v", err)
+	}
+	item := archived[0]
+	if item.Status != StatusArchived {
+		t.Fatalf("expected archived status, got %s", item.Status)
+	}
+	if item.ClosedAt == nil || item.CompletedAt == nil || item.DeletedAt != nil {
+		t.Fatalf("expected archive to keep history without deleting, got %+v", item)
+	}
+
+	listed, err := store.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("failed to list todos: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != blocked.ID {
+		t.Fatalf("expected default list to hide archived todos, got %+v", listed)
+	}
+	listed, err = store.List(ListFilter{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("failed to list todos: %v", err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected IncludeArchived to list both todos, got %d", len(listed))
+	}
+	status := StatusArchived
+	listed, err = store.List(ListFilter{Status: &status})
+	if err != nil {
+		t.Fatalf("failed to list todos: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != finished.ID {
+		t.Fatalf("expected archived status filter to list the archived todo, got %+v", listed)
+	}
+
+	ready, err := store.Ready(0)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != blocked.ID {
+		t.Fatalf("expected archived dependency to count as resolved, got %+v", ready)
+	}
+
+	if _, err := store.Unarchive([]string{finished.ID, blocked.ID}); !errors.Is(err, ErrNotArchived) {
+		t.Fatalf("expected ErrNotArchived, got %v", err)
+	}
+	shown, err := store.Show([]string{finished.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	if shown[0].Status != StatusArchived {
+		t.Fatalf("expected failed unarchive to change nothing, got %s", shown[0].Status)
+	}
+
+	unarchived, err := store.Unarchive([]string{finished.ID})
+	if err != nil {
+		t.Fatalf("failed to unarchive todo: %v", err)
+	}
+	restored := unarchived[0]
+	if restored.Status != StatusDone || restored.ArchivedFrom != "" || restored.ClosedAt == nil || restored.CompletedAt == nil {
+		t.Fatalf("expected unarchived todo to be done again, got %+v", restored)
+	}
+
+	ready, err = store.Ready(0)
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != blocked.ID {
+		t.Fatalf("expected unarchived done todo not to be ready work, got %+v", ready)
+	}
+}
+
+func TestStore_ArchiveRefusesTombstone(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, _ := store.Create("Deleted", CreateOptions{})
+	if _, err := store.Delete([]string{created.ID}, "obsolete"); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	if _, err := store.Archive([]string{created.ID}); !errors.Is(err, ErrArchiveTombstone) {
+		t.Fatalf("expected ErrArchiveTombstone, got %v", err)
+	}
+	if _, err := store.Unarchive([]string{created.ID}); !errors.Is(err, ErrNotArchived) {
+		t.Fatalf("expected ErrNotArchived, got %v", err)
+	}
+
+	shown, err := store.Show([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to show todo: %v", err)
+	}
+	item := shown[0]
+	if item.Status != StatusTombstone || item.DeletedAt == nil || item.DeleteReason != "obsolete" {
+		t.Fatalf("expected refused archive to keep the tombstone intact, got %+v", item)
+	}
+}
+
+func TestStore_UnarchiveReopensInProgress(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, _ := store.Create("Abandoned", CreateOptions{})
+	if _, err := store.Start([]string{created.ID}); err != nil {
+		t.Fatalf("failed to start todo: %v", err)
+	}
+	archived, err := store.Archive([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to archive todo: %v", err)
+	}
+	if archived[0].ArchivedFrom != StatusInProgress {
+		t.Fatalf("expected archived_from in_progress, got %q", archived[0].ArchivedFrom)
+	}
+
+	unarchived, err := store.Unarchive([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to unarchive todo: %v", err)
+	}
+	item := unarchived[0]
+	if item.Status != StatusOpen || item.ArchivedFrom != "" || item.StartedAt != nil {
+		t.Fatalf("expected unarchived in-progress todo to be reopened, got %+v", item)
+	}
+	if err := ValidateTodo(&item); err != nil {
+		t.Fatalf("expected valid todo, got %v", err)
+	}
+}
+
+func TestStore_UnarchiveRestoresOpenStatus(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, _ := store.Create("Someday", CreateOptions{})
+	waiting := StatusWaiting
+	if _, err := store.Update([]string{created.ID}, UpdateOptions{Status: &waiting}); err != nil {
+		t.Fatalf("failed to mark todo waiting: %v", err)
+	}
+	archived, err := store.Archive([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to archive todo: %v", err)
+	}
+	if archived[0].ArchivedFrom != StatusWaiting {
+		t.Fatalf("expected archive to record waiting, got %q", archived[0].ArchivedFrom)
+	}
+
+	unarchived, err := store.Unarchive([]string{created.ID})
+	if err != nil {
+		t.Fatalf("failed to unarchive todo: %v", err)
+	}
+	if unarchived[0].Status != StatusWaiting {
+		t.Fatalf("expected unarchived todo to be waiting again, got %+v", unarchived[0])
+	}
+}
@@ -0,0 +1,155 @@
+package todo
+
+import (
+	"fmt"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// RankPriorities maps an ordered list of todos onto priority buckets.
+//
+// The order is spread evenly from the most to the least urgent priority
+// present in the set, so the first todo keeps the set's most urgent priority
+// and the last its least urgent one. When that range has fewer levels than
+// there are todos, it is widened toward PriorityMax, then PriorityMin, so
+// todos land in distinct buckets whenever the priority levels allow it. The
+// result depends only on the order and the range, so applying the same order
+// twice leaves priorities unchanged.
+func RankPriorities(ordered []Todo) []int {
+	priorities := make([]int, len(ordered))
+	if len(ordered) == 0 {
+		return priorities
+	}
+
+	lo, hi := ordered[0].Priority, ordered[0].Priority
+	for _, item := range ordered[1:] {
+		lo = min(lo, item.Priority)
+		hi = max(hi, item.Priority)
+	}
+	levels := min(len(ordered), PriorityMax-PriorityMin+1)
+	for hi-lo+1 < levels && hi < PriorityMax {
+		hi++
+	}
+	for hi-lo+1 < levels && lo > PriorityMin {
+		lo--
+	}
+
+	last := len(ordered) - 1
+	if last == 0 {
+		priorities[0] = lo
+		return priorities
+	}
+	span := hi - lo
+	for i := range priorities {
+		// Round i*span/last to the nearest level.
+		priorities[i] = lo + (2*i*span+last)/(2*last)
+	}
+	return priorities
+}
+
+// DiscardedReorders reports the todos whose edited order cannot be recorded.
+//
+// offered is the list in its original order and order is the edited list of
+// IDs. Priorities are the only ordering a todo has, so when there are more
+// todos than priority levels and two of them end up in the same bucket,
+// swapping them has no effect. The IDs of todos involved in such a swap are
+// returned in edited order.
+func DiscardedReorders(offered []Todo, order []string) []string {
+	position := make(map[string]int, len(offered))
+	byID := make(map[string]Todo, len(offered))
+	for i, item := range offered {
+		position[item.ID] = i
+		byID[item.ID] = item
+	}
+	ordered := make([]Todo, 0, len(order))
+	for _, id := range order {
+		item, ok := byID[internalstrings.NormalizeLower(id)]
+		if !ok {
+			continue
+		}
+		ordered = append(ordered, item)
+	}
+
+	priorities := RankPriorities(ordered)
+	discarded := make(map[string]bool)
+	for i := range ordered {
+		for j := i + 1; j < len(ordered); j++ {
+			if priorities[i] != priorities[j] {
+				continue
+			}
+			if position[ordered[i].ID] > position[ordered[j].ID] {
+				discarded[ordered[i].ID] = true
+				discarded[ordered[j].ID] = true
+			}
+		}
+	}
+	var ids []string
+	for _, item := range ordered {
+		if discarded[item.ID] {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// Reprioritize assigns priorities to the given todos based on their order.
+//
+// IDs are resolved using the usual prefix matching and must not repeat. The
+// resulting priorities are computed with RankPriorities and written in a
+// single store update. Only todos whose priority changes are returned.
+func (s *Store) Reprioritize(ids []string) ([]Todo, error) {
+	todos, resolvedIDs, err := s.readTodosAndResolveIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(resolvedIDs))
+	for _, id := range resolvedIDs {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateTodoID, id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	indexByID := make(map[string]int, len(todos))
+	for i := range todos {
+		indexByID[todos[i].ID] = i
+	}
+
+	ordered := make([]Todo, 0, len(resolvedIDs))
+	var missing []string
+	for _, id := range resolvedIDs {
+		index, ok := indexByID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		ordered = append(ordered, todos[index])
+	}
+	if err := missingTodoIDsError(missing); err != nil {
+		return nil, err
+	}
+
+	priorities := RankPriorities(ordered)
+	now := s.now()
+	updated := make([]Todo, 0, len(ordered))
+	for i, item := range ordered {
+		if item.Priority == priorities[i] {
+			continue
+		}
+		index := indexByID[item.ID]
+		todos[index].Priority = priorities[i]
+		todos[index].UpdatedAt = now
+		updated = append(updated, todos[index])
+	}
+
+	if len(updated) == 0 {
+		return updated, nil
+	}
+
+	if err := s.writeTodos(todos); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
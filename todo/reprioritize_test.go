@@ -0,0 +1,158 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRankPrioritiesSpreadsOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		priorities []int
+		want       []int
+	}{
+		{name: "empty", priorities: nil, want: []int{}},
+		{name: "single", priorities: []int{PriorityLow}, want: []int{PriorityLow}},
+		{name: "keeps range ends", priorities: []int{PriorityLow, PriorityHigh}, want: []int{PriorityHigh, PriorityLow}},
+		{name: "splits ties", priorities: []int{PriorityMedium, PriorityMedium}, want: []int{PriorityMedium, PriorityLow}},
+		{
+			name:       "widens narrow range",
+			priorities: []int{PriorityLow, PriorityHigh, PriorityMedium, PriorityMedium},
+			want:       []int{PriorityHigh, PriorityMedium, PriorityLow, PriorityBacklog},
+		},
+		{
+			name:       "more todos than levels",
+			priorities: []int{2, 2, 2, 2, 2, 2},
+			want:       []int{0, 1, 2, 2, 3, 4},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered := make([]Todo, len(tc.priorities))
+			for i, priority := range tc.priorities {
+				ordered[i] = Todo{Priority: priority}
+			}
+			got := RankPriorities(ordered)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+
+			for i := range ordered {
+				ordered[i].Priority = got[i]
+			}
+			if again := RankPriorities(ordered); !reflect.DeepEqual(again, got) {
+				t.Fatalf("expected ranking to be stable, got %v then %v", got, again)
+			}
+		})
+	}
+}
+
+func TestDiscardedReorders(t *testing.T) {
+	offered := []Todo{
+		{ID: "a", Priority: 0},
+		{ID: "b", Priority: 1},
+		{ID: "c", Priority: 2},
+		{ID: "d", Priority: 2},
+		{ID: "e", Priority: 3},
+		{ID: "f", Priority: 4},
+	}
+
+	if got := DiscardedReorders(offered, []string{"b", "a", "c", "d", "e", "f"}); len(got) != 0 {
+		t.Fatalf("expected moves across buckets to be kept, got %v", got)
+	}
+	got := DiscardedReorders(offered, []string{"a", "b", "d", "c", "e", "f"})
+	want := []string{"d", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestStore_Reprioritize(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	high, err := store.Create("High", CreateOptions{Priority: PriorityPtr(PriorityHigh)})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	low, err := store.Create("Low", CreateOptions{Priority: PriorityPtr(PriorityLow)})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	medium, err := store.Create("Medium", CreateOptions{Priority: PriorityPtr(PriorityMedium)})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+
+	updated, err := store.Reprioritize([]string{low.ID, high.ID, medium.ID})
+	if err != nil {
+		t.Fatalf("failed to reprioritize: %v", err)
+	}
+	if len(updated) != 3 {
+		t.Fatalf("expected 3 updated todos, got %d", len(updated))
+	}
+
+	items, err := store.Show([]string{low.ID, high.ID, medium.ID})
+	if err != nil {
+		t.Fatalf("failed to show todos: %v", err)
+	}
+	if items[0].Priority != PriorityHigh || items[1].Priority != PriorityMedium || items[2].Priority != PriorityLow {
+		t.Fatalf("unexpected priorities: %d, %d, %d", items[0].Priority, items[1].Priority, items[2].Priority)
+	}
+
+	unchanged, err := store.Reprioritize([]string{low.ID, high.ID, medium.ID})
+	if err != nil {
+		t.Fatalf("failed to reprioritize: %v", err)
+	}
+	if len(unchanged) != 0 {
+		t.Fatalf("expected no updates for an unchanged order, got %d", len(unchanged))
+	}
+
+	_, err = store.Reprioritize([]string{low.ID, low.ID})
+	if !errors.Is(err, ErrDuplicateTodoID) {
+		t.Fatalf("expected ErrDuplicateTodoID, got %v", err)
+	}
+}
+
+func TestStore_ReprioritizeSwapsTiedTodos(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	first, err := store.Create("First", CreateOptions{Priority: PriorityPtr(PriorityMedium)})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	second, err := store.Create("Second", CreateOptions{Priority: PriorityPtr(PriorityMedium)})
+	if err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+
+	if _, err := store.Reprioritize([]string{first.ID, second.ID}); err != nil {
+		t.Fatalf("failed to reprioritize: %v", err)
+	}
+	items, err := store.Show([]string{first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("failed to show todos: %v", err)
+	}
+	firstPriority, secondPriority := items[0].Priority, items[1].Priority
+	if firstPriority >= secondPriority {
+		t.Fatalf("expected the order to split the tie, got %d, %d", firstPriority, secondPriority)
+	}
+
+	if _, err := store.Reprioritize([]string{second.ID, first.ID}); err != nil {
+		t.Fatalf("failed to reprioritize: %v", err)
+	}
+	items, err = store.Show([]string{first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("failed to show todos: %v", err)
+	}
+	if items[0].Priority != secondPriority || items[1].Priority != firstPriority {
+		t.Fatalf("expected swapping to swap priorities, got %d, %d", items[0].Priority, items[1].Priority)
+	}
+}
@@ -0,0 +1,110 @@
+package todo
+
+import (
+	"sort"
+	"strings"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// MatchLocation identifies the field a search query matched.
+type MatchLocation string
+
+const (
+	// MatchTitle indicates a query token matched the title.
+	MatchTitle MatchLocation = "title"
+
+	// MatchDescription indicates a query token matched the description.
+	MatchDescription MatchLocation = "description"
+
+	// MatchNotes indicates a query token matched the text of a note.
+	MatchNotes MatchLocation = "notes"
+)
+
+// matchLocationWeights ranks match locations; title hits rank highest.
+var matchLocationWeights = map[MatchLocation]int{
+	MatchTitle:       3,
+	MatchDescription: 2,
+	MatchNotes:       1,
+}
+
+// SearchResult is a todo matched by Store.Search.
+type SearchResult struct {
+	Todo Todo `json:"todo"`
+
+	// Location is the highest-ranked field any query token matched.
+	Location MatchLocation `json:"location"`
+
+	// Score sums, for each query token, the weight of the highest-ranked
+	// field it matched. Higher scores rank first.
+	Score int `json:"score"`
+}
+
+// Search returns todos matching filter whose title, description, or notes
+// contain every whitespace-separated token of query, case-insensitively.
+// Results are ordered by score, highest first, so todos matching in their
+// titles rank above todos matching only in their descriptions or notes.
+// Ties keep the store's order.
+func (s *Store) Search(query string, filter ListFilter) ([]SearchResult, error) {
+	tokens := strings.Fields(internalstrings.NormalizeLower(query))
+	if len(tokens) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+
+	todos, err := s.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(todos))
+	for _, item := range todos {
+		if result, ok := searchTodo(item, tokens); ok {
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+// searchTodo scores item against lowercase tokens. It reports false unless
+// every token matches some field.
+func searchTodo(item Todo, tokens []string) (SearchResult, bool) {
+	title := internalstrings.NormalizeLower(item.Title)
+	description := internalstrings.NormalizeLower(item.Description)
+	notes := make([]string, len(item.Notes))
+	for i, note := range item.Notes {
+		notes[i] = internalstrings.NormalizeLower(note.Text)
+	}
+
+	result := SearchResult{Todo: item}
+	for _, token := range tokens {
+		var location MatchLocation
+		switch {
+		case strings.Contains(title, token):
+			location = MatchTitle
+		case strings.Contains(description, token):
+			location = MatchDescription
+		case containsAny(notes, token):
+			location = MatchNotes
+		default:
+			return SearchResult{}, false
+		}
+		weight := matchLocationWeights[location]
+		result.Score += weight
+		if weight > matchLocationWeights[result.Location] {
+			result.Location = location
+		}
+	}
+	return result, true
+}
+
+func containsAny(values []string, needle string) bool {
+	for _, value := range values {
+		if strings.Contains(value, needle) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStore_Search_RanksTitleOverDescription(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	inDescription, err := store.Create("Tidy the build", CreateOptions{Description: "Speed up the Login page"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	inTitle, err := store.Create("Fix login page", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.Create("Unrelated", CreateOptions{Description: "nothing here"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	results, err := store.Search("LOGIN", ListFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Todo.ID != inTitle.ID || results[0].Location != MatchTitle {
+		t.Errorf("expected title match %s first, got %s (%s)", inTitle.ID, results[0].Todo.ID, results[0].Location)
+	}
+	if results[1].Todo.ID != inDescription.ID || results[1].Location != MatchDescription {
+		t.Errorf("expected description match %s second, got %s (%s)", inDescription.ID, results[1].Todo.ID, results[1].Location)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected title score %d above description score %d", results[0].Score, results[1].Score)
+	}
+}
+
+func TestStore_Search_RequiresAllTokens(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	both, err := store.Create("Fix login", CreateOptions{Description: "the page times out"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.Create("Fix signup", CreateOptions{Description: "the page times out"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	results, err := store.Search("  login   timeout ", ListFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results for missing token, got %d", len(results))
+	}
+
+	results, err = store.Search("login times", ListFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Todo.ID != both.ID {
+		t.Fatalf("expected only %s, got %v", both.ID, results)
+	}
+	if results[0].Location != MatchTitle {
+		t.Errorf("expected location title, got %s", results[0].Location)
+	}
+}
+
+func TestStore_Search_MatchesNotes(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	created, err := store.Create("Investigate flake", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.AddNote(created.ID, "amonks", "Seen again on the Postgres runner"); err != nil {
+		t.Fatalf("add note: %v", err)
+	}
+
+	results, err := store.Search("postgres", ListFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Location != MatchNotes {
+		t.Fatalf("expected one notes match, got %v", results)
+	}
+}
+
+func TestStore_Search_EmptyQuery(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	if _, err := store.Search(" \t", ListFilter{}); !errors.Is(err, ErrEmptySearchQuery) {
+		t.Fatalf("expected ErrEmptySearchQuery, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package todo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// snapshotFiles lists the store files a TodoSnapshot captures.
+var snapshotFiles = []string{TodosFile, DependenciesFile}
+
+// TodoSnapshot is an immutable in-memory copy of the todo store, taken by
+// Store.Snapshot. Queries read only the captured data, so they never touch
+// the store's files or locks, and a snapshot is safe to query from multiple
+// goroutines.
+type TodoSnapshot struct {
+	store *Store
+}
+
+// Snapshot captures the current todos and dependencies into a TodoSnapshot.
+//
+// Both files are read as one consistent view: a read-only store reads them at
+// a single commit of the store bookmark, and a writable store reads them
+// while briefly holding shared locks on both. Later writes to the store do
+// not affect the snapshot.
+func (s *Store) Snapshot() (TodoSnapshot, error) {
+	var files map[string][]byte
+	var err error
+	switch {
+	case s.files != nil:
+		files = s.files
+	case s.readOnly:
+		files, err = readBookmarkFilesAtCommit(s)
+	default:
+		files, err = readStoreFilesShared(s.wsPath)
+	}
+	if err != nil {
+		return TodoSnapshot{}, fmt.Errorf("snapshot todo store: %w", err)
+	}
+	return TodoSnapshot{store: &Store{
+		repoPath: s.repoPath,
+		readOnly: true,
+		files:    files,
+		clock:    s.clock,
+	}}, nil
+}
+
+// List returns the snapshot's todos matching the filter, like Store.List.
+func (snap TodoSnapshot) List(filter ListFilter) ([]Todo, error) {
+	return snap.store.List(filter)
+}
+
+// Search returns the snapshot's todos matching query, like Store.Search.
+func (snap TodoSnapshot) Search(query string, filter ListFilter) ([]SearchResult, error) {
+	return snap.store.Search(query, filter)
+}
+
+// Show returns the snapshot's todos with the given IDs, like Store.Show.
+func (snap TodoSnapshot) Show(ids []string) ([]Todo, error) {
+	return snap.store.Show(ids)
+}
+
+// Ready returns the snapshot's ready todos, like Store.Ready.
+func (snap TodoSnapshot) Ready(limit int) ([]Todo, error) {
+	return snap.store.Ready(limit)
+}
+
+// ReadyWithOptions returns the snapshot's ready todos plus a full ID index,
+// like Store.ReadyWithOptions.
+func (snap TodoSnapshot) ReadyWithOptions(opts ReadyOptions) ([]Todo, IDIndex, error) {
+	return snap.store.ReadyWithOptions(opts)
+}
+
+// DepTree returns the snapshot's dependency tree for a todo, like
+// Store.DepTree.
+func (snap TodoSnapshot) DepTree(id string, opts DepTreeOptions) (*DepTreeNode, error) {
+	return snap.store.DepTree(id, opts)
+}
+
+// readBookmarkFilesAtCommit resolves the store bookmark once and reads every
+// snapshot file at that commit, so a concurrent writer moving the bookmark
+// cannot split the view.
+func readBookmarkFilesAtCommit(s *Store) (map[string][]byte, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("todo store is missing jj client")
+	}
+	commitID, err := s.client.CommitIDAt(s.repoPath, BookmarkName)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", BookmarkName, err)
+	}
+	files := make(map[string][]byte, len(snapshotFiles))
+	for _, name := range snapshotFiles {
+		data, err := readStoreFileAt(s.client, s.repoPath, commitID, name)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			files[name] = data
+		}
+	}
+	return files, nil
+}
+
+// readStoreFilesShared reads every snapshot file from the workspace while
+// holding shared locks on all of them, so no writer can replace one file
+// between the reads.
+func readStoreFilesShared(wsPath string) (map[string][]byte, error) {
+	locked := make(map[string]*os.File, len(snapshotFiles))
+	defer func() {
+		for _, f := range locked {
+			syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+			f.Close()
+		}
+	}()
+
+	for _, name := range snapshotFiles {
+		f, err := os.Open(storeFilePath(wsPath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", name, err)
+		}
+		if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("lock %s: %w", name, err)
+		}
+		locked[name] = f
+	}
+
+	files := make(map[string][]byte, len(locked))
+	for name, f := range locked {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
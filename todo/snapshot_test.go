@@ -0,0 +1,119 @@
+package todo
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestStore_Snapshot_IsImmutableAndConcurrent(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	blocker, _ := store.Create("Blocker", CreateOptions{})
+	blocked, _ := store.Create("Blocked", CreateOptions{})
+	if _, err := store.DepAdd(blocked.ID, blocker.ID); err != nil {
+		t.Fatalf("failed to add dependency: %v", err)
+	}
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				listed, err := snap.List(ListFilter{})
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(listed) != 2 {
+					errs <- errors.New("snapshot list changed")
+					return
+				}
+				ready, err := snap.Ready(0)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(ready) != 1 || ready[0].ID != blocker.ID {
+					errs <- errors.New("snapshot ready changed")
+					return
+				}
+				if _, err := snap.Show([]string{blocked.ID}); err != nil {
+					errs <- err
+					return
+				}
+				tree, err := snap.DepTree(blocked.ID, DepTreeOptions{})
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(tree.Children) != 1 {
+					errs <- errors.New("snapshot dep tree changed")
+					return
+				}
+			}
+		}()
+	}
+
+	// Writers keep using the store while the snapshot is queried.
+	for i := 0; i < 5; i++ {
+		if _, err := store.Create("Later", CreateOptions{}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if _, err := store.Close([]string{blocker.ID}); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	fresh, err := store.Snapshot()
+	if err != nil {
+		t.Fatalf("fresh snapshot: %v", err)
+	}
+	listed, err := fresh.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("list fresh snapshot: %v", err)
+	}
+	if len(listed) != 7 {
+		t.Fatalf("expected a fresh snapshot to see later writes, got %d todos", len(listed))
+	}
+}
+
+func TestStore_Snapshot_RejectsWrites(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	listed, err := snap.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("list empty snapshot: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Fatalf("expected an empty snapshot, got %d todos", len(listed))
+	}
+	if _, err := snap.store.Create("Nope", CreateOptions{}); !errors.Is(err, ErrReadOnlyStore) {
+		t.Fatalf("expected ErrReadOnlyStore, got %v", err)
+	}
+}
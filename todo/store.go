@@ -3,6 +3,7 @@ package todo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/amonks/incrementum/internal/filelock"
 	internalids "github.com/amonks/incrementum/internal/ids"
 	"github.com/amonks/incrementum/internal/jj"
 	"github.com/amonks/incrementum/internal/paths"
@@ -80,6 +82,14 @@ type Store struct {
 	readOnly  bool
 	wsRelease func() error
 	lockFile  *os.File
+	clock     func() time.Time
+
+	// idGenerator overrides GenerateID for new todos. Nil uses GenerateID.
+	idGenerator func(title string, timestamp time.Time) string
+
+	// files holds captured file contents for snapshot stores. When set,
+	// reads come from it instead of the workspace or bookmark.
+	files map[string][]byte
 }
 
 // Snapshotter records workspace changes.
@@ -127,11 +137,24 @@ type OpenOptions struct {
 	// ReadOnly opens the store without acquiring a workspace.
 	// Read-only mode cannot create missing stores.
 	ReadOnly bool
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	// LockTimeout bounds how long Open waits, with backoff, for another
+	// process to release the store lock before returning
+	// ErrStoreLockTimeout. Zero uses DefaultLockTimeout.
+	LockTimeout time.Duration
 }
 
+// DefaultLockTimeout is how long Open waits for the store lock by default.
+const DefaultLockTimeout = 5 * time.Second
+
 // Open opens the todo store for the repository at repoPath.
 // If the incr/tasks bookmark doesn't exist and PromptToCreate is true,
-// the user will be prompted to create it.
+// the user will be prompted to create it. Open fails up front with
+// workspace.ErrJujutsuNotFound or workspace.ErrJujutsuVersionUnsupported when
+// a supported jj is not installed.
 func Open(repoPath string, opts OpenOptions) (*Store, error) {
 	usesStdioPrompter := opts.Prompter == nil
 	if opts.Prompter == nil {
@@ -143,6 +166,9 @@ func Open(repoPath string, opts OpenOptions) (*Store, error) {
 		purpose = "todo store"
 	}
 
+	if _, err := jj.CheckInstalled(); err != nil {
+		return nil, err
+	}
 	client := jj.New()
 
 	// Update stale working copy before listing bookmarks
@@ -188,10 +214,11 @@ func Open(repoPath string, opts OpenOptions) (*Store, error) {
 			client:   client,
 			prompter: opts.Prompter,
 			readOnly: true,
+			clock:    opts.Now,
 		}, nil
 	}
 
-	lockFile, err := acquireTodoLock(repoPath)
+	lockFile, err := acquireTodoLock(repoPath, opts.LockTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -244,9 +271,18 @@ func Open(repoPath string, opts OpenOptions) (*Store, error) {
 			return pool.Release(wsPath)
 		},
 		lockFile: lockFile,
+		clock:    opts.Now,
 	}, nil
 }
 
+// now returns the store's current time.
+func (s *Store) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
 // Release releases the workspace back to the pool.
 // This should be called when done using the store.
 func (s *Store) Release() error {
@@ -605,6 +641,31 @@ func appendTodoJSONLine(buf []byte, todo *Todo) []byte {
 		buf, hasField = appendJSONFieldPrefix(buf, "project_review_model", hasField)
 		buf = appendJSONString(buf, todo.ProjectReviewModel)
 	}
+	if todo.Profile != "" {
+		buf, hasField = appendJSONFieldPrefix(buf, "profile", hasField)
+		buf = appendJSONString(buf, todo.Profile)
+	}
+	if todo.DesignDoc != "" {
+		buf, hasField = appendJSONFieldPrefix(buf, "design_doc", hasField)
+		buf = appendJSONString(buf, todo.DesignDoc)
+	}
+	if len(todo.Tags) > 0 {
+		buf, hasField = appendJSONFieldPrefix(buf, "tags", hasField)
+		buf = append(buf, '[')
+		for i, tag := range todo.Tags {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, tag)
+		}
+		buf = append(buf, ']')
+	}
+
+	buf, hasField = appendOptionalJSONTime(buf, "due_at", todo.DueAt, hasField)
+	if todo.EstimateMinutes != 0 {
+		buf, hasField = appendJSONFieldPrefix(buf, "estimate_minutes", hasField)
+		buf = strconv.AppendInt(buf, int64(todo.EstimateMinutes), 10)
+	}
 
 	buf, hasField = appendJSONFieldPrefix(buf, "created_at", hasField)
 	buf = appendJSONTime(buf, todo.CreatedAt)
@@ -620,15 +681,46 @@ func appendTodoJSONLine(buf []byte, todo *Todo) []byte {
 		buf, hasField = appendJSONFieldPrefix(buf, "delete_reason", hasField)
 		buf = appendJSONString(buf, todo.DeleteReason)
 	}
+	if todo.ArchivedFrom != "" {
+		buf, hasField = appendJSONFieldPrefix(buf, "archived_from", hasField)
+		buf = appendJSONString(buf, string(todo.ArchivedFrom))
+	}
 	if todo.Source != "" {
 		buf, hasField = appendJSONFieldPrefix(buf, "source", hasField)
 		buf = appendJSONString(buf, todo.Source)
 	}
+	if len(todo.Notes) > 0 {
+		buf, hasField = appendJSONFieldPrefix(buf, "notes", hasField)
+		buf = append(buf, '[')
+		for i := range todo.Notes {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendNoteJSON(buf, &todo.Notes[i])
+		}
+		buf = append(buf, ']')
+	}
 
 	buf = append(buf, '}', '\n')
 	return buf
 }
 
+func appendNoteJSON(buf []byte, note *TodoNote) []byte {
+	buf = append(buf, '{')
+	hasField := false
+
+	buf, hasField = appendJSONFieldPrefix(buf, "author", hasField)
+	buf = appendJSONString(buf, note.Author)
+
+	buf, hasField = appendJSONFieldPrefix(buf, "created_at", hasField)
+	buf = appendJSONTime(buf, note.CreatedAt)
+
+	buf, _ = appendJSONFieldPrefix(buf, "text", hasField)
+	buf = appendJSONString(buf, note.Text)
+
+	return append(buf, '}')
+}
+
 func appendDependencyJSONLine(buf []byte, dependency *Dependency) []byte {
 	buf = append(buf, '{')
 	hasField := false
@@ -639,6 +731,11 @@ func appendDependencyJSONLine(buf []byte, dependency *Dependency) []byte {
 	buf, hasField = appendJSONFieldPrefix(buf, "depends_on_id", hasField)
 	buf = appendJSONString(buf, dependency.DependsOnID)
 
+	if dependency.Type != "" {
+		buf, hasField = appendJSONFieldPrefix(buf, "type", hasField)
+		buf = appendJSONString(buf, string(dependency.Type))
+	}
+
 	buf, hasField = appendJSONFieldPrefix(buf, "created_at", hasField)
 	buf = appendJSONTime(buf, dependency.CreatedAt)
 
@@ -748,6 +845,13 @@ func (s *Store) readTodosByExactIDs(missing map[string]struct{}) (map[string]Tod
 }
 
 func withStoreReader(store *Store, filename string, fn func(io.Reader) error) (bool, error) {
+	if store.files != nil {
+		data, ok := store.files[filename]
+		if !ok {
+			return false, nil
+		}
+		return true, fn(bytes.NewReader(data))
+	}
 	if store.readOnly {
 		output, err := readBookmarkFile(store.client, store.repoPath, filename)
 		if err != nil {
@@ -817,7 +921,7 @@ func snapshotStore(store *Store) error {
 	return store.snapshot.Snapshot(store.wsPath)
 }
 
-func acquireTodoLock(repoPath string) (*os.File, error) {
+func acquireTodoLock(repoPath string, timeout time.Duration) (*os.File, error) {
 	stateDir, err := paths.DefaultStateDir()
 	if err != nil {
 		return nil, err
@@ -831,18 +935,37 @@ func acquireTodoLock(repoPath string) (*os.File, error) {
 	if err != nil {
 		return nil, fmt.Errorf("open todo lock file: %w", err)
 	}
-	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+	if err := lockWithTimeout(file, timeout); err != nil {
 		_ = file.Close()
+		if errors.Is(err, ErrStoreLockTimeout) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("lock todo store: %w", err)
 	}
 	return file, nil
 }
 
+// lockWithTimeout takes an exclusive lock on file, retrying with exponential
+// backoff while another process holds it. It gives up with
+// ErrStoreLockTimeout once timeout elapses; zero uses DefaultLockTimeout.
+func lockWithTimeout(file *os.File, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := filelock.Exclusive(ctx, file)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrStoreLockTimeout, timeout)
+	}
+	return err
+}
+
 func releaseTodoLock(file *os.File) error {
 	if file == nil {
 		return nil
 	}
-	unlockErr := syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	unlockErr := filelock.Unlock(file)
 	closeErr := file.Close()
 	return errors.Join(unlockErr, closeErr)
 }
@@ -965,10 +1088,15 @@ func validateTodoIDs(ids []string) error {
 }
 
 func readBookmarkFile(client *jj.Client, repoPath, path string) ([]byte, error) {
+	return readStoreFileAt(client, repoPath, BookmarkName, path)
+}
+
+// readStoreFileAt reads a store file at rev. A missing file yields nil.
+func readStoreFileAt(client *jj.Client, repoPath, rev, path string) ([]byte, error) {
 	if client == nil {
 		return nil, fmt.Errorf("todo store is missing jj client")
 	}
-	output, err := client.FileShow(repoPath, BookmarkName, path)
+	output, err := client.FileShow(repoPath, rev, path)
 	if errors.Is(err, jj.ErrFileNotFound) {
 		return nil, nil
 	}
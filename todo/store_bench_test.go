@@ -231,7 +231,7 @@ func benchmarkStoreDepTree(b *testing.B, count int) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if _, err := store.DepTree(rootID); err != nil {
+		if _, err := store.DepTree(rootID, DepTreeOptions{}); err != nil {
 			b.Fatalf("dep tree: %v", err)
 		}
 	}
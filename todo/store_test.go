@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/amonks/incrementum/internal/jj"
+	"github.com/amonks/incrementum/internal/paths"
 	"github.com/creack/pty"
 )
 
@@ -581,6 +582,7 @@ func TestWriteJSONL_RoundTripTodos(t *testing.T) {
 	startedAt := baseTime.Add(30 * time.Minute)
 	completedAt := baseTime.Add(3 * time.Hour)
 	deletedAt := baseTime.Add(4 * time.Hour)
+	dueAt := baseTime.Add(48 * time.Hour)
 
 	todos := []Todo{
 		{
@@ -608,6 +610,12 @@ func TestWriteJSONL_RoundTripTodos(t *testing.T) {
 			DeletedAt:    &deletedAt,
 			DeleteReason: "all done",
 			Source:       "habit:cleanup",
+			Tags:         []string{"infra", "needs \"review\""},
+			DueAt:        &dueAt,
+			Notes: []TodoNote{
+				{Author: "amonks", CreatedAt: baseTime, Text: "first\nsecond"},
+				{Author: "", CreatedAt: closedAt, Text: "tab\there"},
+			},
 		},
 	}
 
@@ -668,7 +676,8 @@ func assertTodoEqual(t *testing.T, got, want Todo) {
 		got.Priority != want.Priority ||
 		got.Type != want.Type ||
 		got.DeleteReason != want.DeleteReason ||
-		got.Source != want.Source {
+		got.Source != want.Source ||
+		strings.Join(got.Tags, ",") != strings.Join(want.Tags, ",") {
 		t.Fatalf("todo mismatch: %+v", got)
 	}
 	assertTimeEqual(t, "created_at", got.CreatedAt, want.CreatedAt)
@@ -677,6 +686,16 @@ func assertTodoEqual(t *testing.T, got, want Todo) {
 	assertTimePointerEqual(t, "started_at", got.StartedAt, want.StartedAt)
 	assertTimePointerEqual(t, "completed_at", got.CompletedAt, want.CompletedAt)
 	assertTimePointerEqual(t, "deleted_at", got.DeletedAt, want.DeletedAt)
+	assertTimePointerEqual(t, "due_at", got.DueAt, want.DueAt)
+	if len(got.Notes) != len(want.Notes) {
+		t.Fatalf("expected %d notes, got %+v", len(want.Notes), got.Notes)
+	}
+	for i := range want.Notes {
+		if got.Notes[i].Author != want.Notes[i].Author || got.Notes[i].Text != want.Notes[i].Text {
+			t.Fatalf("note mismatch: %+v", got.Notes[i])
+		}
+		assertTimeEqual(t, "note created_at", got.Notes[i].CreatedAt, want.Notes[i].CreatedAt)
+	}
 }
 
 func assertTimeEqual(t *testing.T, field string, got, want time.Time) {
@@ -738,3 +757,33 @@ func TestOpen_DoesNotModifyUserWorkingCopy(t *testing.T) {
 			changeIDBefore, changeIDAfter)
 	}
 }
+
+func TestAcquireTodoLock_WaitsForRelease(t *testing.T) {
+	t.Setenv(paths.StateDirEnvVar, t.TempDir())
+	repoPath := "/tmp/lock-repo"
+
+	held, err := acquireTodoLock(repoPath, 0)
+	if err != nil {
+		t.Fatalf("acquire lock: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := acquireTodoLock(repoPath, 50*time.Millisecond); !errors.Is(err, ErrStoreLockTimeout) {
+		t.Fatalf("expected ErrStoreLockTimeout, got %v", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Fatalf("expected to wait for the timeout, waited %s", waited)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		releaseTodoLock(held)
+	}()
+	file, err := acquireTodoLock(repoPath, 5*time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire lock once released: %v", err)
+	}
+	if err := releaseTodoLock(file); err != nil {
+		t.Fatalf("release lock: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// TemplatesDir is the directory, relative to the repo root, containing todo
+// templates.
+const TemplatesDir = ".incrementum/todo-templates"
+
+// Template pre-fills fields for todos of a repeated shape. Templates live in
+// TemplatesDir/<name>.toml.
+type Template struct {
+	// Name is the template name (filename without extension).
+	Name string `toml:"-"`
+
+	// Type is the todo type. Empty leaves the usual default.
+	Type TodoType `toml:"type"`
+
+	// Priority is the todo priority. Nil leaves the usual default.
+	Priority *int `toml:"priority"`
+
+	// Description is the description skeleton.
+	Description string `toml:"description"`
+
+	// Tags are added to the todo's tags.
+	Tags []string `toml:"tags"`
+}
+
+// LoadTemplate loads and validates the named todo template from the repo.
+// Validation uses the same rules as Create, so a bad template fails here
+// rather than producing a todo with unexpected fields.
+func LoadTemplate(repoPath, name string) (*Template, error) {
+	name = internalstrings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("template name is required")
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	path := filepath.Join(repoPath, TemplatesDir, name+".toml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return nil, fmt.Errorf("read todo template %s: %w", name, err)
+	}
+
+	var tmpl Template
+	meta, err := toml.Decode(string(data), &tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parse todo template %s: %w", name, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("todo template %s: unknown field %q", name, undecoded[0].String())
+	}
+	tmpl.Name = name
+	if err := tmpl.validate(); err != nil {
+		return nil, fmt.Errorf("todo template %s: %w", name, err)
+	}
+	return &tmpl, nil
+}
+
+func (t *Template) validate() error {
+	if t.Type != "" {
+		normalized, err := normalizeTodoTypeInput(t.Type)
+		if err != nil {
+			return err
+		}
+		t.Type = normalized
+	}
+	if t.Priority != nil {
+		if err := ValidatePriority(*t.Priority); err != nil {
+			return err
+		}
+	}
+	t.Tags = normalizeTags(t.Tags)
+	return nil
+}
+
+// CreateOptions returns the template's fields merged with overrides. Fields
+// set in overrides win; tags from both are kept.
+func (t *Template) CreateOptions(overrides CreateOptions) CreateOptions {
+	opts := overrides
+	if opts.Type == "" {
+		opts.Type = t.Type
+	}
+	if opts.Priority == nil && t.Priority != nil {
+		priority := *t.Priority
+		opts.Priority = &priority
+	}
+	if opts.Description == "" {
+		opts.Description = t.Description
+	}
+	opts.Tags = append(append([]string(nil), t.Tags...), overrides.Tags...)
+	return opts
+}
+
+// CreateFromTemplate creates a todo from the named template in the store's
+// repo, with any fields set in overrides taking precedence.
+func (s *Store) CreateFromTemplate(name, title string, overrides CreateOptions) (*Todo, error) {
+	tmpl, err := LoadTemplate(s.repoPath, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(title, tmpl.CreateOptions(overrides))
+}
@@ -0,0 +1,100 @@
+package todo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeTodoTemplate(t *testing.T, repoPath, name, content string) {
+	t.Helper()
+	dir := filepath.Join(repoPath, TemplatesDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("create templates dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+}
+
+func TestStore_CreateFromTemplate(t *testing.T) {
+	store, err := openTestStore(t)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Release()
+	store.repoPath = t.TempDir()
+
+	writeTodoTemplate(t, store.repoPath, "bug", `
+type = "Bug"
+priority = 1
+description = """
+## Steps to reproduce
+
+## Expected
+"""
+tags = ["Triage", "bugs"]
+`)
+
+	created, err := store.CreateFromTemplate("bug", "Crash on save", CreateOptions{})
+	if err != nil {
+		t.Fatalf("create from template: %v", err)
+	}
+	if created.Type != TypeBug || created.Priority != PriorityHigh {
+		t.Errorf("expected a high-priority bug, got %s p%d", created.Type, created.Priority)
+	}
+	if !strings.HasPrefix(created.Description, "## Steps to reproduce") {
+		t.Errorf("expected the description skeleton, got %q", created.Description)
+	}
+	if !slices.Equal(created.Tags, []string{"triage", "bugs"}) {
+		t.Errorf("expected template tags, got %v", created.Tags)
+	}
+
+	overridden, err := store.CreateFromTemplate("bug", "Crash on load", CreateOptions{
+		Priority:    PriorityPtr(PriorityCritical),
+		Description: "Crashes every time.",
+		Tags:        []string{"io"},
+	})
+	if err != nil {
+		t.Fatalf("create from template with overrides: %v", err)
+	}
+	if overridden.Type != TypeBug || overridden.Priority != PriorityCritical {
+		t.Errorf("expected a critical bug, got %s p%d", overridden.Type, overridden.Priority)
+	}
+	if overridden.Description != "Crashes every time." {
+		t.Errorf("expected the description override, got %q", overridden.Description)
+	}
+	if !slices.Equal(overridden.Tags, []string{"triage", "bugs", "io"}) {
+		t.Errorf("expected template and override tags, got %v", overridden.Tags)
+	}
+}
+
+func TestLoadTemplate_Invalid(t *testing.T) {
+	repoPath := t.TempDir()
+
+	if _, err := LoadTemplate(repoPath, "missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+	if _, err := LoadTemplate(repoPath, "../bug"); err == nil {
+		t.Error("expected a path-like template name to be rejected")
+	}
+
+	writeTodoTemplate(t, repoPath, "bad-type", `type = "chore"`)
+	if _, err := LoadTemplate(repoPath, "bad-type"); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("expected ErrInvalidType, got %v", err)
+	}
+
+	writeTodoTemplate(t, repoPath, "bad-priority", `priority = 9`)
+	if _, err := LoadTemplate(repoPath, "bad-priority"); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("expected ErrInvalidPriority, got %v", err)
+	}
+
+	writeTodoTemplate(t, repoPath, "typo", `prioirty = 1`)
+	_, err := LoadTemplate(repoPath, "typo")
+	if err == nil || !strings.Contains(err.Error(), "prioirty") {
+		t.Errorf("expected the unknown field to be named, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package todo
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Todo represents a single task.
 type Todo struct {
@@ -31,6 +34,23 @@ type Todo struct {
 	// ProjectReviewModel selects the opencode model for final project review on this todo.
 	ProjectReviewModel string `json:"project_review_model,omitempty"`
 
+	// Profile names the config profile whose models jobs for this todo use
+	// where no model is set above.
+	Profile string `json:"profile,omitempty"`
+
+	// DesignDoc is an optional repo-relative path to a design document whose
+	// contents are included in job prompts.
+	DesignDoc string `json:"design_doc,omitempty"`
+
+	// Tags group todos by area. They are lowercase and unique.
+	Tags []string `json:"tags,omitempty"`
+
+	// DueAt is when the todo should be finished by (nil if it has no deadline).
+	DueAt *time.Time `json:"due_at,omitempty"`
+
+	// EstimateMinutes is the expected effort in minutes (0 if not estimated).
+	EstimateMinutes int `json:"estimate_minutes,omitempty"`
+
 	// CreatedAt is when the todo was created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -52,8 +72,59 @@ type Todo struct {
 	// DeleteReason explains why the todo was deleted.
 	DeleteReason string `json:"delete_reason,omitempty"`
 
+	// ArchivedFrom is the status the todo had when it was archived, restored
+	// by Unarchive (empty unless archived).
+	ArchivedFrom Status `json:"archived_from,omitempty"`
+
 	// Source tracks the origin of the todo.
 	// Empty or omitted means user-created. Values like "habit:<name>" indicate
 	// the todo was created by running a habit.
 	Source string `json:"source,omitempty"`
+
+	// Notes is an append-only log of comments left on the todo.
+	Notes []TodoNote `json:"notes,omitempty"`
+}
+
+// TodoNote is a comment left on a todo.
+type TodoNote struct {
+	// Author identifies who left the note.
+	Author string `json:"author"`
+
+	// CreatedAt is when the note was added.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Text is the note body.
+	Text string `json:"text"`
+}
+
+// IsOverdue reports whether the todo is unresolved and past its due date.
+func (t Todo) IsOverdue(now time.Time) bool {
+	return t.DueAt != nil && !t.Status.IsResolved() && t.DueAt.Before(now)
+}
+
+// ActualMinutes returns the effort the todo took, from StartedAt to
+// CompletedAt rounded to the nearest minute. It reports false unless both
+// are set.
+func (t Todo) ActualMinutes() (int, bool) {
+	if t.StartedAt == nil || t.CompletedAt == nil {
+		return 0, false
+	}
+	elapsed := t.CompletedAt.Sub(*t.StartedAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return int(elapsed.Round(time.Minute) / time.Minute), true
+}
+
+// MarshalJSON encodes the todo with its derived actual_minutes when known.
+func (t Todo) MarshalJSON() ([]byte, error) {
+	type todoFields Todo
+	out := struct {
+		todoFields
+		ActualMinutes *int `json:"actual_minutes,omitempty"`
+	}{todoFields: todoFields(t)}
+	if actual, ok := t.ActualMinutes(); ok {
+		out.ActualMinutes = &actual
+	}
+	return json.Marshal(out)
 }
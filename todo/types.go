@@ -5,7 +5,8 @@
 // without polluting the code history.
 //
 // The public API mirrors the CLI commands:
-//   - Create, Update, Start, Close, Finish, Reopen for todo lifecycle
+//   - Create, Update, Start, Close, Finish, Reopen, Archive, Unarchive for
+//     todo lifecycle
 //   - Show, List, Ready for querying
 //   - DepAdd, DepTree for dependency management
 package todo
@@ -39,11 +40,16 @@ const (
 
 	// StatusTombstone indicates the todo has been soft-deleted.
 	StatusTombstone Status = "tombstone"
+
+	// StatusArchived indicates the todo is no longer relevant but kept for
+	// reference. Unlike tombstones, archived todos are not deleted and can be
+	// unarchived.
+	StatusArchived Status = "archived"
 )
 
 // ValidStatuses returns all valid status values.
 func ValidStatuses() []Status {
-	return []Status{StatusOpen, StatusProposed, StatusInProgress, StatusClosed, StatusDone, StatusWaiting, StatusTombstone}
+	return []Status{StatusOpen, StatusProposed, StatusInProgress, StatusClosed, StatusDone, StatusWaiting, StatusTombstone, StatusArchived}
 }
 
 // IsValid returns true if the status is a known valid value.
@@ -54,7 +60,7 @@ func (s Status) IsValid() bool {
 // IsResolved returns true when a status is considered resolved for dependencies.
 func (s Status) IsResolved() bool {
 	switch s {
-	case StatusClosed, StatusDone, StatusTombstone:
+	case StatusClosed, StatusDone, StatusTombstone, StatusArchived:
 		return true
 	default:
 		return false
@@ -94,6 +100,37 @@ func (t TodoType) IsInteractive() bool {
 	return t == TypeDesign
 }
 
+// DependencyType describes how a dependency relates two todos.
+type DependencyType string
+
+const (
+	// DepTypeBlocks means the depended-on todo must be resolved before the
+	// dependent todo is ready (default).
+	DepTypeBlocks DependencyType = "blocks"
+
+	// DepTypeDiscoveredFrom records that the todo was discovered while working
+	// on the depended-on todo. It is informational and never blocks.
+	DepTypeDiscoveredFrom DependencyType = "discovered-from"
+)
+
+// ValidDependencyTypes returns all valid dependency type values.
+func ValidDependencyTypes() []DependencyType {
+	return []DependencyType{DepTypeBlocks, DepTypeDiscoveredFrom}
+}
+
+// IsValid returns true if the dependency type is a known valid value.
+// The empty type is valid and means DepTypeBlocks.
+func (t DependencyType) IsValid() bool {
+	return t == "" || validation.IsValidValue(t, ValidDependencyTypes())
+}
+
+// IsBlocking returns true when a dependency of this type keeps the dependent
+// todo from being ready. Dependencies written before types existed have an
+// empty type and block.
+func (t DependencyType) IsBlocking() bool {
+	return t == "" || t == DepTypeBlocks
+}
+
 // TodoTypeRank returns the sort rank for a todo type.
 func TodoTypeRank(t TodoType) int {
 	switch t {
@@ -3,6 +3,8 @@ package todo
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"unicode/utf8"
 
 	internalstrings "github.com/amonks/incrementum/internal/strings"
@@ -31,6 +33,10 @@ var (
 	// ErrAmbiguousTodoIDPrefix is returned when an ID prefix matches multiple todos.
 	ErrAmbiguousTodoIDPrefix = errors.New("ambiguous todo ID prefix")
 
+	// ErrIDCollision is returned when Create cannot generate an ID that is
+	// distinct from every existing todo ID.
+	ErrIDCollision = errors.New("todo ID collision")
+
 	// ErrSelfDependency is returned when trying to create a dependency on itself.
 	ErrSelfDependency = errors.New("todo cannot depend on itself")
 
@@ -40,15 +46,53 @@ var (
 	// ErrEmptyDependencyDependsOnID is returned when a dependency lacks a depends-on ID.
 	ErrEmptyDependencyDependsOnID = errors.New("depends_on_id cannot be empty")
 
+	// ErrInvalidDependencyType is returned when an invalid dependency type is provided.
+	ErrInvalidDependencyType = errors.New("invalid dependency type")
+
 	// ErrDuplicateDependency is returned when the dependency already exists.
 	ErrDuplicateDependency = errors.New("dependency already exists")
 
+	// ErrDependencyCycle is returned when a dependency would make a todo
+	// depend, directly or transitively, on itself.
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+	// ErrNotArchived is returned when unarchiving a todo that isn't archived.
+	ErrNotArchived = errors.New("todo is not archived")
+
+	// ErrArchiveTombstone is returned when archiving a deleted todo.
+	ErrArchiveTombstone = errors.New("cannot archive a deleted todo")
+
+	// ErrEmptyNote is returned when adding a note without text.
+	ErrEmptyNote = errors.New("note text cannot be empty")
+
+	// ErrEmptySearchQuery is returned when searching with a blank query.
+	ErrEmptySearchQuery = errors.New("search query cannot be empty")
+
+	// ErrTemplateNotFound is returned when a todo template doesn't exist.
+	ErrTemplateNotFound = errors.New("todo template not found")
+
+	// ErrDependencyNotFound is returned when removing a dependency that doesn't exist.
+	ErrDependencyNotFound = errors.New("dependency not found")
+
+	// ErrInvalidDesignDoc is returned when a design doc path is not repo-relative.
+	ErrInvalidDesignDoc = errors.New("design doc must be a repo-relative path")
+
+	// ErrInvalidEstimate is returned when an effort estimate is negative.
+	ErrInvalidEstimate = errors.New("estimate must not be negative")
+
+	// ErrDuplicateTodoID is returned when an ordered ID list repeats a todo.
+	ErrDuplicateTodoID = errors.New("duplicate todo id")
+
 	// ErrNoTodoStore is returned when the todo store bookmark doesn't exist.
 	ErrNoTodoStore = errors.New("no todo store found (bookmark incr/tasks does not exist)")
 
 	// ErrReadOnlyStore is returned when attempting to write using a read-only store.
 	ErrReadOnlyStore = errors.New("todo store opened read-only")
 
+	// ErrStoreLockTimeout is returned when another process holds the todo
+	// store lock for longer than OpenOptions.LockTimeout.
+	ErrStoreLockTimeout = errors.New("timed out waiting for todo store lock")
+
 	// ErrClosedTodoMissingClosedAt is returned when a closed or done todo has no closed_at timestamp.
 	ErrClosedTodoMissingClosedAt = errors.New("closed or done todo must have closed_at timestamp")
 
@@ -97,6 +141,31 @@ func ValidatePriority(priority int) error {
 	return nil
 }
 
+// ValidateEstimate checks that an effort estimate in minutes is not negative.
+// Zero means no estimate.
+func ValidateEstimate(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidEstimate, minutes)
+	}
+	return nil
+}
+
+// ValidateDesignDoc checks that a design doc path stays inside the repo.
+// An empty path is valid.
+func ValidateDesignDoc(path string) error {
+	if path == "" {
+		return nil
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s", ErrInvalidDesignDoc, path)
+	}
+	cleaned := filepath.Clean(path)
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s", ErrInvalidDesignDoc, path)
+	}
+	return nil
+}
+
 // ValidateTodo checks if a todo struct is valid.
 func ValidateTodo(t *Todo) error {
 	if err := ValidateTitle(t.Title); err != nil {
@@ -115,6 +184,14 @@ func ValidateTodo(t *Todo) error {
 		return formatInvalidTypeError(t.Type)
 	}
 
+	if err := ValidateDesignDoc(t.DesignDoc); err != nil {
+		return err
+	}
+
+	if err := ValidateEstimate(t.EstimateMinutes); err != nil {
+		return err
+	}
+
 	if err := validateClosedAt(t); err != nil {
 		return err
 	}
@@ -141,6 +218,8 @@ func validateClosedAt(t *Todo) error {
 		if t.ClosedAt != nil {
 			return ErrTombstoneHasClosedAt
 		}
+	case StatusArchived:
+		// Archived todos keep closed_at from before they were archived.
 	default:
 		if t.ClosedAt != nil {
 			return ErrNotClosedTodoHasClosedAt
@@ -171,7 +250,7 @@ func validateDeletedAt(t *Todo) error {
 }
 
 func validateStartedAt(t *Todo) error {
-	if t.Status != StatusInProgress && t.Status != StatusDone {
+	if t.Status != StatusInProgress && t.Status != StatusDone && t.Status != StatusArchived {
 		if t.StartedAt != nil {
 			return ErrStartedAtRequiresActiveStatus
 		}
@@ -180,7 +259,7 @@ func validateStartedAt(t *Todo) error {
 }
 
 func validateCompletedAt(t *Todo) error {
-	if t.Status != StatusDone {
+	if t.Status != StatusDone && t.Status != StatusArchived {
 		if t.CompletedAt != nil {
 			return ErrCompletedAtRequiresDoneStatus
 		}
@@ -212,6 +291,10 @@ func formatInvalidTypeError(todoType TodoType) error {
 	return validation.FormatInvalidValueError(ErrInvalidType, todoType, ValidTodoTypes())
 }
 
+func formatInvalidDependencyTypeError(depType DependencyType) error {
+	return validation.FormatInvalidValueError(ErrInvalidDependencyType, depType, ValidDependencyTypes())
+}
+
 // ValidateDependency checks if a dependency is valid.
 func ValidateDependency(d *Dependency) error {
 	if d.TodoID == "" {
@@ -223,5 +306,8 @@ func ValidateDependency(d *Dependency) error {
 	if d.TodoID == d.DependsOnID {
 		return ErrSelfDependency
 	}
+	if !d.Type.IsValid() {
+		return formatInvalidDependencyTypeError(d.Type)
+	}
 	return nil
 }
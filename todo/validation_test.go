@@ -252,6 +252,36 @@ func TestValidateTodo(t *testing.T) {
 			},
 			wantErr: ErrTombstoneMissingDeletedAt,
 		},
+		{
+			name: "archived keeps closed_at and completed_at",
+			todo: Todo{
+				ID:          "abc12345",
+				Title:       "Fix bug",
+				Status:      StatusArchived,
+				Priority:    2,
+				Type:        TypeTask,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				ClosedAt:    &now,
+				StartedAt:   &now,
+				CompletedAt: &now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "archived with deleted_at",
+			todo: Todo{
+				ID:        "abc12345",
+				Title:     "Fix bug",
+				Status:    StatusArchived,
+				Priority:  2,
+				Type:      TypeTask,
+				CreatedAt: now,
+				UpdatedAt: now,
+				DeletedAt: &now,
+			},
+			wantErr: ErrDeletedAtRequiresTombstoneStatus,
+		},
 		{
 			name: "tombstone with closed_at",
 			todo: Todo{
@@ -398,6 +428,26 @@ func TestValidateDependency(t *testing.T) {
 			},
 			wantErr: ErrSelfDependency,
 		},
+		{
+			name: "discovered-from dependency",
+			dep: Dependency{
+				TodoID:      "abc12345",
+				DependsOnID: "def67890",
+				Type:        DepTypeDiscoveredFrom,
+				CreatedAt:   now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid dependency type",
+			dep: Dependency{
+				TodoID:      "abc12345",
+				DependsOnID: "def67890",
+				Type:        "relates-to",
+				CreatedAt:   now,
+			},
+			wantErr: ErrInvalidDependencyType,
+		},
 	}
 
 	for _, tt := range tests {
@@ -415,3 +465,30 @@ func TestValidateDependency(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDesignDoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "empty", path: ""},
+		{name: "relative", path: "docs/design.md"},
+		{name: "dotted inside repo", path: "docs/../design.md"},
+		{name: "absolute", path: "/etc/design.md", wantErr: ErrInvalidDesignDoc},
+		{name: "escapes repo", path: "../design.md", wantErr: ErrInvalidDesignDoc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDesignDoc(tt.path)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateDesignDoc() unexpected error: %v", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateDesignDoc() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
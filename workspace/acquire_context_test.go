@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAcquireContextStopsWaitingWhenCanceled(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = pool.AcquireContext(ctx, repoPath, AcquireOptions{Purpose: "waiter", MaxPoolSize: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected acquire to stop soon after cancel, took %v", elapsed)
+	}
+
+	if _, err := pool.AcquireContext(ctx, repoPath, AcquireOptions{Purpose: "late"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected an already-canceled context to fail fast, got %v", err)
+	}
+}
+
+func TestRunOnCreateKillsHookWhenCanceled(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	// A hook that only stops when it is killed.
+	pool.runScript = func(ctx context.Context, _, _ string) error {
+		<-ctx.Done()
+		return errors.New("signal: killed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	go func() {
+		done <- pool.runOnCreate(ctx, t.TempDir(), "npm install", time.Minute)
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if errors.Is(err, ErrHookTimeout) {
+			t.Fatalf("expected cancellation not to be reported as a timeout, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the hook to be interrupted")
+	}
+}
+
+func TestRunOnCreateStopsWaitingForSlotWhenCanceled(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), OnCreateConcurrency: 1})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.runScript = func(context.Context, string, string) error {
+		t.Error("expected the hook not to run")
+		return nil
+	}
+	// Occupy the only slot.
+	pool.onCreateSlots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = pool.runOnCreate(ctx, t.TempDir(), "make setup", 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+	if !errors.Is(err, errOnCreateSlotWait) {
+		t.Fatalf("expected the error to mark that the hook never started, got %v", err)
+	}
+}
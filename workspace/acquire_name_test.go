@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestAcquireNamedWorkspaceBusy(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	_, err = pool.Acquire(repoPath, AcquireOptions{Purpose: "someone else", Name: "ws-001"})
+	if !errors.Is(err, ErrWorkspaceBusy) {
+		t.Fatalf("expected ErrWorkspaceBusy, got %v", err)
+	}
+}
+
+func TestAcquireNamedWorkspaceReattachesSamePurpose(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, 1)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	seeded := st.Workspaces[repoName+"/ws-001"]
+
+	wsPath, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "holder", Name: "ws-001"})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if wsPath != seeded.Path {
+		t.Fatalf("expected path %q, got %q", seeded.Path, wsPath)
+	}
+
+	st, err = statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if pid := st.Workspaces[repoName+"/ws-001"].AcquiredByPID; pid != os.Getpid() {
+		t.Fatalf("expected workspace to be held by this process, got pid %d", pid)
+	}
+}
+
+func TestValidateWorkspaceName(t *testing.T) {
+	valid := []string{"agent-1", "resume_job", "v1.2"}
+	for _, name := range valid {
+		if err := ValidateWorkspaceName(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+
+	invalid := []string{"", " ", "default", ".hidden", "a/b", "has space", ".."}
+	for _, name := range invalid {
+		if err := ValidateWorkspaceName(name); err == nil {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func seedAvailableWorkspace(t *testing.T, stateDir, repoPath, pool string) string {
+	t.Helper()
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+	err = store.Update(func(st *statestore.State) error {
+		st.Workspaces[repoName+"/ws-001"] = statestore.WorkspaceInfo{
+			Name:        "ws-001",
+			Repo:        repoName,
+			Path:        t.TempDir(),
+			Pool:        pool,
+			Status:      statestore.WorkspaceStatusAvailable,
+			Provisioned: true,
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	return repoName
+}
+
+func TestAcquireDoesNotShareWorkspacesAcrossPools(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAvailableWorkspace(t, stateDir, repoPath, "manual")
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// repoPath is not a jj repo, so creating a fresh workspace fails; the
+	// point is that the other pool's workspace is left alone.
+	if _, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "job", Pool: "swarm"}); err == nil {
+		t.Fatal("expected creating a new workspace outside a jj repo to fail")
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	ws := st.Workspaces[repoName+"/ws-001"]
+	if ws.Status != statestore.WorkspaceStatusAvailable {
+		t.Fatalf("expected manual pool workspace to stay available, got %q", ws.Status)
+	}
+	if len(st.Workspaces) != 1 {
+		t.Fatalf("expected failed creation to be cleaned up, got %d workspaces", len(st.Workspaces))
+	}
+}
+
+func TestAcquireNamedWorkspaceFromOtherPoolIsBusy(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedAvailableWorkspace(t, stateDir, repoPath, "manual")
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	_, err = pool.Acquire(repoPath, AcquireOptions{Purpose: "job", Pool: "swarm", Name: "ws-001"})
+	if !errors.Is(err, ErrWorkspaceBusy) {
+		t.Fatalf("expected ErrWorkspaceBusy, got %v", err)
+	}
+}
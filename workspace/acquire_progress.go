@@ -0,0 +1,91 @@
+package workspace
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// AcquirePhase names a step of Pool.Acquire reported to
+// AcquireOptions.OnProgress.
+type AcquirePhase string
+
+const (
+	// AcquirePhaseSelecting covers finding, reclaiming, or waiting for a
+	// workspace and health-checking a reused one.
+	AcquirePhaseSelecting AcquirePhase = "selecting"
+	// AcquirePhaseCreating covers adding a new jj workspace.
+	AcquirePhaseCreating AcquirePhase = "creating"
+	// AcquirePhaseCheckingOut covers creating the new change at the
+	// requested revision.
+	AcquirePhaseCheckingOut AcquirePhase = "checking-out"
+	// AcquirePhaseRunningHooks covers loading config and running the
+	// on-create hook.
+	AcquirePhaseRunningHooks AcquirePhase = "running-hooks"
+)
+
+// acquireTimer tracks how long each acquire phase takes.
+type acquireTimer struct {
+	now        func() time.Time
+	onProgress func(AcquirePhase)
+
+	start      time.Time
+	phase      AcquirePhase
+	phaseStart time.Time
+	durations  map[AcquirePhase]time.Duration
+}
+
+func newAcquireTimer(now func() time.Time, onProgress func(AcquirePhase)) *acquireTimer {
+	return &acquireTimer{
+		now:        now,
+		onProgress: onProgress,
+		start:      now(),
+		durations:  make(map[AcquirePhase]time.Duration),
+	}
+}
+
+// enter ends the current phase and starts phase.
+func (t *acquireTimer) enter(phase AcquirePhase) {
+	t.stop()
+	t.phase = phase
+	t.phaseStart = t.now()
+	if t.onProgress != nil {
+		t.onProgress(phase)
+	}
+}
+
+// stop ends the current phase, if any.
+func (t *acquireTimer) stop() {
+	if t.phase == "" {
+		return
+	}
+	t.durations[t.phase] += t.now().Sub(t.phaseStart)
+	t.phase = ""
+}
+
+// finish ends the current phase and returns the total duration along with
+// the slowest phase and its duration.
+func (t *acquireTimer) finish() (time.Duration, AcquirePhase, time.Duration) {
+	t.stop()
+	total := t.now().Sub(t.start)
+	var slowest AcquirePhase
+	var slowestDuration time.Duration
+	for _, phase := range []AcquirePhase{AcquirePhaseSelecting, AcquirePhaseCreating, AcquirePhaseCheckingOut, AcquirePhaseRunningHooks} {
+		if d, ok := t.durations[phase]; ok && (slowest == "" || d > slowestDuration) {
+			slowest = phase
+			slowestDuration = d
+		}
+	}
+	return total, slowest, slowestDuration
+}
+
+// warnIfSlowAcquire writes a warning to w when the acquire took longer than
+// threshold. A zero threshold disables the warning.
+func warnIfSlowAcquire(w io.Writer, threshold time.Duration, wsName string, timer *acquireTimer) {
+	total, slowest, slowestDuration := timer.finish()
+	if threshold <= 0 || total <= threshold || w == nil {
+		return
+	}
+	fmt.Fprintf(w, "warning: acquiring workspace %s took %s (slowest phase: %s, %s)\n",
+		wsName, total.Round(time.Millisecond), slowest, slowestDuration.Round(time.Millisecond))
+}
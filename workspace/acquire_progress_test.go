@@ -0,0 +1,76 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAcquireReportsProgressOnReattach(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	var warnings bytes.Buffer
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir(), Warnings: &warnings})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	var phases []AcquirePhase
+	_, err = pool.Acquire(repoPath, AcquireOptions{
+		Purpose:    "holder",
+		Name:       "ws-001",
+		OnProgress: func(phase AcquirePhase) { phases = append(phases, phase) },
+	})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if len(phases) != 1 || phases[0] != AcquirePhaseSelecting {
+		t.Fatalf("expected only the selecting phase, got %v", phases)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warning without a threshold, got %q", warnings.String())
+	}
+}
+
+func TestWarnIfSlowAcquireNamesSlowestPhase(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var phases []AcquirePhase
+	timer := newAcquireTimer(clock, func(phase AcquirePhase) { phases = append(phases, phase) })
+	timer.enter(AcquirePhaseSelecting)
+	now = now.Add(time.Second)
+	timer.enter(AcquirePhaseCheckingOut)
+	now = now.Add(2 * time.Second)
+	timer.enter(AcquirePhaseRunningHooks)
+	now = now.Add(5 * time.Second)
+
+	var warnings bytes.Buffer
+	warnIfSlowAcquire(&warnings, 3*time.Second, "ws-001", timer)
+
+	want := "warning: acquiring workspace ws-001 took 8s (slowest phase: running-hooks, 5s)\n"
+	if warnings.String() != want {
+		t.Fatalf("expected warning %q, got %q", want, warnings.String())
+	}
+	if got := fmt.Sprint(phases); got != "[selecting checking-out running-hooks]" {
+		t.Fatalf("unexpected phases: %v", phases)
+	}
+}
+
+func TestWarnIfSlowAcquireQuietUnderThreshold(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	timer := newAcquireTimer(func() time.Time { return now }, nil)
+	timer.enter(AcquirePhaseSelecting)
+	now = now.Add(time.Second)
+
+	var warnings bytes.Buffer
+	warnIfSlowAcquire(&warnings, 2*time.Second, "ws-001", timer)
+	warnIfSlowAcquire(&warnings, 0, "ws-001", timer)
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warning, got %q", warnings.String())
+	}
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestAcquireRejectsMissingRevisionBeforeCreating(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	var probed []string
+	pool.revisionExists = func(_ string, rev string) (bool, error) {
+		probed = append(probed, rev)
+		return false, nil
+	}
+	pool.workspaceAdd = func(string, string, string) error {
+		t.Fatal("expected no workspace to be created")
+		return nil
+	}
+
+	_, err = pool.Acquire(repoPath, AcquireOptions{Purpose: "typo", Rev: "mian"})
+	if !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+	if len(probed) != 1 || probed[0] != "mian" {
+		t.Fatalf("expected the revision to be probed once, got %v", probed)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if len(st.Workspaces) != 0 {
+		t.Fatalf("expected no workspace in state, got %v", st.Workspaces)
+	}
+}
+
+func TestCheckRevision(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	existing := map[string]bool{"main": true, "kxqpmrvzlnwt": true}
+	pool.revisionExists = func(_ string, rev string) (bool, error) {
+		if rev == "@" {
+			t.Fatal("expected @ not to be probed")
+		}
+		return existing[rev], nil
+	}
+
+	for _, rev := range []string{"@", "main", "kxqpmrvzlnwt", "zzzzzzzzzzzz"} {
+		if err := pool.checkRevision("repo", rev); err != nil {
+			t.Errorf("expected %q to be accepted, got %v", rev, err)
+		}
+	}
+	if err := pool.checkRevision("repo", "mian"); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+
+	probeErr := errors.New("jj log: exit status 1: Error: There is no jj repo")
+	pool.revisionExists = func(string, string) (bool, error) { return false, probeErr }
+	if err := pool.checkRevision("repo", "main"); !errors.Is(err, probeErr) {
+		t.Errorf("expected the probe error, got %v", err)
+	}
+}
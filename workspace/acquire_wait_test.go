@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func seedAcquiredWorkspace(t *testing.T, stateDir, repoPath string, pid int) string {
+	t.Helper()
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+	wsPath := filepath.Join(t.TempDir(), "ws-001")
+	err = store.Update(func(st *statestore.State) error {
+		st.Workspaces[repoName+"/ws-001"] = statestore.WorkspaceInfo{
+			Name:          "ws-001",
+			Repo:          repoName,
+			Path:          wsPath,
+			Purpose:       "holder",
+			Status:        statestore.WorkspaceStatusAcquired,
+			AcquiredByPID: pid,
+			Provisioned:   true,
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	return repoName
+}
+
+func TestAcquireTimesOutWhenPoolFull(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	start := time.Now()
+	_, err = pool.Acquire(repoPath, AcquireOptions{
+		Purpose:     "waiter",
+		MaxPoolSize: 1,
+		WaitTimeout: 150 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected ErrAcquireTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected acquire to wait for the timeout, returned after %v", elapsed)
+	}
+}
+
+func TestAcquireReclaimsWorkspaceFromExitedHolder(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("run helper process: %v", err)
+	}
+	deadPID := cmd.Process.Pid
+
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, deadPID)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// The seeded workspace is not a real jj workspace. Let the health-check
+	// repair "recreate" it so the claim survives, and Acquire fails later on;
+	// the claim itself is what this test checks.
+	pool.workspaceAdd = func(_, _, workspacePath string) error {
+		return os.MkdirAll(workspacePath, 0755)
+	}
+	_, err = pool.Acquire(repoPath, AcquireOptions{
+		Purpose:     "reclaimer",
+		MaxPoolSize: 1,
+		WaitTimeout: time.Second,
+	})
+	if errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected dead holder's workspace to be reclaimed, got %v", err)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	ws := st.Workspaces[repoName+"/ws-001"]
+	if ws.AcquiredByPID != os.Getpid() || ws.Purpose != "reclaimer" {
+		t.Fatalf("expected workspace claimed by this process, got pid %d purpose %q", ws.AcquiredByPID, ws.Purpose)
+	}
+}
@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// ReleaseAll releases every acquired workspace for the repository and returns
+// the names of the workspaces it released, ordered by name.
+//
+// A workspace that fails to release does not stop the others from being
+// released; all failures are returned as a joined error.
+func (p *Pool) ReleaseAll(repoPath string) ([]string, error) {
+	acquired, err := p.acquiredWorkspaces(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	released := make([]string, 0, len(acquired))
+	var errs []error
+	for _, ws := range acquired {
+		if err := p.releaseToAvailable(ws.Path); err != nil {
+			errs = append(errs, fmt.Errorf("release %s: %w", ws.Name, err))
+			continue
+		}
+		released = append(released, ws.Name)
+	}
+	return released, errors.Join(errs...)
+}
+
+// RenewAll refreshes the lease timestamp of every acquired workspace for the
+// repository and returns the names of the renewed workspaces, ordered by
+// name. Holders and labels are left unchanged.
+func (p *Pool) RenewAll(repoPath string) ([]string, error) {
+	return p.renew(repoPath, "")
+}
+
+// RenewByName refreshes the lease timestamp of one acquired workspace.
+// Returns ErrWorkspaceNotAcquired if the workspace is available.
+func (p *Pool) RenewByName(repoPath, wsName string) error {
+	_, err := p.renew(repoPath, wsName)
+	return err
+}
+
+// renew refreshes the named acquired workspace, or every acquired workspace
+// when wsName is empty.
+func (p *Pool) renew(repoPath, wsName string) ([]string, error) {
+	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("get repo name: %w", err)
+	}
+
+	var renewed []string
+	err = p.stateStore.Update(func(st *statestore.State) error {
+		now := time.Now()
+		if wsName != "" {
+			key := repoName + "/" + wsName
+			ws, ok := st.Workspaces[key]
+			if !ok {
+				return fmt.Errorf("workspace not found: %s", wsName)
+			}
+			if ws.Status != statestore.WorkspaceStatusAcquired {
+				return fmt.Errorf("%w: %s", ErrWorkspaceNotAcquired, wsName)
+			}
+			ws.UpdatedAt = now
+			st.Workspaces[key] = ws
+			renewed = append(renewed, ws.Name)
+			return nil
+		}
+		for key, ws := range st.Workspaces {
+			if ws.Repo != repoName || ws.Status != statestore.WorkspaceStatusAcquired {
+				continue
+			}
+			ws.UpdatedAt = now
+			st.Workspaces[key] = ws
+			renewed = append(renewed, ws.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(renewed)
+	return renewed, nil
+}
+
+// acquiredWorkspaces returns the repository's acquired workspaces ordered by
+// name.
+func (p *Pool) acquiredWorkspaces(repoPath string) ([]statestore.WorkspaceInfo, error) {
+	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("get repo name: %w", err)
+	}
+
+	st, err := p.stateStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load state: %w", err)
+	}
+
+	var acquired []statestore.WorkspaceInfo
+	for _, ws := range st.Workspaces {
+		if ws.Repo == repoName && ws.Status == statestore.WorkspaceStatusAcquired {
+			acquired = append(acquired, ws)
+		}
+	}
+	sort.Slice(acquired, func(i, j int) bool {
+		return acquired[i].Name < acquired[j].Name
+	})
+	return acquired, nil
+}
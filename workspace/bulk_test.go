@@ -0,0 +1,117 @@
+package workspace
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func seedBulkWorkspaces(t *testing.T, stateDir, repoPath string) string {
+	t.Helper()
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	err = store.Update(func(st *statestore.State) error {
+		for _, seed := range []struct {
+			name   string
+			status statestore.WorkspaceStatus
+		}{
+			{"ws-001", statestore.WorkspaceStatusAcquired},
+			{"ws-002", statestore.WorkspaceStatusAvailable},
+			{"ws-003", statestore.WorkspaceStatusAcquired},
+		} {
+			st.Workspaces[repoName+"/"+seed.name] = statestore.WorkspaceInfo{
+				Name:      seed.name,
+				Repo:      repoName,
+				Path:      filepath.Join(t.TempDir(), seed.name),
+				Purpose:   "crashed job",
+				Status:    seed.status,
+				UpdatedAt: old,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	return repoName
+}
+
+func TestRenewAllRefreshesAcquiredWorkspaces(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedBulkWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	start := time.Now()
+	renewed, err := pool.RenewAll(repoPath)
+	if err != nil {
+		t.Fatalf("renew all: %v", err)
+	}
+	if len(renewed) != 2 || renewed[0] != "ws-001" || renewed[1] != "ws-003" {
+		t.Fatalf("expected ws-001 and ws-003 renewed, got %v", renewed)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if updated := st.Workspaces[repoName+"/ws-001"].UpdatedAt; updated.Before(start) {
+		t.Fatalf("expected ws-001 lease refreshed, got %v", updated)
+	}
+	if updated := st.Workspaces[repoName+"/ws-002"].UpdatedAt; !updated.Before(start) {
+		t.Fatalf("expected available ws-002 untouched, got %v", updated)
+	}
+}
+
+func TestRenewByNameRejectsAvailableWorkspace(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedBulkWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	if err := pool.RenewByName(repoPath, "ws-002"); !errors.Is(err, ErrWorkspaceNotAcquired) {
+		t.Fatalf("expected ErrWorkspaceNotAcquired, got %v", err)
+	}
+}
+
+func TestReleaseAllContinuesPastFailures(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedBulkWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// The seeded paths are not jj workspaces, so every release fails; both
+	// failures should be reported rather than stopping at the first.
+	released, err := pool.ReleaseAll(repoPath)
+	if err == nil {
+		t.Fatal("expected release failures")
+	}
+	if len(released) != 0 {
+		t.Fatalf("expected nothing released, got %v", released)
+	}
+	for _, name := range []string{"ws-001", "ws-003"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to mention %s, got %v", name, err)
+		}
+	}
+}
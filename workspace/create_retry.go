@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	internalstrings "github.com/amonks/incrementum/internal/strings"
+)
+
+// createRetryBackoff is the delay before the first workspace-creation retry.
+// Each later retry waits twice as long as the one before.
+const createRetryBackoff = 100 * time.Millisecond
+
+// addWorkspace runs jj workspace add, retrying transient failures up to the
+// pool's CreateRetries. Permanent failures are returned immediately.
+func (p *Pool) addWorkspace(repoPath, wsName, wsPath string) error {
+	add := p.workspaceAdd
+	if add == nil {
+		add = p.jj.WorkspaceAdd
+	}
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	attempts := 1 + max(p.createRetries, 0)
+	backoff := createRetryBackoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = add(repoPath, wsName, wsPath)
+		if err == nil {
+			return nil
+		}
+		if !isTransientJJError(err) || attempt == attempts {
+			break
+		}
+		// A failed add may leave a partial directory behind, which would
+		// make the next attempt fail.
+		_ = os.RemoveAll(wsPath)
+		sleep(backoff)
+		backoff *= 2
+	}
+	if attempts > 1 && isTransientJJError(err) {
+		return fmt.Errorf("after %d attempts: %w", attempts, err)
+	}
+	return err
+}
+
+// isTransientJJError reports whether a jj failure is likely to succeed when
+// retried, such as lock contention with a concurrent jj command. Missing
+// revisions and other user errors are permanent.
+func isTransientJJError(err error) bool {
+	if err == nil || isMissingRevisionError(err) {
+		return false
+	}
+	if internalstrings.ContainsAnyLower(err.Error(), "no such revision", "already exists", "not a jj repo", "no jj repo") {
+		return false
+	}
+	// jj killed by a signal has no exit code.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 {
+		return true
+	}
+	return internalstrings.ContainsAnyLower(err.Error(),
+		"failed to lock",
+		"lock file",
+		"resource temporarily unavailable",
+		"concurrent modification",
+		"timed out",
+	)
+}
@@ -0,0 +1,107 @@
+package workspace
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddWorkspaceRetriesTransientFailures(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), CreateRetries: 3})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	calls := 0
+	pool.workspaceAdd = func(string, string, string) error {
+		calls++
+		if calls <= 2 {
+			return errors.New("jj workspace add: exit status 1: Error: Failed to lock working copy")
+		}
+		return nil
+	}
+	var sleeps []time.Duration
+	pool.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	if err := pool.addWorkspace("repo", "ws-001", t.TempDir()); err != nil {
+		t.Fatalf("add workspace: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if len(sleeps) != 2 || sleeps[0] != createRetryBackoff || sleeps[1] != 2*createRetryBackoff {
+		t.Fatalf("expected exponential backoff, got %v", sleeps)
+	}
+}
+
+func TestAddWorkspaceReportsAttemptsWhenExhausted(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), CreateRetries: 2})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	calls := 0
+	lockErr := errors.New("jj workspace add: exit status 1: Error: Failed to lock working copy")
+	pool.workspaceAdd = func(string, string, string) error {
+		calls++
+		return lockErr
+	}
+	pool.sleep = func(time.Duration) {}
+
+	err = pool.addWorkspace("repo", "ws-001", t.TempDir())
+	if !errors.Is(err, lockErr) {
+		t.Fatalf("expected the last error to be wrapped, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected the attempt count in %q", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestAddWorkspaceFailsFastOnPermanentErrors(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), CreateRetries: 3})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	calls := 0
+	missing := errors.New(`jj workspace add: exit status 1: Error: Revision "abc" doesn't exist`)
+	pool.workspaceAdd = func(string, string, string) error {
+		calls++
+		return missing
+	}
+	pool.sleep = func(time.Duration) { t.Fatal("expected no retry") }
+
+	if err := pool.addWorkspace("repo", "ws-001", t.TempDir()); err != missing {
+		t.Fatalf("expected the permanent error unchanged, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestIsTransientJJError(t *testing.T) {
+	transient := []string{
+		"jj workspace add: exit status 1: Error: Failed to lock working copy",
+		"jj workspace add: exit status 1: Resource temporarily unavailable",
+		"jj workspace add: exit status 255: Concurrent modification detected",
+	}
+	for _, msg := range transient {
+		if !isTransientJJError(errors.New(msg)) {
+			t.Errorf("expected %q to be transient", msg)
+		}
+	}
+	permanent := []string{
+		"jj workspace add: exit status 1: Error: No such revision: abc",
+		`jj workspace add: exit status 1: Error: Workspace named "ws-001" already exists`,
+		"jj workspace add: exit status 1: Error: There is no jj repo in \".\"",
+	}
+	for _, msg := range permanent {
+		if isTransientJJError(errors.New(msg)) {
+			t.Errorf("expected %q to be permanent", msg)
+		}
+	}
+}
@@ -32,12 +32,15 @@
 //
 //	[workspace]
 //	on-create = ["npm install"]  # Run every time workspace is acquired
+//	on-create-timeout = "10m"    # Kill a hung on-create hook after this long
 //
 // # Storage
 //
 // By default, workspaces are stored in ~/.local/share/incrementum/workspaces/ and
 // state is stored in ~/.local/state/incrementum/. These locations follow the XDG
-// Base Directory Specification.
+// Base Directory Specification. The INCREMENTUM_WORKSPACES_DIR and
+// INCREMENTUM_STATE_DIR environment variables override them; explicit Options
+// take precedence over both.
 //
 // # Concurrency
 //
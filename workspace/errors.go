@@ -3,12 +3,35 @@ package workspace
 import (
 	"errors"
 
+	"github.com/amonks/incrementum/internal/jj"
 	statestore "github.com/amonks/incrementum/internal/state"
 )
 
 var (
 	// ErrWorkspaceRootNotFound indicates a path is not in a jj workspace.
 	ErrWorkspaceRootNotFound = errors.New("workspace root not found")
+	// ErrWorkspaceNotAcquired indicates an operation requires a held workspace.
+	ErrWorkspaceNotAcquired = errors.New("workspace is not acquired")
+	// ErrWorkspaceBusy indicates a named workspace is held for another purpose.
+	ErrWorkspaceBusy = errors.New("workspace is busy")
+	// ErrAcquireTimeout indicates Acquire gave up waiting for a free workspace.
+	ErrAcquireTimeout = errors.New("timed out waiting for a free workspace")
+	// ErrRevisionNotFound indicates Acquire was asked for a revision that does
+	// not exist.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrRefreshConflict indicates refreshing a reattached workspace onto the
+	// requested revision produced conflicts. The rebase is undone.
+	ErrRefreshConflict = errors.New("refresh produced conflicts")
+	// ErrHookTimeout indicates a workspace hook was killed for running longer
+	// than its configured timeout.
+	ErrHookTimeout = errors.New("hook timed out")
+	// ErrInvalidSelectionStrategy indicates Options.SelectionStrategy is not
+	// a known strategy.
+	ErrInvalidSelectionStrategy = errors.New("invalid selection strategy")
+	// ErrJujutsuNotFound indicates the jj executable is not on PATH.
+	ErrJujutsuNotFound = jj.ErrNotFound
+	// ErrJujutsuVersionUnsupported indicates the installed jj is too old.
+	ErrJujutsuVersionUnsupported = jj.ErrVersionUnsupported
 	// ErrRepoPathNotFound indicates a workspace is tracked but missing repo info.
 	ErrRepoPathNotFound = statestore.ErrRepoPathNotFound
 )
@@ -12,3 +12,29 @@ func TestWorkspaceErrorsAliasModel(t *testing.T) {
 		t.Fatalf("expected ErrRepoPathNotFound to wrap the state error")
 	}
 }
+
+func TestJujutsuVersionReportsCheckResult(t *testing.T) {
+	version, err := JujutsuVersion()
+	switch {
+	case err == nil:
+		if version == "" {
+			t.Fatal("expected a version when jj is installed")
+		}
+	case errors.Is(err, ErrJujutsuNotFound):
+		if version != "" {
+			t.Fatalf("expected no version when jj is missing, got %q", version)
+		}
+		if _, openErr := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir()}); openErr != nil {
+			t.Fatalf("expected opening the pool not to need jj, got %v", openErr)
+		}
+		if _, rootErr := RepoRoot(t.TempDir()); !errors.Is(rootErr, ErrJujutsuNotFound) {
+			t.Fatalf("expected the first jj command to report ErrJujutsuNotFound, got %v", rootErr)
+		}
+	case errors.Is(err, ErrJujutsuVersionUnsupported):
+		if version == "" {
+			t.Fatal("expected the unsupported version to be reported")
+		}
+	default:
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
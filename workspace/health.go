@@ -0,0 +1,125 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// HealthStatus summarizes whether a workspace's working copy is usable.
+type HealthStatus string
+
+const (
+	// HealthOK indicates jj can operate in the workspace.
+	HealthOK HealthStatus = "ok"
+	// HealthUnhealthy indicates the workspace directory is missing or jj
+	// cannot operate in it.
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// Health reports the result of checking one workspace.
+type Health struct {
+	// Name is the workspace identifier (e.g., "ws-001").
+	Name string
+
+	// Path is the absolute path to the workspace directory.
+	Path string
+
+	// Status is the outcome of the check.
+	Status HealthStatus
+
+	// Error describes why the workspace is unhealthy. Empty when healthy.
+	Error string
+}
+
+// Check runs a lightweight jj health check against every workspace for the
+// repository and returns the results ordered by name. It does not modify the
+// workspaces.
+func (p *Pool) Check(repoPath string) ([]Health, error) {
+	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("get repo name: %w", err)
+	}
+
+	st, err := p.stateStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load state: %w", err)
+	}
+
+	var results []Health
+	for _, ws := range st.Workspaces {
+		if ws.Repo != repoName {
+			continue
+		}
+		results = append(results, p.checkHealth(ws.Name, ws.Path))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+func (p *Pool) checkHealth(wsName, wsPath string) Health {
+	health := Health{Name: wsName, Path: wsPath, Status: HealthOK}
+	if err := p.checkWorkspace(wsPath); err != nil {
+		health.Status = HealthUnhealthy
+		health.Error = err.Error()
+	}
+	return health
+}
+
+func (p *Pool) checkWorkspace(wsPath string) error {
+	if _, err := os.Stat(wsPath); err != nil {
+		return fmt.Errorf("stat workspace: %w", err)
+	}
+	return p.jj.Status(wsPath)
+}
+
+// ensureHealthy brings a reused workspace up to date and, when jj still
+// cannot operate in it, recreates it in place under the same name. It
+// reports whether the workspace was recreated.
+func (p *Pool) ensureHealthy(repoPath, wsName, wsPath string) (bool, error) {
+	err := p.jj.WorkspaceUpdateStale(wsPath)
+	if err == nil {
+		err = p.checkWorkspace(wsPath)
+	}
+	if err == nil {
+		return false, nil
+	}
+
+	// The workspace may already be unknown to jj, so forgetting is best-effort.
+	_ = p.jj.WorkspaceForget(repoPath, wsName)
+	if err := os.RemoveAll(wsPath); err != nil {
+		return false, fmt.Errorf("remove unhealthy workspace: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(wsPath), 0755); err != nil {
+		return false, fmt.Errorf("create workspace parent dir: %w", err)
+	}
+	if addErr := p.addWorkspace(repoPath, wsName, wsPath); addErr != nil {
+		return false, fmt.Errorf("recreate workspace after failed health check (%v): %w", err, addErr)
+	}
+	return true, nil
+}
+
+// markUnhealthy records a failed health check so later acquisitions skip the
+// workspace, and gives back this process's claim on it so it is not left
+// acquired until its lease expires.
+func (p *Pool) markUnhealthy(repoName, wsName string) error {
+	return p.stateStore.Update(func(st *statestore.State) error {
+		wsKey := repoName + "/" + wsName
+		if ws, ok := st.Workspaces[wsKey]; ok {
+			ws.Unhealthy = true
+			ws.Provisioned = false
+			if ws.Status == statestore.WorkspaceStatusAcquired && ws.AcquiredByPID == os.Getpid() {
+				clearAcquisition(&ws, time.Now())
+			}
+			st.Workspaces[wsKey] = ws
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package workspace
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestCheckReportsMissingWorkspaceUnhealthy(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAvailableWorkspace(t, stateDir, repoPath, "")
+
+	missing := filepath.Join(t.TempDir(), "gone")
+	err := statestore.NewStore(stateDir).Update(func(st *statestore.State) error {
+		ws := st.Workspaces[repoName+"/ws-001"]
+		ws.Path = missing
+		st.Workspaces[repoName+"/ws-001"] = ws
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update state: %v", err)
+	}
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	results, err := pool.Check(repoPath)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Name != "ws-001" || results[0].Path != missing {
+		t.Fatalf("unexpected result: %+v", results[0])
+	}
+	if results[0].Status != HealthUnhealthy || results[0].Error == "" {
+		t.Fatalf("expected unhealthy result with error, got %+v", results[0])
+	}
+}
+
+func TestAcquireMarksWorkspaceUnhealthyWhenRepairFails(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAvailableWorkspace(t, stateDir, repoPath, "")
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir(), Warnings: io.Discard})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// repoPath is not a jj repo, so neither the health check nor recreating
+	// the workspace can succeed.
+	if _, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "job"}); err == nil {
+		t.Fatal("expected acquire to fail")
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	ws := st.Workspaces[repoName+"/ws-001"]
+	if !ws.Unhealthy {
+		t.Fatal("expected workspace to be marked unhealthy")
+	}
+	if ws.Provisioned {
+		t.Fatal("expected unhealthy workspace to need provisioning again")
+	}
+	if ws.Status != statestore.WorkspaceStatusAvailable || ws.AcquiredByPID != 0 {
+		t.Fatalf("expected unhealthy workspace to be given back, got %+v", ws)
+	}
+}
+
+func TestAcquireSkipsUnhealthyWorkspaces(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAvailableWorkspace(t, stateDir, repoPath, "")
+
+	store := statestore.NewStore(stateDir)
+	err := store.Update(func(st *statestore.State) error {
+		ws := st.Workspaces[repoName+"/ws-001"]
+		ws.Unhealthy = true
+		st.Workspaces[repoName+"/ws-001"] = ws
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update state: %v", err)
+	}
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// Creating a replacement fails outside a jj repo; the point is that the
+	// unhealthy workspace is not handed out.
+	if _, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "job"}); err == nil {
+		t.Fatal("expected creating a new workspace outside a jj repo to fail")
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if status := st.Workspaces[repoName+"/ws-001"].Status; status != statestore.WorkspaceStatusAvailable {
+		t.Fatalf("expected unhealthy workspace to stay available, got %q", status)
+	}
+}
@@ -0,0 +1,94 @@
+package workspace
+
+import (
+	"path/filepath"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// SetDefaultTTL sets how long an acquired workspace's lease lasts without
+// being renewed. Once a lease has expired, an Acquire that finds the pool at
+// MaxPoolSize may reclaim the workspace. Zero (the default) means leases
+// never expire.
+func (p *Pool) SetDefaultTTL(d time.Duration) {
+	p.leaseMu.Lock()
+	defer p.leaseMu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	p.defaultTTL = d
+}
+
+func (p *Pool) ttl() time.Duration {
+	p.leaseMu.Lock()
+	defer p.leaseMu.Unlock()
+	return p.defaultTTL
+}
+
+// leaseExpired reports whether an acquired workspace's lease is older than
+// ttl. A zero ttl never expires.
+func leaseExpired(ws statestore.WorkspaceInfo, ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && !ws.UpdatedAt.IsZero() && now.Sub(ws.UpdatedAt) > ttl
+}
+
+// startAutoRenew renews the lease on wsPath every half TTL until the
+// workspace is released. The goroutine lives in this process, so if the
+// process exits the renewals stop and the lease expires normally.
+func (p *Pool) startAutoRenew(wsPath string) {
+	ttl := p.ttl()
+	if ttl <= 0 {
+		return
+	}
+	wsPath = filepath.Clean(wsPath)
+
+	stop := make(chan struct{})
+	p.leaseMu.Lock()
+	if p.renewers == nil {
+		p.renewers = make(map[string]chan struct{})
+	}
+	if existing, ok := p.renewers[wsPath]; ok {
+		close(existing)
+	}
+	p.renewers[wsPath] = stop
+	p.leaseMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = p.renewPath(wsPath)
+			}
+		}
+	}()
+}
+
+// stopAutoRenew stops renewing the lease on wsPath, if it was being renewed.
+func (p *Pool) stopAutoRenew(wsPath string) {
+	wsPath = filepath.Clean(wsPath)
+	p.leaseMu.Lock()
+	defer p.leaseMu.Unlock()
+	if stop, ok := p.renewers[wsPath]; ok {
+		close(stop)
+		delete(p.renewers, wsPath)
+	}
+}
+
+// renewPath refreshes the lease on the acquired workspace at wsPath.
+func (p *Pool) renewPath(wsPath string) error {
+	return p.stateStore.Update(func(st *statestore.State) error {
+		for key, ws := range st.Workspaces {
+			if filepath.Clean(ws.Path) != wsPath || ws.Status != statestore.WorkspaceStatusAcquired {
+				continue
+			}
+			ws.UpdatedAt = time.Now()
+			st.Workspaces[key] = ws
+			return nil
+		}
+		return nil
+	})
+}
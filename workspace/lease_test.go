@@ -0,0 +1,126 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func ageWorkspaceLease(t *testing.T, stateDir, repoName string, age time.Duration) time.Time {
+	t.Helper()
+	updated := time.Now().Add(-age)
+	err := statestore.NewStore(stateDir).Update(func(st *statestore.State) error {
+		ws := st.Workspaces[repoName+"/ws-001"]
+		ws.UpdatedAt = updated
+		st.Workspaces[repoName+"/ws-001"] = ws
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update state: %v", err)
+	}
+	return updated
+}
+
+func TestAcquireReclaimsExpiredLease(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+	ageWorkspaceLease(t, stateDir, repoName, time.Hour)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.SetDefaultTTL(time.Minute)
+
+	// The seeded workspace is not a real jj workspace. Let the health-check
+	// repair "recreate" it so the claim survives, and Acquire fails later on;
+	// the claim itself is what this test checks.
+	pool.workspaceAdd = func(_, _, workspacePath string) error {
+		return os.MkdirAll(workspacePath, 0755)
+	}
+	_, err = pool.Acquire(repoPath, AcquireOptions{
+		Purpose:     "reclaimer",
+		MaxPoolSize: 1,
+		WaitTimeout: time.Second,
+	})
+	if errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected expired lease to be reclaimed, got %v", err)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if purpose := st.Workspaces[repoName+"/ws-001"].Purpose; purpose != "reclaimer" {
+		t.Fatalf("expected workspace claimed by reclaimer, got %q", purpose)
+	}
+}
+
+func TestAcquireWaitsWhenLeasesDoNotExpire(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+	ageWorkspaceLease(t, stateDir, repoName, time.Hour)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	_, err = pool.Acquire(repoPath, AcquireOptions{
+		Purpose:     "waiter",
+		MaxPoolSize: 1,
+		WaitTimeout: 150 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected ErrAcquireTimeout without a TTL, got %v", err)
+	}
+}
+
+func TestAutoRenewRefreshesLeaseUntilStopped(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+	aged := ageWorkspaceLease(t, stateDir, repoName, time.Hour)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.SetDefaultTTL(40 * time.Millisecond)
+
+	store := statestore.NewStore(stateDir)
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	wsPath := st.Workspaces[repoName+"/ws-001"].Path
+
+	pool.startAutoRenew(wsPath)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		st, err = store.Load()
+		if err != nil {
+			t.Fatalf("load state: %v", err)
+		}
+		if st.Workspaces[repoName+"/ws-001"].UpdatedAt.After(aged) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected auto-renew to refresh the lease")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	pool.stopAutoRenew(wsPath)
+	pool.leaseMu.Lock()
+	remaining := len(pool.renewers)
+	pool.leaseMu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("expected renewer to be removed, got %d", remaining)
+	}
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"testing"
+)
+
+func TestListWithOptionsIncludesSizes(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	seedPruneWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	items, err := pool.ListWithOptions(repoPath, ListOptions{IncludeSizes: true})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 workspaces, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.SizeBytes != 10 {
+			t.Fatalf("expected %s to be 10 bytes, got %d", item.Name, item.SizeBytes)
+		}
+	}
+
+	items, err = pool.List(repoPath)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, item := range items {
+		if item.SizeBytes != 0 {
+			t.Fatalf("expected List to skip sizes, got %d for %s", item.SizeBytes, item.Name)
+		}
+	}
+}
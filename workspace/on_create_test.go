@@ -0,0 +1,117 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestRunOnCreateLimitsConcurrency(t *testing.T) {
+	pool, err := OpenWithOptions(Options{
+		StateDir:            t.TempDir(),
+		WorkspacesDir:       t.TempDir(),
+		OnCreateConcurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "running")
+	// Each hook fails if another hook is running at the same time.
+	script := fmt.Sprintf("set -e\nif [ -e %q ]; then exit 1; fi\ntouch %q\nsleep 0.05\nrm %q\n", lockPath, lockPath, lockPath)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- pool.runOnCreate(context.Background(), dir, script, 0)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected serialized on-create hooks, got %v", err)
+		}
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("expected marker to be removed, got %v", err)
+	}
+}
+
+func TestRunOnCreateSkipsBlankScript(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), OnCreateConcurrency: 1})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	if err := pool.runOnCreate(context.Background(), t.TempDir(), strings.Repeat(" ", 3), 0); err != nil {
+		t.Fatalf("expected blank script to be a no-op, got %v", err)
+	}
+}
+
+func TestRunOnCreateKillsHookAfterTimeout(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.runScript = func(ctx context.Context, _, _ string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	wsPath := t.TempDir()
+	err = pool.runOnCreate(context.Background(), wsPath, "#!/bin/bash\nnpm install\n", 20*time.Millisecond)
+	if !errors.Is(err, ErrHookTimeout) {
+		t.Fatalf("expected ErrHookTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"npm install" killed after`) {
+		t.Fatalf("expected the error to name the command, got %v", err)
+	}
+
+	pool.discardWorkspace(repoPath, repoName, "ws-001", wsPath)
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if ws, ok := st.Workspaces[repoName+"/ws-001"]; ok {
+		t.Fatalf("expected the timed-out workspace to leave the pool, got %+v", ws)
+	}
+	if _, err := os.Stat(wsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the workspace directory to be removed, got %v", err)
+	}
+}
+
+func TestRunOnCreateWithoutTimeoutPassesErrorsThrough(t *testing.T) {
+	pool, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	hookErr := errors.New("exit status 1")
+	pool.runScript = func(ctx context.Context, _, _ string) error {
+		if ctx.Done() != nil {
+			t.Error("expected no deadline without a timeout")
+		}
+		return hookErr
+	}
+
+	if err := pool.runOnCreate(context.Background(), t.TempDir(), "make setup", 0); err != hookErr {
+		t.Fatalf("expected the hook error unchanged, got %v", err)
+	}
+}
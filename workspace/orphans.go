@@ -0,0 +1,92 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// Orphans returns the repository's acquired workspaces whose holder process
+// ran on this host and no longer exists, ordered by name. Workspaces held
+// from other hosts are never reported, since their processes cannot be
+// checked from here.
+func (p *Pool) Orphans(repoPath string) ([]Info, error) {
+	acquired, err := p.acquiredWorkspaces(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]Info, 0)
+	for _, ws := range acquired {
+		if holderExited(ws, p.hostname) {
+			orphans = append(orphans, newInfo(ws))
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Name < orphans[j].Name
+	})
+	return orphans, nil
+}
+
+// ReapOrphans releases every workspace Orphans reports and returns the names
+// of the workspaces it released, ordered by name.
+//
+// Each orphan is re-checked and taken over by this process under the state
+// lock before its working copy is touched, so a workspace that another
+// process reclaimed in the meantime is skipped rather than released out from
+// under its new holder. A workspace that fails to release does not stop the
+// others from being released; all failures are returned as a joined error.
+func (p *Pool) ReapOrphans(repoPath string) ([]string, error) {
+	orphans, err := p.Orphans(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reaped := make([]string, 0, len(orphans))
+	var errs []error
+	for _, ws := range orphans {
+		claimed, err := p.claimOrphan(ws)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("claim %s: %w", ws.Name, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := p.releaseToAvailable(ws.Path); err != nil {
+			errs = append(errs, fmt.Errorf("release %s: %w", ws.Name, err))
+			continue
+		}
+		reaped = append(reaped, ws.Name)
+	}
+	return reaped, errors.Join(errs...)
+}
+
+// claimOrphan makes this process the holder of an orphan reported by
+// Orphans. It reports false, changing nothing, when the workspace has changed
+// hands or its holder is no longer known to have exited.
+func (p *Pool) claimOrphan(orphan Info) (bool, error) {
+	claimed := false
+	err := p.stateStore.Update(func(st *statestore.State) error {
+		for key, ws := range st.Workspaces {
+			if ws.Path != orphan.Path {
+				continue
+			}
+			if ws.AcquiredByPID != orphan.AcquiredByPID || ws.AcquiredByHost != orphan.AcquiredByHost || !holderExited(ws, p.hostname) {
+				return nil
+			}
+			ws.AcquiredByPID = os.Getpid()
+			ws.AcquiredByHost = p.hostname
+			ws.UpdatedAt = time.Now()
+			st.Workspaces[key] = ws
+			claimed = true
+			return nil
+		}
+		return nil
+	})
+	return claimed, err
+}
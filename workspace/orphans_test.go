@@ -0,0 +1,168 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("run true: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestOrphansReportsDeadLocalHolders(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.hostname = "this-host"
+
+	dead := deadPID(t)
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+	err = store.Update(func(st *statestore.State) error {
+		for _, seed := range []struct {
+			name   string
+			status statestore.WorkspaceStatus
+			pid    int
+			host   string
+		}{
+			{"ws-001", statestore.WorkspaceStatusAcquired, dead, "this-host"},
+			{"ws-002", statestore.WorkspaceStatusAcquired, os.Getpid(), "this-host"},
+			{"ws-003", statestore.WorkspaceStatusAcquired, dead, "other-host"},
+			{"ws-004", statestore.WorkspaceStatusAcquired, dead, ""},
+			{"ws-005", statestore.WorkspaceStatusAvailable, 0, ""},
+		} {
+			st.Workspaces[repoName+"/"+seed.name] = statestore.WorkspaceInfo{
+				Name:           seed.name,
+				Repo:           repoName,
+				Path:           filepath.Join(t.TempDir(), seed.name),
+				Purpose:        "crashed job",
+				Status:         seed.status,
+				AcquiredByPID:  seed.pid,
+				AcquiredByHost: seed.host,
+				UpdatedAt:      time.Now(),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	orphans, err := pool.Orphans(repoPath)
+	if err != nil {
+		t.Fatalf("orphans: %v", err)
+	}
+	if len(orphans) != 2 || orphans[0].Name != "ws-001" || orphans[1].Name != "ws-004" {
+		t.Fatalf("expected ws-001 and ws-004 orphaned, got %+v", orphans)
+	}
+	if orphans[0].AcquiredByPID != dead || orphans[0].AcquiredByHost != "this-host" {
+		t.Fatalf("expected orphan to report its holder, got %+v", orphans[0])
+	}
+
+	// The seeded paths are not jj workspaces, so releasing fails; the
+	// failures should name exactly the orphans.
+	reaped, err := pool.ReapOrphans(repoPath)
+	if err == nil {
+		t.Fatal("expected release failures")
+	}
+	if len(reaped) != 0 {
+		t.Fatalf("expected nothing reaped, got %v", reaped)
+	}
+	for _, name := range []string{"ws-001", "ws-004"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to mention %s, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"ws-002", "ws-003"} {
+		if strings.Contains(err.Error(), name) {
+			t.Fatalf("expected %s not to be reaped, got %v", name, err)
+		}
+	}
+}
+
+func TestReapOrphansSkipsReclaimedWorkspaces(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.hostname = "this-host"
+
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+	key := repoName + "/ws-001"
+	err = store.Update(func(st *statestore.State) error {
+		st.Workspaces[key] = statestore.WorkspaceInfo{
+			Name:           "ws-001",
+			Repo:           repoName,
+			Path:           filepath.Join(t.TempDir(), "ws-001"),
+			Purpose:        "crashed job",
+			Status:         statestore.WorkspaceStatusAcquired,
+			AcquiredByPID:  deadPID(t),
+			AcquiredByHost: "this-host",
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	orphans, err := pool.Orphans(repoPath)
+	if err != nil {
+		t.Fatalf("orphans: %v", err)
+	}
+	if len(orphans) != 1 {
+		t.Fatalf("expected one orphan, got %+v", orphans)
+	}
+
+	// Another process reclaims the workspace after it was reported.
+	err = store.Update(func(st *statestore.State) error {
+		ws := st.Workspaces[key]
+		ws.Purpose = "new job"
+		ws.AcquiredByPID = os.Getpid()
+		st.Workspaces[key] = ws
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("reclaim: %v", err)
+	}
+
+	claimed, err := pool.claimOrphan(orphans[0])
+	if err != nil {
+		t.Fatalf("claim orphan: %v", err)
+	}
+	if claimed {
+		t.Fatal("expected reclaimed workspace not to be claimed")
+	}
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if ws := st.Workspaces[key]; ws.Status != statestore.WorkspaceStatusAcquired || ws.Purpose != "new job" || ws.AcquiredByPID != os.Getpid() {
+		t.Fatalf("expected reclaimed workspace untouched, got %+v", ws)
+	}
+}
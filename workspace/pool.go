@@ -1,11 +1,15 @@
 package workspace
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/amonks/incrementum/internal/config"
@@ -24,28 +28,115 @@ type Pool struct {
 	stateStore    *statestore.Store
 	workspacesDir string
 	jj            *jj.Client
+	onCreateSlots chan struct{}
+
+	slowAcquireThreshold time.Duration
+	warnings             io.Writer
+
+	selection SelectionStrategy
+
+	// hostname identifies this machine in acquisition records, so dead
+	// holders are only detected for processes that ran here.
+	hostname string
+
+	createRetries  int
+	workspaceAdd   func(repoPath, name, workspacePath string) error
+	sleep          func(time.Duration)
+	revisionExists func(repoPath, rev string) (bool, error)
+	runScript      func(ctx context.Context, dir, script string) error
+
+	refreshWorkspace func(wsPath, rev string) (bool, error)
+
+	leaseMu    sync.Mutex
+	defaultTTL time.Duration
+	renewers   map[string]chan struct{}
 }
 
 // Options configures a workspace pool.
 type Options struct {
 	// StateDir is the directory where pool state is stored.
-	// Defaults to ~/.local/state/incrementum if empty.
+	// Defaults to $INCREMENTUM_STATE_DIR, then ~/.local/state/incrementum,
+	// if empty.
 	StateDir string
 
 	// WorkspacesDir is the directory where workspaces are created.
-	// Defaults to ~/.local/share/incrementum/workspaces if empty.
+	// Defaults to $INCREMENTUM_WORKSPACES_DIR, then
+	// ~/.local/share/incrementum/workspaces, if empty.
 	WorkspacesDir string
+
+	// OnCreateConcurrency limits how many on-create hooks this pool runs at
+	// once across concurrent acquisitions. Zero or negative means unlimited.
+	OnCreateConcurrency int
+
+	// SlowAcquireThreshold makes Acquire write a warning naming the slowest
+	// phase when an acquire takes longer than this. Zero disables the
+	// warning.
+	SlowAcquireThreshold time.Duration
+
+	// Warnings receives slow-acquire and unhealthy-workspace warnings.
+	// Defaults to os.Stderr.
+	Warnings io.Writer
+
+	// CreateRetries is how many more times Acquire retries jj workspace add
+	// after a transient failure, such as lock contention with a concurrent
+	// acquire. Retries back off exponentially from 100ms. Permanent
+	// failures are never retried. Zero disables retries.
+	CreateRetries int
+
+	// SelectionStrategy chooses which available workspace Acquire reuses
+	// when several are free. Defaults to StrategyAny.
+	SelectionStrategy SelectionStrategy
 }
 
+// SelectionStrategy chooses among available workspaces on acquire.
+type SelectionStrategy string
+
+const (
+	// StrategyAny reuses whichever available workspace is found first.
+	StrategyAny SelectionStrategy = ""
+
+	// StrategyMRU reuses the most recently released workspace, whose build
+	// caches are most likely to still be warm.
+	StrategyMRU SelectionStrategy = "mru"
+
+	// StrategyLRU reuses the least recently released workspace, spreading
+	// use evenly across the pool.
+	StrategyLRU SelectionStrategy = "lru"
+)
+
 // Open creates a new Pool with default options.
-// State is stored in ~/.local/state/incrementum and workspaces in
-// ~/.local/share/incrementum/workspaces.
+// State is stored in $INCREMENTUM_STATE_DIR and workspaces in
+// $INCREMENTUM_WORKSPACES_DIR when set, otherwise in
+// ~/.local/state/incrementum and ~/.local/share/incrementum/workspaces.
+//
+// Open does not shell out to jj, so state-only operations work without it.
+// The first operation that runs jj fails with an error wrapping
+// ErrJujutsuNotFound or ErrJujutsuVersionUnsupported when a supported jj is
+// not installed.
 func Open() (*Pool, error) {
 	return OpenWithOptions(Options{})
 }
 
+// JujutsuVersion returns the installed jj version, such as "0.28.2". It
+// returns ErrJujutsuNotFound when jj is not on PATH and
+// ErrJujutsuVersionUnsupported (along with the version) when it is too old.
+// The check runs once per process.
+func JujutsuVersion() (string, error) {
+	version, err := jj.CheckInstalled()
+	if version == (jj.Version{}) {
+		return "", err
+	}
+	return version.String(), err
+}
+
 // OpenWithOptions creates a new Pool with custom options.
 func OpenWithOptions(opts Options) (*Pool, error) {
+	switch opts.SelectionStrategy {
+	case StrategyAny, StrategyMRU, StrategyLRU:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSelectionStrategy, opts.SelectionStrategy)
+	}
+
 	stateDir, err := paths.ResolveWithDefault(opts.StateDir, paths.DefaultStateDir)
 	if err != nil {
 		return nil, err
@@ -56,11 +147,29 @@ func OpenWithOptions(opts Options) (*Pool, error) {
 		return nil, err
 	}
 
-	return &Pool{
+	pool := &Pool{
 		stateStore:    statestore.NewStore(stateDir),
 		workspacesDir: workspacesDir,
 		jj:            jj.New(),
-	}, nil
+
+		slowAcquireThreshold: opts.SlowAcquireThreshold,
+		warnings:             opts.Warnings,
+
+		selection: opts.SelectionStrategy,
+
+		createRetries: opts.CreateRetries,
+	}
+	pool.hostname, _ = os.Hostname()
+	pool.revisionExists = pool.jj.RevisionExists
+	pool.runScript = config.RunScriptContext
+	pool.refreshWorkspace = pool.refreshWithJJ
+	if pool.warnings == nil {
+		pool.warnings = os.Stderr
+	}
+	if opts.OnCreateConcurrency > 0 {
+		pool.onCreateSlots = make(chan struct{}, opts.OnCreateConcurrency)
+	}
+	return pool, nil
 }
 
 // RepoSlug returns the repo slug used for state storage.
@@ -84,8 +193,54 @@ type AcquireOptions struct {
 	// NewChangeMessage is an optional description to apply when a new change
 	// is created because the requested revision is immutable.
 	NewChangeMessage string
+
+	// Labels are optional key/value annotations stored with the acquisition.
+	// They are cleared when the workspace is released.
+	Labels map[string]string
+
+	// MaxPoolSize caps how many workspaces the repo may have in Pool. When
+	// the pool is at capacity, Acquire waits for a workspace to be released.
+	// Zero means unlimited.
+	MaxPoolSize int
+
+	// Pool is an isolation key. Workspaces created under one pool are only
+	// handed out to acquisitions with the same pool, so unrelated callers do
+	// not share workspaces (or their on-create state). Empty is the shared
+	// default pool.
+	Pool string
+
+	// Name requests a specific workspace instead of any free one. The named
+	// workspace is reused when available or already acquired for the same
+	// Purpose, and created when it does not exist. If another purpose holds
+	// it, Acquire returns ErrWorkspaceBusy.
+	Name string
+
+	// AutoRenew keeps the lease alive by renewing it every half of the pool's
+	// default TTL (see SetDefaultTTL) until the workspace is released. It
+	// has no effect when leases do not expire.
+	AutoRenew bool
+
+	// RefreshToRev rebases a reattached workspace's work onto Rev so a
+	// long-held workspace does not stay on a stale base. A rebase that
+	// conflicts is undone and fails the acquire with ErrRefreshConflict.
+	// Freshly claimed workspaces always start at Rev, and Rev "@" is never
+	// refreshed.
+	RefreshToRev bool
+
+	// WaitTimeout bounds how long Acquire waits for a free workspace when the
+	// pool is at capacity, after which it returns ErrAcquireTimeout.
+	// Zero waits indefinitely.
+	WaitTimeout time.Duration
+
+	// OnProgress, when set, is called as Acquire enters each phase so
+	// callers can report progress. Phases that do not apply (such as
+	// creating, for a reused workspace) are skipped.
+	OnProgress func(AcquirePhase)
 }
 
+// acquirePollInterval is how often a blocked Acquire rechecks the pool.
+const acquirePollInterval = 100 * time.Millisecond
+
 // ValidateAcquirePurpose ensures the purpose is present and single-line.
 func ValidateAcquirePurpose(purpose string) error {
 	if internalstrings.IsBlank(purpose) {
@@ -97,6 +252,34 @@ func ValidateAcquirePurpose(purpose string) error {
 	return nil
 }
 
+// ValidateAcquirePool ensures the pool key is single-line.
+func ValidateAcquirePool(pool string) error {
+	if strings.ContainsAny(pool, "\r\n") {
+		return fmt.Errorf("pool must be a single line")
+	}
+	return nil
+}
+
+// ValidateWorkspaceName ensures a caller-supplied workspace name is usable as
+// a directory and jj workspace name.
+func ValidateWorkspaceName(name string) error {
+	if internalstrings.IsBlank(name) {
+		return fmt.Errorf("workspace name is required")
+	}
+	if name == "default" {
+		return fmt.Errorf("workspace name %q is reserved", name)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		case r == '.' && i > 0:
+		default:
+			return fmt.Errorf("invalid workspace name %q: use letters, digits, '-', '_', or '.'", name)
+		}
+	}
+	return nil
+}
+
 // Acquire obtains a workspace from the pool for the given repository.
 //
 // If an available workspace exists, it will be reused. Otherwise, a new
@@ -108,8 +291,19 @@ func ValidateAcquirePurpose(purpose string) error {
 //
 // If the repository contains an incrementum.toml or .incrementum/config.toml
 // configuration file, the on-create hooks run on every acquire.
-
 func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
+	return p.AcquireContext(context.Background(), repoPath, opts)
+}
+
+// AcquireContext is like Acquire, but gives up when ctx is done: waiting for
+// a free workspace or hook slot stops, a running on-create hook is killed,
+// and a workspace claimed by this call is returned to the pool (or discarded
+// if the hook was interrupted) rather than left half-created. The returned
+// error wraps ctx.Err().
+func (p *Pool) AcquireContext(ctx context.Context, repoPath string, opts AcquireOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("acquire workspace: %w", err)
+	}
 	// Apply defaults
 	if opts.Rev == "" {
 		opts.Rev = "@"
@@ -117,6 +311,17 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 	if err := ValidateAcquirePurpose(opts.Purpose); err != nil {
 		return "", err
 	}
+	if opts.Name != "" {
+		if err := ValidateWorkspaceName(opts.Name); err != nil {
+			return "", err
+		}
+	}
+	if err := ValidateAcquirePool(opts.Pool); err != nil {
+		return "", err
+	}
+	if err := p.checkRevision(repoPath, opts.Rev); err != nil {
+		return "", err
+	}
 
 	// Get the repo name (creates entry if needed)
 	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
@@ -124,69 +329,221 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 		return "", fmt.Errorf("get repo name: %w", err)
 	}
 
+	timer := newAcquireTimer(time.Now, opts.OnProgress)
+	timer.enter(AcquirePhaseSelecting)
+
 	var wsPath string
 	var wsName string
 	var needsCreate bool
 	var needsProvision bool
+	var reattached bool
+
+	claim := func(ws statestore.WorkspaceInfo, now time.Time) statestore.WorkspaceInfo {
+		wsPath = ws.Path
+		wsName = ws.Name
+		needsProvision = !ws.Provisioned
+
+		ws.Unhealthy = false
+		ws.RefreshedAt = time.Time{}
+		ws.Status = statestore.WorkspaceStatusAcquired
+		ws.Purpose = opts.Purpose
+		ws.Rev = opts.Rev
+		ws.Labels = copyLabels(opts.Labels)
+		ws.AcquiredByPID = os.Getpid()
+		ws.AcquiredByHost = p.hostname
+		ws.AcquiredAt = now
+		ws.CreatedAt = now
+		ws.UpdatedAt = now
+		return ws
+	}
 
-	// Find or create a workspace
-	err = p.stateStore.Update(func(st *statestore.State) error {
-		now := time.Now()
+	ttl := p.ttl()
+
+	var deadline time.Time
+	if opts.WaitTimeout > 0 {
+		deadline = time.Now().Add(opts.WaitTimeout)
+	}
+
+	for {
+		needsCreate = false
+		needsProvision = false
+		reattached = false
+
+		// Find or create a workspace, waiting for one to free up when the pool
+		// is at capacity.
+		for {
+			full := false
+			err = p.stateStore.Update(func(st *statestore.State) error {
+				now := time.Now()
+
+				if opts.Name != "" {
+					// A named workspace is reused when free or already held for
+					// the same purpose; otherwise it is created below.
+					key := repoName + "/" + opts.Name
+					if ws, ok := st.Workspaces[key]; ok {
+						switch {
+						case ws.Pool != opts.Pool:
+							return fmt.Errorf("%w: %s belongs to pool %q", ErrWorkspaceBusy, opts.Name, ws.Pool)
+						case ws.Status == statestore.WorkspaceStatusAvailable:
+							st.Workspaces[key] = claim(ws, now)
+							return nil
+						case ws.Status == statestore.WorkspaceStatusAcquired && ws.Purpose == opts.Purpose:
+							wsPath = ws.Path
+							wsName = ws.Name
+							reattached = true
+							ws.AcquiredByPID = os.Getpid()
+							ws.AcquiredByHost = p.hostname
+							if opts.Labels != nil {
+								ws.Labels = copyLabels(opts.Labels)
+							}
+							ws.UpdatedAt = now
+							st.Workspaces[key] = ws
+							return nil
+						default:
+							return fmt.Errorf("%w: %s is held for %q", ErrWorkspaceBusy, opts.Name, ws.Purpose)
+						}
+					}
+				} else {
+					// Find an available workspace, skipping ones that failed
+					// their last health check.
+					if key, ok := selectAvailable(st, repoName, opts.Pool, p.selection); ok {
+						st.Workspaces[key] = claim(st.Workspaces[key], now)
+						return nil
+					}
+				}
+
+				if opts.MaxPoolSize > 0 && countPoolWorkspaces(st, repoName, opts.Pool) >= opts.MaxPoolSize {
+					if opts.Name != "" {
+						full = true
+						return nil
+					}
+					// Reclaim a workspace whose holder exited without releasing
+					// or whose lease expired.
+					for key, ws := range st.Workspaces {
+						if ws.Repo != repoName || ws.Pool != opts.Pool || ws.Status != statestore.WorkspaceStatusAcquired {
+							continue
+						}
+						if holderExited(ws, p.hostname) || leaseExpired(ws, ttl, now) {
+							st.Workspaces[key] = claim(ws, now)
+							return nil
+						}
+					}
+					full = true
+					return nil
+				}
+
+				// No available workspace - create a new one
+				wsName = opts.Name
+				if wsName == "" {
+					wsName = p.nextWorkspaceName(st, repoName)
+				}
+				wsPath = filepath.Join(p.workspacesDir, repoName, wsName)
+				needsCreate = true
+				needsProvision = true
+
+				wsKey := repoName + "/" + wsName
+				st.Workspaces[wsKey] = statestore.WorkspaceInfo{
+					Name:           wsName,
+					Repo:           repoName,
+					Path:           wsPath,
+					Purpose:        opts.Purpose,
+					Pool:           opts.Pool,
+					Rev:            opts.Rev,
+					Labels:         copyLabels(opts.Labels),
+					Status:         statestore.WorkspaceStatusAcquired,
+					AcquiredByPID:  os.Getpid(),
+					AcquiredByHost: p.hostname,
+					AcquiredAt:     now,
+					CreatedAt:      now,
+					UpdatedAt:      now,
+					Provisioned:    false,
+				}
 
-		// Find an available workspace
-		for key, ws := range st.Workspaces {
-			if ws.Repo == repoName && ws.Status == statestore.WorkspaceStatusAvailable {
-				wsPath = ws.Path
-				wsName = ws.Name
-				needsProvision = !ws.Provisioned
-
-				// Acquire it
-				ws.Status = statestore.WorkspaceStatusAcquired
-				ws.Purpose = opts.Purpose
-				ws.Rev = opts.Rev
-				ws.AcquiredByPID = os.Getpid()
-				ws.AcquiredAt = now
-				ws.CreatedAt = now
-				ws.UpdatedAt = now
-				st.Workspaces[key] = ws
 				return nil
+			})
+			if err != nil {
+				return "", err
+			}
+			if !full {
+				break
+			}
+			if !deadline.IsZero() && !time.Now().Before(deadline) {
+				return "", fmt.Errorf("%w: %d workspaces in use", ErrAcquireTimeout, opts.MaxPoolSize)
+			}
+			select {
+			case <-ctx.Done():
+				return "", fmt.Errorf("acquire workspace: %w", ctx.Err())
+			case <-time.After(acquirePollInterval):
 			}
 		}
 
-		// No available workspace - create a new one
-		wsName = p.nextWorkspaceName(st, repoName)
-		wsPath = filepath.Join(p.workspacesDir, repoName, wsName)
-		needsCreate = true
-		needsProvision = true
+		// Reattaching to a workspace already held for this purpose keeps its
+		// working copy as-is.
+		if reattached {
+			if opts.RefreshToRev && opts.Rev != "@" {
+				timer.enter(AcquirePhaseCheckingOut)
+				if err := p.refreshReattached(repoName, wsName, wsPath, opts.Rev); err != nil {
+					return "", err
+				}
+			}
+			if opts.AutoRenew {
+				p.startAutoRenew(wsPath)
+			}
+			warnIfSlowAcquire(p.warnings, p.slowAcquireThreshold, wsName, timer)
+			return wsPath, nil
+		}
 
-		wsKey := repoName + "/" + wsName
-		st.Workspaces[wsKey] = statestore.WorkspaceInfo{
-			Name:          wsName,
-			Repo:          repoName,
-			Path:          wsPath,
-			Purpose:       opts.Purpose,
-			Rev:           opts.Rev,
-			Status:        statestore.WorkspaceStatusAcquired,
-			AcquiredByPID: os.Getpid(),
-			AcquiredAt:    now,
-			CreatedAt:     now,
-			UpdatedAt:     now,
-			Provisioned:   false,
+		// Make sure a reused workspace is usable, recreating it under the same
+		// name if jj cannot operate in it. One that cannot be recreated is
+		// marked unhealthy and given back, and an unnamed acquire moves on to
+		// the next candidate.
+		if needsCreate {
+			break
+		}
+		recreated, err := p.ensureHealthy(repoPath, wsName, wsPath)
+		if err == nil {
+			if recreated {
+				needsProvision = true
+			}
+			break
 		}
+		p.markUnhealthy(repoName, wsName)
+		if opts.Name != "" {
+			return "", fmt.Errorf("workspace %s is unhealthy: %w", wsName, err)
+		}
+		fmt.Fprintf(p.warnings, "workspace %s is unhealthy, skipping it: %v\n", wsName, err)
+	}
 
-		return nil
-	})
-	if err != nil {
+	// canceled gives back the claimed workspace when ctx is done between
+	// steps. A workspace whose directory was never created is dropped from
+	// state instead.
+	canceled := func() error {
+		if ctx.Err() == nil {
+			return nil
+		}
+		if needsCreate {
+			p.stateStore.Update(func(st *statestore.State) error {
+				delete(st.Workspaces, repoName+"/"+wsName)
+				return nil
+			})
+		} else {
+			p.Release(wsPath)
+		}
+		return fmt.Errorf("acquire workspace %s: %w", wsName, ctx.Err())
+	}
+
+	if err := canceled(); err != nil {
 		return "", err
 	}
 
 	// Create the workspace directory if needed
 	if needsCreate {
+		timer.enter(AcquirePhaseCreating)
 		if err := os.MkdirAll(filepath.Dir(wsPath), 0755); err != nil {
 			return "", fmt.Errorf("create workspace parent dir: %w", err)
 		}
 
-		if err := p.jj.WorkspaceAdd(repoPath, wsName, wsPath); err != nil {
+		if err := p.addWorkspace(repoPath, wsName, wsPath); err != nil {
 			// Clean up state on failure
 			p.stateStore.Update(func(st *statestore.State) error {
 				delete(st.Workspaces, repoName+"/"+wsName)
@@ -194,6 +551,11 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 			})
 			return "", fmt.Errorf("jj workspace add: %w", err)
 		}
+		// From here on the workspace exists, so cancellation releases it.
+		needsCreate = false
+		if err := canceled(); err != nil {
+			return "", err
+		}
 	}
 
 	newChange := func(parentRev string) (string, error) {
@@ -203,6 +565,7 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 		return p.jj.NewChange(wsPath, parentRev)
 	}
 
+	timer.enter(AcquirePhaseCheckingOut)
 	actualRev, err := newChange(opts.Rev)
 	if err != nil {
 		if isMissingRevisionError(err) && looksLikeChangeID(opts.Rev) {
@@ -229,14 +592,28 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 	}
 
 	// Load config and run hooks
+	timer.enter(AcquirePhaseRunningHooks)
 	cfg, err := config.Load(repoPath)
 	if err != nil {
 		return "", fmt.Errorf("load config: %w", err)
 	}
 
+	if err := canceled(); err != nil {
+		return "", err
+	}
+
 	// Run on-create script for every acquire
-	if err := config.RunScript(wsPath, cfg.Workspace.OnCreate); err != nil {
-		p.Release(wsPath)
+	if err := p.runOnCreate(ctx, wsPath, cfg.Workspace.OnCreate, time.Duration(cfg.Workspace.OnCreateTimeout)); err != nil {
+		if errors.Is(err, errOnCreateSlotWait) {
+			// The hook never ran, so the workspace is as clean as before.
+			p.Release(wsPath)
+		} else if errors.Is(err, ErrHookTimeout) || ctx.Err() != nil {
+			// The hook was killed partway through, so the workspace is in
+			// an unknown state and must not be handed out again.
+			p.discardWorkspace(repoPath, repoName, wsName, wsPath)
+		} else {
+			p.Release(wsPath)
+		}
 		return "", fmt.Errorf("on-create script: %w", err)
 	}
 
@@ -252,9 +629,132 @@ func (p *Pool) Acquire(repoPath string, opts AcquireOptions) (string, error) {
 		})
 	}
 
+	if opts.AutoRenew {
+		p.startAutoRenew(wsPath)
+	}
+
+	warnIfSlowAcquire(p.warnings, p.slowAcquireThreshold, wsName, timer)
 	return wsPath, nil
 }
 
+// checkRevision makes sure rev exists before any workspace is claimed or
+// created. Missing revisions that look like change IDs are let through, since
+// Acquire falls back to @ for them.
+func (p *Pool) checkRevision(repoPath, rev string) error {
+	if rev == "@" || p.revisionExists == nil {
+		return nil
+	}
+	exists, err := p.revisionExists(repoPath, rev)
+	if err != nil {
+		return fmt.Errorf("check revision %q: %w", rev, err)
+	}
+	if !exists && !looksLikeChangeID(rev) {
+		return fmt.Errorf("%w: %s", ErrRevisionNotFound, rev)
+	}
+	return nil
+}
+
+// errOnCreateSlotWait marks a canceled wait for an on-create slot, where the
+// hook never started.
+var errOnCreateSlotWait = errors.New("wait for on-create slot")
+
+// runOnCreate runs the on-create hook, waiting for a free slot when the pool
+// limits hook concurrency. A positive timeout kills the hook once it has run
+// that long (time spent waiting for a slot does not count) and returns
+// ErrHookTimeout. When ctx is done the wait stops or the hook is killed, and
+// the error wraps ctx.Err().
+func (p *Pool) runOnCreate(ctx context.Context, wsPath, script string, timeout time.Duration) error {
+	if internalstrings.IsBlank(script) {
+		return nil
+	}
+	if p.onCreateSlots != nil {
+		select {
+		case p.onCreateSlots <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", errOnCreateSlotWait, ctx.Err())
+		}
+		defer func() { <-p.onCreateSlots }()
+	}
+
+	hookCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := p.runScript(hookCtx, wsPath, script)
+	if err == nil {
+		return nil
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if ctx.Err() != nil {
+		return fmt.Errorf("%q interrupted after %s: %w", hookCommand(script), elapsed, ctx.Err())
+	}
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %q killed after %s", ErrHookTimeout, hookCommand(script), elapsed)
+	}
+	return err
+}
+
+// hookCommand returns the first command line of a hook script, skipping any
+// shebang, for use in error messages.
+func hookCommand(script string) string {
+	for _, line := range strings.Split(script, "\n") {
+		line = internalstrings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#!") {
+			continue
+		}
+		return line
+	}
+	return internalstrings.TrimSpace(script)
+}
+
+// discardWorkspace removes an acquired workspace from the pool entirely:
+// it is dropped from state, forgotten by jj, and deleted from disk. Cleanup is
+// best-effort since the caller is already reporting a failure.
+func (p *Pool) discardWorkspace(repoPath, repoName, wsName, wsPath string) {
+	p.stopAutoRenew(wsPath)
+	_ = p.stateStore.Update(func(st *statestore.State) error {
+		delete(st.Workspaces, repoName+"/"+wsName)
+		return nil
+	})
+	_ = p.jj.WorkspaceForget(repoPath, wsName)
+	_ = os.RemoveAll(wsPath)
+}
+
+// selectAvailable returns the state key of the healthy available workspace in
+// the repo's pool that strategy prefers. Workspaces released at the same time
+// (or never) are ordered by name so the choice is deterministic.
+func selectAvailable(st *statestore.State, repoName, pool string, strategy SelectionStrategy) (string, bool) {
+	var bestKey string
+	var best statestore.WorkspaceInfo
+	found := false
+	for key, ws := range st.Workspaces {
+		if ws.Repo != repoName || ws.Pool != pool || ws.Status != statestore.WorkspaceStatusAvailable || ws.Unhealthy {
+			continue
+		}
+		if strategy == StrategyAny {
+			return key, true
+		}
+		if !found || preferWorkspace(ws, best, strategy) {
+			bestKey, best, found = key, ws, true
+		}
+	}
+	return bestKey, found
+}
+
+// preferWorkspace reports whether strategy prefers a over b.
+func preferWorkspace(a, b statestore.WorkspaceInfo, strategy SelectionStrategy) bool {
+	if !a.LastUsedAt.Equal(b.LastUsedAt) {
+		if strategy == StrategyMRU {
+			return a.LastUsedAt.After(b.LastUsedAt)
+		}
+		return a.LastUsedAt.Before(b.LastUsedAt)
+	}
+	return a.Name < b.Name
+}
+
 // Release returns a workspace to the pool, making it available for reuse.
 //
 // After releasing, the workspace path should no longer be used. The workspace
@@ -264,20 +764,16 @@ func (p *Pool) Release(wsPath string) error {
 }
 
 func (p *Pool) releaseToAvailable(wsPath string) error {
+	p.stopAutoRenew(wsPath)
+
 	if _, err := p.jj.NewChange(wsPath, "root()"); err != nil {
 		return fmt.Errorf("jj new root(): %w", err)
 	}
 
 	return p.stateStore.Update(func(st *statestore.State) error {
-		now := time.Now()
 		for key, ws := range st.Workspaces {
 			if ws.Path == wsPath {
-				ws.Status = statestore.WorkspaceStatusAvailable
-				ws.Purpose = ""
-				ws.Rev = ""
-				ws.AcquiredByPID = 0
-				ws.AcquiredAt = time.Time{}
-				ws.UpdatedAt = now
+				clearAcquisition(&ws, time.Now())
 				st.Workspaces[key] = ws
 				return nil
 			}
@@ -286,6 +782,69 @@ func (p *Pool) releaseToAvailable(wsPath string) error {
 	})
 }
 
+// clearAcquisition marks ws available, dropping everything recorded about
+// its current holder.
+func clearAcquisition(ws *statestore.WorkspaceInfo, now time.Time) {
+	ws.Status = statestore.WorkspaceStatusAvailable
+	ws.Purpose = ""
+	ws.Rev = ""
+	ws.Labels = nil
+	ws.AcquiredByPID = 0
+	ws.AcquiredByHost = ""
+	ws.AcquiredAt = time.Time{}
+	ws.RefreshedAt = time.Time{}
+	ws.LastUsedAt = now
+	ws.UpdatedAt = now
+}
+
+// Relabel updates the labels on an acquired workspace without releasing it.
+//
+// Keys in labels replace existing values; a key with an empty value removes
+// that label. Returns ErrWorkspaceNotAcquired if the workspace is available.
+func (p *Pool) Relabel(wsPath string, labels map[string]string) error {
+	wsPath = filepath.Clean(wsPath)
+	return p.stateStore.Update(func(st *statestore.State) error {
+		for key, ws := range st.Workspaces {
+			if filepath.Clean(ws.Path) != wsPath {
+				continue
+			}
+			if ws.Status != statestore.WorkspaceStatusAcquired {
+				return fmt.Errorf("%w: %s", ErrWorkspaceNotAcquired, wsPath)
+			}
+			merged := copyLabels(ws.Labels)
+			if merged == nil {
+				merged = make(map[string]string, len(labels))
+			}
+			for name, value := range labels {
+				if value == "" {
+					delete(merged, name)
+					continue
+				}
+				merged[name] = value
+			}
+			if len(merged) == 0 {
+				merged = nil
+			}
+			ws.Labels = merged
+			ws.UpdatedAt = time.Now()
+			st.Workspaces[key] = ws
+			return nil
+		}
+		return fmt.Errorf("workspace not found: %s", wsPath)
+	})
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(labels))
+	for key, value := range labels {
+		copied[key] = value
+	}
+	return copied
+}
+
 // ReleaseByName returns a workspace to the pool by name.
 func (p *Pool) ReleaseByName(repoPath, wsName string) error {
 	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
@@ -318,6 +877,10 @@ type Info struct {
 	// Purpose describes why the workspace was acquired.
 	Purpose string
 
+	// Pool is the isolation key the workspace belongs to. Empty is the
+	// shared default pool.
+	Pool string
+
 	// Rev is the jj revision the workspace was opened to.
 	Rev string
 
@@ -328,15 +891,51 @@ type Info struct {
 	// Zero if not acquired.
 	AcquiredByPID int
 
+	// AcquiredByHost is the hostname of the machine that acquired this
+	// workspace. Empty if not acquired or acquired before hosts were
+	// recorded.
+	AcquiredByHost string
+
 	// AcquiredAt is when the workspace was acquired.
 	// Zero if not acquired.
 	AcquiredAt time.Time
 
+	// RefreshedAt is when AcquireOptions.RefreshToRev last moved the
+	// current acquisition onto a newer base. Zero if it has not.
+	RefreshedAt time.Time
+
+	// LastUsedAt is when the workspace was last released. Zero if it has
+	// never been released.
+	LastUsedAt time.Time
+
 	// CreatedAt is when the workspace acquisition started.
 	CreatedAt time.Time
 
 	// UpdatedAt is when the workspace was last released.
 	UpdatedAt time.Time
+
+	// Labels are key/value annotations attached to the current acquisition.
+	Labels map[string]string
+
+	// SizeBytes is the disk space used by the workspace directory.
+	// Only populated when ListOptions.IncludeSizes is set.
+	SizeBytes int64
+
+	// Health is the result of a jj health check on the workspace.
+	// Only populated when ListOptions.CheckHealth is set.
+	Health HealthStatus
+}
+
+// ListOptions configures a list operation.
+type ListOptions struct {
+	// IncludeSizes walks each workspace directory to populate SizeBytes.
+	IncludeSizes bool
+
+	// IncludeVCS counts the .jj directory toward SizeBytes.
+	IncludeVCS bool
+
+	// CheckHealth runs a jj health check on each workspace to populate Health.
+	CheckHealth bool
 }
 
 // List returns information about all workspaces for the given repository.
@@ -344,6 +943,12 @@ type Info struct {
 // The returned slice includes both available and acquired workspaces.
 
 func (p *Pool) List(repoPath string) ([]Info, error) {
+	return p.ListWithOptions(repoPath, ListOptions{})
+}
+
+// ListWithOptions returns information about all workspaces for the given
+// repository, optionally measuring each workspace's disk usage.
+func (p *Pool) ListWithOptions(repoPath string, opts ListOptions) ([]Info, error) {
 	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("get repo name: %w", err)
@@ -361,16 +966,16 @@ func (p *Pool) List(repoPath string) ([]Info, error) {
 			continue
 		}
 
-		item := Info{
-			Name:          ws.Name,
-			Path:          ws.Path,
-			Purpose:       ws.Purpose,
-			Rev:           ws.Rev,
-			Status:        ws.Status,
-			AcquiredByPID: ws.AcquiredByPID,
-			AcquiredAt:    ws.AcquiredAt,
-			CreatedAt:     ws.CreatedAt,
-			UpdatedAt:     ws.UpdatedAt,
+		item := newInfo(ws)
+		if opts.IncludeSizes {
+			size, err := dirSize(ws.Path, opts.IncludeVCS)
+			if err != nil {
+				return nil, fmt.Errorf("measure workspace %s: %w", ws.Name, err)
+			}
+			item.SizeBytes = size
+		}
+		if opts.CheckHealth {
+			item.Health = p.checkHealth(ws.Name, ws.Path).Status
 		}
 
 		items = append(items, item)
@@ -389,6 +994,26 @@ func (p *Pool) List(repoPath string) ([]Info, error) {
 	return items, nil
 }
 
+// newInfo converts a stored workspace into its public Info.
+func newInfo(ws statestore.WorkspaceInfo) Info {
+	return Info{
+		Name:           ws.Name,
+		Path:           ws.Path,
+		Purpose:        ws.Purpose,
+		Pool:           ws.Pool,
+		Rev:            ws.Rev,
+		Status:         ws.Status,
+		AcquiredByPID:  ws.AcquiredByPID,
+		AcquiredByHost: ws.AcquiredByHost,
+		AcquiredAt:     ws.AcquiredAt,
+		RefreshedAt:    ws.RefreshedAt,
+		LastUsedAt:     ws.LastUsedAt,
+		CreatedAt:      ws.CreatedAt,
+		UpdatedAt:      ws.UpdatedAt,
+		Labels:         copyLabels(ws.Labels),
+	}
+}
+
 func workspaceStatusRank(status Status) int {
 	switch status {
 	case StatusAcquired:
@@ -496,6 +1121,18 @@ func repoRootFromPathWithOptions(path string, opts Options) (string, error) {
 	return root, nil
 }
 
+// countPoolWorkspaces returns how many workspaces the repo has in the named
+// pool, whether acquired or available.
+func countPoolWorkspaces(st *statestore.State, repoName, pool string) int {
+	count := 0
+	for _, ws := range st.Workspaces {
+		if ws.Repo == repoName && ws.Pool == pool {
+			count++
+		}
+	}
+	return count
+}
+
 // nextWorkspaceName returns the next sequential workspace name for the repo.
 func (p *Pool) nextWorkspaceName(st *statestore.State, repoName string) string {
 	maxNum := 0
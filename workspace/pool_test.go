@@ -96,6 +96,57 @@ func TestPool_Acquire_CreatesNewWorkspace(t *testing.T) {
 	}
 }
 
+func TestPool_Open_UsesEnvDirs(t *testing.T) {
+	repoPath := setupTestRepo(t)
+	workspacesDir, _ := filepath.EvalSymlinks(t.TempDir())
+	stateDir := t.TempDir()
+	t.Setenv("INCREMENTUM_WORKSPACES_DIR", workspacesDir)
+	t.Setenv("INCREMENTUM_STATE_DIR", stateDir)
+
+	pool, err := workspace.Open()
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+
+	wsPath, err := pool.Acquire(repoPath, acquireOptions())
+	if err != nil {
+		t.Fatalf("failed to acquire workspace: %v", err)
+	}
+	defer pool.Release(wsPath)
+
+	if !strings.HasPrefix(wsPath, workspacesDir+string(filepath.Separator)) {
+		t.Fatalf("expected workspace under %s, got %s", workspacesDir, wsPath)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "state.json")); err != nil {
+		t.Fatalf("expected state in %s: %v", stateDir, err)
+	}
+}
+
+func TestPool_OpenWithOptions_ExplicitDirsOverrideEnv(t *testing.T) {
+	envStateDir := t.TempDir()
+	t.Setenv("INCREMENTUM_WORKSPACES_DIR", t.TempDir())
+	t.Setenv("INCREMENTUM_STATE_DIR", envStateDir)
+	stateDir := t.TempDir()
+
+	pool, err := workspace.OpenWithOptions(workspace.Options{
+		StateDir:      stateDir,
+		WorkspacesDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	if _, err := pool.RepoSlug("/tmp/my-repo"); err != nil {
+		t.Fatalf("repo slug: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(stateDir, "state.json")); err != nil {
+		t.Fatalf("expected state in explicit dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(envStateDir, "state.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no state in env dir, got %v", err)
+	}
+}
+
 func TestPool_RepoSlug(t *testing.T) {
 	pool, err := workspace.OpenWithOptions(workspace.Options{
 		StateDir:      t.TempDir(),
@@ -466,6 +517,61 @@ func TestPool_List(t *testing.T) {
 
 }
 
+func TestPool_Relabel(t *testing.T) {
+	repoPath := setupTestRepo(t)
+	workspacesDir := t.TempDir()
+	workspacesDir, _ = filepath.EvalSymlinks(workspacesDir)
+	stateDir := t.TempDir()
+
+	pool, err := workspace.OpenWithOptions(workspace.Options{
+		StateDir:      stateDir,
+		WorkspacesDir: workspacesDir,
+	})
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+
+	opts := acquireOptions()
+	opts.Labels = map[string]string{"job": "abc", "todo": "xyz"}
+	wsPath, err := pool.Acquire(repoPath, opts)
+	if err != nil {
+		t.Fatalf("failed to acquire: %v", err)
+	}
+
+	if err := pool.Relabel(wsPath, map[string]string{"job": "def", "todo": "", "attempt": "2"}); err != nil {
+		t.Fatalf("failed to relabel: %v", err)
+	}
+
+	list, err := pool.List(repoPath)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 workspace, got %d", len(list))
+	}
+	labels := list[0].Labels
+	if len(labels) != 2 || labels["job"] != "def" || labels["attempt"] != "2" {
+		t.Fatalf("unexpected labels: %#v", labels)
+	}
+
+	if err := pool.Release(wsPath); err != nil {
+		t.Fatalf("failed to release workspace: %v", err)
+	}
+
+	err = pool.Relabel(wsPath, map[string]string{"job": "ghi"})
+	if !errors.Is(err, workspace.ErrWorkspaceNotAcquired) {
+		t.Fatalf("expected ErrWorkspaceNotAcquired, got %v", err)
+	}
+
+	list, err = pool.List(repoPath)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(list[0].Labels) != 0 {
+		t.Fatalf("expected labels cleared on release, got %#v", list[0].Labels)
+	}
+}
+
 func TestPool_List_SortsByStatusThenName(t *testing.T) {
 	repoPath := setupTestRepo(t)
 	workspacesDir := t.TempDir()
@@ -734,3 +840,51 @@ func TestPool_WorkspaceNameForPath_NotInWorkspace(t *testing.T) {
 		t.Fatalf("expected workspace root not found error, got %v", err)
 	}
 }
+
+func TestPool_Acquire_SelectionStrategy(t *testing.T) {
+	for _, tt := range []struct {
+		strategy workspace.SelectionStrategy
+		want     int
+	}{
+		{workspace.StrategyMRU, 2},
+		{workspace.StrategyLRU, 1},
+	} {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			repoPath := setupTestRepo(t)
+			workspacesDir, _ := filepath.EvalSymlinks(t.TempDir())
+
+			pool, err := workspace.OpenWithOptions(workspace.Options{
+				StateDir:          t.TempDir(),
+				WorkspacesDir:     workspacesDir,
+				SelectionStrategy: tt.strategy,
+			})
+			if err != nil {
+				t.Fatalf("failed to open pool: %v", err)
+			}
+
+			var paths []string
+			for range 3 {
+				wsPath, err := pool.Acquire(repoPath, acquireOptions())
+				if err != nil {
+					t.Fatalf("failed to acquire workspace: %v", err)
+				}
+				paths = append(paths, wsPath)
+			}
+			// Release in the order 1, 0, 2: 2 is then the most recently used and 1
+			// the least.
+			for _, i := range []int{1, 0, 2} {
+				if err := pool.Release(paths[i]); err != nil {
+					t.Fatalf("failed to release workspace: %v", err)
+				}
+			}
+
+			got, err := pool.Acquire(repoPath, acquireOptions())
+			if err != nil {
+				t.Fatalf("failed to acquire workspace: %v", err)
+			}
+			if got != paths[tt.want] {
+				t.Fatalf("expected %s, got %s", paths[tt.want], got)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	"errors"
+	"syscall"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// holderExited reports whether the process that acquired ws is known to have
+// exited. Only holders on host can be checked; a workspace acquired on
+// another machine never counts as exited. Acquisitions recorded without a
+// host are treated as local.
+func holderExited(ws statestore.WorkspaceInfo, host string) bool {
+	if ws.Status != statestore.WorkspaceStatusAcquired || ws.AcquiredByPID <= 0 {
+		return false
+	}
+	if ws.AcquiredByHost != "" && ws.AcquiredByHost != host {
+		return false
+	}
+	return !processAlive(ws.AcquiredByPID)
+}
@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// PruneOptions configures a prune operation.
+type PruneOptions struct {
+	// OlderThan limits pruning to workspaces released at least this long ago.
+	// Zero prunes every available workspace.
+	OlderThan time.Duration
+
+	// DryRun reports what would be pruned without changing anything.
+	DryRun bool
+}
+
+// PrunedWorkspace describes a workspace removed (or, in a dry run, selected)
+// by Prune.
+type PrunedWorkspace struct {
+	// Name is the workspace identifier (e.g., "ws-001").
+	Name string
+
+	// Path is the workspace directory.
+	Path string
+
+	// SizeBytes is the disk space the workspace directory occupied.
+	SizeBytes int64
+}
+
+// Prune deletes available workspaces for the repository that were released
+// longer ago than opts.OlderThan.
+//
+// Acquired workspaces are never pruned. Each pruned workspace is removed from
+// state, forgotten from jj, and deleted from disk. The result is ordered by
+// workspace name.
+func (p *Pool) Prune(repoPath string, opts PruneOptions) ([]PrunedWorkspace, error) {
+	repoName, err := p.stateStore.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("get repo name: %w", err)
+	}
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	var candidates []statestore.WorkspaceInfo
+	var repoSourcePath string
+
+	// Remove candidates from state first so no one acquires them mid-delete.
+	err = p.stateStore.Update(func(st *statestore.State) error {
+		if repo, ok := st.Repos[repoName]; ok {
+			repoSourcePath = repo.SourcePath
+		}
+		for key, ws := range st.Workspaces {
+			if ws.Repo != repoName || ws.Status != statestore.WorkspaceStatusAvailable {
+				continue
+			}
+			if ws.UpdatedAt.After(cutoff) {
+				continue
+			}
+			candidates = append(candidates, ws)
+			if !opts.DryRun {
+				delete(st.Workspaces, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	pruned := make([]PrunedWorkspace, 0, len(candidates))
+	var errs []error
+	for _, ws := range candidates {
+		size, err := dirSize(ws.Path, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("measure workspace %s: %w", ws.Name, err))
+		}
+		pruned = append(pruned, PrunedWorkspace{Name: ws.Name, Path: ws.Path, SizeBytes: size})
+		if opts.DryRun {
+			continue
+		}
+
+		if repoSourcePath != "" {
+			if err := p.jj.WorkspaceForget(repoSourcePath, ws.Name); err != nil {
+				errs = append(errs, fmt.Errorf("forget workspace %s: %w", ws.Name, err))
+			}
+		}
+		if err := os.RemoveAll(ws.Path); err != nil {
+			errs = append(errs, fmt.Errorf("remove workspace %s: %w", ws.Path, err))
+		}
+	}
+
+	return pruned, errors.Join(errs...)
+}
+
+// dirSize returns the total size of regular files under path. When
+// includeVCS is false, the .jj directory is skipped. A missing path has size
+// zero.
+func dirSize(path string, includeVCS bool) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if !includeVCS && current != path && entry.Name() == ".jj" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
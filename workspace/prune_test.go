@@ -0,0 +1,193 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func seedPruneWorkspaces(t *testing.T, stateDir, repoPath string) (string, map[string]string) {
+	t.Helper()
+	store := statestore.NewStore(stateDir)
+	repoName, err := store.GetOrCreateRepoName(repoPath)
+	if err != nil {
+		t.Fatalf("repo name: %v", err)
+	}
+
+	now := time.Now()
+	root := t.TempDir()
+	seeds := []statestore.WorkspaceInfo{
+		{Name: "ws-001", Status: statestore.WorkspaceStatusAvailable, UpdatedAt: now.Add(-2 * time.Hour)},
+		{Name: "ws-002", Status: statestore.WorkspaceStatusAvailable, UpdatedAt: now},
+		{Name: "ws-003", Status: statestore.WorkspaceStatusAcquired, UpdatedAt: now.Add(-2 * time.Hour)},
+	}
+	paths := make(map[string]string, len(seeds))
+	for _, ws := range seeds {
+		wsPath := filepath.Join(root, ws.Name)
+		if err := os.MkdirAll(wsPath, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(wsPath, "file.txt"), []byte("0123456789"), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		paths[ws.Name] = wsPath
+	}
+
+	err = store.Update(func(st *statestore.State) error {
+		for _, ws := range seeds {
+			ws.Repo = repoName
+			ws.Path = paths[ws.Name]
+			st.Workspaces[repoName+"/"+ws.Name] = ws
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	return repoName, paths
+}
+
+func TestPruneDryRunSelectsOldAvailableWorkspaces(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName, paths := seedPruneWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	pruned, err := pool.Prune(repoPath, PruneOptions{OlderThan: time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0].Name != "ws-001" {
+		t.Fatalf("expected only ws-001 to be selected, got %+v", pruned)
+	}
+	if pruned[0].SizeBytes != 10 {
+		t.Fatalf("expected 10 bytes, got %d", pruned[0].SizeBytes)
+	}
+
+	if _, err := os.Stat(paths["ws-001"]); err != nil {
+		t.Fatalf("expected dry run to keep directory: %v", err)
+	}
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if _, ok := st.Workspaces[repoName+"/ws-001"]; !ok {
+		t.Fatal("expected dry run to keep workspace state")
+	}
+}
+
+func TestPruneRemovesWorkspaces(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName, paths := seedPruneWorkspaces(t, stateDir, repoPath)
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// repoPath is not a jj repo, so forgetting the workspace fails; the
+	// directory and state entry are removed regardless.
+	pruned, err := pool.Prune(repoPath, PruneOptions{OlderThan: time.Hour})
+	if err != nil && !strings.Contains(err.Error(), "forget workspace ws-001") {
+		t.Fatalf("unexpected prune error: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0].Name != "ws-001" {
+		t.Fatalf("expected ws-001 to be pruned, got %+v", pruned)
+	}
+
+	if _, err := os.Stat(paths["ws-001"]); !os.IsNotExist(err) {
+		t.Fatalf("expected ws-001 directory removed, got %v", err)
+	}
+	for _, name := range []string{"ws-002", "ws-003"} {
+		if _, err := os.Stat(paths[name]); err != nil {
+			t.Fatalf("expected %s directory kept: %v", name, err)
+		}
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if _, ok := st.Workspaces[repoName+"/ws-001"]; ok {
+		t.Fatal("expected ws-001 removed from state")
+	}
+	if _, ok := st.Workspaces[repoName+"/ws-003"]; !ok {
+		t.Fatal("expected acquired ws-003 kept in state")
+	}
+}
+
+func TestDirSizeSkipsVCS(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abc"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".jj"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".jj", "store"), []byte("12345"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	withVCS, err := dirSize(dir, true)
+	if err != nil {
+		t.Fatalf("dir size: %v", err)
+	}
+	if withVCS != 8 {
+		t.Fatalf("expected 8 bytes with VCS, got %d", withVCS)
+	}
+	withoutVCS, err := dirSize(dir, false)
+	if err != nil {
+		t.Fatalf("dir size: %v", err)
+	}
+	if withoutVCS != 3 {
+		t.Fatalf("expected 3 bytes without VCS, got %d", withoutVCS)
+	}
+
+	missing, err := dirSize(filepath.Join(dir, "missing"), true)
+	if err != nil || missing != 0 {
+		t.Fatalf("expected missing dir to be 0 bytes, got %d, %v", missing, err)
+	}
+}
+
+func TestPruneReportsEveryWorkspaceError(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName, _ := seedPruneWorkspaces(t, stateDir, repoPath)
+	err := statestore.NewStore(stateDir).Update(func(st *statestore.State) error {
+		ws := st.Workspaces[repoName+"/ws-002"]
+		ws.UpdatedAt = time.Now().Add(-2 * time.Hour)
+		st.Workspaces[repoName+"/ws-002"] = ws
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("age ws-002: %v", err)
+	}
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+
+	// repoPath is not a jj repo, so forgetting fails for both workspaces.
+	pruned, err := pool.Prune(repoPath, PruneOptions{OlderThan: time.Hour})
+	if len(pruned) != 2 {
+		t.Fatalf("expected two pruned workspaces, got %+v", pruned)
+	}
+	if err == nil {
+		t.Fatal("expected forget errors")
+	}
+	for _, name := range []string{"ws-001", "ws-002"} {
+		if !strings.Contains(err.Error(), "forget workspace "+name) {
+			t.Fatalf("expected error for %s, got %v", name, err)
+		}
+	}
+}
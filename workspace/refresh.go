@@ -0,0 +1,65 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+// refreshReattached rebases a reattached workspace's work onto rev and
+// records the refresh on the lease. Conflicts from the rebase are returned as
+// ErrRefreshConflict so a conflicted workspace is never handed out.
+func (p *Pool) refreshReattached(repoName, wsName, wsPath, rev string) error {
+	moved, err := p.refreshWorkspace(wsPath, rev)
+	if err != nil {
+		return fmt.Errorf("refresh workspace %s to %s: %w", wsName, rev, err)
+	}
+	return p.stateStore.Update(func(st *statestore.State) error {
+		wsKey := repoName + "/" + wsName
+		if ws, ok := st.Workspaces[wsKey]; ok {
+			ws.Rev = rev
+			if moved {
+				ws.RefreshedAt = time.Now()
+			}
+			st.Workspaces[wsKey] = ws
+		}
+		return nil
+	})
+}
+
+// refreshWithJJ rebases the workspace's branch onto rev and reports whether
+// the working copy moved. A rebase that leaves conflicts is undone by
+// reverting just the rebase operation, so the workspace keeps its old,
+// conflict-free base while operations from other workspaces are kept.
+func (p *Pool) refreshWithJJ(wsPath, rev string) (bool, error) {
+	before, err := p.jj.CurrentCommitID(wsPath)
+	if err != nil {
+		return false, err
+	}
+	if err := p.jj.Rebase(wsPath, "@", rev); err != nil {
+		return false, err
+	}
+	rebaseOp, err := p.jj.CurrentOperationID(wsPath)
+	if err != nil {
+		return false, err
+	}
+	conflicted, err := p.jj.ConflictedChangeIDs(wsPath, rev+"..@")
+	if err != nil {
+		return false, err
+	}
+	if len(conflicted) > 0 {
+		conflictErr := fmt.Errorf("%w: %s", ErrRefreshConflict, strings.Join(conflicted, ", "))
+		if err := p.jj.OperationRevert(wsPath, rebaseOp); err != nil {
+			return false, errors.Join(conflictErr, fmt.Errorf("undo rebase: %w", err))
+		}
+		return false, conflictErr
+	}
+	after, err := p.jj.CurrentCommitID(wsPath)
+	if err != nil {
+		return false, err
+	}
+	return after != before, nil
+}
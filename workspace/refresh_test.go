@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestAcquireRefreshesReattachedWorkspace(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.revisionExists = func(string, string) (bool, error) { return true, nil }
+	var refreshed []string
+	pool.refreshWorkspace = func(_ string, rev string) (bool, error) {
+		refreshed = append(refreshed, rev)
+		return true, nil
+	}
+
+	// Without RefreshToRev the working copy is left alone.
+	if _, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "holder", Name: "ws-001", Rev: "main"}); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if len(refreshed) != 0 {
+		t.Fatalf("expected no refresh by default, got %v", refreshed)
+	}
+
+	if _, err := pool.Acquire(repoPath, AcquireOptions{Purpose: "holder", Name: "ws-001", Rev: "main", RefreshToRev: true}); err != nil {
+		t.Fatalf("acquire with refresh: %v", err)
+	}
+	if len(refreshed) != 1 || refreshed[0] != "main" {
+		t.Fatalf("expected one refresh onto main, got %v", refreshed)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	ws := st.Workspaces[repoName+"/ws-001"]
+	if ws.RefreshedAt.IsZero() || ws.Rev != "main" {
+		t.Fatalf("expected the refresh to be recorded, got rev %q refreshed at %v", ws.Rev, ws.RefreshedAt)
+	}
+}
+
+func TestAcquireFailsOnRefreshConflict(t *testing.T) {
+	stateDir := t.TempDir()
+	repoPath := t.TempDir()
+	repoName := seedAcquiredWorkspace(t, stateDir, repoPath, os.Getpid())
+
+	pool, err := OpenWithOptions(Options{StateDir: stateDir, WorkspacesDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	pool.revisionExists = func(string, string) (bool, error) { return true, nil }
+	pool.refreshWorkspace = func(string, string) (bool, error) {
+		return false, ErrRefreshConflict
+	}
+
+	_, err = pool.Acquire(repoPath, AcquireOptions{Purpose: "holder", Name: "ws-001", Rev: "main", RefreshToRev: true})
+	if !errors.Is(err, ErrRefreshConflict) {
+		t.Fatalf("expected ErrRefreshConflict, got %v", err)
+	}
+
+	st, err := statestore.NewStore(stateDir).Load()
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if ws := st.Workspaces[repoName+"/ws-001"]; !ws.RefreshedAt.IsZero() {
+		t.Fatalf("expected no refresh to be recorded, got %v", ws.RefreshedAt)
+	}
+}
@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	statestore "github.com/amonks/incrementum/internal/state"
+)
+
+func TestSelectAvailableByStrategy(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	st := &statestore.State{Workspaces: map[string]statestore.WorkspaceInfo{}}
+	// Released in the order ws-002, ws-003, ws-001.
+	for name, lastUsed := range map[string]time.Time{
+		"ws-001": base.Add(2 * time.Minute),
+		"ws-002": base,
+		"ws-003": base.Add(time.Minute),
+	} {
+		st.Workspaces["repo/"+name] = statestore.WorkspaceInfo{
+			Name:       name,
+			Repo:       "repo",
+			Status:     statestore.WorkspaceStatusAvailable,
+			LastUsedAt: lastUsed,
+		}
+	}
+	st.Workspaces["repo/ws-004"] = statestore.WorkspaceInfo{
+		Name:       "ws-004",
+		Repo:       "repo",
+		Status:     statestore.WorkspaceStatusAcquired,
+		LastUsedAt: base.Add(time.Hour),
+	}
+	st.Workspaces["repo/ws-005"] = statestore.WorkspaceInfo{
+		Name:       "ws-005",
+		Repo:       "repo",
+		Status:     statestore.WorkspaceStatusAvailable,
+		Unhealthy:  true,
+		LastUsedAt: base.Add(-time.Hour),
+	}
+
+	tests := []struct {
+		strategy SelectionStrategy
+		want     string
+	}{
+		{StrategyMRU, "repo/ws-001"},
+		{StrategyLRU, "repo/ws-002"},
+	}
+	for _, tt := range tests {
+		got, ok := selectAvailable(st, "repo", "", tt.strategy)
+		if !ok || got != tt.want {
+			t.Errorf("strategy %q: expected %s, got %q (ok=%v)", tt.strategy, tt.want, got, ok)
+		}
+	}
+
+	if got, ok := selectAvailable(st, "repo", "", StrategyAny); !ok || got == "repo/ws-004" || got == "repo/ws-005" {
+		t.Errorf("strategy any: expected an available healthy workspace, got %q (ok=%v)", got, ok)
+	}
+	if _, ok := selectAvailable(st, "repo", "other", StrategyMRU); ok {
+		t.Error("expected no workspace from another pool")
+	}
+}
+
+func TestSelectAvailableBreaksTiesByName(t *testing.T) {
+	st := &statestore.State{Workspaces: map[string]statestore.WorkspaceInfo{}}
+	for _, name := range []string{"ws-003", "ws-001", "ws-002"} {
+		st.Workspaces["repo/"+name] = statestore.WorkspaceInfo{
+			Name:   name,
+			Repo:   "repo",
+			Status: statestore.WorkspaceStatusAvailable,
+		}
+	}
+	for _, strategy := range []SelectionStrategy{StrategyMRU, StrategyLRU} {
+		if got, _ := selectAvailable(st, "repo", "", strategy); got != "repo/ws-001" {
+			t.Errorf("strategy %q: expected repo/ws-001, got %q", strategy, got)
+		}
+	}
+}
+
+func TestOpenRejectsUnknownSelectionStrategy(t *testing.T) {
+	_, err := OpenWithOptions(Options{StateDir: t.TempDir(), WorkspacesDir: t.TempDir(), SelectionStrategy: "newest"})
+	if !errors.Is(err, ErrInvalidSelectionStrategy) {
+		t.Fatalf("expected ErrInvalidSelectionStrategy, got %v", err)
+	}
+}